@@ -13,6 +13,7 @@ import (
 	"starter-gofiber/internal/handler/middleware"
 	"starter-gofiber/internal/infrastructure/cache"
 	"starter-gofiber/internal/infrastructure/email"
+	"starter-gofiber/internal/repository/postgres"
 	"starter-gofiber/internal/worker"
 	"starter-gofiber/pkg/apierror"
 	"starter-gofiber/pkg/crypto"
@@ -87,6 +88,9 @@ func main() {
 			logger.Info("Initializing Asynq job queue")
 			asynqClient := config.InitAsynqClient()
 			worker.SetAsynqClient(asynqClient)
+			worker.SetDB(config.DB)
+			worker.SetPostRepository(postgres.NewPostRepository(config.DB))
+			worker.SetFollowRepository(postgres.NewFollowRepository(config.DB))
 			worker.SetRedisConfig(
 				config.ENV.REDIS_HOST+":"+config.ENV.REDIS_PORT,
 				config.ENV.REDIS_PASSWORD,
@@ -104,6 +108,13 @@ func main() {
 			// Start Asynq worker server in background
 			go startWorkerServer()
 
+			// Relay job progress published by every worker to the SSE clients of this process
+			go worker.RelayProgress(context.Background())
+			// and the notifications sent by them
+			go worker.RelayNotifications(context.Background(), postgres.NewPollRepository(config.DB))
+			// and the role changes made by them
+			go worker.RelayRoles(context.Background())
+
 			// Start Asynq scheduler
 			go func() {
 				if err := config.AsynqScheduler.Run(); err != nil {
@@ -115,6 +126,31 @@ func main() {
 
 	// Initialize API Key middleware
 	middleware.InitAPIKeyMiddleware(config.DB)
+	middleware.SetSignatureClockSkew(time.Duration(config.ENV.API_KEY_SIGNATURE_SKEW) * time.Second)
+
+	// Initialize cookie-session auth (after Redis so sessions can use it)
+	config.LoadSession()
+
+	// Initialize email verification gate and reminder/purge policy
+	config.LoadEmailVerification()
+
+	// Initialize security event lockout/anomaly policy and GeoIP data
+	config.LoadSecurityEvents()
+
+	// Initialize home timeline fan-out policy
+	config.LoadTimeline()
+
+	// Initialize full-text search engine and index callbacks
+	config.LoadSearch()
+
+	// Initialize post attachment limit
+	config.LoadPosts()
+
+	// Initialize the post keyword filter and moderation audit logging
+	config.LoadModeration()
+
+	// Initialize where import files are kept and their size limit
+	config.LoadImports()
 
 	// Set default timeout values if not configured
 	requestTimeout := config.ENV.REQUEST_TIMEOUT
@@ -136,6 +172,7 @@ func main() {
 		IdleTimeout:     120 * time.Second, // Keep-alive timeout
 		ReadBufferSize:  4096,
 		WriteBufferSize: 4096,
+		BodyLimit:       config.BodyLimit(),
 	}
 	if config.ENV.ENV_TYPE == "prod" {
 		conf.Prefork = true
@@ -277,6 +314,21 @@ func startWorkerServer() {
 	mux.HandleFunc("backup:database", worker.HandleDatabaseBackup)
 	mux.HandleFunc("email:daily_digest", worker.HandleDailyEmailDigest)
 	mux.HandleFunc("metrics:collect", worker.HandleMetricsCollection)
+	mux.HandleFunc(worker.TaskEmailVerificationReminder, worker.HandleEmailVerificationReminder)
+	mux.HandleFunc(worker.TaskPurgeUnverifiedUsers, worker.HandlePurgeUnverifiedUsers)
+	mux.HandleFunc(worker.TaskReconcileReactions, worker.HandleReconcileReactions)
+	mux.HandleFunc(worker.TaskTimelineFanout, worker.HandleTimelineFanout)
+	mux.HandleFunc(worker.TaskSearchIndex, worker.HandleSearchIndex)
+	mux.HandleFunc(worker.TaskSearchReindex, worker.HandleSearchReindex)
+	mux.HandleFunc(worker.TaskPurgeOrphanAttachments, worker.HandlePurgeOrphanAttachments)
+	mux.HandleFunc(worker.TaskPublishPost, worker.HandlePublishPost)
+	mux.HandleFunc(worker.TaskPublishDuePosts, worker.HandlePublishDuePosts)
+	mux.HandleFunc(worker.TaskClosePolls, worker.HandleClosePolls)
+	mux.HandleFunc(worker.TaskUnfurlLink, worker.HandleUnfurlLink)
+	mux.HandleFunc(worker.TaskRollupPostStats, worker.HandleRollupPostStats)
+	mux.HandleFunc(worker.TaskPurgeTrashedPosts, worker.HandlePurgeTrashedPosts)
+	mux.HandleFunc(worker.TaskPostBulk, worker.HandlePostBulk)
+	mux.HandleFunc(worker.TaskProcessImport, worker.HandleProcessImport)
 
 	// Start server
 	if err := server.Run(mux); err != nil {
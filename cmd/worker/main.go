@@ -9,6 +9,10 @@ import (
 	"time"
 
 	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/internal/repository/postgres"
+	importsvc "starter-gofiber/internal/service/dataimport"
+	postsvc "starter-gofiber/internal/service/post"
 	"starter-gofiber/internal/worker"
 	"starter-gofiber/pkg/logger"
 
@@ -45,6 +49,7 @@ func main() {
 		logger.Fatal("Failed to initialize Redis", zap.Error(err))
 	}
 	defer client.Close()
+	cache.InitRedisClient(client)
 
 	// Initialize Asynq client and server
 	logger.Info("Initializing Asynq worker server")
@@ -52,11 +57,26 @@ func main() {
 	defer asynqClient.Close()
 
 	worker.SetAsynqClient(asynqClient)
+	worker.SetDB(config.DB)
+	worker.SetPostRepository(postgres.NewPostRepository(config.DB))
+	worker.SetFollowRepository(postgres.NewFollowRepository(config.DB))
+	// The worker holds no casbin enforcer, the roles of purged and imported accounts are changed
+	// by the API through the role changes published on Redis
 	worker.SetRedisConfig(
 		config.ENV.REDIS_HOST+":"+config.ENV.REDIS_PORT,
 		config.ENV.REDIS_PASSWORD,
 		config.ENV.REDIS_DB,
 	)
+	config.LoadEmailVerification()
+	config.LoadPosts()
+	config.LoadTimeline()
+	config.LoadSearch()
+	config.LoadImports()
+
+	// Bulk post jobs run through the post service, their progress is relayed to the API over Redis
+	posts := postsvc.NewPostService(postgres.NewPostRepository(config.DB), postgres.NewUserRepository(config.DB), postgres.NewModerationRepository(config.DB))
+	worker.SetPostBulkRunner(posts.RunBulk)
+	worker.SetImportRunner(importsvc.NewImportService(postgres.NewImportRepository(config.DB), postgres.NewPostRepository(config.DB)).Process)
 
 	// Initialize Asynq scheduler
 	config.InitAsynqScheduler()
@@ -94,6 +114,21 @@ func main() {
 	mux.HandleFunc("backup:database", worker.HandleDatabaseBackup)
 	mux.HandleFunc("email:daily_digest", worker.HandleDailyEmailDigest)
 	mux.HandleFunc("metrics:collect", worker.HandleMetricsCollection)
+	mux.HandleFunc(worker.TaskEmailVerificationReminder, worker.HandleEmailVerificationReminder)
+	mux.HandleFunc(worker.TaskPurgeUnverifiedUsers, worker.HandlePurgeUnverifiedUsers)
+	mux.HandleFunc(worker.TaskReconcileReactions, worker.HandleReconcileReactions)
+	mux.HandleFunc(worker.TaskTimelineFanout, worker.HandleTimelineFanout)
+	mux.HandleFunc(worker.TaskSearchIndex, worker.HandleSearchIndex)
+	mux.HandleFunc(worker.TaskSearchReindex, worker.HandleSearchReindex)
+	mux.HandleFunc(worker.TaskPurgeOrphanAttachments, worker.HandlePurgeOrphanAttachments)
+	mux.HandleFunc(worker.TaskPublishPost, worker.HandlePublishPost)
+	mux.HandleFunc(worker.TaskPublishDuePosts, worker.HandlePublishDuePosts)
+	mux.HandleFunc(worker.TaskClosePolls, worker.HandleClosePolls)
+	mux.HandleFunc(worker.TaskUnfurlLink, worker.HandleUnfurlLink)
+	mux.HandleFunc(worker.TaskRollupPostStats, worker.HandleRollupPostStats)
+	mux.HandleFunc(worker.TaskPurgeTrashedPosts, worker.HandlePurgeTrashedPosts)
+	mux.HandleFunc(worker.TaskPostBulk, worker.HandlePostBulk)
+	mux.HandleFunc(worker.TaskProcessImport, worker.HandleProcessImport)
 
 	logger.Info("Worker server initialized",
 		zap.Int("concurrency", concurrency),
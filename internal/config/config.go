@@ -46,6 +46,48 @@ type Config struct {
 
 	// Audit Log Configuration
 	AUDIT_LOG_ENABLE bool // Enable/disable audit logging (default: false)
+
+	// API Key Configuration
+	API_KEY_SIGNATURE_SKEW int // Allowed clock skew for HMAC signed requests in seconds (default: 300)
+
+	// Session Configuration (cookie-session auth mode for browser clients)
+	SESSION_AUTH_ENABLE bool // Enable/disable cookie-session auth (default: false)
+	SESSION_TTL         int  // Session lifetime in minutes (default: 1440)
+
+	// Email Verification Configuration
+	EMAIL_VERIFICATION_GATED         string // Comma separated permissions requiring verified email (default: post:create)
+	EMAIL_VERIFICATION_GRACE_HOURS   int    // Hours new accounts may use gated permissions unverified (default: 24)
+	EMAIL_VERIFICATION_REMINDER_DAYS int    // Days between verification reminder emails (default: 3)
+	UNVERIFIED_PURGE_DAYS            int    // Days before unverified accounts are deleted (default: 30)
+
+	// Security Event Configuration
+	SECURITY_LOCKOUT_THRESHOLD     int    // Failed logins per account before lockout (default: 5)
+	SECURITY_LOCKOUT_MINUTES       int    // Failed login window and lockout duration in minutes (default: 15)
+	SECURITY_IP_FAILURE_ACCOUNTS   int    // Distinct accounts failing from one IP before an alert (default: 5)
+	SECURITY_IP_FAILURE_MINUTES    int    // Window for the IP failure rule in minutes (default: 10)
+	SECURITY_IMPOSSIBLE_TRAVEL_KMH int    // Max plausible speed between logins in km/h (default: 900)
+	SECURITY_ALERT_EMAILS          string // Comma separated alert recipients (default: all admins)
+	GEOIP_DB_PATH                  string // Local GeoIP CSV file (network,country,latitude,longitude)
+
+	// Timeline Configuration
+	TIMELINE_FANOUT_THRESHOLD int // Follower count from which posts are merged at read time (default: 10000)
+
+	// Post Configuration
+	POST_MAX_ATTACHMENTS int // Attachments per post (default: 4)
+	POST_TRASH_DAYS      int // Days deleted posts can be restored before they are purged (default: 30)
+
+	// Moderation Configuration
+	MODERATION_BLOCK_KEYWORDS string // Comma separated words and phrases that reject a post
+	MODERATION_FLAG_KEYWORDS  string // Comma separated words and phrases that hold a post for review
+	MODERATION_FILTER_FILE    string // Regex rules, one "block <regex>" or "flag <regex>" per line
+
+	// Import Configuration
+	IMPORT_DIR    string // Uploaded import files and their error reports, kept private (default: ./assets/imports)
+	IMPORT_MAX_MB int    // Largest import file in megabytes (default: 20)
+
+	// Search Configuration
+	SEARCH_BACKEND    string // postgres, sqlite or bleve (default: the database's own, bleve for others)
+	SEARCH_BLEVE_PATH string // Bleve index directory (default: ./assets/<DB_NAME>_search.bleve)
 }
 
 var ENV *Config
@@ -5,7 +5,19 @@ import (
 	"strings"
 	"time"
 
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/poll"
 	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/internal/domain/security"
 	"starter-gofiber/internal/domain/user"
 	"starter-gofiber/pkg/database"
 
@@ -24,11 +36,36 @@ func GetModelsForMigration() []interface{} {
 	models := []interface{}{
 		&user.User{},
 		&post.Post{},
+		&post.Tag{},
+		&post.PostTag{},
+		&post.PostMention{},
+		&post.Attachment{},
+		&post.Revision{},
+		&moderation.Report{},
+		&comment.Comment{},
+		&reaction.Reaction{},
+		&reaction.Count{},
+		&bookmark.Bookmark{},
+		&bookmark.Collection{},
+		&repost.Repost{},
+		&poll.Poll{},
+		&poll.Option{},
+		&poll.Ballot{},
+		&poll.Vote{},
+		&linkpreview.LinkPreview{},
+		&analytics.View{},
+		&analytics.DailyStat{},
+		&dataimport.Import{},
+		&follow.Follow{},
 		&user.RefreshToken{},
 		&user.PasswordReset{},
 		&user.EmailVerification{},
 		&user.APIKey{},
 		&user.UserPreferences{},
+		&consent.PolicyVersion{},
+		&consent.UserConsent{},
+		&consent.MarketingConsent{},
+		&security.Event{},
 	}
 
 	// Add AuditLog to migration if audit logging is enabled
@@ -134,6 +171,11 @@ func LoadDB() {
 		sqlDB.SetConnMaxIdleTime(5 * time.Minute)
 	}
 
+	// Cleanup of rows referencing soft-deleted ones, e.g. bookmarks of deleted posts
+	if err := database.RegisterSoftDeleteCallbacks(db); err != nil {
+		panic(err)
+	}
+
 	// Register audit log callbacks for automatic tracking
 	// Note: AuditLog is now defined in pkg/database/audit.go
 	// Enable audit logging if configured
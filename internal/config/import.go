@@ -0,0 +1,9 @@
+package config
+
+import "starter-gofiber/internal/domain/dataimport"
+
+// LoadImports applies where import files are kept and how large they may be
+func LoadImports() {
+	dataimport.SetDir(ENV.IMPORT_DIR)
+	dataimport.SetMaxFileSize(ENV.IMPORT_MAX_MB)
+}
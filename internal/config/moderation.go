@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// LoadModeration builds the post filter from the keyword lists and rule file, moderation
+// actions go to the audit log when it is enabled
+func LoadModeration() {
+	var rules []moderation.Rule
+	keywords := func(action, list string) {
+		for _, keyword := range strings.Split(list, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				rules = append(rules, moderation.KeywordRule(action, keyword))
+			}
+		}
+	}
+	keywords(moderation.FilterBlock, ENV.MODERATION_BLOCK_KEYWORDS)
+	keywords(moderation.FilterFlag, ENV.MODERATION_FLAG_KEYWORDS)
+
+	if ENV.MODERATION_FILTER_FILE != "" {
+		fileRules, err := loadFilterFile(ENV.MODERATION_FILTER_FILE)
+		if err != nil {
+			logger.Warn("Failed to load moderation filter file", zap.String("path", ENV.MODERATION_FILTER_FILE), zap.Error(err))
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	moderation.SetFilter(rules)
+	moderation.SetAuditLog(ENV.AUDIT_LOG_ENABLE)
+	if len(rules) > 0 {
+		logger.Info("Moderation filter loaded", zap.Int("rules", len(rules)))
+	}
+}
+
+// loadFilterFile reads "block <regex>" and "flag <regex>" lines, skipping blank lines,
+// comments and invalid rules
+func loadFilterFile(path string) ([]moderation.Rule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules []moderation.Rule
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		action, expr, _ := strings.Cut(text, " ")
+		expr = strings.TrimSpace(expr)
+		if (action != moderation.FilterBlock && action != moderation.FilterFlag) || expr == "" {
+			logger.Warn("Skipping invalid moderation filter rule", zap.String("path", path), zap.Int("line", line))
+			continue
+		}
+		rule, err := moderation.PatternRule(action, expr)
+		if err != nil {
+			logger.Warn("Skipping invalid moderation filter rule", zap.String("path", path), zap.Int("line", line), zap.Error(err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
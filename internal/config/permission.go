@@ -17,14 +17,43 @@ var (
 
 func InitializePermission(enforcer *casbin.Enforcer) error {
 	Enforcer = enforcer
-	post, files := "post", "files"
+	post, postRevision, postStats, postTrash, comment, files, consent, security, search, moderation, imports := "post", "post_revision", "post_stats", "post_trash", "comment", "files", "consent", "security", "search", "moderation", "import"
 	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, post, CREATE_P)
 	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, post, READ_P)
 	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, post, UPDATE_P)
 	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, post, DELETE_P)
 	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, post, LIST_P)
 
+	// Restore revisions of posts written by other users
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, postRevision, UPDATE_P)
+
+	// Read the stats of posts written by other users
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, postStats, READ_P)
+
+	// List, restore and purge deleted posts of other users
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, postTrash, UPDATE_P)
+
+	// Moderate comments written by other users
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, comment, UPDATE_P)
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, comment, DELETE_P)
+
+	// Work the report queue and act on reported content
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, moderation, READ_P)
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, moderation, UPDATE_P)
+
 	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, files, READ_P)
+
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, consent, CREATE_P)
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, consent, READ_P)
+
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, security, READ_P)
+
+	// Rebuild the full-text search index
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, search, UPDATE_P)
+
+	// Import users and posts from files and download the reports of rejected rows
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, imports, CREATE_P)
+	enforcer.AddPermissionForUser(variables.ADMIN_ROLE, imports, READ_P)
 	enforcer.SavePolicy()
 	return enforcer.LoadPolicy()
 }
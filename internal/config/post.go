@@ -0,0 +1,19 @@
+package config
+
+import (
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/storage"
+)
+
+// LoadPosts applies the attachment limit of posts and how long deleted posts stay in the trash
+func LoadPosts() {
+	post.SetMaxAttachments(ENV.POST_MAX_ATTACHMENTS)
+	post.SetTrashRetention(ENV.POST_TRASH_DAYS)
+}
+
+// BodyLimit is the request size limit, large enough for a post with every
+// attachment at the maximum image size and for the largest import file
+func BodyLimit() int {
+	return max(post.MaxAttachments()*int(storage.DefaultImageConfig().MaxSize), int(dataimport.MaxFileSize())) + 1024*1024
+}
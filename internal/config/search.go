@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+
+	"starter-gofiber/internal/infrastructure/fulltext"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// LoadSearch builds the full-text search engine and hooks index updates into database writes.
+// Search stays disabled when the engine can't be created, the rest of the API keeps working.
+func LoadSearch() {
+	blevePath := ENV.SEARCH_BLEVE_PATH
+	if blevePath == "" {
+		blevePath = fmt.Sprintf("./assets/%s_search.bleve", ENV.DB_NAME)
+	}
+
+	engine, err := fulltext.New(ENV.SEARCH_BACKEND, DB, blevePath)
+	if err != nil {
+		logger.Warn("Full-text search disabled", zap.Error(err))
+		return
+	}
+	fulltext.SetEngine(engine)
+
+	if err := worker.RegisterSearchCallbacks(DB); err != nil {
+		logger.Warn("Failed to register search index callbacks", zap.Error(err))
+	}
+	logger.Info("Full-text search enabled", zap.String("engine", engine.Name()))
+}
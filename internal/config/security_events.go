@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/infrastructure/geoip"
+	securityService "starter-gofiber/internal/service/security"
+	"starter-gofiber/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// LoadSecurityEvents applies the lockout/anomaly policy and loads the local GeoIP file
+func LoadSecurityEvents() {
+	policy := security.DefaultPolicy()
+	if ENV.SECURITY_LOCKOUT_THRESHOLD > 0 {
+		policy.LockoutThreshold = ENV.SECURITY_LOCKOUT_THRESHOLD
+	}
+	if ENV.SECURITY_LOCKOUT_MINUTES > 0 {
+		policy.LockoutWindow = time.Duration(ENV.SECURITY_LOCKOUT_MINUTES) * time.Minute
+	}
+	if ENV.SECURITY_IP_FAILURE_ACCOUNTS > 0 {
+		policy.IPFailureAccounts = ENV.SECURITY_IP_FAILURE_ACCOUNTS
+	}
+	if ENV.SECURITY_IP_FAILURE_MINUTES > 0 {
+		policy.IPFailureWindow = time.Duration(ENV.SECURITY_IP_FAILURE_MINUTES) * time.Minute
+	}
+	if ENV.SECURITY_IMPOSSIBLE_TRAVEL_KMH > 0 {
+		policy.ImpossibleTravelKmh = float64(ENV.SECURITY_IMPOSSIBLE_TRAVEL_KMH)
+	}
+	for _, email := range strings.Split(ENV.SECURITY_ALERT_EMAILS, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			policy.AlertEmailRecipients = append(policy.AlertEmailRecipients, email)
+		}
+	}
+	securityService.SetPolicy(policy)
+
+	if ENV.GEOIP_DB_PATH != "" {
+		if err := geoip.Load(ENV.GEOIP_DB_PATH); err != nil {
+			logger.Warn("Failed to load GeoIP file, impossible travel detection disabled", zap.Error(err))
+		} else {
+			logger.Info("GeoIP file loaded", zap.String("path", ENV.GEOIP_DB_PATH))
+		}
+	}
+}
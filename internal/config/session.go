@@ -0,0 +1,42 @@
+package config
+
+import (
+	"time"
+
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// LoadSession enables cookie-session auth mode for browser clients
+// Sessions are stored in Redis when available, otherwise in the SQLite storage
+func LoadSession() {
+	if !ENV.SESSION_AUTH_ENABLE {
+		return
+	}
+
+	ttl := ENV.SESSION_TTL
+	if ttl == 0 {
+		ttl = 24 * 60 // Default 24 hours
+	}
+
+	var storage fiber.Storage = STORAGE
+	if cache.RedisClient != nil {
+		storage = cache.NewRedisStorage(cache.RedisClient, "session:")
+	}
+
+	store := session.New(session.Config{
+		Storage:        storage,
+		Expiration:     time.Duration(ttl) * time.Minute,
+		KeyLookup:      "cookie:" + middleware.SessionCookieName,
+		CookieSecure:   ENV.ENV_TYPE != "dev",
+		CookieHTTPOnly: true,
+		CookieSameSite: "Lax",
+	})
+
+	middleware.InitSessionAuth(store)
+	logger.Info("Cookie-session auth mode enabled")
+}
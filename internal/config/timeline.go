@@ -0,0 +1,8 @@
+package config
+
+import "starter-gofiber/internal/domain/follow"
+
+// LoadTimeline applies the home timeline fan-out threshold
+func LoadTimeline() {
+	follow.SetFanoutThreshold(int64(ENV.TIMELINE_FANOUT_THRESHOLD))
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/worker"
+)
+
+// LoadEmailVerification applies the email verification gate and cleanup policy
+func LoadEmailVerification() {
+	var permissions []string
+	for _, permission := range strings.Split(ENV.EMAIL_VERIFICATION_GATED, ",") {
+		if permission = strings.TrimSpace(permission); permission != "" {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	grace := time.Duration(-1)
+	if ENV.EMAIL_VERIFICATION_GRACE_HOURS > 0 {
+		grace = time.Duration(ENV.EMAIL_VERIFICATION_GRACE_HOURS) * time.Hour
+	}
+
+	middleware.InitEmailVerificationGate(permissions, grace)
+	worker.SetVerificationPolicy(ENV.EMAIL_VERIFICATION_REMINDER_DAYS, ENV.UNVERIFIED_PURGE_DAYS)
+}
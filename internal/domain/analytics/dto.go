@@ -0,0 +1,68 @@
+package analytics
+
+// MaxRangeDays limits how many days one stats request covers
+const MaxRangeDays = 366
+
+// DefaultRangeDays is the range ending today shown when no from date is given
+const DefaultRangeDays = 30
+
+type DailyStatResponse struct {
+	Day           string `json:"day"`
+	Views         int    `json:"views"`
+	UniqueViewers int    `json:"unique_viewers"`
+	Reactions     int    `json:"reactions"`
+	Comments      int    `json:"comments"`
+}
+
+func (r DailyStatResponse) FromEntity(s DailyStat) DailyStatResponse {
+	r.Day = s.Day.UTC().Format(DayFormat)
+	r.Views = s.Views
+	r.UniqueViewers = s.UniqueViewers
+	r.Reactions = s.Reactions
+	r.Comments = s.Comments
+	return r
+}
+
+// TotalsResponse adds up the days of the range. Unique viewers are only known per day and are not totalled.
+type TotalsResponse struct {
+	Views     int `json:"views"`
+	Reactions int `json:"reactions"`
+	Comments  int `json:"comments"`
+}
+
+// PostStatsResponse has one entry per day of the range, days without activity included
+type PostStatsResponse struct {
+	PostID uint                `json:"post_id"`
+	From   string              `json:"from"`
+	To     string              `json:"to"`
+	Totals TotalsResponse      `json:"totals"`
+	Days   []DailyStatResponse `json:"days"`
+}
+
+// StatExportRow is a day of the stats for exports
+// Field names are used as export headers
+type StatExportRow struct {
+	Day           string
+	Views         int
+	UniqueViewers int
+	Reactions     int
+	Comments      int
+}
+
+// ExportHeaders lists StatExportRow fields in export column order
+var ExportHeaders = []string{"Day", "Views", "UniqueViewers", "Reactions", "Comments"}
+
+// ExportRows flattens the days of the stats for exports
+func (r PostStatsResponse) ExportRows() []StatExportRow {
+	rows := make([]StatExportRow, len(r.Days))
+	for i, d := range r.Days {
+		rows[i] = StatExportRow{
+			Day:           d.Day,
+			Views:         d.Views,
+			UniqueViewers: d.UniqueViewers,
+			Reactions:     d.Reactions,
+			Comments:      d.Comments,
+		}
+	}
+	return rows
+}
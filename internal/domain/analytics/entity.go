@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// ViewWindow is how long repeated opens of a post by the same viewer count as one view.
+// Windows restart at midnight UTC, with the daily counters.
+const ViewWindow = 30 * time.Minute
+
+// DayFormat keys the daily counters and formats the days of the stats
+const DayFormat = "2006-01-02"
+
+// KeyTTL keeps the Redis counters of a day around until the roll-up persisted them
+const KeyTTL = 72 * time.Hour
+
+// ViewsKey counts the views of a post on a day
+func ViewsKey(postID uint, day time.Time) string {
+	return fmt.Sprintf("post:views:%d:%s", postID, day.Format(DayFormat))
+}
+
+// ViewersKey is the HyperLogLog of the viewers of a post on a day
+func ViewersKey(postID uint, day time.Time) string {
+	return fmt.Sprintf("post:viewers:%d:%s", postID, day.Format(DayFormat))
+}
+
+// SeenKey marks a viewer who opened the post during the current window
+func SeenKey(postID uint, day time.Time, viewer string) string {
+	return fmt.Sprintf("post:view:seen:%d:%s:%s", postID, day.Format(DayFormat), viewer)
+}
+
+// ViewedKey is the set of posts viewed on a day, which the roll-up reads from Redis
+func ViewedKey(day time.Time) string {
+	return fmt.Sprintf("post:views:viewed:%s", day.Format(DayFormat))
+}
+
+// Day truncates the time to its day in UTC
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Viewer identifies who opened a post, users by ID and anonymous viewers by a hash of their
+// address and user agent so no address is stored
+type Viewer struct {
+	UserID    uint
+	IP        string
+	UserAgent string
+}
+
+func (v Viewer) Key() string {
+	if v.UserID != 0 {
+		return fmt.Sprintf("u:%d", v.UserID)
+	}
+	sum := sha256.Sum256([]byte(v.IP + "|" + v.UserAgent))
+	return "a:" + hex.EncodeToString(sum[:16])
+}
+
+// View counts a viewer's opens of a post on a day. Views are only recorded here without Redis,
+// the roll-up adds them up into DailyStat.
+type View struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	PostID       uint      `gorm:"not null;uniqueIndex:idx_post_view_viewer"`
+	Day          time.Time `gorm:"type:date;not null;uniqueIndex:idx_post_view_viewer;index"`
+	ViewerKey    string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_post_view_viewer"`
+	Views        int       `gorm:"not null;default:1"`
+	LastViewedAt time.Time `gorm:"not null"`
+}
+
+func (View) TableName() string {
+	return "post_views"
+}
+
+// DailyStat is the roll-up of a post's activity on a day. Reactions and comments count the ones
+// given that day which still stand.
+type DailyStat struct {
+	PostID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	Day           time.Time `gorm:"primaryKey;type:date"`
+	Views         int       `gorm:"not null;default:0"`
+	UniqueViewers int       `gorm:"not null;default:0"`
+	Reactions     int       `gorm:"not null;default:0"`
+	Comments      int       `gorm:"not null;default:0"`
+	UpdatedAt     time.Time
+}
+
+func (DailyStat) TableName() string {
+	return "post_daily_stats"
+}
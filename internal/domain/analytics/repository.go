@@ -0,0 +1,20 @@
+package analytics
+
+import "time"
+
+// Repository defines the interface for post analytics repository operations.
+// Days are midnight UTC, see Day.
+type Repository interface {
+	// RecordView counts the viewer's open of the post, false when they opened it within the window already
+	RecordView(postID uint, viewer string, at time.Time, window time.Duration) (bool, error)
+	// CountViews adds up the recorded views and viewers of each post on the day
+	CountViews(day time.Time) ([]DailyStat, error)
+	// CountActivity counts the reactions and comments given to each post on the day which still stand
+	CountActivity(day time.Time) ([]DailyStat, error)
+	// SaveDaily stores the roll-ups, replacing the ones of the same post and day
+	SaveDaily(stats []DailyStat) error
+	// FindDaily returns the post's roll-ups from one day to another, both included, oldest first
+	FindDaily(postID uint, from, to time.Time) ([]DailyStat, error)
+	// PurgeViews deletes recorded views of days before the given one
+	PurgeViews(before time.Time) (int64, error)
+}
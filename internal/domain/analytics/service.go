@@ -0,0 +1,12 @@
+package analytics
+
+import "time"
+
+// Service defines the interface for post analytics service operations
+type Service interface {
+	// RecordView counts a view of the post, authors opening their own posts are not counted
+	RecordView(postID, authorID uint, viewer Viewer)
+	// Stats returns the post's daily stats between the dates, to its author or to users who may read
+	// any post's stats. Nil dates default to the last DefaultRangeDays days.
+	Stats(postID, userID uint, readAny bool, from, to *time.Time) (*PostStatsResponse, error)
+}
@@ -0,0 +1,48 @@
+package bookmark
+
+import "starter-gofiber/variables"
+
+type BookmarkRequest struct {
+	// CollectionID files the bookmark in one of the user's collections, omit it to keep it unfiled
+	CollectionID *uint `json:"collection_id"`
+}
+
+type CollectionRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// ReorderRequest lists every collection of the user in the new order
+type ReorderRequest struct {
+	IDs []uint `json:"ids" validate:"required,min=1"`
+}
+
+type BookmarkResponse struct {
+	PostID       uint   `json:"post_id"`
+	CollectionID *uint  `json:"collection_id"`
+	BookmarkedAt string `json:"bookmarked_at"`
+}
+
+type CollectionResponse struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Position      int    `json:"position"`
+	BookmarkCount int    `json:"bookmark_count"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+func (r BookmarkResponse) FromEntity(b Bookmark) BookmarkResponse {
+	r.PostID = b.PostID
+	r.CollectionID = b.CollectionID
+	r.BookmarkedAt = b.CreatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
+
+func (r CollectionResponse) FromEntity(c Collection) CollectionResponse {
+	r.ID = c.ID
+	r.Name = c.Name
+	r.Position = c.Position
+	r.CreatedAt = c.CreatedAt.Format(variables.FORMAT_TIME)
+	r.UpdatedAt = c.UpdatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
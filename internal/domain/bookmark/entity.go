@@ -0,0 +1,27 @@
+package bookmark
+
+import "time"
+
+// Bookmark is a post a user saved privately, optionally filed in one of their collections
+type Bookmark struct {
+	ID           uint  `gorm:"primaryKey;autoIncrement"`
+	UserID       uint  `gorm:"not null;uniqueIndex:idx_bookmark_user_post"`
+	PostID       uint  `gorm:"not null;uniqueIndex:idx_bookmark_user_post;index"`
+	CollectionID *uint `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Collection is a named group of a user's bookmarks, listed in Position order
+type Collection struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_bookmark_collection_user_name"`
+	Name      string `gorm:"type:varchar(50);not null;uniqueIndex:idx_bookmark_collection_user_name"`
+	Position  int    `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Collection) TableName() string {
+	return "bookmark_collections"
+}
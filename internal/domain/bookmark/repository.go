@@ -0,0 +1,28 @@
+package bookmark
+
+import "starter-gofiber/pkg/pagination"
+
+// Repository defines the interface for bookmark repository operations
+type Repository interface {
+	// Save bookmarks the post, or files the existing bookmark in b.CollectionID
+	Save(b *Bookmark) error
+	// Remove deletes the user's bookmark of the post, false when there was none
+	Remove(postID, userID uint) (bool, error)
+	// FindBookmarkedPostIDs returns which of the posts the user bookmarked
+	FindBookmarkedPostIDs(postIDs []uint, userID uint) ([]uint, error)
+	// FindByUser returns the user's bookmarks of published posts they may see, newest first with one
+	// look-ahead row past the page. A nil collectionID lists every bookmark.
+	FindByUser(userID uint, collectionID *uint, page pagination.CursorPagination) ([]Bookmark, error)
+
+	CreateCollection(c *Collection) error
+	FindCollection(id uint) (*Collection, error)
+	// FindCollections returns the user's collections in Position order
+	FindCollections(userID uint) ([]Collection, error)
+	// CountByCollections counts the bookmarks in each of the user's collections
+	CountByCollections(userID uint) (map[uint]int, error)
+	UpdateCollection(c *Collection) error
+	// DeleteCollection deletes the collection, its bookmarks are kept unfiled
+	DeleteCollection(id uint) error
+	// SetPositions stores the order of the user's collections, ids in the new order
+	SetPositions(userID uint, ids []uint) error
+}
@@ -0,0 +1,22 @@
+package bookmark
+
+import "starter-gofiber/pkg/pagination"
+
+// Service defines the interface for bookmark service operations
+type Service interface {
+	// Bookmark saves a post for the user, repeating it only moves the bookmark to req.CollectionID
+	Bookmark(postID uint, req *BookmarkRequest, userID uint) (*BookmarkResponse, error)
+	// Unbookmark removes the user's bookmark, removing one that does not exist is a no-op
+	Unbookmark(postID uint, userID uint) error
+	// Bookmarked returns which of the posts the viewer bookmarked, none for anonymous viewers (0)
+	Bookmarked(postIDs []uint, viewerID uint) (map[uint]bool, error)
+	// List returns the user's bookmarked posts, newest bookmark first
+	List(userID uint, collectionID *uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+
+	Collections(userID uint) ([]CollectionResponse, error)
+	CreateCollection(req *CollectionRequest, userID uint) (*CollectionResponse, error)
+	RenameCollection(id uint, req *CollectionRequest, userID uint) (*CollectionResponse, error)
+	DeleteCollection(id uint, userID uint) error
+	// ReorderCollections stores a new order of the user's collections
+	ReorderCollections(req *ReorderRequest, userID uint) ([]CollectionResponse, error)
+}
@@ -0,0 +1,59 @@
+package comment
+
+import (
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+)
+
+// DeletedBody replaces the body of a deleted comment that still has replies
+const DeletedBody = "[deleted]"
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" validate:"required,max=1000"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=1000"`
+}
+
+type CommentResponse struct {
+	ID         uint               `json:"id"`
+	PostID     uint               `json:"post_id"`
+	ParentID   *uint              `json:"parent_id"`
+	Depth      int                `json:"depth"`
+	Body       string             `json:"body"`
+	ReplyCount int                `json:"reply_count"`
+	Edited     bool               `json:"edited"`
+	Deleted    bool               `json:"deleted"`
+	UserID     uint               `json:"user_id,omitempty"`
+	User       *user.UserResponse `json:"user,omitempty"`
+	CreatedAt  string             `json:"created_at"`
+	UpdatedAt  string             `json:"updated_at"`
+}
+
+func (r CommentResponse) FromEntity(c Comment) CommentResponse {
+	r.ID = c.ID
+	r.PostID = c.PostID
+	r.ParentID = c.ParentID
+	r.Depth = c.Depth
+	r.ReplyCount = c.ReplyCount
+	r.CreatedAt = c.CreatedAt.Format(variables.FORMAT_TIME)
+	r.UpdatedAt = c.UpdatedAt.Format(variables.FORMAT_TIME)
+
+	// Deleted comments stay in the thread as placeholders for their replies
+	if c.DeletedAt.Valid {
+		r.Deleted = true
+		r.Body = DeletedBody
+		return r
+	}
+
+	r.Body = c.Body
+	r.Edited = c.EditedAt != nil
+	r.UserID = c.UserID
+	if c.User != nil {
+		usr := user.UserResponse{}.FromEntity(*c.User)
+		r.User = &usr
+	}
+	return r
+}
@@ -0,0 +1,25 @@
+package comment
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+// MaxDepth is the deepest reply level, top-level comments have depth 0
+const MaxDepth = 4
+
+type Comment struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement"`
+	PostID     uint       `gorm:"not null;index"`
+	ParentID   *uint      `gorm:"index"`
+	Depth      int        `gorm:"not null;default:0"`
+	Body       string     `gorm:"type:varchar(1000);not null"`
+	ReplyCount int        `gorm:"not null;default:0"`
+	EditedAt   *time.Time `gorm:"type:timestamp"`
+	UserID     uint       `gorm:"not null;index"`
+	User       *user.User `gorm:"foreignKey:UserID"`
+	gorm.Model
+}
@@ -0,0 +1,13 @@
+package comment
+
+import "starter-gofiber/pkg/pagination"
+
+// Repository defines the interface for comment repository operations
+type Repository interface {
+	Create(comment *Comment) error
+	FindByID(id uint) (*Comment, error)
+	FindThreadParent(id uint) (*Comment, error)
+	FindThread(postID uint, parentID *uint, page pagination.CursorPagination) ([]Comment, error)
+	Update(comment *Comment) error
+	Delete(comment *Comment) error
+}
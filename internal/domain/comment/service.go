@@ -0,0 +1,12 @@
+package comment
+
+import "starter-gofiber/pkg/pagination"
+
+// Service defines the interface for comment service operations
+type Service interface {
+	Create(postID uint, req *CreateCommentRequest, userID uint) (*CommentResponse, error)
+	ListByPost(postID, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+	ListReplies(commentID, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+	Update(id uint, req *UpdateCommentRequest, userID uint, moderator bool) (*CommentResponse, error)
+	Delete(id uint, userID uint, moderator bool) error
+}
@@ -0,0 +1,86 @@
+package consent
+
+import (
+	"starter-gofiber/variables"
+)
+
+type PublishPolicyRequest struct {
+	Type        string `json:"type" validate:"required,oneof=tos privacy"`
+	Version     string `json:"version" validate:"required,max=50"`
+	URL         string `json:"url" validate:"omitempty,url,max=500"`
+	PublishedAt string `json:"published_at" validate:"omitempty"` // RFC3339, defaults to now
+}
+
+// AcceptConsentRequest versions must match the current ones, unpublished types may be empty
+type AcceptConsentRequest struct {
+	TosVersion     string `json:"tos_version" validate:"max=50"`
+	PrivacyVersion string `json:"privacy_version" validate:"max=50"`
+}
+
+type MarketingConsentRequest struct {
+	OptIn *bool `json:"opt_in" validate:"required"`
+}
+
+type PolicyVersionResponse struct {
+	ID          uint   `json:"id"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+	URL         string `json:"url,omitempty"`
+	PublishedAt string `json:"published_at"`
+}
+
+func (r PolicyVersionResponse) FromEntity(p PolicyVersion) PolicyVersionResponse {
+	r.ID = p.ID
+	r.Type = p.Type.String()
+	r.Version = p.Version
+	r.URL = p.URL
+	r.PublishedAt = p.PublishedAt.Format(variables.FORMAT_TIME)
+	return r
+}
+
+type UserConsentResponse struct {
+	Type       string `json:"type"`
+	Version    string `json:"version"`
+	AcceptedAt string `json:"accepted_at"`
+}
+
+func (r UserConsentResponse) FromEntity(c UserConsent) UserConsentResponse {
+	r.Type = c.PolicyType.String()
+	r.Version = c.Version
+	r.AcceptedAt = c.AcceptedAt.Format(variables.FORMAT_TIME)
+	return r
+}
+
+type MarketingConsentResponse struct {
+	OptIn     bool   `json:"opt_in"`
+	CreatedAt string `json:"created_at"`
+}
+
+func (r MarketingConsentResponse) FromEntity(c MarketingConsent) MarketingConsentResponse {
+	r.OptIn = c.OptIn
+	r.CreatedAt = c.CreatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
+
+type ConsentStatusResponse struct {
+	ConsentRequired  bool                       `json:"consent_required"`
+	Current          []PolicyVersionResponse    `json:"current"`
+	Accepted         []UserConsentResponse      `json:"accepted"`
+	MarketingOptIn   bool                       `json:"marketing_opt_in"`
+	MarketingHistory []MarketingConsentResponse `json:"marketing_history"`
+}
+
+// ConsentExportRow is a flattened consent record for admin exports
+// Field names are used as export headers
+type ConsentExportRow struct {
+	UserID    uint
+	Email     string
+	Type      string
+	Value     string
+	At        string
+	IPAddress string
+	UserAgent string
+}
+
+// ExportHeaders lists ConsentExportRow fields in export column order
+var ExportHeaders = []string{"UserID", "Email", "Type", "Value", "At", "IPAddress", "UserAgent"}
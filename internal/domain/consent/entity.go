@@ -0,0 +1,55 @@
+package consent
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+)
+
+type PolicyType string
+
+func (t PolicyType) String() string {
+	return string(t)
+}
+
+const (
+	PolicyTerms   PolicyType = "tos"
+	PolicyPrivacy PolicyType = "privacy"
+)
+
+// RequiredPolicies must be accepted by every user
+var RequiredPolicies = []PolicyType{PolicyTerms, PolicyPrivacy}
+
+// PolicyVersion is a published version of the terms of service or privacy policy
+// The latest published version of each type is the current one
+type PolicyVersion struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	Type        PolicyType `gorm:"type:varchar(20);not null;uniqueIndex:idx_policy_type_version"`
+	Version     string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_policy_type_version"`
+	URL         string     `gorm:"type:varchar(500)"`
+	PublishedAt time.Time  `gorm:"not null;index"`
+	CreatedAt   time.Time
+}
+
+// UserConsent records that a user accepted a specific policy version
+type UserConsent struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement"`
+	UserID     uint       `gorm:"not null;index"`
+	PolicyType PolicyType `gorm:"type:varchar(20);not null;index"`
+	Version    string     `gorm:"type:varchar(50);not null"`
+	AcceptedAt time.Time  `gorm:"not null"`
+	IPAddress  string     `gorm:"type:varchar(45)"`
+	UserAgent  string     `gorm:"type:varchar(255)"`
+	User       *user.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// MarketingConsent is an opt-in/opt-out history entry, the latest row is current
+type MarketingConsent struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `gorm:"not null;index"`
+	OptIn     bool       `gorm:"not null"`
+	IPAddress string     `gorm:"type:varchar(45)"`
+	UserAgent string     `gorm:"type:varchar(255)"`
+	CreatedAt time.Time  `gorm:"index"`
+	User      *user.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
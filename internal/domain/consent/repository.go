@@ -0,0 +1,17 @@
+package consent
+
+import "time"
+
+// Repository defines the interface for consent repository operations
+type Repository interface {
+	CreatePolicyVersion(policy *PolicyVersion) error
+	FindPolicyVersion(policyType PolicyType, version string) (*PolicyVersion, error)
+	FindCurrentPolicies(now time.Time) ([]PolicyVersion, error)
+	CreateUserConsents(consents []UserConsent) error
+	FindLatestUserConsents(userID uint) ([]UserConsent, error)
+	FindUserConsents(userID uint) ([]UserConsent, error)
+	CreateMarketingConsent(consent *MarketingConsent) error
+	FindMarketingConsents(userID uint) ([]MarketingConsent, error)
+	FindAllUserConsents(from, to *time.Time) ([]UserConsent, error)
+	FindAllMarketingConsents(from, to *time.Time) ([]MarketingConsent, error)
+}
@@ -0,0 +1,19 @@
+package consent
+
+import "time"
+
+// ErrCodeConsentRequired is the error order returned when the user must accept
+// the current terms of service or privacy policy before using the API
+const ErrCodeConsentRequired = "CONSENT_REQUIRED"
+
+// Service defines the interface for consent service operations
+type Service interface {
+	PublishVersion(req *PublishPolicyRequest) (*PolicyVersionResponse, error)
+	CurrentVersions() ([]PolicyVersionResponse, error)
+	ValidateAcceptance(tosVersion, privacyVersion string) error
+	Accept(userID uint, req *AcceptConsentRequest, ipAddress, userAgent string) error
+	RequiresConsent(userID uint) (bool, error)
+	SetMarketingConsent(userID uint, optIn bool, ipAddress, userAgent string) error
+	GetStatus(userID uint) (*ConsentStatusResponse, error)
+	Export(from, to *time.Time) ([]ConsentExportRow, error)
+}
@@ -0,0 +1,205 @@
+package dataimport
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"starter-gofiber/variables"
+)
+
+// Fields lists the fields of each kind that file columns can map to
+var Fields = map[string][]string{
+	KindUsers: {"name", "email", "username", "password", "email_verified", "bio"},
+	KindPosts: {"author_email", "user_id", "tweet", "title", "body", "visibility", "status", "created_at"},
+}
+
+// requiredFields lists, per kind, groups of fields of which a file needs a column for at least one
+var requiredFields = map[string][][]string{
+	KindUsers: {{"name"}, {"email"}},
+	KindPosts: {{"author_email", "user_id"}, {"tweet", "body"}},
+}
+
+// ImportRequest starts an import of the uploaded file
+type ImportRequest struct {
+	Kind    string            `json:"kind" validate:"required,oneof=posts users"`
+	DryRun  bool              `json:"dry_run"`
+	Mapping map[string]string `json:"mapping"`
+}
+
+// FormatOf returns the format of a file from its extension, empty when it is not supported
+func FormatOf(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	}
+	return ""
+}
+
+// CheckMapping returns an error for a mapping to a field the kind does not have
+func CheckMapping(kind string, mapping map[string]string) error {
+	for column, field := range mapping {
+		if !slices.Contains(Fields[kind], field) {
+			return fmt.Errorf("column %q maps to unknown field %q, %s have %s", column, field, kind, strings.Join(Fields[kind], ", "))
+		}
+	}
+	return nil
+}
+
+// FieldOf returns the field a column maps to, the mapped one or else the field named like the
+// column regardless of case, spaces, dashes and underscores. Columns of no field are ignored.
+func FieldOf(kind, column string, mapping map[string]string) string {
+	if field, ok := mapping[column]; ok {
+		return field
+	}
+	name := fieldKey(column)
+	for _, field := range Fields[kind] {
+		if fieldKey(field) == name {
+			return field
+		}
+	}
+	return ""
+}
+
+// fieldKey reduces a column or field name to its lowercase letters and digits
+func fieldKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// MissingFields returns the required fields none of the header's columns maps to
+func MissingFields(kind string, header []string, mapping map[string]string) []string {
+	mapped := make(map[string]bool, len(header))
+	for _, column := range header {
+		mapped[FieldOf(kind, column, mapping)] = true
+	}
+
+	var missing []string
+	for _, group := range requiredFields[kind] {
+		if !slices.ContainsFunc(group, func(field string) bool { return mapped[field] }) {
+			missing = append(missing, strings.Join(group, " or "))
+		}
+	}
+	return missing
+}
+
+// UserRow is a user read from a file, validated before it is inserted
+type UserRow struct {
+	Name     string `json:"name" validate:"required,min=3,max=200"`
+	Email    string `json:"email" validate:"required,email,max=200"`
+	Username string `json:"username" validate:"omitempty,min=3,max=30"`
+	// Password is hashed on insert, users without one set it through the password reset
+	Password      string `json:"password" validate:"omitempty,min=6,max=72"`
+	EmailVerified string `json:"email_verified" validate:"omitempty,oneof=true false TRUE FALSE 1 0 yes no"`
+	Bio           string `json:"bio" validate:"max=1000"`
+}
+
+func NewUserRow(values map[string]string) UserRow {
+	return UserRow{
+		Name:          strings.TrimSpace(values["name"]),
+		Email:         strings.ToLower(strings.TrimSpace(values["email"])),
+		Username:      strings.TrimSpace(values["username"]),
+		Password:      values["password"],
+		EmailVerified: strings.TrimSpace(values["email_verified"]),
+		Bio:           strings.TrimSpace(values["bio"]),
+	}
+}
+
+// Verified reports whether the row marks the email as verified
+func (r UserRow) Verified() bool {
+	return slices.Contains([]string{"true", "1", "yes"}, strings.ToLower(r.EmailVerified))
+}
+
+// PostRow is a post read from a file, its author is an existing user given by email or ID
+type PostRow struct {
+	AuthorEmail string `json:"author_email" validate:"required_without=UserID,omitempty,email"`
+	UserID      string `json:"user_id" validate:"required_without=AuthorEmail,omitempty,number"`
+	Tweet       string `json:"tweet" validate:"required_without=Body,max=500"`
+	Title       string `json:"title" validate:"required_with=Body,max=200"`
+	Body        string `json:"body" validate:"required_with=Title,max=50000"`
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=public followers mentioned private"`
+	Status      string `json:"status" validate:"omitempty,oneof=draft published"`
+	// CreatedAt keeps the original date, RFC 3339 or YYYY-MM-DD
+	CreatedAt string `json:"created_at"`
+}
+
+func NewPostRow(values map[string]string) PostRow {
+	return PostRow{
+		AuthorEmail: strings.ToLower(strings.TrimSpace(values["author_email"])),
+		UserID:      strings.TrimSpace(values["user_id"]),
+		Tweet:       strings.TrimSpace(values["tweet"]),
+		Title:       strings.TrimSpace(values["title"]),
+		Body:        values["body"],
+		Visibility:  strings.TrimSpace(values["visibility"]),
+		Status:      strings.TrimSpace(values["status"]),
+		CreatedAt:   strings.TrimSpace(values["created_at"]),
+	}
+}
+
+// CreatedTime parses CreatedAt, zero when it is empty
+func (r PostRow) CreatedTime() (time.Time, error) {
+	if r.CreatedAt == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, r.CreatedAt); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, r.CreatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("created_at must be RFC 3339 or YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+type ImportResponse struct {
+	ID           uint              `json:"id"`
+	UserID       uint              `json:"user_id"`
+	Kind         string            `json:"kind"`
+	Format       string            `json:"format"`
+	FileName     string            `json:"file_name"`
+	Mapping      map[string]string `json:"mapping"`
+	DryRun       bool              `json:"dry_run"`
+	Status       string            `json:"status"`
+	TotalRows    int               `json:"total_rows"`
+	ImportedRows int               `json:"imported_rows"`
+	RejectedRows int               `json:"rejected_rows"`
+	ReportURL    *string           `json:"report_url"`
+	Error        string            `json:"error,omitempty"`
+	CreatedAt    string            `json:"created_at"`
+	StartedAt    *string           `json:"started_at"`
+	FinishedAt   *string           `json:"finished_at"`
+}
+
+func (r ImportResponse) FromEntity(i Import) ImportResponse {
+	r.ID = i.ID
+	r.UserID = i.UserID
+	r.Kind = i.Kind
+	r.Format = i.Format
+	r.FileName = i.FileName
+	r.Mapping = i.Mapping
+	r.DryRun = i.DryRun
+	r.Status = i.Status
+	r.TotalRows = i.TotalRows
+	r.ImportedRows = i.ImportedRows
+	r.RejectedRows = i.RejectedRows
+	r.Error = i.Error
+	r.CreatedAt = i.CreatedAt.Format(variables.FORMAT_TIME)
+	if i.ReportPath != nil {
+		url := fmt.Sprintf("/api/imports/%d/report", i.ID)
+		r.ReportURL = &url
+	}
+	if i.StartedAt != nil {
+		started := i.StartedAt.Format(variables.FORMAT_TIME)
+		r.StartedAt = &started
+	}
+	if i.FinishedAt != nil {
+		finished := i.FinishedAt.Format(variables.FORMAT_TIME)
+		r.FinishedAt = &finished
+	}
+	return r
+}
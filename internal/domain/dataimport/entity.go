@@ -0,0 +1,98 @@
+package dataimport
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Kinds of records a file can import
+const (
+	KindPosts = "posts"
+	KindUsers = "users"
+)
+
+// File formats, told apart by the extension of the upload
+const (
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson"
+)
+
+// Import states, an import goes from pending through processing to completed or failed
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+)
+
+// ChunkSize is the number of valid rows inserted at a time
+const ChunkSize = 500
+
+var (
+	// dir holds the uploads waiting to be processed and the error reports, outside the public storage
+	dir = "./assets/imports"
+	// maxFileSize is the largest upload accepted
+	maxFileSize int64 = 20 * 1024 * 1024
+)
+
+// SetDir sets where uploads and error reports are kept (empty keeps the default)
+func SetDir(path string) {
+	if path != "" {
+		dir = path
+	}
+}
+
+// UploadDir returns the directory of uploads waiting to be processed
+func UploadDir() string {
+	return filepath.Join(dir, "uploads")
+}
+
+// ReportDir returns the directory of error reports
+func ReportDir() string {
+	return filepath.Join(dir, "reports")
+}
+
+// SetMaxFileSize sets the largest upload in megabytes (0 keeps the default)
+func SetMaxFileSize(mb int) {
+	if mb > 0 {
+		maxFileSize = int64(mb) * 1024 * 1024
+	}
+}
+
+// MaxFileSize returns the largest upload in bytes
+func MaxFileSize() int64 {
+	return maxFileSize
+}
+
+// Import is an uploaded file of users or posts and the outcome of processing it
+type Import struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement"`
+	UserID uint   `gorm:"not null;index"`
+	Kind   string `gorm:"type:varchar(20);not null"`
+	Format string `gorm:"type:varchar(10);not null"`
+	// FileName is the name of the upload, FilePath where it is kept until processed
+	FileName string `gorm:"type:varchar(255);not null"`
+	FilePath string `gorm:"type:varchar(500)"`
+	// Mapping maps file columns to fields, columns named like a field need no entry
+	Mapping map[string]string `gorm:"serializer:json;type:text"`
+	// DryRun validates every row without inserting any
+	DryRun bool   `gorm:"not null;default:false"`
+	Status string `gorm:"type:varchar(20);not null;default:pending;index"`
+	// ImportedRows counts the rows inserted, or that would be in a dry run
+	TotalRows    int `gorm:"not null;default:0"`
+	ImportedRows int `gorm:"not null;default:0"`
+	RejectedRows int `gorm:"not null;default:0"`
+	// ReportPath is the CSV of rejected rows, set when any row was rejected
+	ReportPath *string `gorm:"type:varchar(500)"`
+	// Error is why a failed import stopped
+	Error      string `gorm:"type:text"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (Import) TableName() string {
+	return "imports"
+}
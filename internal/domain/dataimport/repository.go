@@ -0,0 +1,20 @@
+package dataimport
+
+import (
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/database"
+)
+
+type Repository interface {
+	Create(i *Import) error
+	FindByID(id uint) (*Import, error)
+	// FindAll lists imports, newest first
+	FindAll(limit, offset int) ([]Import, int64, error)
+	Save(i *Import) error
+	// FindUsers returns the users having one of the lower case emails or usernames, to tell rows that already exist
+	FindUsers(emails, usernames []string) ([]user.User, error)
+	// FindAuthors returns the users having one of the lower case emails or IDs
+	FindAuthors(emails []string, ids []uint) ([]user.User, error)
+	// Insert creates the records one by one, failing records are reported by their index
+	Insert(records []interface{}) *database.BulkCreateResult
+}
@@ -0,0 +1,26 @@
+package dataimport
+
+import (
+	"mime/multipart"
+)
+
+type Service interface {
+	// Start keeps the uploaded file and queues it for processing, without a queue it is processed right away
+	Start(req *ImportRequest, file *multipart.FileHeader, userID uint) (*ImportResponse, error)
+	// Process reads, validates and inserts the rows of a pending import and writes its error report
+	Process(id uint) error
+	FindByID(id uint) (*ImportResponse, error)
+	FindAll(page, limit int) ([]ImportResponse, *PaginationMeta, error)
+	// Report returns the path of the error report of an import and the name to download it as
+	Report(id uint) (path, name string, err error)
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	Total       int64 `json:"total"`
+	Page        int   `json:"page"`
+	Limit       int   `json:"limit"`
+	TotalPages  int   `json:"total_pages"`
+	HasNextPage bool  `json:"has_next_page"`
+	HasPrevPage bool  `json:"has_prev_page"`
+}
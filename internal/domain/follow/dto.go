@@ -0,0 +1,41 @@
+package follow
+
+import (
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+)
+
+type FollowResponse struct {
+	UserID     uint               `json:"user_id"`
+	User       *user.UserResponse `json:"user,omitempty"`
+	FollowedAt string             `json:"followed_at"`
+}
+
+// FromFollower describes the follower side of the edge
+func (r FollowResponse) FromFollower(f Follow) FollowResponse {
+	r.UserID = f.FollowerID
+	r.FollowedAt = f.CreatedAt.Format(variables.FORMAT_TIME)
+	if f.Follower != nil {
+		usr := user.UserResponse{}.FromEntity(*f.Follower)
+		r.User = &usr
+	}
+	return r
+}
+
+// FromFollowee describes the followed side of the edge
+func (r FollowResponse) FromFollowee(f Follow) FollowResponse {
+	r.UserID = f.FolloweeID
+	r.FollowedAt = f.CreatedAt.Format(variables.FORMAT_TIME)
+	if f.Followee != nil {
+		usr := user.UserResponse{}.FromEntity(*f.Followee)
+		r.User = &usr
+	}
+	return r
+}
+
+type FollowStats struct {
+	Followers int64 `json:"followers"`
+	Following int64 `json:"following"`
+	// IsFollowing tells whether the viewer follows this user
+	IsFollowing bool `json:"is_following"`
+}
@@ -0,0 +1,42 @@
+package follow
+
+import (
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+)
+
+// Follow is a directed edge of the social graph, FollowerID follows FolloweeID
+type Follow struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement"`
+	FollowerID uint       `gorm:"not null;uniqueIndex:idx_follow_pair"`
+	FolloweeID uint       `gorm:"not null;uniqueIndex:idx_follow_pair;index"`
+	Follower   *user.User `gorm:"foreignKey:FollowerID"`
+	Followee   *user.User `gorm:"foreignKey:FolloweeID"`
+	CreatedAt  time.Time
+}
+
+// TimelineMaxLen is the number of post IDs kept in each Redis home timeline
+const TimelineMaxLen = 800
+
+// TimelineKey is the Redis sorted set of post IDs, scored by post.Post.PublishedScore, for a user's home timeline
+func TimelineKey(userID uint) string {
+	return fmt.Sprintf("timeline:%d", userID)
+}
+
+// fanoutThreshold is the follower count from which an author is read-merged
+var fanoutThreshold int64 = 10000
+
+// SetFanoutThreshold sets the follower count from which an author's posts are
+// merged at read time instead of being pushed to every follower (0 keeps the default)
+func SetFanoutThreshold(n int64) {
+	if n > 0 {
+		fanoutThreshold = n
+	}
+}
+
+// FanoutThreshold returns the follower count from which fan-out-on-read is used
+func FanoutThreshold() int64 {
+	return fanoutThreshold
+}
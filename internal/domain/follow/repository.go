@@ -0,0 +1,22 @@
+package follow
+
+import "starter-gofiber/pkg/pagination"
+
+// Repository defines the interface for follow repository operations
+type Repository interface {
+	// Create stores the edge, returning false when it already existed
+	Create(follow *Follow) (bool, error)
+	// Delete removes the edge, returning false when it did not exist
+	Delete(followerID, followeeID uint) (bool, error)
+	Exists(followerID, followeeID uint) (bool, error)
+	FindFollowers(userID uint, page pagination.CursorPagination) ([]Follow, error)
+	FindFollowing(userID uint, page pagination.CursorPagination) ([]Follow, error)
+	CountFollowers(userID uint) (int64, error)
+	CountFollowing(userID uint) (int64, error)
+	// FollowingIDs returns every user ID followed by userID
+	FollowingIDs(userID uint) ([]uint, error)
+	// FollowerIDsAfter returns up to limit follower IDs greater than afterID, for batched fan-out
+	FollowerIDsAfter(userID, afterID uint, limit int) ([]uint, error)
+	// PopularIDs filters userIDs down to users with at least minFollowers followers
+	PopularIDs(userIDs []uint, minFollowers int64) ([]uint, error)
+}
@@ -0,0 +1,15 @@
+package follow
+
+import "starter-gofiber/pkg/pagination"
+
+// Service defines the interface for follow and timeline operations
+type Service interface {
+	Follow(followerID, followeeID uint) error
+	Unfollow(followerID, followeeID uint) error
+	Stats(userID, viewerID uint) (*FollowStats, error)
+	Followers(userID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+	Following(userID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+	// Timeline returns the user's home timeline, posts by followed users and the user, newest first.
+	// Data holds []post.PostResponse.
+	Timeline(userID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+}
@@ -0,0 +1,32 @@
+package linkpreview
+
+import "starter-gofiber/variables"
+
+// LinkPreviewResponse is the card shown under a post linking to a URL
+type LinkPreviewResponse struct {
+	URL          string  `json:"url"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	SiteName     string  `json:"site_name"`
+	ImageURL     *string `json:"image_url"`
+	ThumbnailURL *string `json:"thumbnail_url"`
+}
+
+func (r LinkPreviewResponse) FromEntity(p LinkPreview) LinkPreviewResponse {
+	r.URL = p.TargetURL
+	if r.URL == "" {
+		r.URL = p.URL
+	}
+	r.Title = p.Title
+	r.Description = p.Description
+	r.SiteName = p.SiteName
+	if p.ImagePath != nil {
+		url := variables.GenerateStatic([]string{*p.ImagePath})
+		r.ImageURL = &url
+	}
+	if p.ThumbnailPath != nil {
+		url := variables.GenerateStatic([]string{*p.ThumbnailPath})
+		r.ThumbnailURL = &url
+	}
+	return r
+}
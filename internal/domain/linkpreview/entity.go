@@ -0,0 +1,73 @@
+package linkpreview
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Preview statuses, failed fetches are cached too so a broken link is not fetched on every post
+const (
+	StatusReady  = "ready"
+	StatusFailed = "failed"
+)
+
+// How long an unfurled URL is reused before it is fetched again
+const (
+	CacheTTL   = 24 * time.Hour
+	FailureTTL = time.Hour
+)
+
+// MaxURLLength is the longest URL unfurled, longer ones get no preview
+const MaxURLLength = 2048
+
+// LinkPreview is the card of an unfurled URL, shared by every post linking to it
+type LinkPreview struct {
+	ID uint `gorm:"primaryKey;autoIncrement"`
+	// URLHash identifies the URL as written in posts, URLs are too long to index
+	URLHash string `gorm:"type:char(64);not null;uniqueIndex"`
+	URL     string `gorm:"type:varchar(2048);not null"`
+	// TargetURL is where the URL led after redirects, the card links to it
+	TargetURL   string `gorm:"type:varchar(2048)"`
+	Title       string `gorm:"type:varchar(300)"`
+	Description string `gorm:"type:varchar(500)"`
+	SiteName    string `gorm:"type:varchar(100)"`
+	// Image paths are relative to the public directory, e.g. /link-previews/<uuid>.jpg
+	ImagePath     *string `gorm:"type:varchar(255)"`
+	ThumbnailPath *string `gorm:"type:varchar(255)"`
+	Status        string  `gorm:"type:varchar(20);not null;default:ready"`
+	FetchedAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// HashURL returns the URLHash of a URL
+func HashURL(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsFresh reports whether the cached preview can be reused instead of fetching the URL again
+func (p LinkPreview) IsFresh(now time.Time) bool {
+	ttl := CacheTTL
+	if p.Status == StatusFailed {
+		ttl = FailureTTL
+	}
+	return now.Sub(p.FetchedAt) < ttl
+}
+
+// IsReady reports whether the preview has a card to show
+func (p LinkPreview) IsReady() bool {
+	return p.Status == StatusReady
+}
+
+// Files returns the stored image files of the preview
+func (p LinkPreview) Files() []string {
+	var files []string
+	for _, path := range []*string{p.ImagePath, p.ThumbnailPath} {
+		if path != nil {
+			files = append(files, *path)
+		}
+	}
+	return files
+}
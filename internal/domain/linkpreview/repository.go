@@ -0,0 +1,12 @@
+package linkpreview
+
+// Repository defines the interface for link preview repository operations.
+// Previews are written by the unfurl job only.
+type Repository interface {
+	FindByURL(url string) (*LinkPreview, error)
+	// Save stores the preview of its URL, replacing an older fetch. It returns the replaced
+	// preview's image files, which are no longer used.
+	Save(p *LinkPreview) ([]string, error)
+	// AttachToPost sets the preview shown with a post, nil removes it
+	AttachToPost(postID uint, previewID *uint) error
+}
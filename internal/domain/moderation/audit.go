@@ -0,0 +1,15 @@
+package moderation
+
+// auditLog enables recording moderation actions in the audit log
+var auditLog bool
+
+// SetAuditLog turns recording moderation actions in the audit log on or off,
+// it follows the audit log configuration since its table only exists when enabled
+func SetAuditLog(enabled bool) {
+	auditLog = enabled
+}
+
+// AuditLogEnabled reports whether moderation actions are recorded in the audit log
+func AuditLogEnabled() bool {
+	return auditLog
+}
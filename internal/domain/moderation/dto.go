@@ -0,0 +1,81 @@
+package moderation
+
+import (
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+)
+
+type ReportRequest struct {
+	TargetType string `json:"target_type" validate:"required,oneof=post comment user"`
+	TargetID   uint   `json:"target_id" validate:"required"`
+	Reason     string `json:"reason" validate:"required,oneof=spam harassment hate violence nudity misinformation other"`
+	Note       string `json:"note" validate:"max=500"`
+}
+
+// AssignRequest hands a report to a moderator, the current one when AssigneeID is nil
+type AssignRequest struct {
+	AssigneeID *uint `json:"assignee_id"`
+}
+
+type ResolveRequest struct {
+	Action string `json:"action" validate:"required,oneof=dismiss hide delete suspend"`
+	Note   string `json:"note" validate:"max=500"`
+}
+
+// ReportFilter narrows the moderation queue, an empty Status lists open and in review reports
+type ReportFilter struct {
+	Status     string
+	TargetType string
+	Reason     string
+	AssigneeID *uint
+}
+
+type ReportResponse struct {
+	ID             uint               `json:"id"`
+	ReporterID     *uint              `json:"reporter_id"`
+	Reporter       *user.UserResponse `json:"reporter,omitempty"`
+	TargetType     string             `json:"target_type"`
+	TargetID       uint               `json:"target_id"`
+	TargetUserID   uint               `json:"target_user_id"`
+	Reason         string             `json:"reason"`
+	Note           string             `json:"note"`
+	Status         string             `json:"status"`
+	AssigneeID     *uint              `json:"assignee_id"`
+	Assignee       *user.UserResponse `json:"assignee,omitempty"`
+	Resolution     string             `json:"resolution,omitempty"`
+	ResolutionNote string             `json:"resolution_note,omitempty"`
+	ResolvedByID   *uint              `json:"resolved_by_id,omitempty"`
+	ResolvedAt     *string            `json:"resolved_at,omitempty"`
+	CreatedAt      string             `json:"created_at"`
+	UpdatedAt      string             `json:"updated_at"`
+}
+
+func (r ReportResponse) FromEntity(e Report) ReportResponse {
+	r.ID = e.ID
+	r.ReporterID = e.ReporterID
+	if e.Reporter != nil {
+		usr := user.UserResponse{}.FromEntity(*e.Reporter)
+		r.Reporter = &usr
+	}
+	r.TargetType = e.TargetType
+	r.TargetID = e.TargetID
+	r.TargetUserID = e.TargetUserID
+	r.Reason = e.Reason
+	r.Note = e.Note
+	r.Status = e.Status
+	r.AssigneeID = e.AssigneeID
+	if e.Assignee != nil {
+		usr := user.UserResponse{}.FromEntity(*e.Assignee)
+		r.Assignee = &usr
+	}
+	r.Resolution = e.Resolution
+	r.ResolutionNote = e.ResolutionNote
+	r.ResolvedByID = e.ResolvedByID
+	if e.ResolvedAt != nil {
+		resolvedAt := e.ResolvedAt.Format(variables.FORMAT_TIME)
+		r.ResolvedAt = &resolvedAt
+	}
+	r.CreatedAt = e.CreatedAt.Format(variables.FORMAT_TIME)
+	r.UpdatedAt = e.UpdatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
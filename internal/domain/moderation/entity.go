@@ -0,0 +1,71 @@
+package moderation
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+)
+
+// Targets a report can be filed against
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+	TargetUser    = "user"
+)
+
+// Reasons a report can give, ReasonFilter is only used for posts flagged by the keyword filter
+const (
+	ReasonSpam           = "spam"
+	ReasonHarassment     = "harassment"
+	ReasonHate           = "hate"
+	ReasonViolence       = "violence"
+	ReasonNudity         = "nudity"
+	ReasonMisinformation = "misinformation"
+	ReasonOther          = "other"
+	ReasonFilter         = "filter"
+)
+
+// Report statuses, open and in review reports make up the queue
+const (
+	StatusOpen      = "open"
+	StatusInReview  = "in_review"
+	StatusResolved  = "resolved"
+	StatusDismissed = "dismissed"
+)
+
+// Resolution actions
+const (
+	ActionDismiss = "dismiss"
+	ActionHide    = "hide"
+	ActionDelete  = "delete"
+	ActionSuspend = "suspend"
+)
+
+// Report is a complaint about a post, comment or user waiting for a moderator
+type Report struct {
+	ID uint `gorm:"primaryKey;autoIncrement"`
+	// ReporterID is nil for posts flagged by the keyword filter
+	ReporterID *uint      `gorm:"index"`
+	Reporter   *user.User `gorm:"foreignKey:ReporterID"`
+	TargetType string     `gorm:"type:varchar(20);not null;index:idx_reports_target"`
+	TargetID   uint       `gorm:"not null;index:idx_reports_target"`
+	// TargetUserID is the author of the reported content, or the reported user
+	TargetUserID uint       `gorm:"not null;index"`
+	Reason       string     `gorm:"type:varchar(30);not null"`
+	Note         string     `gorm:"type:varchar(500)"`
+	Status       string     `gorm:"type:varchar(20);not null;default:open;index"`
+	AssigneeID   *uint      `gorm:"index"`
+	Assignee     *user.User `gorm:"foreignKey:AssigneeID"`
+	// Resolution is the action taken, with the moderator's note
+	Resolution     string `gorm:"type:varchar(20)"`
+	ResolutionNote string `gorm:"type:varchar(500)"`
+	ResolvedByID   *uint
+	ResolvedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Pending reports whether the report is still in the queue
+func (r Report) Pending() bool {
+	return r.Status == StatusOpen || r.Status == StatusInReview
+}
@@ -0,0 +1,67 @@
+package moderation
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Filter actions, blocking rejects a post and flagging holds it for review
+const (
+	FilterBlock = "block"
+	FilterFlag  = "flag"
+)
+
+// Rule is a keyword or regular expression of the post filter
+type Rule struct {
+	Action  string
+	Pattern *regexp.Regexp
+}
+
+var (
+	filterMu sync.RWMutex
+	filter   []Rule
+)
+
+// KeywordRule matches a whole word or phrase regardless of case
+func KeywordRule(action, keyword string) Rule {
+	return Rule{Action: action, Pattern: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)}
+}
+
+// PatternRule matches a regular expression, case sensitive unless it starts with (?i)
+func PatternRule(action, expr string) (Rule, error) {
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Action: action, Pattern: pattern}, nil
+}
+
+// SetFilter replaces the rules posts are screened with
+func SetFilter(rules []Rule) {
+	filterMu.Lock()
+	defer filterMu.Unlock()
+	filter = rules
+}
+
+// Screen checks text against the filter, a blocking rule wins over a flagging one
+func Screen(text string) (Rule, bool) {
+	filterMu.RLock()
+	defer filterMu.RUnlock()
+
+	var flagged *Rule
+	for i, rule := range filter {
+		if !rule.Pattern.MatchString(text) {
+			continue
+		}
+		if rule.Action == FilterBlock {
+			return rule, true
+		}
+		if flagged == nil {
+			flagged = &filter[i]
+		}
+	}
+	if flagged != nil {
+		return *flagged, true
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,17 @@
+package moderation
+
+import "time"
+
+// Repository defines the interface for report repository operations
+type Repository interface {
+	Create(report *Report) error
+	FindByID(id uint) (*Report, error)
+	// FindPendingByReporter finds the reporter's report on a target that is still in the queue
+	FindPendingByReporter(reporterID uint, targetType string, targetID uint) (*Report, error)
+	FindAll(filter ReportFilter, limit, offset int) ([]Report, int64, error)
+	Update(report *Report) error
+	// ResolvePending closes every pending report on a target with the same outcome
+	ResolvePending(targetType string, targetID uint, status, resolution, note string, resolverID uint, at time.Time) error
+	// LogAction records a moderation action in the audit log, moderatorID is nil for the filter
+	LogAction(moderatorID *uint, entityType string, entityID uint, description string, data interface{}) error
+}
@@ -0,0 +1,14 @@
+package moderation
+
+// Service defines the interface for content reporting and moderation operations
+type Service interface {
+	// Report files a complaint about a post, comment or user
+	Report(req *ReportRequest, reporterID uint) (*ReportResponse, error)
+	// Queue lists reports for moderators, oldest first
+	Queue(filter ReportFilter, page, limit int) ([]ReportResponse, int64, error)
+	FindByID(id uint) (*ReportResponse, error)
+	// Assign hands a pending report to a moderator and marks it in review
+	Assign(id uint, req *AssignRequest, moderatorID uint) (*ReportResponse, error)
+	// Resolve takes action on the report's target and closes every pending report on it
+	Resolve(id uint, req *ResolveRequest, moderatorID uint) (*ReportResponse, error)
+}
@@ -0,0 +1,101 @@
+package poll
+
+import (
+	"time"
+
+	"starter-gofiber/variables"
+)
+
+// PollRequest attaches a poll to a new post
+type PollRequest struct {
+	Options        []string `json:"options" validate:"min=2,max=4,dive,required,max=100"`
+	MultipleChoice bool     `json:"multiple_choice"`
+	// ExpiresAt ends the voting, between MinDuration and MaxDuration after the post goes live
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+}
+
+type VoteRequest struct {
+	// OptionIDs are the chosen options, exactly one unless the poll is multiple choice
+	OptionIDs []uint `json:"option_ids" validate:"required,min=1,max=4"`
+}
+
+type OptionResponse struct {
+	ID   uint   `json:"id"`
+	Text string `json:"text"`
+	// Votes is null while the results are hidden from the viewer
+	Votes *int `json:"votes"`
+}
+
+// PollResponse is a poll as seen by one viewer. Results are shown once the viewer voted, to the
+// author and to everyone once the poll closed.
+type PollResponse struct {
+	ID             uint             `json:"id"`
+	MultipleChoice bool             `json:"multiple_choice"`
+	ExpiresAt      string           `json:"expires_at"`
+	Closed         bool             `json:"closed"`
+	Options        []OptionResponse `json:"options"`
+	VoterCount     *int             `json:"voter_count"`
+	Voted          bool             `json:"voted"`
+	MyVotes        []uint           `json:"my_votes"`
+	// AuthorID is the author of the post, who always sees the results
+	AuthorID uint `json:"-"`
+	// final holds the tallies persisted when the poll closed
+	final *Tally
+}
+
+func (r PollResponse) FromEntity(p Poll, authorID uint) PollResponse {
+	r.ID = p.ID
+	r.MultipleChoice = p.MultipleChoice
+	r.ExpiresAt = p.ExpiresAt.Format(variables.FORMAT_TIME)
+	r.Closed = p.IsClosed(time.Now())
+	r.AuthorID = authorID
+	r.MyVotes = []uint{}
+	r.Options = make([]OptionResponse, 0, len(p.Options))
+	for _, o := range p.Options {
+		r.Options = append(r.Options, OptionResponse{ID: o.ID, Text: o.Text})
+	}
+	if p.ClosedAt != nil {
+		final := Tally{Options: make(map[uint]int, len(p.Options)), Voters: p.VoterCount}
+		for _, o := range p.Options {
+			final.Options[o.ID] = o.VoteCount
+		}
+		r.final = &final
+	}
+	return r
+}
+
+// Final returns the tallies persisted when the poll closed, nil while it is open
+func (r PollResponse) Final() *Tally {
+	return r.final
+}
+
+// WithResults shows the tallies
+func (r *PollResponse) WithResults(t Tally) {
+	for i := range r.Options {
+		votes := t.Options[r.Options[i].ID]
+		r.Options[i].Votes = &votes
+	}
+	voters := t.Voters
+	r.VoterCount = &voters
+}
+
+// WithBallot marks the options the viewer voted for
+func (r *PollResponse) WithBallot(b Ballot) {
+	r.Voted = true
+	r.MyVotes = make([]uint, 0, len(b.Votes))
+	for _, v := range b.Votes {
+		r.MyVotes = append(r.MyVotes, v.OptionID)
+	}
+}
+
+func (r PollRequest) ToEntity() Poll {
+	p := Poll{
+		MultipleChoice: r.MultipleChoice,
+		ExpiresAt:      r.ExpiresAt,
+		Options:        make([]Option, len(r.Options)),
+	}
+	for i, text := range r.Options {
+		p.Options[i] = Option{Position: i, Text: text}
+	}
+	return p
+}
@@ -0,0 +1,91 @@
+package poll
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits of a poll, its duration counts from when the post goes live
+const (
+	MinOptions    = 2
+	MaxOptions    = 4
+	MaxOptionText = 100
+	MinDuration   = 5 * time.Minute
+	MaxDuration   = 7 * 24 * time.Hour
+)
+
+// ResultsEvent is the SSE event carrying the tallies of a poll to its author and voters
+const ResultsEvent = "poll_results"
+
+// VotersField is the field of the counter hash counting the users who voted
+const VotersField = "voters"
+
+// CounterKey is the Redis hash holding the live tallies of an open poll, by option ID and VotersField
+func CounterKey(pollID uint) string {
+	return fmt.Sprintf("poll:count:%d", pollID)
+}
+
+// Poll is attached to a post and takes one ballot per user until it expires
+type Poll struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement"`
+	PostID         uint      `gorm:"not null;uniqueIndex"`
+	MultipleChoice bool      `gorm:"not null;default:false"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	// ClosedAt is set by the closing job, which persists the final tallies in VoterCount and the options
+	ClosedAt   *time.Time
+	VoterCount int `gorm:"not null;default:0"`
+	// Options are shown in Position order
+	Options   []Option `gorm:"foreignKey:PollID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsClosed reports whether the poll stopped taking votes, expired polls count as closed before the job ran
+func (p Poll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || !now.Before(p.ExpiresAt)
+}
+
+// Option is one answer of a poll
+type Option struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement"`
+	PollID   uint   `gorm:"not null;index"`
+	Position int    `gorm:"not null;default:0"`
+	Text     string `gorm:"type:varchar(100);not null"`
+	// VoteCount is the final tally, set when the poll closes
+	VoteCount int `gorm:"not null;default:0"`
+}
+
+func (Option) TableName() string {
+	return "poll_options"
+}
+
+// Ballot is a user's vote in a poll, for one option or several in multiple choice polls. It cannot be changed.
+type Ballot struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	PollID    uint   `gorm:"not null;uniqueIndex:idx_poll_ballot_user"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_poll_ballot_user"`
+	Votes     []Vote `gorm:"foreignKey:BallotID"`
+	CreatedAt time.Time
+}
+
+func (Ballot) TableName() string {
+	return "poll_ballots"
+}
+
+// Vote is one chosen option of a ballot
+type Vote struct {
+	ID       uint `gorm:"primaryKey;autoIncrement"`
+	BallotID uint `gorm:"not null;index"`
+	PollID   uint `gorm:"not null;index"`
+	OptionID uint `gorm:"not null;index"`
+}
+
+func (Vote) TableName() string {
+	return "poll_votes"
+}
+
+// Tally counts the votes of each option and the users who voted
+type Tally struct {
+	Options map[uint]int
+	Voters  int
+}
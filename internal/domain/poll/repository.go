@@ -0,0 +1,21 @@
+package poll
+
+import "time"
+
+// Repository defines the interface for poll repository operations.
+// Polls are created with their post, Options are loaded in Position order.
+type Repository interface {
+	FindByPostID(postID uint) (*Poll, error)
+	// CastBallot stores the ballot with its votes, false when the user already voted in the poll
+	CastBallot(b *Ballot) (bool, error)
+	// FindBallots returns the user's ballots in the polls, with their votes
+	FindBallots(pollIDs []uint, userID uint) ([]Ballot, error)
+	// CountVotes tallies the polls straight from the votes
+	CountVotes(pollIDs []uint) (map[uint]Tally, error)
+	// FindVoterIDs returns which of the users voted in the poll
+	FindVoterIDs(pollID uint, userIDs []uint) ([]uint, error)
+	// FindDue returns up to limit open polls of posts that were not deleted and expired by now, oldest first
+	FindDue(now time.Time, limit int) ([]Poll, error)
+	// Close persists the final tallies and marks the poll closed, false when it was closed already
+	Close(pollID uint, tally Tally, at time.Time) (bool, error)
+}
@@ -0,0 +1,11 @@
+package poll
+
+// Service defines the interface for poll service operations
+type Service interface {
+	// Find returns the poll of a post the viewer may open, viewerID 0 for anonymous viewers
+	Find(postID, viewerID uint) (*PollResponse, error)
+	// Vote casts the user's ballot, a user votes once per poll
+	Vote(postID uint, req *VoteRequest, userID uint) (*PollResponse, error)
+	// Attach fills in the viewer's ballots and the results they may see, for polls rendered in listings
+	Attach(polls []*PollResponse, viewerID uint) error
+}
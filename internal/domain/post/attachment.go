@@ -0,0 +1,61 @@
+package post
+
+import "time"
+
+// Attachment kinds, only images are accepted for now
+const (
+	AttachmentImage = "image"
+)
+
+// MaxAltTextLength is the maximum length of an attachment's alt text
+const MaxAltTextLength = 1000
+
+// maxAttachments is the number of attachments a post may carry
+var maxAttachments = 4
+
+// SetMaxAttachments sets the number of attachments a post may carry (0 keeps the default)
+func SetMaxAttachments(n int) {
+	if n > 0 {
+		maxAttachments = n
+	}
+}
+
+// MaxAttachments returns the number of attachments a post may carry
+func MaxAttachments() int {
+	return maxAttachments
+}
+
+// Attachment is a processed file shown with a post. Uploads start without a
+// post and are claimed by the first post of their owner that lists their ID.
+type Attachment struct {
+	ID     uint  `gorm:"primaryKey;autoIncrement"`
+	PostID *uint `gorm:"index"`
+	// UserID is the uploader, only they can attach the file
+	UserID uint   `gorm:"index;not null"`
+	Kind   string `gorm:"type:varchar(20);not null"`
+	// Paths are relative to the public directory, e.g. /attachments/<uuid>.jpg
+	Path          string  `gorm:"type:varchar(255);not null"`
+	ThumbnailPath *string `gorm:"type:varchar(255)"`
+	MediumPath    *string `gorm:"type:varchar(255)"`
+	MimeType      string  `gorm:"type:varchar(100)"`
+	Size          int64
+	Width         int
+	Height        int
+	AltText       string `gorm:"type:varchar(1000)"`
+	Position      int    `gorm:"not null;default:0"`
+	// DetachedAt is set when an edit removed the attachment, it stays for the post's revisions
+	DetachedAt *time.Time `gorm:"index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Files returns every stored file of the attachment
+func (a Attachment) Files() []string {
+	files := []string{a.Path}
+	for _, p := range []*string{a.ThumbnailPath, a.MediumPath} {
+		if p != nil {
+			files = append(files, *p)
+		}
+	}
+	return files
+}
@@ -0,0 +1,89 @@
+package post
+
+// Bulk actions, each runs on the author's own posts only
+const (
+	BulkCreate  = "create"
+	BulkUpdate  = "update"
+	BulkDelete  = "delete"
+	BulkRestore = "restore"
+)
+
+const (
+	// BulkSyncLimit is the largest batch run during the request, larger ones are queued
+	BulkSyncLimit = 50
+	// BulkMaxItems is the largest batch accepted
+	BulkMaxItems = 1000
+)
+
+// Bulk job states, queued batches go from pending through active to completed or failed
+const (
+	BulkStatusPending   = "pending"
+	BulkStatusActive    = "active"
+	BulkStatusCompleted = "completed"
+	BulkStatusFailed    = "failed"
+)
+
+// BulkRequest is a batch of one action, Posts are created, Updates carry the ID of the post
+// they change and IDs are deleted or restored
+type BulkRequest struct {
+	Action  string              `json:"action" validate:"required,oneof=create update delete restore"`
+	Posts   []PostRequest       `json:"posts,omitempty"`
+	Updates []PostUpdateRequest `json:"updates,omitempty"`
+	IDs     []uint              `json:"ids,omitempty"`
+}
+
+// Len returns the number of items of the request's action
+func (r BulkRequest) Len() int {
+	switch r.Action {
+	case BulkCreate:
+		return len(r.Posts)
+	case BulkUpdate:
+		return len(r.Updates)
+	default:
+		return len(r.IDs)
+	}
+}
+
+// BulkError is an item of a batch that was left unchanged, shaped like the errors of the
+// database bulk helpers
+type BulkError struct {
+	Index   int    `json:"index"`
+	ID      uint   `json:"id,omitempty"`
+	Message string `json:"message"`
+}
+
+// BulkResult reports a batch, Errors holds the index in the request and the post ID of each
+// failed item. A queued batch has a JobID and no counts until it completed.
+type BulkResult struct {
+	JobID     string      `json:"job_id,omitempty"`
+	Status    string      `json:"status"`
+	Action    string      `json:"action"`
+	Total     int         `json:"total"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	IDs       []uint      `json:"ids"`
+	Errors    []BulkError `json:"errors"`
+}
+
+// NewBulkResult starts the result of a batch with no items processed
+func NewBulkResult(action string, total int) *BulkResult {
+	return &BulkResult{
+		Status: BulkStatusCompleted,
+		Action: action,
+		Total:  total,
+		IDs:    make([]uint, 0, total),
+		Errors: make([]BulkError, 0),
+	}
+}
+
+// Succeed records a processed item
+func (r *BulkResult) Succeed(id uint) {
+	r.Succeeded++
+	r.IDs = append(r.IDs, id)
+}
+
+// Fail records an item that was left unchanged
+func (r *BulkResult) Fail(index int, id uint, message string) {
+	r.Failed++
+	r.Errors = append(r.Errors, BulkError{Index: index, ID: id, Message: message})
+}
@@ -1,24 +1,107 @@
 package post
 
 import (
+	"time"
+
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/reaction"
 	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/utils"
 	"starter-gofiber/variables"
 )
 
+// SortFields are the fields cursor listings of posts can be sorted by, ties are broken by id
+var SortFields = []string{"id", "created_at", "comment_count"}
+
+// FilterFields are the columns filter_<field>_<op> query parameters may filter on
+var FilterFields = []string{"user_id", "created_at", "comment_count"}
+
+// ListFilter narrows cursor listings of posts, zero fields do not filter
+type ListFilter struct {
+	UserID      uint
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// HasPhoto keeps posts with (true) or without (false) a photo or image attachment
+	HasPhoto *bool
+	// Filters are further conditions on FilterFields
+	Filters []utils.Filter
+	// QuotedPostID keeps the quotes of a post
+	QuotedPostID uint
+}
+
 type PostResponse struct {
-	ID        uint               `json:"id"`
-	Tweet     string             `json:"tweet"`
-	Photo     *string            `json:"photo"`
-	UserID    uint               `json:"user_id"`
-	User      *user.UserResponse `json:"user"`
-	CreatedAt string             `json:"created_at"`
-	UpdatedAt string             `json:"updated_at"`
+	ID           uint                 `json:"id"`
+	Tweet        string               `json:"tweet"`
+	Photo        *string              `json:"photo"`
+	UserID       uint                 `json:"user_id"`
+	User         *user.UserResponse   `json:"user"`
+	CommentCount int                  `json:"comment_count"`
+	RepostCount  int                  `json:"repost_count"`
+	QuoteCount   int                  `json:"quote_count"`
+	Entities     []TextEntity         `json:"entities"`
+	Attachments  []AttachmentResponse `json:"attachments"`
+	// QuotedPost embeds the quoted post one level deep while the viewer may open it, it is null
+	// with QuotedPostID still set once the quoted post was deleted or is no longer shared
+	QuotedPostID *uint         `json:"quoted_post_id"`
+	QuotedPost   *PostResponse `json:"quoted_post"`
+	// Poll shows its results once the viewer voted, to the author and after it closed
+	Poll *poll.PollResponse `json:"poll"`
+	// LinkPreview is the card of the post's first URL, null until it was unfurled
+	LinkPreview *linkpreview.LinkPreviewResponse `json:"link_preview"`
+	// Title, Slug, the raw Markdown Body and its sanitised HTML are set for long-form posts
+	Title       *string `json:"title"`
+	Slug        *string `json:"slug"`
+	Body        string  `json:"body"`
+	BodyHTML    string  `json:"body_html"`
+	Excerpt     string  `json:"excerpt"`
+	ReadingTime int     `json:"reading_time"`
+	Status      string  `json:"status"`
+	// ModerationState is visible, flagged or hidden, only authors see their posts in the last two
+	ModerationState string            `json:"moderation_state"`
+	Visibility      string            `json:"visibility"`
+	PublishAt       *string           `json:"publish_at"`
+	Edited          bool              `json:"edited"`
+	EditedAt        *string           `json:"edited_at"`
+	Reactions       *reaction.Summary `json:"reactions,omitempty"`
+	Reacted         bool              `json:"reacted"`
+	Bookmarked      bool              `json:"bookmarked"`
+	CreatedAt       string            `json:"created_at"`
+	UpdatedAt       string            `json:"updated_at"`
 }
 
 type PostRequest struct {
-	Tweet  string `json:"tweet" validate:"required,max=500"`
+	Tweet  string `json:"tweet" validate:"required_without=Body,max=500"`
 	Photo  string `json:"photo"`
 	UserID uint   `json:"user_id" validate:"required"`
+	// Title and the Markdown Body make a long-form post, the tweet is optional then
+	Title string `json:"title" form:"title" validate:"required_with=Body,max=200"`
+	Body  string `json:"body" form:"body" validate:"required_with=Title,max=50000"`
+	// QuotedPostID quotes a live post the author may see
+	QuotedPostID *uint `json:"quoted_post_id" form:"quoted_post_id"`
+	// Poll attaches a poll, it cannot be added or changed later
+	Poll *poll.PollRequest `json:"poll" form:"-"`
+	// AttachmentIDs are uploads of the author, in display order
+	AttachmentIDs []uint `json:"attachment_ids" form:"attachment_ids"`
+	// Status defaults to scheduled when PublishAt is set and to published otherwise
+	Status    string     `json:"status" form:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt *time.Time `json:"publish_at" form:"publish_at"`
+	// Visibility defaults to the author's profile_visibility preference
+	Visibility string `json:"visibility" form:"visibility" validate:"omitempty,oneof=public followers mentioned private"`
+}
+
+// AttachmentResponse is an attachment with its public URLs
+type AttachmentResponse struct {
+	ID           uint    `json:"id"`
+	Kind         string  `json:"kind"`
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnail_url"`
+	MediumURL    *string `json:"medium_url"`
+	MimeType     string  `json:"mime_type"`
+	Size         int64   `json:"size"`
+	Width        int     `json:"width"`
+	Height       int     `json:"height"`
+	Alt          string  `json:"alt"`
 }
 
 type CreatePostRequest struct {
@@ -33,17 +116,39 @@ type UpdatePostRequest struct {
 
 type PostUpdateRequest struct {
 	ID     uint    `json:"id" validate:"required"`
-	Tweet  string  `json:"tweet" validate:"required,max=500"`
+	Tweet  string  `json:"tweet" validate:"max=500"`
 	Photo  *string `json:"photo"`
 	UserID uint    `json:"user_id" validate:"required"`
+	// Title and Body replace the long-form content, omit them to keep it and set both empty to remove it
+	Title *string `json:"title" form:"title" validate:"omitempty,max=200"`
+	Body  *string `json:"body" form:"body" validate:"omitempty,max=50000"`
+	// AttachmentIDs replaces the attachments in the given order, omit it to keep them
+	AttachmentIDs []uint `json:"attachment_ids" form:"attachment_ids"`
+	// UploadedIDs are set by the handler from multipart files and appended after the kept attachments
+	UploadedIDs []uint `json:"-" form:"-"`
+	// Status and PublishAt reschedule or cancel an unpublished post, omit both to keep them
+	Status    string     `json:"status" form:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt *time.Time `json:"publish_at" form:"publish_at"`
+	// Visibility changes who sees the post, omit it to keep it
+	Visibility string `json:"visibility" form:"visibility" validate:"omitempty,oneof=public followers mentioned private"`
 }
 
 func (r PostRequest) ToEntity() Post {
-	return Post{
-		Tweet:  r.Tweet,
-		Photo:  &r.Photo,
-		UserID: r.UserID,
+	p := Post{
+		Tweet:        r.Tweet,
+		Photo:        &r.Photo,
+		UserID:       r.UserID,
+		Body:         r.Body,
+		QuotedPostID: r.QuotedPostID,
 	}
+	if r.Title != "" {
+		p.Title = &r.Title
+	}
+	if r.Poll != nil {
+		pl := r.Poll.ToEntity()
+		p.Poll = &pl
+	}
+	return p
 }
 
 func (r PostResponse) FromEntity(p Post) PostResponse {
@@ -53,12 +158,99 @@ func (r PostResponse) FromEntity(p Post) PostResponse {
 		path := variables.GenerateStatic([]string{variables.POST_PATH, *p.Photo})
 		r.Photo = &path
 	}
+	r.Title = p.Title
+	r.Slug = p.Slug
+	r.Body = p.Body
+	r.BodyHTML = p.BodyHTML
+	r.Excerpt = p.Excerpt
+	r.ReadingTime = p.ReadingTime
 	r.UserID = p.UserID
 	if p.User != nil {
 		usr := user.UserResponse{}.FromEntity(*p.User)
 		r.User = &usr
 	}
+	r.CommentCount = p.CommentCount
+	r.RepostCount = p.RepostCount
+	r.QuoteCount = p.QuoteCount
+	r.Entities = resolveEntities(p)
+	r.Attachments = make([]AttachmentResponse, 0, len(p.Attachments))
+	for _, a := range p.Attachments {
+		r.Attachments = append(r.Attachments, AttachmentResponse{}.FromEntity(a))
+	}
+	r.QuotedPostID = p.QuotedPostID
+	if p.QuotedPost != nil {
+		quoted := PostResponse{}.FromEntity(*p.QuotedPost)
+		r.QuotedPost = &quoted
+	}
+	if p.Poll != nil {
+		pl := poll.PollResponse{}.FromEntity(*p.Poll, p.UserID)
+		r.Poll = &pl
+	}
+	// A preview left from before an edit changed the link is not shown
+	if lp := p.LinkPreview; lp != nil && lp.IsReady() && lp.URL == p.LinkURL() {
+		preview := linkpreview.LinkPreviewResponse{}.FromEntity(*lp)
+		r.LinkPreview = &preview
+	}
+	r.Status = p.Status
+	r.ModerationState = p.ModerationState
+	r.Visibility = p.Visibility
+	if p.PublishAt != nil {
+		publishAt := p.PublishAt.Format(variables.FORMAT_TIME)
+		r.PublishAt = &publishAt
+	}
+	if p.EditedAt != nil {
+		editedAt := p.EditedAt.Format(variables.FORMAT_TIME)
+		r.Edited = true
+		r.EditedAt = &editedAt
+	}
 	r.CreatedAt = p.CreatedAt.Format(variables.FORMAT_TIME)
 	r.UpdatedAt = p.UpdatedAt.Format(variables.FORMAT_TIME)
 	return r
 }
+
+func (r AttachmentResponse) FromEntity(a Attachment) AttachmentResponse {
+	r.ID = a.ID
+	r.Kind = a.Kind
+	r.URL = variables.GenerateStatic([]string{a.Path})
+	if a.ThumbnailPath != nil {
+		url := variables.GenerateStatic([]string{*a.ThumbnailPath})
+		r.ThumbnailURL = &url
+	}
+	if a.MediumPath != nil {
+		url := variables.GenerateStatic([]string{*a.MediumPath})
+		r.MediumURL = &url
+	}
+	r.MimeType = a.MimeType
+	r.Size = a.Size
+	r.Width = a.Width
+	r.Height = a.Height
+	r.Alt = a.AltText
+	return r
+}
+
+// resolveEntities keeps hashtags and the mentions that resolved to a user
+func resolveEntities(p Post) []TextEntity {
+	users := make(map[string]uint, len(p.Mentions))
+	for _, m := range p.Mentions {
+		users[m.Username] = m.UserID
+	}
+
+	entities := make([]TextEntity, 0)
+	for _, e := range ParseEntities(p.Tweet) {
+		if e.Type == EntityMention {
+			id, ok := users[e.Text]
+			if !ok {
+				continue
+			}
+			e.UserID = &id
+		}
+		entities = append(entities, e)
+	}
+	return entities
+}
+
+// WithReactions attaches the viewer's reaction summary
+func (r *PostResponse) WithReactions(s reaction.Summary) {
+	r.Reactions = &s
+	r.Reacted = s.MyReaction != nil
+}
@@ -0,0 +1,156 @@
+package post
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+
+	"starter-gofiber/internal/domain/linkpreview"
+)
+
+const (
+	EntityHashtag = "hashtag"
+	EntityMention = "mention"
+
+	maxHashtagLen = 50
+	minMentionLen = 3
+	maxMentionLen = 30
+)
+
+// TextEntity is a #hashtag or @mention inside a post. Start and End are
+// rune offsets into the text (End exclusive) so clients can render links.
+type TextEntity struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	UserID *uint  `json:"user_id,omitempty"`
+}
+
+// Tag is a lowercase hashtag, linked to posts through PostTag
+type Tag struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement"`
+	Name string `gorm:"type:varchar(50);uniqueIndex;not null"`
+}
+
+type PostTag struct {
+	PostID uint `gorm:"primaryKey"`
+	TagID  uint `gorm:"primaryKey;index"`
+}
+
+// PostMention is a resolved @mention of a user in a post
+type PostMention struct {
+	PostID   uint   `gorm:"primaryKey"`
+	UserID   uint   `gorm:"primaryKey;index"`
+	Username string `gorm:"type:varchar(30);not null"`
+}
+
+// ParseEntities finds hashtags and mentions in text, in order of appearance.
+// Text is the lowercase tag or username without the # or @ prefix.
+func ParseEntities(text string) []TextEntity {
+	var entities []TextEntity
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' && runes[i] != '@' {
+			continue
+		}
+		// "a#b" or "mail@host" are not entities
+		if i > 0 && isWordRune(runes[i-1]) {
+			continue
+		}
+
+		j := i + 1
+		if runes[i] == '#' {
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			word := string(runes[i+1 : j])
+			if j-i-1 >= 1 && j-i-1 <= maxHashtagLen && !isDigits(word) {
+				entities = append(entities, TextEntity{Type: EntityHashtag, Text: strings.ToLower(word), Start: i, End: j})
+			}
+		} else {
+			for j < len(runes) && isHandleRune(runes[j]) {
+				j++
+			}
+			// A handle directly followed by other word runes is not a mention
+			if j < len(runes) && isWordRune(runes[j]) {
+				i = j
+				continue
+			}
+			if n := j - i - 1; n >= minMentionLen && n <= maxMentionLen {
+				entities = append(entities, TextEntity{Type: EntityMention, Text: strings.ToLower(string(runes[i+1 : j])), Start: i, End: j})
+			}
+		}
+		i = j - 1
+	}
+	return entities
+}
+
+// Hashtags returns the distinct tags of the given entities
+func Hashtags(entities []TextEntity) []string {
+	return distinctText(entities, EntityHashtag)
+}
+
+// Mentions returns the distinct usernames of the given entities
+func Mentions(entities []TextEntity) []string {
+	return distinctText(entities, EntityMention)
+}
+
+func distinctText(entities []TextEntity, kind string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, e := range entities {
+		if e.Type == kind && !seen[e.Text] {
+			seen[e.Text] = true
+			out = append(out, e.Text)
+		}
+	}
+	return out
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isHandleRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// FirstURL returns the first http or https URL in text. Punctuation closing a sentence or
+// brackets around the URL is not part of it.
+func FirstURL(text string) string {
+	for _, word := range strings.Fields(text) {
+		lower := strings.ToLower(word)
+		start := strings.Index(lower, "https://")
+		if i := strings.Index(lower, "http://"); i >= 0 && (start < 0 || i < start) {
+			start = i
+		}
+		if start < 0 {
+			continue
+		}
+
+		raw := strings.TrimRightFunc(word[start:], func(r rune) bool {
+			return strings.ContainsRune(".,;:!?'\"<>]}", r)
+		})
+		if strings.HasSuffix(raw, ")") && !strings.Contains(raw, "(") {
+			raw = strings.TrimRight(raw, ")")
+		}
+		if len(raw) > linkpreview.MaxURLLength {
+			continue
+		}
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			return raw
+		}
+	}
+	return ""
+}
@@ -1,16 +1,131 @@
 package post
 
 import (
+	"time"
+
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/poll"
 	"starter-gofiber/internal/domain/user"
 
 	"gorm.io/gorm"
 )
 
+// Post statuses, only published posts are listed publicly
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+)
+
+// Moderation states, flagged posts are held back until a moderator reviews them
+const (
+	ModerationVisible = "visible"
+	ModerationFlagged = "flagged"
+	ModerationHidden  = "hidden"
+)
+
+// Visibilities decide who sees a live post besides its author. Mentioned users see every post but a private one.
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityMentioned = "mentioned"
+	VisibilityPrivate   = "private"
+)
+
 type Post struct {
-	ID     uint    `gorm:"primaryKey;autoIncrement"`
-	Tweet  string  `gorm:"type:varchar(500)"`
-	Photo  *string `gorm:"type:varchar(150)"`
-	UserID uint
+	ID     uint       `gorm:"primaryKey;autoIncrement"`
+	Tweet  string     `gorm:"type:varchar(500)"`
+	Photo  *string    `gorm:"type:varchar(150)"`
+	UserID uint       `gorm:"uniqueIndex:idx_post_user_slug,priority:1"`
 	User   *user.User `gorm:"foreignKey:UserID"`
+	// Title, Slug and Body are set for long-form posts. The slug is derived from the first
+	// title and kept on later edits so links stay valid.
+	Title *string `gorm:"type:varchar(200)"`
+	Slug  *string `gorm:"type:varchar(100);uniqueIndex:idx_post_user_slug,priority:2"`
+	// Body is Markdown, the other fields are rendered from it whenever it changes
+	Body        string `gorm:"type:text"`
+	BodyHTML    string `gorm:"type:text"`
+	Excerpt     string `gorm:"type:varchar(300)"`
+	ReadingTime int    `gorm:"not null;default:0"`
+	// QuotedPostID makes the post a quote of another one. It is only set on creation, so a quote
+	// always refers to an older post and quotes cannot form a cycle.
+	QuotedPostID *uint `gorm:"index"`
+	QuotedPost   *Post `gorm:"foreignKey:QuotedPostID"`
+	// LinkPreview is the card of the first URL in Tweet or Body, set by the unfurl job
+	LinkPreviewID *uint                    `gorm:"index"`
+	LinkPreview   *linkpreview.LinkPreview `gorm:"foreignKey:LinkPreviewID"`
+	// Mentions are the users resolved from @handles in Tweet
+	Mentions []PostMention `gorm:"foreignKey:PostID"`
+	// Attachments are shown in Position order
+	Attachments []Attachment `gorm:"foreignKey:PostID"`
+	// Poll is created with the post and cannot be changed afterwards
+	Poll   *poll.Poll `gorm:"foreignKey:PostID"`
+	Status string     `gorm:"type:varchar(20);not null;default:published;index"`
+	// PublishAt is when a scheduled post goes live, and once published when it did
+	PublishAt       *time.Time `gorm:"index"`
+	ModerationState string     `gorm:"type:varchar(20);not null;default:visible;index"`
+	Visibility      string     `gorm:"type:varchar(20);not null;default:public;index"`
+	// EditedAt is set by the first edit after the post went live, older content is kept as Revisions
+	EditedAt *time.Time
+	// Denormalised counters, maintained by their own modules
+	CommentCount int `gorm:"not null;default:0"`
+	RepostCount  int `gorm:"not null;default:0"`
+	QuoteCount   int `gorm:"not null;default:0"`
 	gorm.Model
 }
+
+// IsLongForm reports whether the post is an article with a title and a Markdown body
+func (p Post) IsLongForm() bool {
+	return p.Title != nil && *p.Title != ""
+}
+
+// LinkURL returns the URL the post shows a preview for, the first one in Tweet or else in Body
+func (p Post) LinkURL() string {
+	if u := FirstURL(p.Tweet); u != "" {
+		return u
+	}
+	return FirstURL(p.Body)
+}
+
+// IsQuote reports whether the post quotes another post
+func (p Post) IsQuote() bool {
+	return p.QuotedPostID != nil
+}
+
+// PublishedScore is when the post went live in Unix microseconds, the order of timelines.
+// Posts from before scheduling have no publish time and use their creation time.
+func (p Post) PublishedScore() int64 {
+	if p.PublishAt != nil {
+		return p.PublishAt.UnixMicro()
+	}
+	return p.CreatedAt.UnixMicro()
+}
+
+// TimelineEntry places a post on a timeline, newest first by PublishedScore then by ID
+type TimelineEntry struct {
+	ID    uint
+	Score int64
+}
+
+// OlderThan reports whether e comes after o on a timeline
+func (e TimelineEntry) OlderThan(o TimelineEntry) bool {
+	return e.Score < o.Score || (e.Score == o.Score && e.ID < o.ID)
+}
+
+// IsPublic reports whether the post is live, i.e. published and not held back by moderation.
+// Who sees a live post is up to its Visibility, authors always see their own.
+func (p Post) IsPublic() bool {
+	return p.Status == StatusPublished && (p.ModerationState == ModerationVisible || p.ModerationState == "")
+}
+
+// DefaultVisibility maps the author's profile_visibility preference to the visibility of new posts
+func DefaultVisibility(profileVisibility string) string {
+	switch profileVisibility {
+	case "friends":
+		return VisibilityFollowers
+	case "private":
+		return VisibilityPrivate
+	default:
+		return VisibilityPublic
+	}
+}
@@ -0,0 +1,126 @@
+package post
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"starter-gofiber/pkg/validator"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+const (
+	MaxTitleLength = 200
+	MaxBodyLength  = 50000
+
+	excerptLength  = 200
+	wordsPerMinute = 200
+	maxSlugLength  = 80
+)
+
+// markdown renders GitHub flavoured Markdown, raw HTML in the source is left out
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// RenderMarkdown converts a Markdown body to HTML sanitised with the UGC policy
+func RenderMarkdown(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	return validator.SanitizeHTML(buf.String()), nil
+}
+
+// RenderBody fills BodyHTML, Excerpt and ReadingTime from Body
+func (p *Post) RenderBody() error {
+	if p.Body == "" {
+		p.BodyHTML, p.Excerpt, p.ReadingTime = "", "", 0
+		return nil
+	}
+
+	rendered, err := RenderMarkdown(p.Body)
+	if err != nil {
+		return err
+	}
+	text := PlainText(rendered)
+	p.BodyHTML = rendered
+	p.Excerpt = Excerpt(text, excerptLength)
+	p.ReadingTime = ReadingTime(text)
+	return nil
+}
+
+// PlainText strips the tags of rendered HTML, collapsing whitespace
+func PlainText(rendered string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(validator.SanitizeInput(rendered))), " ")
+}
+
+// Excerpt shortens text to at most n runes, cutting at a word boundary and marking the cut with an ellipsis
+func Excerpt(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	cut := string([]rune(text)[:n])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + "…"
+}
+
+// ReadingTime estimates the minutes needed to read text, at least one for any text
+func ReadingTime(text string) int {
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return 0
+	}
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// Slugify turns a title into lowercase words joined by hyphens, "post" when no letter or digit is left
+func Slugify(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			if b.Len()+utf8.RuneLen(r) > maxSlugLength {
+				return finishSlug(b.String())
+			}
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+	return finishSlug(b.String())
+}
+
+// UniqueSlug slugifies title and numbers repeats ("title-2", "title-3", ...) until taken reports a free slug
+func UniqueSlug(title string, taken func(slug string) (bool, error)) (string, error) {
+	base := Slugify(title)
+	slug := base
+	for n := 2; ; n++ {
+		used, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+func finishSlug(slug string) string {
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "post"
+	}
+	return slug
+}
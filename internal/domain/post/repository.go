@@ -1,11 +1,81 @@
 package post
 
-// Repository defines the interface for post repository operations
+import (
+	"time"
+
+	"starter-gofiber/pkg/pagination"
+)
+
+// Repository defines the interface for post repository operations.
+// Listings only return published posts that moderation did not flag or hide and whose visibility
+// shares them with viewerID (0 for anonymous viewers). Posts loaded for a viewer come with the post they
+// quote while the viewer may open it. FindByID returns a post in any state, without the quoted post.
 type Repository interface {
+	// Create stores the post and counts it on the post it quotes
 	Create(post *Post) error
 	FindByID(id uint) (*Post, error)
-	FindAll(limit, offset int) ([]Post, int64, error)
+	FindAll(viewerID uint, limit, offset int) ([]Post, int64, error)
+	// FindPage lists posts matching the filter in the page's sort order, with one look-ahead row past the page
+	FindPage(filter ListFilter, viewerID uint, page pagination.CursorPagination) ([]Post, error)
+	// FindVisibleByID returns the post when the viewer may open it, their own in any state
+	FindVisibleByID(id, viewerID uint) (*Post, error)
+	// FindVisibleBySlug returns the oldest post with the slug the viewer may open, of any author when authorID is 0
+	FindVisibleBySlug(slug string, authorID, viewerID uint) (*Post, error)
+	// SlugTaken reports whether the author used the slug before, deleted posts included
+	SlugTaken(userID uint, slug string) (bool, error)
 	Update(post *Post) error
 	Delete(id uint) error
-	FindByUserID(userID uint, limit, offset int) ([]Post, int64, error)
+	FindByUserID(userID, viewerID uint, limit, offset int) ([]Post, int64, error)
+	// FindByIDs loads published posts by ID, missing IDs are skipped
+	FindByIDs(ids []uint, viewerID uint) ([]Post, error)
+	// FindTimeline returns up to limit posts of the given authors older than before (nil for the newest), newest first
+	FindTimeline(userIDs []uint, viewerID uint, before *TimelineEntry, limit int) ([]TimelineEntry, error)
+	// FindUnpublishedByUserID lists the author's drafts and scheduled posts, next to go live first
+	FindUnpublishedByUserID(userID uint, limit, offset int) ([]Post, int64, error)
+	// Publish marks a scheduled post as published at the given time, false when it is no longer scheduled
+	Publish(id uint, at time.Time) (bool, error)
+	// SetModerationState shows, flags or hides a post, edits by the author keep the state
+	SetModerationState(id uint, state string) error
+
+	// SyncTags replaces the post's hashtags, creating missing tags
+	SyncTags(postID uint, names []string) error
+	// SyncMentions replaces the post's mentions and returns the ones that were not there before
+	SyncMentions(postID uint, mentions []PostMention) ([]PostMention, error)
+	// FindByTag returns posts carrying the tag, with one look-ahead row past the page
+	FindByTag(name string, viewerID uint, page pagination.CursorPagination) ([]Post, error)
+
+	CreateAttachment(a *Attachment) error
+	// FindAttachmentsByIDs loads attachments by ID, missing IDs are skipped
+	FindAttachmentsByIDs(ids []uint) ([]Attachment, error)
+	// SetAttachments attaches the IDs to the post in order and detaches the ones it no longer lists
+	SetAttachments(postID uint, ids []uint) error
+	// FindPostAttachments loads every attachment of the post, detached ones included
+	FindPostAttachments(postID uint) ([]Attachment, error)
+	// DeletePostAttachments deletes every attachment of the post, detached ones included, and returns them
+	DeletePostAttachments(postID uint) ([]Attachment, error)
+	DeleteAttachments(ids []uint) error
+
+	CreateRevision(r *Revision) error
+	FindRevision(id uint) (*Revision, error)
+	// FindRevisions returns the post's revisions newest first, with one look-ahead row past the page
+	FindRevisions(postID uint, page pagination.CursorPagination) ([]Revision, error)
+	// DeleteRevisions deletes the post's revisions and returns them
+	DeleteRevisions(postID uint) ([]Revision, error)
+
+	// FindTrashed lists deleted posts, last deleted first, of every author when userID is 0
+	FindTrashed(userID uint, limit, offset int) ([]Post, int64, error)
+	// FindTrashedByID returns the post while it is in the trash
+	FindTrashedByID(id uint) (*Post, error)
+	// FindTrashedBefore returns up to limit posts deleted before the given time, first deleted first
+	FindTrashedBefore(before time.Time, limit int) ([]Post, error)
+	// Restore takes the post out of the trash, counting it again on the post it quotes
+	Restore(id uint) error
+	// ForceDelete permanently deletes the post with every row referencing it. Files are left to the caller.
+	ForceDelete(id uint) error
+	// FindForBulk returns the posts among ids with their owner and schedule, live ones or the ones in the trash
+	FindForBulk(ids []uint, trashed bool) ([]Post, error)
+	// BulkDelete moves the posts to the trash at once, returning how many were deleted
+	BulkDelete(ids []uint) (int, error)
+	// BulkRestore takes the posts out of the trash at once like Restore, returning how many were restored
+	BulkRestore(ids []uint) (int, error)
 }
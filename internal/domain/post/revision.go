@@ -0,0 +1,169 @@
+package post
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+)
+
+// Revision is the content of a post before an edit replaced it
+type Revision struct {
+	ID     uint `gorm:"primaryKey;autoIncrement"`
+	PostID uint `gorm:"index;not null"`
+	// UserID is the editor, the author or a moderator restoring an older revision
+	UserID uint       `gorm:"index;not null"`
+	User   *user.User `gorm:"foreignKey:UserID"`
+	Tweet  string     `gorm:"type:varchar(500)"`
+	Photo  *string    `gorm:"type:varchar(150)"`
+	Title  *string    `gorm:"type:varchar(200)"`
+	Body   string     `gorm:"type:text"`
+	// AttachmentIDs is the JSON list of attachments in display order, removed
+	// attachments stay with the post detached so they can be restored
+	AttachmentIDs string `gorm:"type:text"`
+	// CreatedAt is when the edit happened
+	CreatedAt time.Time
+}
+
+func (Revision) TableName() string {
+	return "post_revisions"
+}
+
+// Attachments decodes AttachmentIDs
+func (r Revision) Attachments() []uint {
+	var ids []uint
+	_ = json.Unmarshal([]byte(r.AttachmentIDs), &ids)
+	return ids
+}
+
+// NewRevision snapshots the current content of a post
+func NewRevision(p Post, editorID uint) Revision {
+	ids := make([]uint, len(p.Attachments))
+	for i, a := range p.Attachments {
+		ids[i] = a.ID
+	}
+	encoded, _ := json.Marshal(ids)
+	return Revision{
+		PostID:        p.ID,
+		UserID:        editorID,
+		Tweet:         p.Tweet,
+		Photo:         p.Photo,
+		Title:         p.Title,
+		Body:          p.Body,
+		AttachmentIDs: string(encoded),
+	}
+}
+
+type RevisionResponse struct {
+	ID          uint                 `json:"id"`
+	PostID      uint                 `json:"post_id"`
+	EditorID    uint                 `json:"editor_id"`
+	Editor      *user.UserResponse   `json:"editor"`
+	Tweet       string               `json:"tweet"`
+	Photo       *string              `json:"photo"`
+	Title       *string              `json:"title"`
+	Body        string               `json:"body"`
+	Attachments []AttachmentResponse `json:"attachments"`
+	// Diff turns Tweet into the text of the version that replaced it
+	Diff     []DiffSegment `json:"diff"`
+	EditedAt string        `json:"edited_at"`
+}
+
+// FromEntity renders a revision, attachments holds the post's attachments by ID
+func (r RevisionResponse) FromEntity(rev Revision, attachments map[uint]Attachment, replacedBy string) RevisionResponse {
+	r.ID = rev.ID
+	r.PostID = rev.PostID
+	r.EditorID = rev.UserID
+	if rev.User != nil {
+		usr := user.UserResponse{}.FromEntity(*rev.User)
+		r.Editor = &usr
+	}
+	r.Tweet = rev.Tweet
+	r.Title = rev.Title
+	r.Body = rev.Body
+	if rev.Photo != nil && *rev.Photo != "" {
+		path := variables.GenerateStatic([]string{variables.POST_PATH, *rev.Photo})
+		r.Photo = &path
+	}
+	r.Attachments = make([]AttachmentResponse, 0)
+	for _, id := range rev.Attachments() {
+		if a, ok := attachments[id]; ok {
+			r.Attachments = append(r.Attachments, AttachmentResponse{}.FromEntity(a))
+		}
+	}
+	r.Diff = DiffText(rev.Tweet, replacedBy)
+	r.EditedAt = rev.CreatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
+
+// Diff operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffSegment is a run of text that was kept, inserted or deleted
+type DiffSegment struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// diffToken is a word or a run of whitespace
+var diffToken = regexp.MustCompile(`\S+|\s+`)
+
+// DiffText compares two texts word by word, concatenating the equal and deleted
+// segments gives from and the equal and inserted segments gives to
+func DiffText(from, to string) []DiffSegment {
+	a := diffToken.FindAllString(from, -1)
+	b := diffToken.FindAllString(to, -1)
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	segments := []DiffSegment{}
+	add := func(op, text string) {
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, DiffSegment{Op: op, Text: text})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(DiffDelete, a[i])
+			i++
+		default:
+			add(DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(DiffInsert, b[j])
+	}
+	return segments
+}
@@ -1,13 +1,51 @@
 package post
 
+import (
+	"mime/multipart"
+
+	"starter-gofiber/pkg/pagination"
+)
+
 // Service defines the interface for post service operations
 type Service interface {
 	Create(req *PostRequest, userID uint) (*PostResponse, error)
-	FindByID(id uint) (*PostResponse, error)
-	FindAll(page, limit int) ([]PostResponse, *PaginationMeta, error)
+	// FindByID returns a live post whose visibility shares it with the viewer, or any post of the
+	// viewer's own. viewerID is 0 for anonymous viewers, here and in the listings below.
+	FindByID(id uint, viewerID uint) (*PostResponse, error)
+	// FindBySlug returns a long-form post by slug, author is an optional username for slugs used by several authors
+	FindBySlug(slug, author string, viewerID uint) (*PostResponse, error)
+	FindAll(viewerID uint, page, limit int) ([]PostResponse, *PaginationMeta, error)
+	// List returns the posts matching the filter, sorted by one of SortFields and paginated by cursor
+	List(filter ListFilter, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
 	Update(id uint, req *PostUpdateRequest, userID uint) (*PostResponse, error)
-	Delete(id uint, userID uint) error
-	FindByUserID(userID uint, page, limit int) ([]PostResponse, *PaginationMeta, error)
+	// Delete moves a post to the trash, moderators may delete any post
+	Delete(id uint, userID uint, moderator bool) error
+	// FindTrashed lists the user's deleted posts, of every user when userID is 0
+	FindTrashed(userID uint, page, limit int) ([]TrashedPostResponse, *PaginationMeta, error)
+	// Restore takes a post out of the trash, moderators may restore any post
+	Restore(id uint, userID uint, moderator bool) (*PostResponse, error)
+	// PermanentDelete purges a post in the trash with its files, moderators may purge any post
+	PermanentDelete(id uint, userID uint, moderator bool) error
+	// Bulk creates, updates, deletes or restores a batch of the user's own posts, reporting each item.
+	// Batches larger than BulkSyncLimit are queued, the result then carries the job to follow.
+	Bulk(req *BulkRequest, userID uint) (*BulkResult, error)
+	// RunBulk runs a batch right away, calling progress after each item when it is not nil
+	RunBulk(req *BulkRequest, userID uint, progress func(done, total int)) *BulkResult
+	// BulkJob returns the state of a queued batch of the user, with its result once completed
+	BulkJob(jobID string, userID uint) (*BulkResult, error)
+	FindByUserID(userID, viewerID uint, page, limit int) ([]PostResponse, *PaginationMeta, error)
+	// FindUnpublished lists the user's drafts and scheduled posts
+	FindUnpublished(userID uint, page, limit int) ([]PostResponse, *PaginationMeta, error)
+	// FindByTag returns the posts carrying a hashtag, newest first
+	FindByTag(tag string, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+	// ListRevisions returns the earlier versions of a post newest first, each with the diff of the edit that replaced it
+	ListRevisions(postID uint, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+	// RestoreRevision brings back an earlier version, recording the current one as a revision. Moderators may restore any post.
+	RestoreRevision(postID, revisionID uint, userID uint, moderator bool) (*PostResponse, error)
+	// UploadAttachment validates and processes a file into an attachment waiting to be used by a post
+	UploadAttachment(file *multipart.FileHeader, alt string, userID uint) (*AttachmentResponse, error)
+	// DeleteAttachment removes an upload of the user that no post uses yet
+	DeleteAttachment(id uint, userID uint) error
 }
 
 // PaginationMeta represents pagination metadata
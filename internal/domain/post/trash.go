@@ -0,0 +1,38 @@
+package post
+
+import (
+	"time"
+
+	"starter-gofiber/variables"
+)
+
+// trashRetention is how long deleted posts can be restored before they are purged with their files
+var trashRetention = 30 * 24 * time.Hour
+
+// SetTrashRetention sets the days deleted posts stay in the trash (0 keeps the default)
+func SetTrashRetention(days int) {
+	if days > 0 {
+		trashRetention = time.Duration(days) * 24 * time.Hour
+	}
+}
+
+// TrashRetention returns how long deleted posts stay in the trash
+func TrashRetention() time.Duration {
+	return trashRetention
+}
+
+// TrashedPostResponse is a deleted post with when it will be purged
+type TrashedPostResponse struct {
+	PostResponse
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"`
+}
+
+func (r TrashedPostResponse) FromEntity(p Post) TrashedPostResponse {
+	r.PostResponse = PostResponse{}.FromEntity(p)
+	if p.DeletedAt.Valid {
+		r.DeletedAt = p.DeletedAt.Time.Format(variables.FORMAT_TIME)
+		r.PurgeAt = p.DeletedAt.Time.Add(trashRetention).Format(variables.FORMAT_TIME)
+	}
+	return r
+}
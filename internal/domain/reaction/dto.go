@@ -0,0 +1,35 @@
+package reaction
+
+import (
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+)
+
+type ReactRequest struct {
+	Type string `json:"type" validate:"required,oneof=like love haha wow sad angry"`
+}
+
+// Summary is the reaction state of a post as seen by one viewer
+type Summary struct {
+	Counts     map[Type]int `json:"counts"`
+	Total      int          `json:"total"`
+	MyReaction *Type        `json:"my_reaction"`
+}
+
+type ReactorResponse struct {
+	UserID    uint               `json:"user_id"`
+	User      *user.UserResponse `json:"user,omitempty"`
+	Type      Type               `json:"type"`
+	ReactedAt string             `json:"reacted_at"`
+}
+
+func (r ReactorResponse) FromEntity(e Reaction) ReactorResponse {
+	r.UserID = e.UserID
+	r.Type = e.Type
+	r.ReactedAt = e.UpdatedAt.Format(variables.FORMAT_TIME)
+	if e.User != nil {
+		usr := user.UserResponse{}.FromEntity(*e.User)
+		r.User = &usr
+	}
+	return r
+}
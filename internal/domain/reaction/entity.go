@@ -0,0 +1,53 @@
+package reaction
+
+import (
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+)
+
+type Type string
+
+const (
+	TypeLike  Type = "like"
+	TypeLove  Type = "love"
+	TypeHaha  Type = "haha"
+	TypeWow   Type = "wow"
+	TypeSad   Type = "sad"
+	TypeAngry Type = "angry"
+)
+
+// Types is the fixed set of reactions a user can give
+var Types = []Type{TypeLike, TypeLove, TypeHaha, TypeWow, TypeSad, TypeAngry}
+
+// Redis keys for the hot counters, shared with the reconciliation job
+const DirtyKey = "reaction:dirty"
+
+// CounterKey is the Redis hash holding per-type counts of a post
+func CounterKey(postID uint) string {
+	return fmt.Sprintf("reaction:count:%d", postID)
+}
+
+// Reaction is a single user's reaction to a post, a user has at most one per post
+type Reaction struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	PostID    uint       `gorm:"not null;uniqueIndex:idx_reaction_post_user;index:idx_reaction_post_type,priority:1"`
+	UserID    uint       `gorm:"not null;uniqueIndex:idx_reaction_post_user"`
+	Type      Type       `gorm:"type:varchar(20);not null;index:idx_reaction_post_type,priority:2"`
+	User      *user.User `gorm:"foreignKey:UserID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Count is the reconciled per-type counter persisted from Redis
+type Count struct {
+	PostID    uint `gorm:"primaryKey;autoIncrement:false"`
+	Type      Type `gorm:"primaryKey;type:varchar(20)"`
+	Count     int  `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+func (Count) TableName() string {
+	return "reaction_counts"
+}
@@ -0,0 +1,19 @@
+package reaction
+
+import "starter-gofiber/pkg/pagination"
+
+// Repository defines the interface for reaction repository operations
+type Repository interface {
+	// Set stores the user's reaction and returns the type it replaced, if any
+	Set(reaction *Reaction) (*Type, error)
+	// Remove deletes the user's reaction and returns its type, nil when there was none
+	Remove(postID, userID uint) (*Type, error)
+	FindByUser(postIDs []uint, userID uint) ([]Reaction, error)
+	FindReactors(postID uint, reactionType Type, page pagination.CursorPagination) ([]Reaction, error)
+	// CountByPosts counts reactions straight from the reactions table
+	CountByPosts(postIDs []uint) ([]Count, error)
+	// FindCounts returns the reconciled counters from reaction_counts
+	FindCounts(postIDs []uint) ([]Count, error)
+	// SaveCounts replaces the reconciled counters of the given posts
+	SaveCounts(postIDs []uint, counts []Count) error
+}
@@ -0,0 +1,12 @@
+package reaction
+
+import "starter-gofiber/pkg/pagination"
+
+// Service defines the interface for reaction service operations
+type Service interface {
+	React(postID uint, req *ReactRequest, userID uint) (*Summary, error)
+	Unreact(postID uint, userID uint) (*Summary, error)
+	// Summaries returns the reaction summary of each post, viewerID 0 for anonymous viewers
+	Summaries(postIDs []uint, viewerID uint) (map[uint]Summary, error)
+	Reactors(postID, viewerID uint, reactionType string, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+}
@@ -0,0 +1,23 @@
+package repost
+
+import (
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/variables"
+)
+
+// RepostResponse is a repost with the reposted post as the viewer sees it
+type RepostResponse struct {
+	ID         uint               `json:"id"`
+	UserID     uint               `json:"user_id"`
+	PostID     uint               `json:"post_id"`
+	Post       *post.PostResponse `json:"post"`
+	RepostedAt string             `json:"reposted_at"`
+}
+
+func (r RepostResponse) FromEntity(e Repost) RepostResponse {
+	r.ID = e.ID
+	r.UserID = e.UserID
+	r.PostID = e.PostID
+	r.RepostedAt = e.CreatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
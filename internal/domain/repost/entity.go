@@ -0,0 +1,11 @@
+package repost
+
+import "time"
+
+// Repost shares another post on the reposter's profile as it is, a user reposts a post at most once
+type Repost struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_repost_user_post"`
+	PostID    uint `gorm:"not null;uniqueIndex:idx_repost_user_post;index"`
+	CreatedAt time.Time
+}
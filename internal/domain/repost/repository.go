@@ -0,0 +1,15 @@
+package repost
+
+import "starter-gofiber/pkg/pagination"
+
+// Repository defines the interface for repost repository operations.
+// Saving and removing reposts keeps the repost_count of the post in step.
+type Repository interface {
+	// Save stores the repost, false with the existing one in r when the user already reposted the post
+	Save(r *Repost) (bool, error)
+	// Remove deletes the user's repost of the post, false when there was none
+	Remove(postID, userID uint) (bool, error)
+	// FindByUser returns the user's reposts of live posts shared with the viewer, newest first with one
+	// look-ahead row past the page
+	FindByUser(userID, viewerID uint, page pagination.CursorPagination) ([]Repost, error)
+}
@@ -0,0 +1,13 @@
+package repost
+
+import "starter-gofiber/pkg/pagination"
+
+// Service defines the interface for repost service operations
+type Service interface {
+	// Repost shares a live post the user may see, reposting it again is a no-op
+	Repost(postID uint, userID uint) (*RepostResponse, error)
+	// Unrepost removes the user's repost, removing one that does not exist is a no-op
+	Unrepost(postID uint, userID uint) error
+	// ListByUser returns a user's reposts of posts the viewer may see, newest repost first. viewerID is 0 for anonymous viewers.
+	ListByUser(userID, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error)
+}
@@ -0,0 +1,31 @@
+package search
+
+import "starter-gofiber/internal/domain/post"
+
+// SearchRequest is bound from the /api/search query string
+type SearchRequest struct {
+	Q        string `query:"q" validate:"required,min=2,max=200"`
+	Type     string `query:"type"` // Comma separated document types, empty for all
+	AuthorID uint   `query:"author_id"`
+	Since    string `query:"since"` // RFC 3339 or YYYY-MM-DD
+	Until    string `query:"until"`
+	Sort     string `query:"sort" validate:"omitempty,oneof=relevance recent"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit"`
+}
+
+type Result struct {
+	Type      string             `json:"type"`
+	Score     float64            `json:"score"`
+	Highlight string             `json:"highlight"`
+	Post      *post.PostResponse `json:"post,omitempty"`
+	User      *UserResult        `json:"user,omitempty"`
+}
+
+// UserResult is the public part of a matching user profile
+type UserResult struct {
+	ID       uint    `json:"id"`
+	Name     string  `json:"name"`
+	Username *string `json:"username"`
+	Avatar   string  `json:"avatar,omitempty"`
+}
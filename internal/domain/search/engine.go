@@ -0,0 +1,15 @@
+package search
+
+// Engine is a full-text index backend
+type Engine interface {
+	// Name identifies the backend, e.g. "postgres", "sqlite" or "bleve"
+	Name() string
+	// Index adds or replaces documents
+	Index(docs ...Document) error
+	// Delete removes documents, unknown IDs are ignored
+	Delete(docType string, ids ...uint) error
+	// Search returns ranked hits for the query window
+	Search(q Query) ([]Hit, error)
+	// Reset removes every document, used before a full reindex
+	Reset() error
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+)
+
+const (
+	TypePost = "post"
+	TypeUser = "user"
+
+	SortRelevance = "relevance"
+	SortRecent    = "recent"
+
+	// MaxOffset bounds how deep ranked results can be paged
+	MaxOffset = 1000
+
+	// Engines mark matched terms with these bytes, HighlightHTML turns them into <mark> tags
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// Types lists the searchable document types
+var Types = []string{TypePost, TypeUser}
+
+// Document is the searchable projection of a post or user
+type Document struct {
+	Type      string
+	ID        uint
+	UserID    uint // Author of a post, the user itself for users
+	Body      string
+	CreatedAt time.Time
+}
+
+// Query is a full-text query with optional filters
+type Query struct {
+	Text     string
+	Types    []string
+	AuthorID uint
+	Since    *time.Time
+	Until    *time.Time
+	Sort     string
+	Offset   int
+	Limit    int
+}
+
+// Hit is a matching document, Highlight is HTML-escaped with matches in <mark>
+type Hit struct {
+	Type      string
+	ID        uint
+	Score     float64
+	Highlight string
+}
+
+// PostDocument indexes the tweet and, for long-form posts, the title and the text of the body
+func PostDocument(p post.Post) Document {
+	parts := []string{p.Tweet}
+	if p.IsLongForm() {
+		parts = append(parts, *p.Title, post.PlainText(p.BodyHTML))
+	}
+	return Document{
+		Type:      TypePost,
+		ID:        p.ID,
+		UserID:    p.UserID,
+		Body:      strings.TrimSpace(strings.Join(parts, "\n")),
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+// UserDocument indexes the public profile only, never the email
+func UserDocument(u user.User) Document {
+	parts := []string{u.Name}
+	if u.Username != nil {
+		parts = append(parts, *u.Username)
+	}
+	if u.Bio != "" {
+		parts = append(parts, u.Bio)
+	}
+	return Document{
+		Type:      TypeUser,
+		ID:        u.ID,
+		UserID:    u.ID,
+		Body:      strings.Join(parts, " "),
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+// HighlightHTML escapes an engine fragment and converts the highlight markers to <mark> tags
+func HighlightHTML(fragment string) string {
+	escaped := html.EscapeString(fragment)
+	return strings.NewReplacer(HighlightStart, "<mark>", HighlightEnd, "</mark>").Replace(escaped)
+}
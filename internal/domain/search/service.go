@@ -0,0 +1,10 @@
+package search
+
+import "starter-gofiber/pkg/pagination"
+
+// Service defines the interface for search service operations
+type Service interface {
+	Search(req *SearchRequest) (*pagination.CursorResponse, error)
+	// Reindex rebuilds the whole index in the background
+	Reindex() error
+}
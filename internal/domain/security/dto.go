@@ -0,0 +1,45 @@
+package security
+
+import (
+	"encoding/json"
+
+	"starter-gofiber/variables"
+)
+
+type EventResponse struct {
+	ID        uint                   `json:"id"`
+	UserID    *uint                  `json:"user_id,omitempty"`
+	Email     string                 `json:"email,omitempty"`
+	Type      string                 `json:"type"`
+	Severity  string                 `json:"severity"`
+	IPAddress string                 `json:"ip_address"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+	Subject   string                 `json:"subject,omitempty"`
+	Country   string                 `json:"country,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt string                 `json:"created_at"`
+}
+
+func (r EventResponse) FromEntity(e Event) EventResponse {
+	r.ID = e.ID
+	r.UserID = e.UserID
+	r.Email = e.Email
+	r.Type = e.Type.String()
+	r.Severity = string(e.Severity)
+	r.IPAddress = e.IPAddress
+	r.UserAgent = e.UserAgent
+	r.Subject = e.Subject
+	r.Country = e.Country
+	if e.Metadata != "" {
+		_ = json.Unmarshal([]byte(e.Metadata), &r.Metadata)
+	}
+	r.CreatedAt = e.CreatedAt.Format(variables.FORMAT_TIME)
+	return r
+}
+
+// AlertPayload is pushed to admins over SSE when an anomaly is detected
+type AlertPayload struct {
+	Event     EventResponse `json:"event"`
+	Message   string        `json:"message"`
+	Timestamp int64         `json:"timestamp"`
+}
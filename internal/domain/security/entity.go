@@ -0,0 +1,86 @@
+package security
+
+import "time"
+
+type EventType string
+
+func (t EventType) String() string {
+	return string(t)
+}
+
+const (
+	EventLoginSuccess     EventType = "login_success"
+	EventLoginFailed      EventType = "login_failed"
+	EventAccountLocked    EventType = "account_locked"
+	EventTokenReuse       EventType = "token_reuse"
+	EventPasswordChanged  EventType = "password_changed"
+	EventPasswordReset    EventType = "password_reset"
+	EventAPIKeyNewIP      EventType = "api_key_new_ip"
+	EventPermissionDenied EventType = "permission_denied"
+
+	// Anomalies raised by the detection rules
+	EventAnomalyIPFailures       EventType = "anomaly_ip_failures"
+	EventAnomalyImpossibleTravel EventType = "anomaly_impossible_travel"
+)
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Event is a security relevant action, kept separate from the data-change AuditLog
+type Event struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    *uint     `gorm:"index"`
+	Email     string    `gorm:"type:varchar(255);index"`
+	Type      EventType `gorm:"type:varchar(50);not null;index"`
+	Severity  Severity  `gorm:"type:varchar(20);not null;index"`
+	IPAddress string    `gorm:"type:varchar(45);index"`
+	UserAgent string    `gorm:"type:text"`
+	Subject   string    `gorm:"type:varchar(255);index"` // e.g. api_key:12 or "POST /api/posts"
+	Country   string    `gorm:"type:varchar(2)"`
+	Latitude  *float64
+	Longitude *float64
+	Metadata  string    `gorm:"type:text"` // JSON encoded details
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+// TableName specifies the table name for Event
+func (Event) TableName() string {
+	return "security_events"
+}
+
+// EventFilter for querying security events
+type EventFilter struct {
+	UserID    *uint
+	Email     string
+	Type      string
+	Severity  string
+	IPAddress string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// Policy configures lockouts and anomaly rules
+type Policy struct {
+	LockoutThreshold     int           // Failed logins per account before lockout
+	LockoutWindow        time.Duration // Window for counting failed logins and lockout duration
+	IPFailureAccounts    int           // Distinct accounts failing from one IP before an alert
+	IPFailureWindow      time.Duration
+	ImpossibleTravelKmh  float64 // Max plausible travel speed between two logins
+	AlertEmailRecipients []string
+}
+
+// DefaultPolicy returns the default security policy
+func DefaultPolicy() Policy {
+	return Policy{
+		LockoutThreshold:    5,
+		LockoutWindow:       15 * time.Minute,
+		IPFailureAccounts:   5,
+		IPFailureWindow:     10 * time.Minute,
+		ImpossibleTravelKmh: 900,
+	}
+}
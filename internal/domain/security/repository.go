@@ -0,0 +1,21 @@
+package security
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+)
+
+// Repository defines the interface for security event repository operations
+type Repository interface {
+	Create(event *Event) error
+	FindAll(filter EventFilter, limit, offset int) ([]Event, int64, error)
+	FindByUserID(userID uint, limit int) ([]Event, error)
+	CountByEmailSince(eventType EventType, email string, since time.Time) (int64, error)
+	FindLatestByEmail(eventType EventType, email string) (*Event, error)
+	CountDistinctEmailsByIPSince(eventType EventType, ip string, since time.Time) (int64, error)
+	ExistsSince(eventType EventType, field, value string, since time.Time) (bool, error)
+	ExistsBySubjectAndIP(eventType EventType, subject, ip string) (bool, error)
+	FindPreviousLocated(userID uint, eventType EventType, beforeID uint) (*Event, error)
+	FindAdmins() ([]user.User, error)
+}
@@ -0,0 +1,10 @@
+package security
+
+// Service defines the interface for security event operations
+type Service interface {
+	Record(event *Event)
+	IsLocked(email string) bool
+	RecordAPIKeyUse(keyID, userID uint, ipAddress, userAgent string)
+	Query(filter EventFilter, page, limit int) ([]EventResponse, int64, error)
+	RecentActivity(userID uint, limit int) ([]EventResponse, error)
+}
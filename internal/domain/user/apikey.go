@@ -13,6 +13,10 @@ type APIKey struct {
 	CreatedAt  time.Time  `gorm:"type:timestamp;autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time  `gorm:"type:timestamp;autoUpdateTime" json:"updated_at"`
 
+	// HMAC request signing (secret is stored encrypted)
+	SigningSecret    string `gorm:"size:255" json:"-"`
+	RequireSignature bool   `gorm:"default:false" json:"require_signature"`
+
 	// Relationships
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
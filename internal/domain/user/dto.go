@@ -5,15 +5,19 @@ import (
 )
 
 type UserClaims struct {
-	ID    uint   `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID            uint   `json:"id"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	RegisteredAt  int64  `json:"registered_at"`
 }
 
 func (r UserClaims) FromEntity(u User) UserClaims {
 	r.ID = u.ID
 	r.Email = u.Email
 	r.Role = u.Role.String()
+	r.EmailVerified = u.EmailVerified
+	r.RegisteredAt = u.CreatedAt.Unix()
 	return r
 }
 
@@ -27,6 +31,12 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required;email"`
 	Password string `json:"password" binding:"required;min=6"`
 	Role     string `json:"role" binding:"oneof=admin user;default:user"`
+	// Optional @handle, 3-30 letters, digits or underscores
+	Username string `json:"username"`
+	// Current ToS and privacy policy versions the user accepts, see GET /api/consent/current
+	TosVersion     string `json:"tos_version"`
+	PrivacyVersion string `json:"privacy_version"`
+	MarketingOptIn bool   `json:"marketing_opt_in"`
 }
 
 func (r RegisterRequest) ToEntity() User {
@@ -39,19 +49,23 @@ func (r RegisterRequest) ToEntity() User {
 }
 
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID            uint    `json:"id"`
+	Name          string  `json:"name"`
+	Username      *string `json:"username"`
+	Email         string  `json:"email"`
+	Role          string  `json:"role"`
+	EmailVerified bool    `json:"email_verified"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
 }
 
 func (r UserResponse) FromEntity(u User) UserResponse {
 	r.ID = u.ID
 	r.Name = u.Name
+	r.Username = u.Username
 	r.Email = u.Email
 	r.Role = u.Role.String()
+	r.EmailVerified = u.EmailVerified
 	r.CreatedAt = u.CreatedAt.Format(variables.FORMAT_TIME)
 	r.UpdatedAt = u.UpdatedAt.Format(variables.FORMAT_TIME)
 	return r
@@ -63,6 +77,12 @@ type LoginResponse struct {
 	RefreshToken string       `json:"refresh_token"`
 }
 
+// SessionLoginResponse is returned by cookie-session login, tokens stay server-side
+type SessionLoginResponse struct {
+	User      UserResponse `json:"user"`
+	CSRFToken string       `json:"csrf_token"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
@@ -115,21 +135,23 @@ func (r SessionResponse) FromEntity(t RefreshToken) SessionResponse {
 
 // Profile DTOs
 type GetProfileResponse struct {
-	ID            uint   `json:"id"`
-	Name          string `json:"name"`
-	Email         string `json:"email"`
-	Role          string `json:"role"`
-	Avatar        string `json:"avatar,omitempty"`
-	Bio           string `json:"bio,omitempty"`
-	EmailVerified bool   `json:"email_verified"`
-	CreatedAt     string `json:"created_at"`
-	UpdatedAt     string `json:"updated_at"`
+	ID            uint    `json:"id"`
+	Name          string  `json:"name"`
+	Username      *string `json:"username"`
+	Email         string  `json:"email"`
+	Role          string  `json:"role"`
+	Avatar        string  `json:"avatar,omitempty"`
+	Bio           string  `json:"bio,omitempty"`
+	EmailVerified bool    `json:"email_verified"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
 }
 
 func (r GetProfileResponse) FromEntity(u User) GetProfileResponse {
 	return GetProfileResponse{
 		ID:            u.ID,
 		Name:          u.Name,
+		Username:      u.Username,
 		Email:         u.Email,
 		Role:          u.Role.String(),
 		Avatar:        u.Avatar,
@@ -141,8 +163,9 @@ func (r GetProfileResponse) FromEntity(u User) GetProfileResponse {
 }
 
 type UpdateProfileRequest struct {
-	Name string `json:"name" binding:"omitempty,min=3"`
-	Bio  string `json:"bio" binding:"omitempty,max=500"`
+	Name     string `json:"name" binding:"omitempty,min=3"`
+	Bio      string `json:"bio" binding:"omitempty,max=500"`
+	Username string `json:"username"`
 }
 
 type UpdateAvatarRequest struct {
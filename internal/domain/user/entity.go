@@ -1,6 +1,8 @@
 package user
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -25,5 +27,11 @@ type User struct {
 	Bio           string `gorm:"type:text;default:null"`         // User bio/description
 	// Role     UserRole `gorm:"type:varchar(10);default:user"` // for sql server only
 	Role UserRole `gorm:"type:user_role;default:user"` // for mysql and postgres
+	// VerificationRemindedAt is when the last verification reminder email was sent
+	VerificationRemindedAt *time.Time `gorm:"default:null"`
+	// Username is the optional lowercase @handle used for mentions
+	Username *string `gorm:"type:varchar(30);uniqueIndex"`
+	// SuspendedAt is set by moderators, suspended users can no longer sign in
+	SuspendedAt *time.Time `gorm:"default:null"`
 	gorm.Model
 }
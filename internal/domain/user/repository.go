@@ -8,10 +8,15 @@ type Repository interface {
 	FindByEmail(email string) (*User, error)
 	FindByID(id uint) (*User, error)
 	Update(user *User) error
+	// FindByUsernames resolves lowercase usernames, unknown names are skipped
+	FindByUsernames(usernames []string) ([]User, error)
+	// FindByIDs loads users by ID, missing IDs are skipped
+	FindByIDs(ids []uint) ([]User, error)
 
 	// RefreshToken operations
 	CreateRefreshToken(token *RefreshToken) error
 	FindRefreshTokenByToken(token string) (*RefreshToken, error)
+	FindRevokedRefreshToken(token string) (*RefreshToken, error)
 	RevokeRefreshToken(token string) error
 	RevokeAllUserTokens(userID uint) error
 	FindUserRefreshTokens(userID uint) ([]RefreshToken, error)
@@ -2,14 +2,14 @@ package user
 
 // Service defines the interface for authentication service operations
 type Service interface {
-	Register(user *RegisterRequest) error
+	Register(user *RegisterRequest, ipAddress, userAgent string) error
 	Login(req *LoginRequest, ipAddress, userAgent string) (*LoginResponse, error)
 	RefreshToken(req *RefreshTokenRequest, ipAddress, userAgent string) (*RefreshTokenResponse, error)
 	Logout(refreshToken string) error
 	LogoutAll(userID uint) error
 	ForgotPassword(req *ForgotPasswordRequest) error
-	ResetPassword(req *ResetPasswordRequest) error
-	ChangePassword(userID uint, req *ChangePasswordRequest) error
+	ResetPassword(req *ResetPasswordRequest, ipAddress, userAgent string) error
+	ChangePassword(userID uint, req *ChangePasswordRequest, ipAddress, userAgent string) error
 	VerifyEmail(req *VerifyEmailRequest) error
 	ResendVerificationEmail(email string) error
 	GetActiveSessions(userID uint) ([]SessionResponse, error)
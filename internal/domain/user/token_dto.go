@@ -7,9 +7,11 @@ import (
 )
 
 type CustomClaims struct {
-	ID    uint   `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID            uint   `json:"id"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	RegisteredAt  int64  `json:"registered_at"`
 	jwt.RegisteredClaims
 }
 
@@ -47,5 +49,13 @@ func (c CustomClaims) FromToken(j jwt.MapClaims) (CustomClaims, error) {
 	}
 	c.Role = roleStr
 
+	// Optional claims, tokens issued before these existed are treated as unverified
+	if verified, ok := j["email_verified"].(bool); ok {
+		c.EmailVerified = verified
+	}
+	if registeredAt, ok := j["registered_at"].(float64); ok {
+		c.RegisteredAt = int64(registeredAt)
+	}
+
 	return c, nil
 }
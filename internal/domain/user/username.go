@@ -0,0 +1,17 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+// NormalizeUsername lowercases a username and reports whether it is a valid @handle
+func NormalizeUsername(s string) (string, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "@")
+	if !usernamePattern.MatchString(s) {
+		return "", false
+	}
+	return strings.ToLower(s), true
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+	"starter-gofiber/pkg/utils"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsHandler struct {
+	service  analytics.Service
+	enforcer *casbin.Enforcer
+}
+
+func NewAnalyticsHandler(s analytics.Service, enforcer *casbin.Enforcer) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		service:  s,
+		enforcer: enforcer,
+	}
+}
+
+// canReadAny reports whether the user may read the stats of other users' posts
+func (h *AnalyticsHandler) canReadAny(email string) bool {
+	if h.enforcer == nil {
+		return false
+	}
+	ok, err := h.enforcer.Enforce(email, "post_stats", "read")
+	return err == nil && ok
+}
+
+// Stats returns the daily stats of a post between ?from= and ?to= (YYYY-MM-DD),
+// ?format=csv|excel|pdf downloads them instead
+func (h *AnalyticsHandler) Stats(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	format := utils.ExportFormat(c.Query("format"))
+	ext := ""
+	switch format {
+	case "":
+	case utils.FormatCSV:
+		ext = "csv"
+	case utils.FormatExcel:
+		ext = "xlsx"
+	case utils.FormatPDF:
+		ext = "pdf"
+	default:
+		return &apierror.BadRequestError{
+			Message: "format must be csv, excel or pdf",
+			Order:   "H2",
+		}
+	}
+
+	from, err := parseDateQuery(c, "from", false)
+	if err != nil {
+		return err
+	}
+	to, err := parseDateQuery(c, "to", false)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.service.Stats(id, userClaims.ID, h.canReadAny(userClaims.Email), from, to)
+	if err != nil {
+		return err
+	}
+
+	if format == "" {
+		return response.Response(dto.ResponseResult{
+			Data:       stats,
+			StatusCode: fiber.StatusOK,
+			Message:    "Post stats retrieved successfully",
+		}, c)
+	}
+
+	filename := fmt.Sprintf("post_%d_stats_%s.%s", id, time.Now().Format("20060102_150405"), ext)
+	config := utils.DefaultExportConfig(format)
+	config.Filename = filepath.Join(os.TempDir(), filename)
+	config.SheetName = "Stats"
+	config.Title = fmt.Sprintf("Post %d stats, %s to %s", id, stats.From, stats.To)
+
+	path, err := utils.ExportData(stats.ExportRows(), analytics.ExportHeaders, config)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "H3",
+		}
+	}
+
+	c.Attachment(filename)
+	return c.Send(data)
+}
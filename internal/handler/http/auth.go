@@ -2,16 +2,20 @@ package http
 
 import (
 	"strconv"
+	"time"
 
 	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/handler/middleware"
 	"starter-gofiber/internal/infrastructure/storage"
 	"starter-gofiber/pkg/apierror"
 	"starter-gofiber/pkg/crypto"
 	"starter-gofiber/pkg/dto"
 	"starter-gofiber/pkg/response"
+	"starter-gofiber/variables"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
 )
 
 type AuthHandler struct {
@@ -31,7 +35,7 @@ func (h *AuthHandler) Register(enforcer *casbin.Enforcer) fiber.Handler {
 			return &apierror.UnprocessableEntityError{Message: err.Error(), Order: "H1"}
 		}
 
-		if err := h.userS.Register(req); err != nil {
+		if err := h.userS.Register(req, c.IP(), c.Get("User-Agent")); err != nil {
 			return err
 		}
 		ok, err := enforcer.AddRoleForUser(req.Email, req.Role)
@@ -74,6 +78,82 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}, c)
 }
 
+// SessionLogin authenticates and starts a cookie session instead of returning tokens
+func (h *AuthHandler) SessionLogin(store *session.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var userReq *user.LoginRequest
+		if err := c.BodyParser(&userReq); err != nil {
+			return &apierror.UnprocessableEntityError{
+				Message: err.Error(),
+				Order:   "H1",
+			}
+		}
+
+		loginResp, err := h.userS.Login(userReq, c.IP(), c.Get("User-Agent"))
+		if err != nil {
+			return err
+		}
+
+		registeredAt, _ := time.Parse(variables.FORMAT_TIME, loginResp.User.CreatedAt)
+		csrfToken, err := middleware.StartSession(c, store, middleware.SessionData{
+			UserID:        loginResp.User.ID,
+			Email:         loginResp.User.Email,
+			Role:          loginResp.User.Role,
+			EmailVerified: loginResp.User.EmailVerified,
+			RegisteredAt:  registeredAt.Unix(),
+			RefreshToken:  loginResp.RefreshToken,
+		})
+		if err != nil {
+			return &apierror.InternalServerError{
+				Message: err.Error(),
+				Order:   "H2",
+			}
+		}
+
+		return response.Response(dto.ResponseResult{
+			StatusCode: fiber.StatusOK,
+			Message:    "Login Success",
+			Data: user.SessionLoginResponse{
+				User:      loginResp.User,
+				CSRFToken: csrfToken,
+			},
+		}, c)
+	}
+}
+
+// SessionLogout destroys the cookie session and revokes its refresh token
+func (h *AuthHandler) SessionLogout(store *session.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		refreshToken, err := middleware.EndSession(c, store)
+		if err != nil {
+			return &apierror.InternalServerError{
+				Message: err.Error(),
+				Order:   "H1",
+			}
+		}
+
+		if refreshToken != "" {
+			if err := h.userS.Logout(refreshToken); err != nil {
+				return err
+			}
+		}
+
+		return response.Response(dto.ResponseResult{
+			StatusCode: fiber.StatusOK,
+			Message:    "Logout successful",
+		}, c)
+	}
+}
+
+// SessionCSRFToken returns the CSRF token bound to the current session
+func (h *AuthHandler) SessionCSRFToken(c *fiber.Ctx) error {
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "CSRF token retrieved",
+		Data:       fiber.Map{"csrf_token": middleware.GetCSRFToken(c)},
+	}, c)
+}
+
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	var req *user.RefreshTokenRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -161,7 +241,7 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 		}
 	}
 
-	if err := h.userS.ResetPassword(req); err != nil {
+	if err := h.userS.ResetPassword(req, c.IP(), c.Get("User-Agent")); err != nil {
 		return err
 	}
 
@@ -185,7 +265,7 @@ func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
 		}
 	}
 
-	if err := h.userS.ChangePassword(userClaims.ID, req); err != nil {
+	if err := h.userS.ChangePassword(userClaims.ID, req, c.IP(), c.Get("User-Agent")); err != nil {
 		return err
 	}
 
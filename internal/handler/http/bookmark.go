@@ -0,0 +1,252 @@
+package http
+
+import (
+	"strconv"
+
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BookmarkHandler struct {
+	service   bookmark.Service
+	reactions reaction.Service
+	polls     poll.Service
+}
+
+func NewBookmarkHandler(s bookmark.Service, reactions reaction.Service, polls poll.Service) *BookmarkHandler {
+	return &BookmarkHandler{
+		service:   s,
+		reactions: reactions,
+		polls:     polls,
+	}
+}
+
+// Bookmark saves a post, optionally in a collection given as collection_id
+func (h *BookmarkHandler) Bookmark(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req bookmark.BookmarkRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return &apierror.UnprocessableEntityError{
+				Message: err.Error(),
+				Order:   "H2",
+			}
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Bookmark(postID, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Post bookmarked",
+		Data:       resp,
+	}, c)
+}
+
+func (h *BookmarkHandler) Unbookmark(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Unbookmark(postID, userClaims.ID); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Bookmark removed",
+	}, c)
+}
+
+// List returns the current user's bookmarked posts, all of them or one collection's by collection_id
+func (h *BookmarkHandler) List(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	var collectionID *uint
+	if raw := c.Query("collection_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return &apierror.BadRequestError{
+				Message: "collection_id must be a number",
+				Order:   "H1",
+			}
+		}
+		parsed := uint(id)
+		collectionID = &parsed
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.List(userClaims.ID, collectionID, page)
+	if err != nil {
+		return err
+	}
+
+	if posts, ok := resp.Data.([]post.PostResponse); ok {
+		if err := attachReactions(c, h.reactions, posts); err != nil {
+			return err
+		}
+		if err := attachPolls(c, h.polls, posts); err != nil {
+			return err
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+func (h *BookmarkHandler) Collections(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Collections(userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+func (h *BookmarkHandler) CreateCollection(c *fiber.Ctx) error {
+	var req bookmark.CollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.CreateCollection(&req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusCreated,
+		Message:    "Collection created successfully",
+		Data:       resp,
+	}, c)
+}
+
+func (h *BookmarkHandler) RenameCollection(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req bookmark.CollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.RenameCollection(id, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Collection updated successfully",
+		Data:       resp,
+	}, c)
+}
+
+// DeleteCollection removes a collection, the bookmarks in it are kept
+func (h *BookmarkHandler) DeleteCollection(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteCollection(id, userClaims.ID); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Collection deleted successfully",
+	}, c)
+}
+
+// ReorderCollections takes the IDs of all the user's collections in their new order
+func (h *BookmarkHandler) ReorderCollections(c *fiber.Ctx) error {
+	var req bookmark.ReorderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.ReorderCollections(&req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Collections reordered successfully",
+		Data:       resp,
+	}, c)
+}
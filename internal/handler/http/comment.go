@@ -0,0 +1,181 @@
+package http
+
+import (
+	"strconv"
+
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/response"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CommentHandler struct {
+	service  comment.Service
+	enforcer *casbin.Enforcer
+}
+
+func NewCommentHandler(s comment.Service, enforcer *casbin.Enforcer) *CommentHandler {
+	return &CommentHandler{
+		service:  s,
+		enforcer: enforcer,
+	}
+}
+
+// canModerate reports whether the user may edit or delete other users' comments
+func (h *CommentHandler) canModerate(email, action string) bool {
+	if h.enforcer == nil {
+		return false
+	}
+	ok, err := h.enforcer.Enforce(email, "comment", action)
+	return err == nil && ok
+}
+
+func parseID(c *fiber.Ctx) (uint, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+	return uint(id), nil
+}
+
+func (h *CommentHandler) List(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	// Only posts shared with the viewer list their comments
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	// Comments are shown oldest first within a thread
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "asc")
+	resp, err := h.service.ListByPost(postID, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+func (h *CommentHandler) Replies(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	// Only posts shared with the viewer list their comments
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "asc")
+	resp, err := h.service.ListReplies(id, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+func (h *CommentHandler) Create(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req comment.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Create(postID, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusCreated,
+		Message:    "Comment created successfully",
+	}, c)
+}
+
+func (h *CommentHandler) Update(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req comment.UpdateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Update(id, &req, userClaims.ID, h.canModerate(userClaims.Email, "update"))
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusOK,
+		Message:    "Comment updated successfully",
+	}, c)
+}
+
+func (h *CommentHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(id, userClaims.ID, h.canModerate(userClaims.Email, "delete")); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Comment deleted successfully",
+	}, c)
+}
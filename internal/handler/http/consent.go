@@ -0,0 +1,211 @@
+package http
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+	"starter-gofiber/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ConsentHandler struct {
+	service consent.Service
+}
+
+func NewConsentHandler(s consent.Service) *ConsentHandler {
+	return &ConsentHandler{
+		service: s,
+	}
+}
+
+// Current returns the current ToS and privacy policy versions
+func (h *ConsentHandler) Current(c *fiber.Ctx) error {
+	versions, err := h.service.CurrentVersions()
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       versions,
+	}, c)
+}
+
+// Status returns the authenticated user's accepted versions and marketing history
+func (h *ConsentHandler) Status(c *fiber.Ctx) error {
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	status, err := h.service.GetStatus(claims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       status,
+	}, c)
+}
+
+// Accept records acceptance of the current policy versions
+func (h *ConsentHandler) Accept(c *fiber.Ctx) error {
+	var req consent.AcceptConsentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Accept(claims.ID, &req, c.IP(), c.Get("User-Agent")); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Consent recorded",
+	}, c)
+}
+
+// Marketing records a marketing consent opt-in or opt-out
+func (h *ConsentHandler) Marketing(c *fiber.Ctx) error {
+	var req consent.MarketingConsentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+	if req.OptIn == nil {
+		return &apierror.UnprocessableEntityError{
+			Message: "opt_in is required",
+			Order:   "H2",
+		}
+	}
+
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.SetMarketingConsent(claims.ID, *req.OptIn, c.IP(), c.Get("User-Agent")); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Marketing consent updated",
+	}, c)
+}
+
+// Publish publishes a new policy version (admin)
+func (h *ConsentHandler) Publish(c *fiber.Ctx) error {
+	var req consent.PublishPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	policy, err := h.service.PublishVersion(&req)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusCreated,
+		Message:    "Policy version published",
+		Data:       policy,
+	}, c)
+}
+
+// Export downloads consent records as CSV or Excel (admin)
+// Query: format=csv|excel, from=YYYY-MM-DD, to=YYYY-MM-DD
+func (h *ConsentHandler) Export(c *fiber.Ctx) error {
+	format := utils.ExportFormat(c.Query("format", string(utils.FormatCSV)))
+	ext := "csv"
+	switch format {
+	case utils.FormatCSV:
+	case utils.FormatExcel:
+		ext = "xlsx"
+	default:
+		return &apierror.BadRequestError{
+			Message: "format must be csv or excel",
+			Order:   "H1",
+		}
+	}
+
+	from, err := parseDateQuery(c, "from", false)
+	if err != nil {
+		return err
+	}
+	to, err := parseDateQuery(c, "to", true)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.service.Export(from, to)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("consents_%s.%s", time.Now().Format("20060102_150405"), ext)
+	config := utils.DefaultExportConfig(format)
+	config.Filename = filepath.Join(os.TempDir(), filename)
+	config.SheetName = "Consents"
+
+	path, err := utils.ExportData(rows, consent.ExportHeaders, config)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	c.Attachment(filename)
+	return c.Send(data)
+}
+
+// parseDateQuery parses a YYYY-MM-DD query value, endOfDay includes the whole day
+func parseDateQuery(c *fiber.Ctx, key string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: fmt.Sprintf("%s must be formatted as YYYY-MM-DD", key),
+			Order:   "H-Date-1",
+		}
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FollowHandler struct {
+	service   follow.Service
+	reactions reaction.Service
+	bookmarks bookmark.Service
+	polls     poll.Service
+}
+
+func NewFollowHandler(s follow.Service, reactions reaction.Service, bookmarks bookmark.Service, polls poll.Service) *FollowHandler {
+	return &FollowHandler{
+		service:   s,
+		reactions: reactions,
+		bookmarks: bookmarks,
+		polls:     polls,
+	}
+}
+
+func (h *FollowHandler) Follow(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Follow(userClaims.ID, id); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "User followed",
+	}, c)
+}
+
+func (h *FollowHandler) Unfollow(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Unfollow(userClaims.ID, id); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "User unfollowed",
+	}, c)
+}
+
+func (h *FollowHandler) Stats(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	resp, err := h.service.Stats(id, viewerID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+func (h *FollowHandler) Followers(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.Followers(id, page)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+func (h *FollowHandler) Following(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.Following(id, page)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+// Timeline returns the authenticated user's home timeline
+func (h *FollowHandler) Timeline(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.Timeline(userClaims.ID, page)
+	if err != nil {
+		return err
+	}
+
+	if posts, ok := resp.Data.([]post.PostResponse); ok {
+		if err := attachReactions(c, h.reactions, posts); err != nil {
+			return err
+		}
+		if err := attachBookmarks(c, h.bookmarks, posts); err != nil {
+			return err
+		}
+		if err := attachPolls(c, h.polls, posts); err != nil {
+			return err
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
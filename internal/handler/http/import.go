@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	service dataimport.Service
+}
+
+func NewImportHandler(s dataimport.Service) *ImportHandler {
+	return &ImportHandler{
+		service: s,
+	}
+}
+
+// Create uploads a file of users or posts, its rows are imported by a worker job
+func (h *ImportHandler) Create(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return &apierror.BadRequestError{
+			Message: "File is required",
+			Order:   "H1",
+		}
+	}
+
+	req := dataimport.ImportRequest{Kind: c.FormValue("kind")}
+	if v := c.FormValue("dry_run"); v != "" {
+		if req.DryRun, err = strconv.ParseBool(v); err != nil {
+			return &apierror.UnprocessableEntityError{
+				Message: "dry_run must be true or false",
+				Order:   "H2",
+			}
+		}
+	}
+	if v := c.FormValue("mapping"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Mapping); err != nil {
+			return &apierror.UnprocessableEntityError{
+				Message: "mapping must be a JSON object of column names to fields",
+				Order:   "H3",
+			}
+		}
+	}
+
+	resp, err := h.service.Start(&req, file, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	// Without a queue the file was processed during the request
+	statusCode, message := fiber.StatusOK, "Import finished"
+	if resp.Status == dataimport.StatusPending {
+		statusCode, message = fiber.StatusAccepted, "Import started"
+	}
+	return response.Response(dto.ResponseResult{
+		StatusCode: statusCode,
+		Message:    message,
+		Data:       resp,
+	}, c)
+}
+
+func (h *ImportHandler) FindAll(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	imports, meta, err := h.service.FindAll(page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       imports,
+		Paginate: &dto.Pagination{
+			Page:       meta.Page,
+			PerPage:    meta.Limit,
+			Total:      meta.Total,
+			TotalPages: meta.TotalPages,
+			NextPage:   meta.HasNextPage,
+		},
+	}, c)
+}
+
+// FindByID returns an import with its counts, clients poll it while the job runs
+func (h *ImportHandler) FindByID(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+// Report downloads the CSV of rejected rows with the line and error of each
+func (h *ImportHandler) Report(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	path, name, err := h.service.Report(id)
+	if err != nil {
+		return err
+	}
+	return c.Download(path, name)
+}
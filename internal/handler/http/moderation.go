@@ -0,0 +1,193 @@
+package http
+
+import (
+	"strconv"
+
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ModerationHandler struct {
+	service moderation.Service
+}
+
+func NewModerationHandler(s moderation.Service) *ModerationHandler {
+	return &ModerationHandler{
+		service: s,
+	}
+}
+
+// Report files a complaint about a post, comment or user
+func (h *ModerationHandler) Report(c *fiber.Ctx) error {
+	var req moderation.ReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Report(&req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusCreated,
+		Message:    "Report submitted successfully",
+	}, c)
+}
+
+// Queue lists reports for moderators
+// Query: status (default open and in_review), target_type, reason, assignee_id ("me" for the current user), page, limit
+func (h *ModerationHandler) Queue(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := moderation.ReportFilter{
+		Status:     c.Query("status"),
+		TargetType: c.Query("target_type"),
+		Reason:     c.Query("reason"),
+	}
+	if assignee := c.Query("assignee_id"); assignee != "" {
+		var id uint
+		if assignee == "me" {
+			claims, err := crypto.GetUserFromToken(c)
+			if err != nil {
+				return err
+			}
+			id = claims.ID
+		} else {
+			parsed, err := strconv.ParseUint(assignee, 10, 32)
+			if err != nil {
+				return &apierror.BadRequestError{
+					Message: "assignee_id must be a number or me",
+					Order:   "H1",
+				}
+			}
+			id = uint(parsed)
+		}
+		filter.AssigneeID = &id
+	}
+
+	reports, total, err := h.service.Queue(filter, page, limit)
+	if err != nil {
+		return err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       reports,
+		Paginate: &dto.Pagination{
+			Page:       page,
+			PerPage:    limit,
+			Total:      total,
+			TotalPages: totalPages,
+			NextPage:   page < totalPages,
+		},
+	}, c)
+}
+
+func (h *ModerationHandler) GetByID(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusOK,
+		Message:    "Report found successfully",
+	}, c)
+}
+
+// Assign hands a report to a moderator, the current user when no assignee_id is given
+func (h *ModerationHandler) Assign(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req moderation.AssignRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return &apierror.UnprocessableEntityError{
+				Message: err.Error(),
+				Order:   "H2",
+			}
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Assign(id, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusOK,
+		Message:    "Report assigned successfully",
+	}, c)
+}
+
+// Resolve dismisses a report or hides, deletes or suspends its target
+func (h *ModerationHandler) Resolve(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req moderation.ResolveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Resolve(id, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusOK,
+		Message:    "Report resolved successfully",
+	}, c)
+}
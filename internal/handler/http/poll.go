@@ -0,0 +1,109 @@
+package http
+
+import (
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PollHandler struct {
+	service poll.Service
+}
+
+func NewPollHandler(s poll.Service) *PollHandler {
+	return &PollHandler{
+		service: s,
+	}
+}
+
+// Find returns the poll of a post, with the results when the viewer may see them
+func (h *PollHandler) Find(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	resp, err := h.service.Find(postID, viewerID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+// Vote casts the user's ballot and returns the poll with its results
+func (h *PollHandler) Vote(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req poll.VoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Vote(postID, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Vote recorded",
+		Data:       resp,
+	}, c)
+}
+
+// attachPolls fills in the viewer's ballots and the results they may see for the polls of the
+// posts and of the posts they quote
+func attachPolls(c *fiber.Ctx, polls poll.Service, posts []post.PostResponse) error {
+	if polls == nil || len(posts) == 0 {
+		return nil
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	var attached []*poll.PollResponse
+	for i := range posts {
+		if posts[i].Poll != nil {
+			attached = append(attached, posts[i].Poll)
+		}
+		if quoted := posts[i].QuotedPost; quoted != nil && quoted.Poll != nil {
+			attached = append(attached, quoted.Poll)
+		}
+	}
+
+	if err := polls.Attach(attached, viewerID); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "H-Poll-1",
+		}
+	}
+	return nil
+}
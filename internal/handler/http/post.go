@@ -1,30 +1,186 @@
 package http
 
 import (
+	"fmt"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
 	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/handler/middleware"
 	"starter-gofiber/internal/infrastructure/storage"
 	"starter-gofiber/pkg/apierror"
 	"starter-gofiber/pkg/crypto"
 	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/pagination"
 	"starter-gofiber/pkg/response"
+	"starter-gofiber/pkg/utils"
 	"starter-gofiber/variables"
 
+	"github.com/casbin/casbin/v2"
 	"github.com/gofiber/fiber/v2"
 )
 
 type PostHandler struct {
-	service post.Service
+	service   post.Service
+	reactions reaction.Service
+	bookmarks bookmark.Service
+	polls     poll.Service
+	analytics analytics.Service
+	enforcer  *casbin.Enforcer
 }
 
-func NewPostHandler(s post.Service) *PostHandler {
+func NewPostHandler(s post.Service, reactions reaction.Service, bookmarks bookmark.Service, polls poll.Service, analytics analytics.Service, enforcer *casbin.Enforcer) *PostHandler {
 	return &PostHandler{
-		service: s,
+		service:   s,
+		reactions: reactions,
+		bookmarks: bookmarks,
+		polls:     polls,
+		analytics: analytics,
+		enforcer:  enforcer,
 	}
 }
 
+// canRestoreAny reports whether the user may restore revisions of other users' posts
+func (h *PostHandler) canRestoreAny(email string) bool {
+	if h.enforcer == nil {
+		return false
+	}
+	ok, err := h.enforcer.Enforce(email, "post_revision", "update")
+	return err == nil && ok
+}
+
+// canManageTrash reports whether the user may list, restore or purge other users' deleted posts
+func (h *PostHandler) canManageTrash(email string) bool {
+	if h.enforcer == nil {
+		return false
+	}
+	ok, err := h.enforcer.Enforce(email, "post_trash", "update")
+	return err == nil && ok
+}
+
+// withViewerState attaches the viewer's reactions, bookmarks and poll ballots to the posts
+func (h *PostHandler) withViewerState(c *fiber.Ctx, posts []post.PostResponse) error {
+	if err := attachReactions(c, h.reactions, posts); err != nil {
+		return err
+	}
+	if err := attachBookmarks(c, h.bookmarks, posts); err != nil {
+		return err
+	}
+	return attachPolls(c, h.polls, posts)
+}
+
+// recordView counts the viewer opening the post for its stats
+func (h *PostHandler) recordView(c *fiber.Ctx, p post.PostResponse, viewerID uint) {
+	if h.analytics == nil {
+		return
+	}
+	h.analytics.RecordView(p.ID, p.UserID, analytics.Viewer{
+		UserID:    viewerID,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
+}
+
+// attachReactions attaches reaction summaries for the current viewer, anonymous when not logged in
+func attachReactions(c *fiber.Ctx, reactions reaction.Service, posts []post.PostResponse) error {
+	if reactions == nil || len(posts) == 0 {
+		return nil
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	ids := make([]uint, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+
+	summaries, err := reactions.Summaries(ids, viewerID)
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "H-Reaction-1",
+		}
+	}
+	for i := range posts {
+		posts[i].WithReactions(summaries[posts[i].ID])
+	}
+	return nil
+}
+
+// attachBookmarks marks the posts the viewer bookmarked, none for anonymous viewers
+func attachBookmarks(c *fiber.Ctx, bookmarks bookmark.Service, posts []post.PostResponse) error {
+	if bookmarks == nil || len(posts) == 0 {
+		return nil
+	}
+
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+
+	bookmarked, err := bookmarks.Bookmarked(ids, claims.ID)
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "H-Bookmark-1",
+		}
+	}
+	for i := range posts {
+		posts[i].Bookmarked = bookmarked[posts[i].ID]
+	}
+	return nil
+}
+
+// All lists live posts. The cursor listing is used when the request has a cursor, sort or filter
+// parameter, otherwise and whenever page is given the page-based listing is kept for compatibility.
 func (h *PostHandler) All(c *fiber.Ctx) error {
+	if !usesCursor(c) {
+		return h.pageListing(c, h.service.FindAll)
+	}
+	return h.cursorListing(c, post.ListFilter{})
+}
+
+// ByUser lists the live posts of the user in the URL, paginated like All
+func (h *PostHandler) ByUser(c *fiber.Ctx) error {
+	userID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	if !usesCursor(c) {
+		return h.pageListing(c, func(viewerID uint, page, limit int) ([]post.PostResponse, *post.PaginationMeta, error) {
+			return h.service.FindByUserID(userID, viewerID, page, limit)
+		})
+	}
+	return h.cursorListing(c, post.ListFilter{UserID: userID})
+}
+
+// Quotes lists the live posts quoting the post in the URL, with the cursor listing's filters and sorting
+func (h *PostHandler) Quotes(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	return h.cursorListing(c, post.ListFilter{QuotedPostID: postID})
+}
+
+// pageListing renders a page-based listing from page and limit
+func (h *PostHandler) pageListing(c *fiber.Ctx, find func(viewerID uint, page, limit int) ([]post.PostResponse, *post.PaginationMeta, error)) error {
 	page := 1
 	limit := 10
 
@@ -40,10 +196,18 @@ func (h *PostHandler) All(c *fiber.Ctx) error {
 		}
 	}
 
-	posts, meta, err := h.service.FindAll(page, limit)
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	posts, meta, err := find(viewerID, page, limit)
 	if err != nil {
 		return err
 	}
+	if err := h.withViewerState(c, posts); err != nil {
+		return err
+	}
 
 	return response.Response(dto.ResponseResult{
 		StatusCode: fiber.StatusOK,
@@ -59,6 +223,142 @@ func (h *PostHandler) All(c *fiber.Ctx) error {
 	}, c)
 }
 
+// cursorListing renders a cursor listing, the set fields of scope come from the path and take
+// precedence over the query's filters
+func (h *PostHandler) cursorListing(c *fiber.Ctx, scope post.ListFilter) error {
+	filter, page, err := parseListQuery(c)
+	if err != nil {
+		return err
+	}
+	if scope.UserID > 0 {
+		filter.UserID = scope.UserID
+	}
+	if scope.QuotedPostID > 0 {
+		filter.QuotedPostID = scope.QuotedPostID
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	resp, err := h.service.List(filter, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	if posts, ok := resp.Data.([]post.PostResponse); ok {
+		if err := h.withViewerState(c, posts); err != nil {
+			return err
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+// cursorParams are the query parameters that select the cursor listing, besides filter_<field>_<op>
+var cursorParams = []string{"cursor", "sort", "sort_by", "user_id", "created_from", "created_to", "has_photo"}
+
+// usesCursor reports whether a listing request asks for the cursor listing
+func usesCursor(c *fiber.Ctx) bool {
+	args := c.Context().QueryArgs()
+	if args.Has("page") {
+		return false
+	}
+	for _, key := range cursorParams {
+		if args.Has(key) {
+			return true
+		}
+	}
+
+	filtered := false
+	args.VisitAll(func(key, _ []byte) {
+		if strings.HasPrefix(string(key), "filter_") {
+			filtered = true
+		}
+	})
+	return filtered
+}
+
+// filterOperators are the filter_<field>_<op> operators accepted by post listings
+var filterOperators = []utils.FilterOperator{
+	utils.OpEqual, utils.OpNotEqual,
+	utils.OpGreaterThan, utils.OpGreaterThanOrEqual,
+	utils.OpLessThan, utils.OpLessThanOrEqual,
+}
+
+// parseListQuery reads the filters, sort and cursor of a post listing. Sorting is by a single field of
+// post.SortFields, newest first by default.
+func parseListQuery(c *fiber.Ctx) (post.ListFilter, pagination.CursorPagination, error) {
+	var filter post.ListFilter
+	params := c.Queries()
+
+	filter.Filters = utils.BuildFilterFromQuery(params)
+	for _, f := range filter.Filters {
+		if !slices.Contains(filterOperators, f.Operator) {
+			return filter, pagination.CursorPagination{}, &apierror.BadRequestError{
+				Message: fmt.Sprintf("Operator '%s' is not supported for filtering", f.Operator),
+				Order:   "H-List-1",
+			}
+		}
+		if err := utils.ValidateFilter(f, post.FilterFields); err != nil {
+			return filter, pagination.CursorPagination{}, err
+		}
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, pagination.CursorPagination{}, &apierror.BadRequestError{
+				Message: "user_id must be a number",
+				Order:   "H-List-2",
+			}
+		}
+		filter.UserID = uint(id)
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseDateQuery(c, "created_from", false); err != nil {
+		return filter, pagination.CursorPagination{}, err
+	}
+	if filter.CreatedTo, err = parseDateQuery(c, "created_to", true); err != nil {
+		return filter, pagination.CursorPagination{}, err
+	}
+
+	if value := c.Query("has_photo"); value != "" {
+		hasPhoto, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, pagination.CursorPagination{}, &apierror.BadRequestError{
+				Message: "has_photo must be true or false",
+				Order:   "H-List-3",
+			}
+		}
+		filter.HasPhoto = &hasPhoto
+	}
+
+	sort := utils.BuildSortFromQuery(params, post.SortFields)
+	if len(sort.Fields) > 1 {
+		return filter, pagination.CursorPagination{}, &apierror.BadRequestError{
+			Message: "Posts can only be sorted by one field",
+			Order:   "H-List-4",
+		}
+	}
+	if err := utils.ValidateSortFields(sort.Fields, post.SortFields); err != nil {
+		return filter, pagination.CursorPagination{}, err
+	}
+
+	sortBy, order := "", string(utils.SortDesc)
+	if len(sort.Fields) == 1 {
+		sortBy, order = sort.Fields[0].Field, string(sort.Fields[0].Order)
+	}
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), sortBy, order, post.SortFields...)
+	return filter, page, nil
+}
+
 func (h *PostHandler) Create(c *fiber.Ctx) error {
 	var req post.PostRequest
 
@@ -90,8 +390,15 @@ func (h *PostHandler) Create(c *fiber.Ctx) error {
 		req.Photo = fileName
 	}
 
+	uploaded, err := h.uploadAttachments(c, len(req.AttachmentIDs), userClaims.ID)
+	if err != nil {
+		return err
+	}
+	req.AttachmentIDs = append(req.AttachmentIDs, uploaded...)
+
 	resp, err := h.service.Create(&req, userClaims.ID)
 	if err != nil {
+		h.discardAttachments(uploaded, userClaims.ID)
 		return err
 	}
 
@@ -148,8 +455,14 @@ func (h *PostHandler) Update(c *fiber.Ctx) error {
 		req.Photo = &fileName
 	}
 
+	req.UploadedIDs, err = h.uploadAttachments(c, len(req.AttachmentIDs), userClaims.ID)
+	if err != nil {
+		return err
+	}
+
 	resp, err := h.service.Update(uint(id), &req, userClaims.ID)
 	if err != nil {
+		h.discardAttachments(req.UploadedIDs, userClaims.ID)
 		return err
 	}
 
@@ -164,6 +477,95 @@ func (h *PostHandler) Update(c *fiber.Ctx) error {
 	}, c)
 }
 
+// uploadAttachments stores the multipart "attachments" files, each with the "alt" value at the same index
+func (h *PostHandler) uploadAttachments(c *fiber.Ctx, listed int, userID uint) ([]uint, error) {
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["attachments"]) == 0 {
+		return nil, nil
+	}
+	files := form.File["attachments"]
+	alts := form.Value["alt"]
+
+	// Fail before processing any image, the service checks the final count again
+	if listed+len(files) > post.MaxAttachments() {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: fmt.Sprintf("A post can have at most %d attachments", post.MaxAttachments()),
+			Order:   "H-Attachments-1",
+		}
+	}
+
+	ids := make([]uint, 0, len(files))
+	for i, file := range files {
+		var alt string
+		if i < len(alts) {
+			alt = alts[i]
+		}
+		attachment, err := h.service.UploadAttachment(file, alt, userID)
+		if err != nil {
+			h.discardAttachments(ids, userID)
+			return nil, err
+		}
+		ids = append(ids, attachment.ID)
+	}
+	return ids, nil
+}
+
+// discardAttachments removes uploads of a request that failed
+func (h *PostHandler) discardAttachments(ids []uint, userID uint) {
+	for _, id := range ids {
+		_ = h.service.DeleteAttachment(id, userID)
+	}
+}
+
+// UploadAttachment stores a file to be attached to a post later through attachment_ids
+func (h *PostHandler) UploadAttachment(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return &apierror.BadRequestError{
+			Message: "File is required",
+			Order:   "H1",
+		}
+	}
+
+	resp, err := h.service.UploadAttachment(file, c.FormValue("alt"), userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusCreated,
+		Message:    "Attachment uploaded successfully",
+	}, c)
+}
+
+// DeleteAttachment removes an upload that is not used by a post
+func (h *PostHandler) DeleteAttachment(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteAttachment(id, userClaims.ID); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Attachment deleted successfully",
+	}, c)
+}
+
 func (h *PostHandler) Delete(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -179,7 +581,8 @@ func (h *PostHandler) Delete(c *fiber.Ctx) error {
 		return err
 	}
 
-	err = h.service.Delete(uint(id), userClaims.ID)
+	// Moderators remove other users' posts through the moderation queue, where it is recorded
+	err = h.service.Delete(uint(id), userClaims.ID, false)
 	if err != nil {
 		return err
 	}
@@ -200,10 +603,22 @@ func (h *PostHandler) GetByID(c *fiber.Ctx) error {
 		}
 	}
 
-	resp, err := h.service.FindByID(uint(id))
+	// Authors can open their own drafts and scheduled posts
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	resp, err := h.service.FindByID(uint(id), viewerID)
 	if err != nil {
 		return err
 	}
+	result := []post.PostResponse{*resp}
+	if err := h.withViewerState(c, result); err != nil {
+		return err
+	}
+	resp = &result[0]
+	h.recordView(c, *resp, viewerID)
 
 	return response.Response(dto.ResponseResult{
 		Data:       resp,
@@ -211,3 +626,327 @@ func (h *PostHandler) GetByID(c *fiber.Ctx) error {
 		Message:    "Post found successfully",
 	}, c)
 }
+
+// BySlug opens a long-form post by its slug, ?author=<username> picks the author when several use it
+func (h *PostHandler) BySlug(c *fiber.Ctx) error {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		return &apierror.BadRequestError{
+			Message: "Invalid slug",
+			Order:   "H1",
+		}
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	resp, err := h.service.FindBySlug(slug, c.Query("author"), viewerID)
+	if err != nil {
+		return err
+	}
+	result := []post.PostResponse{*resp}
+	if err := h.withViewerState(c, result); err != nil {
+		return err
+	}
+	h.recordView(c, result[0], viewerID)
+
+	return response.Response(dto.ResponseResult{
+		Data:       result[0],
+		StatusCode: fiber.StatusOK,
+		Message:    "Post found successfully",
+	}, c)
+}
+
+// Unpublished lists the current user's drafts and scheduled posts
+func (h *PostHandler) Unpublished(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	posts, meta, err := h.service.FindUnpublished(userClaims.ID, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       posts,
+		Paginate: &dto.Pagination{
+			Page:       meta.Page,
+			PerPage:    meta.Limit,
+			Total:      meta.Total,
+			TotalPages: meta.TotalPages,
+			NextPage:   meta.HasNextPage,
+		},
+	}, c)
+}
+
+// Revisions lists the earlier versions of a post, newest first
+func (h *PostHandler) Revisions(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.ListRevisions(id, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+// RestoreRevision brings back an earlier version of a post
+func (h *PostHandler) RestoreRevision(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	revisionID, err := strconv.ParseUint(c.Params("revisionId"), 10, 32)
+	if err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.RestoreRevision(id, uint(revisionID), userClaims.ID, h.canRestoreAny(userClaims.Email))
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusOK,
+		Message:    "Post restored successfully",
+	}, c)
+}
+
+// Trash lists the current user's deleted posts, ?user_id= lists another user's and 0 every user's for admins
+func (h *PostHandler) Trash(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	userID := userClaims.ID
+	if c.Query("user_id") != "" {
+		if !h.canManageTrash(userClaims.Email) {
+			return &apierror.ForbiddenError{
+				Message: "You don't have permission to list other users' deleted posts",
+				Order:   "H1",
+			}
+		}
+		id, err := strconv.ParseUint(c.Query("user_id"), 10, 32)
+		if err != nil {
+			return &apierror.UnprocessableEntityError{
+				Message: err.Error(),
+				Order:   "H2",
+			}
+		}
+		userID = uint(id)
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	posts, meta, err := h.service.FindTrashed(userID, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       posts,
+		Paginate: &dto.Pagination{
+			Page:       meta.Page,
+			PerPage:    meta.Limit,
+			Total:      meta.Total,
+			TotalPages: meta.TotalPages,
+			NextPage:   meta.HasNextPage,
+		},
+	}, c)
+}
+
+// Restore takes a deleted post out of the trash
+func (h *PostHandler) Restore(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Restore(id, userClaims.ID, h.canManageTrash(userClaims.Email))
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       resp,
+		StatusCode: fiber.StatusOK,
+		Message:    "Post restored successfully",
+	}, c)
+}
+
+// PermanentDelete purges a deleted post with its files, it cannot be restored afterwards
+func (h *PostHandler) PermanentDelete(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.PermanentDelete(id, userClaims.ID, h.canManageTrash(userClaims.Email)); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Post permanently deleted successfully",
+	}, c)
+}
+
+// canBulk reports whether the user may run the bulk action, restoring needs no permission like
+// restoring a single post
+func (h *PostHandler) canBulk(email, action string) bool {
+	if action == post.BulkRestore {
+		return true
+	}
+	if h.enforcer == nil {
+		return false
+	}
+	ok, err := h.enforcer.Enforce(email, "post", action)
+	return err == nil && ok
+}
+
+// Bulk creates, updates, deletes or restores a batch of the user's own posts. Large batches are
+// queued and answered with 202, their progress is streamed over SSE and the result is read from BulkJob.
+func (h *PostHandler) Bulk(c *fiber.Ctx) error {
+	var req post.BulkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if !h.canBulk(userClaims.Email, req.Action) {
+		return &apierror.ForbiddenError{
+			Message: "You don't have permission to " + req.Action + " posts",
+			Order:   "H2",
+		}
+	}
+	if middleware.IsVerificationRequired(userClaims, "post:"+req.Action, time.Now()) {
+		return &apierror.ForbiddenError{
+			Message: "Email verification required",
+			Order:   "H3",
+		}
+	}
+
+	result, err := h.service.Bulk(&req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	if result.JobID != "" {
+		return response.Response(dto.ResponseResult{
+			Data:       result,
+			StatusCode: fiber.StatusAccepted,
+			Message:    "Bulk " + req.Action + " queued",
+		}, c)
+	}
+	return response.Response(dto.ResponseResult{
+		Data:       result,
+		StatusCode: fiber.StatusOK,
+		Message:    "Bulk " + req.Action + " completed",
+	}, c)
+}
+
+// BulkJob returns the state of a queued bulk job of the user, with its result once completed
+func (h *PostHandler) BulkJob(c *fiber.Ctx) error {
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.BulkJob(c.Params("jobId"), userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		Data:       result,
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+	}, c)
+}
+
+// ByTag lists posts carrying the hashtag in the URL, with or without the leading #
+func (h *PostHandler) ByTag(c *fiber.Ctx) error {
+	tag, err := url.PathUnescape(c.Params("tag"))
+	if err != nil {
+		return &apierror.BadRequestError{
+			Message: "Invalid tag",
+			Order:   "H1",
+		}
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.FindByTag(tag, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	if posts, ok := resp.Data.([]post.PostResponse); ok {
+		if err := h.withViewerState(c, posts); err != nil {
+			return err
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
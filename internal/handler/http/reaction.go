@@ -0,0 +1,131 @@
+package http
+
+import (
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReactionHandler struct {
+	service reaction.Service
+}
+
+func NewReactionHandler(s reaction.Service) *ReactionHandler {
+	return &ReactionHandler{
+		service: s,
+	}
+}
+
+// Summary returns reaction counts of a post and the viewer's own reaction
+func (h *ReactionHandler) Summary(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	summaries, err := h.service.Summaries([]uint{postID}, viewerID)
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       summaries[postID],
+	}, c)
+}
+
+// React sets the user's reaction, repeating the same request has no further effect
+func (h *ReactionHandler) React(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var req reaction.ReactRequest
+	if err := c.BodyParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H2",
+		}
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.React(postID, &req, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Reaction saved",
+		Data:       resp,
+	}, c)
+}
+
+// Unreact removes the user's reaction, succeeding even when there was none
+func (h *ReactionHandler) Unreact(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Unreact(postID, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Reaction removed",
+		Data:       resp,
+	}, c)
+}
+
+// Reactors lists who reacted to a post, newest first, optionally filtered by ?type=
+func (h *ReactionHandler) Reactors(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	// Only posts shared with the viewer list their reactors
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.Reactors(postID, viewerID, c.Query("type"), page)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
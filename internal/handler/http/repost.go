@@ -0,0 +1,114 @@
+package http
+
+import (
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RepostHandler struct {
+	service   repost.Service
+	reactions reaction.Service
+	polls     poll.Service
+}
+
+func NewRepostHandler(s repost.Service, reactions reaction.Service, polls poll.Service) *RepostHandler {
+	return &RepostHandler{
+		service:   s,
+		reactions: reactions,
+		polls:     polls,
+	}
+}
+
+func (h *RepostHandler) Repost(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.Repost(postID, userClaims.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Post reposted",
+		Data:       resp,
+	}, c)
+}
+
+func (h *RepostHandler) Unrepost(c *fiber.Ctx) error {
+	postID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	userClaims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Unrepost(postID, userClaims.ID); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Repost removed",
+	}, c)
+}
+
+// ByUser lists the posts the user in the URL reposted, newest repost first
+func (h *RepostHandler) ByUser(c *fiber.Ctx) error {
+	userID, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	var viewerID uint
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		viewerID = claims.ID
+	}
+
+	page := pagination.ParseCursorParams(c.Query("cursor"), c.Query("limit"), "", "desc")
+	resp, err := h.service.ListByUser(userID, viewerID, page)
+	if err != nil {
+		return err
+	}
+
+	// Reposted posts get the viewer's reactions like any other post listing
+	if reposts, ok := resp.Data.([]repost.RepostResponse); ok {
+		posts := make([]post.PostResponse, len(reposts))
+		for i, rp := range reposts {
+			posts[i] = *rp.Post
+		}
+		if err := attachReactions(c, h.reactions, posts); err != nil {
+			return err
+		}
+		if err := attachPolls(c, h.polls, posts); err != nil {
+			return err
+		}
+		for i := range reposts {
+			*reposts[i].Post = posts[i]
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/search"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler struct {
+	service   search.Service
+	reactions reaction.Service
+	bookmarks bookmark.Service
+	polls     poll.Service
+}
+
+func NewSearchHandler(s search.Service, reactions reaction.Service, bookmarks bookmark.Service, polls poll.Service) *SearchHandler {
+	return &SearchHandler{
+		service:   s,
+		reactions: reactions,
+		bookmarks: bookmarks,
+		polls:     polls,
+	}
+}
+
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	var req search.SearchRequest
+	if err := c.QueryParser(&req); err != nil {
+		return &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "H1",
+		}
+	}
+
+	resp, err := h.service.Search(&req)
+	if err != nil {
+		return err
+	}
+
+	// Matching posts get the viewer's reactions like any other post listing
+	if results, ok := resp.Data.([]search.Result); ok {
+		var posts []post.PostResponse
+		for _, r := range results {
+			if r.Post != nil {
+				posts = append(posts, *r.Post)
+			}
+		}
+		if err := attachReactions(c, h.reactions, posts); err != nil {
+			return err
+		}
+		if err := attachBookmarks(c, h.bookmarks, posts); err != nil {
+			return err
+		}
+		if err := attachPolls(c, h.polls, posts); err != nil {
+			return err
+		}
+		i := 0
+		for _, r := range results {
+			if r.Post != nil {
+				*r.Post = posts[i]
+				i++
+			}
+		}
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       resp,
+	}, c)
+}
+
+// Reindex queues a full rebuild of the search index
+func (h *SearchHandler) Reindex(c *fiber.Ctx) error {
+	if err := h.service.Reindex(); err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusAccepted,
+		Message:    "Search reindex started",
+	}, c)
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"strconv"
+
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/dto"
+	"starter-gofiber/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SecurityHandler struct {
+	service security.Service
+}
+
+func NewSecurityHandler(s security.Service) *SecurityHandler {
+	return &SecurityHandler{
+		service: s,
+	}
+}
+
+// Events lists security events for admins
+// Query: user_id, email, type, severity, ip, from, to (YYYY-MM-DD), page, limit
+func (h *SecurityHandler) Events(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := security.EventFilter{
+		Email:     c.Query("email"),
+		Type:      c.Query("type"),
+		Severity:  c.Query("severity"),
+		IPAddress: c.Query("ip"),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			return &apierror.BadRequestError{
+				Message: "user_id must be a number",
+				Order:   "H1",
+			}
+		}
+		id := uint(userID)
+		filter.UserID = &id
+	}
+
+	var err error
+	if filter.StartDate, err = parseDateQuery(c, "from", false); err != nil {
+		return err
+	}
+	if filter.EndDate, err = parseDateQuery(c, "to", true); err != nil {
+		return err
+	}
+
+	events, total, err := h.service.Query(filter, page, limit)
+	if err != nil {
+		return err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       events,
+		Paginate: &dto.Pagination{
+			Page:       page,
+			PerPage:    limit,
+			Total:      total,
+			TotalPages: totalPages,
+			NextPage:   page < totalPages,
+		},
+	}, c)
+}
+
+// Activity returns the authenticated user's recent security activity
+func (h *SecurityHandler) Activity(c *fiber.Ctx) error {
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.service.RecentActivity(claims.ID, c.QueryInt("limit", 20))
+	if err != nil {
+		return err
+	}
+
+	return response.Response(dto.ResponseResult{
+		StatusCode: fiber.StatusOK,
+		Message:    "Success",
+		Data:       events,
+	}, c)
+}
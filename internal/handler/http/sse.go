@@ -132,9 +132,10 @@ func (h *SSEHandler) NotifyUserMessage(userID uint, message interface{}) {
 	})
 }
 
-// Example: Progress update notification
-func (h *SSEHandler) NotifyProgress(userID uint, progress int, status string) {
+// NotifyProgress sends the progress of a background job, e.g. a bulk post job, to its user
+func (h *SSEHandler) NotifyProgress(userID uint, jobID string, progress int, status string) {
 	utils.NotifyUser(userID, "progress_update", fiber.Map{
+		"job_id":    jobID,
 		"progress":  progress,
 		"status":    status,
 		"timestamp": time.Now().Unix(),
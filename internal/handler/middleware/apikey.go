@@ -1,22 +1,38 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"starter-gofiber/internal/infrastructure/auth"
+	"starter-gofiber/internal/infrastructure/cache"
 	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-var apiKeyDB *gorm.DB
+var (
+	apiKeyDB *gorm.DB
+	// signatureClockSkew is the allowed difference between client and server clocks
+	signatureClockSkew = 5 * time.Minute
+)
 
 // InitAPIKeyMiddleware sets the database instance
 func InitAPIKeyMiddleware(db *gorm.DB) {
 	apiKeyDB = db
 }
 
+// SetSignatureClockSkew overrides the clock skew tolerance for signed requests
+func SetSignatureClockSkew(skew time.Duration) {
+	if skew > 0 {
+		signatureClockSkew = skew
+	}
+}
+
 // APIKeyAuth middleware validates API key from header
 func APIKeyAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -30,7 +46,7 @@ func APIKeyAuth() fiber.Handler {
 		}
 
 		// Validate API key
-		valid, userID := auth.ValidateAPIKey(apiKeyDB, apiKey)
+		key, valid := auth.LookupAPIKey(apiKeyDB, apiKey)
 		if !valid {
 			return &apierror.UnauthorizedError{
 				Message: "Invalid API key",
@@ -38,24 +54,106 @@ func APIKeyAuth() fiber.Handler {
 			}
 		}
 
+		// Verify HMAC signature when provided or required by the key
+		authHeader := c.Get("Authorization")
+		signed := strings.HasPrefix(authHeader, auth.HMACScheme+" ")
+		if signed || key.RequireSignature {
+			if err := verifySignedRequest(c, key, authHeader); err != nil {
+				return err
+			}
+		}
+
+		recordAPIKeyUse(c, key)
+
 		// Store user ID in context
-		c.Locals("api_user_id", userID)
+		c.Locals("api_user_id", key.UserID)
 		c.Locals("auth_method", "api_key")
+		c.Locals("api_key_signed", signed)
+
+		if err := rejectSuspendedUser(key.UserID); err != nil {
+			return err
+		}
+		if err := requireUserConsent(c, key.UserID); err != nil {
+			return err
+		}
 
 		return c.Next()
 	}
 }
 
+// verifySignedRequest validates the HMAC Authorization header and records its nonce
+func verifySignedRequest(c *fiber.Ctx, key *auth.APIKeyRecord, authHeader string) error {
+	if key.SigningSecret == "" {
+		return &apierror.UnauthorizedError{
+			Message: "API key has no signing secret",
+			Order:   "AK4",
+		}
+	}
+
+	sig, err := auth.ParseHMACHeader(authHeader)
+	if err != nil {
+		return &apierror.UnauthorizedError{
+			Message: "Signed request required",
+			Order:   "AK5",
+		}
+	}
+
+	if err := auth.VerifyHMACSignature(key.SigningSecret, sig, c.Method(), c.OriginalURL(), c.Body(), time.Now(), signatureClockSkew); err != nil {
+		return &apierror.UnauthorizedError{
+			Message: err.Error(),
+			Order:   "AK6",
+		}
+	}
+
+	// Nonce replay protection, the nonce only needs to live as long as the skew window
+	if cache.RedisClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		nonceKey := fmt.Sprintf("apikey:nonce:%d:%s", key.ID, sig.Nonce)
+		fresh, err := cache.RedisClient.SetNX(ctx, nonceKey, "1", 2*signatureClockSkew).Result()
+		if err != nil {
+			logger.Error("API key nonce check error: " + err.Error())
+			return &apierror.InternalServerError{
+				Message: "Failed to verify request nonce",
+				Order:   "AK7",
+			}
+		}
+		if !fresh {
+			return &apierror.UnauthorizedError{
+				Message: "Request nonce already used",
+				Order:   "AK8",
+			}
+		}
+	}
+
+	return nil
+}
+
 // OptionalAPIKeyAuth tries API key first, falls back to JWT
 func OptionalAPIKeyAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Try API key first
 		apiKey := c.Get("X-API-Key")
 		if apiKey != "" {
-			valid, userID := auth.ValidateAPIKey(apiKeyDB, apiKey)
-			if valid {
-				c.Locals("api_user_id", userID)
+			if key, valid := auth.LookupAPIKey(apiKeyDB, apiKey); valid {
+				authHeader := c.Get("Authorization")
+				signed := strings.HasPrefix(authHeader, auth.HMACScheme+" ")
+				if signed || key.RequireSignature {
+					if err := verifySignedRequest(c, key, authHeader); err != nil {
+						return err
+					}
+				}
+				recordAPIKeyUse(c, key)
+				c.Locals("api_user_id", key.UserID)
 				c.Locals("auth_method", "api_key")
+				c.Locals("api_key_signed", signed)
+				if err := rejectSuspendedUser(key.UserID); err != nil {
+					return err
+				}
+				if err := requireUserConsent(c, key.UserID); err != nil {
+					return err
+				}
 				return c.Next()
 			}
 		}
@@ -80,8 +178,13 @@ func APIKeyOrJWT() fiber.Handler {
 			return AuthMiddleware()(c)
 		}
 
+		// Check for session cookie
+		if hasSessionCookie(c) {
+			return SessionAuth()(c)
+		}
+
 		return &apierror.UnauthorizedError{
-			Message: "Authentication required (API key or Bearer token)",
+			Message: "Authentication required (API key, Bearer token or session)",
 			Order:   "AK3",
 		}
 	}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"strings"
+
 	"starter-gofiber/pkg/apierror"
 	"starter-gofiber/pkg/crypto"
 
@@ -8,7 +10,34 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// AuthMiddleware authenticates Bearer JWT requests, or session cookie requests
+// when cookie-session auth mode is enabled
 func AuthMiddleware() func(*fiber.Ctx) error {
+	jwtHandler := JWTMiddleware()
+	sessionHandler := SessionAuth()
+	return func(c *fiber.Ctx) error {
+		if hasSessionCookie(c) {
+			return sessionHandler(c)
+		}
+		return jwtHandler(c)
+	}
+}
+
+// OptionalAuthMiddleware authenticates the request like AuthMiddleware when it
+// carries credentials and lets anonymous requests through, so public routes can
+// personalise responses for logged in users.
+func OptionalAuthMiddleware() func(*fiber.Ctx) error {
+	authHandler := AuthMiddleware()
+	return func(c *fiber.Ctx) error {
+		if hasSessionCookie(c) || strings.HasPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ") {
+			return authHandler(c)
+		}
+		return c.Next()
+	}
+}
+
+// JWTMiddleware validates the Bearer access token
+func JWTMiddleware() func(*fiber.Ctx) error {
 	privateKey := crypto.GetPrivateKey()
 	return jwtware.New(jwtware.Config{
 		ContextKey: "user",
@@ -16,6 +45,15 @@ func AuthMiddleware() func(*fiber.Ctx) error {
 			Key:    privateKey.Public(),
 			JWTAlg: jwtware.RS256,
 		},
+		SuccessHandler: func(c *fiber.Ctx) error {
+			if err := rejectSuspended(c); err != nil {
+				return err
+			}
+			if err := requireConsent(c); err != nil {
+				return err
+			}
+			return c.Next()
+		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return apierror.ErrorHelper(c, &apierror.UnauthorizedError{Message: err.Error()})
 		},
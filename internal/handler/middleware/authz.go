@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"os"
+	"time"
 
 	"starter-gofiber/pkg/apierror"
 	"starter-gofiber/pkg/crypto"
@@ -11,7 +12,32 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func LoadAuthzMiddleware() *casbin.Middleware {
+// Authz checks route permissions with casbin, gated permissions also need a verified email
+type Authz struct {
+	*casbin.Middleware
+}
+
+// RequiresPermissions blocks unverified users past their grace period when one of the
+// permissions is gated, then checks the permissions
+func (a *Authz) RequiresPermissions(permissions []string, opts ...casbin.Option) fiber.Handler {
+	check := a.Middleware.RequiresPermissions(permissions, opts...)
+
+	return func(c *fiber.Ctx) error {
+		if claims, err := crypto.GetUserFromToken(c); err == nil {
+			for _, permission := range permissions {
+				if IsVerificationRequired(claims, permission, time.Now()) {
+					return &apierror.ForbiddenError{
+						Message: "Email verification required",
+						Order:   "EV1",
+					}
+				}
+			}
+		}
+		return check(c)
+	}
+}
+
+func LoadAuthzMiddleware() *Authz {
 	modelPath := "./assets/rbac/model.conf"
 	policyPath := "./assets/rbac/policy.csv"
 
@@ -21,7 +47,7 @@ func LoadAuthzMiddleware() *casbin.Middleware {
 		policyPath = "../assets/rbac/policy.csv"
 	}
 
-	return casbin.New(casbin.Config{
+	return &Authz{casbin.New(casbin.Config{
 		ModelFilePath: modelPath,
 		PolicyAdapter: fileadapter.NewAdapter(policyPath),
 		Lookup: func(c *fiber.Ctx) string {
@@ -38,10 +64,11 @@ func LoadAuthzMiddleware() *casbin.Middleware {
 			}
 		},
 		Forbidden: func(c *fiber.Ctx) error {
+			recordPermissionDenied(c)
 			return &apierror.ForbiddenError{
 				Message: "Tidak ada hak akses",
 				Order:   "M-casbin-authz",
 			}
 		},
-	})
+	})}
 }
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const consentExemptKey = "consent_exempt"
+
+// consentChecker reports whether a user must accept the current ToS/privacy policy
+var consentChecker func(userID uint) (bool, error)
+
+// InitConsentMiddleware sets the consent checker used after authentication
+func InitConsentMiddleware(checker func(userID uint) (bool, error)) {
+	consentChecker = checker
+}
+
+// ConsentExempt lets a route skip the consent check, e.g. the routes used to
+// read and accept the new policy versions. Use before AuthMiddleware.
+func ConsentExempt() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(consentExemptKey, true)
+		return c.Next()
+	}
+}
+
+// requireConsent rejects authenticated users who have not accepted the
+// current policy versions with Order consent.ErrCodeConsentRequired
+func requireConsent(c *fiber.Ctx) error {
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return nil
+	}
+	return requireUserConsent(c, claims.ID)
+}
+
+// requireUserConsent is requireConsent for a user authenticated without a token,
+// like the owner of an API key
+func requireUserConsent(c *fiber.Ctx, userID uint) error {
+	if consentChecker == nil {
+		return nil
+	}
+	if exempt, _ := c.Locals(consentExemptKey).(bool); exempt {
+		return nil
+	}
+
+	required, err := consentChecker(userID)
+	if err != nil {
+		// Fail open so a consent lookup failure does not lock everyone out
+		logger.Error("Failed to check user consent", zap.Error(err), zap.Uint("user_id", userID))
+		return nil
+	}
+	if required {
+		return &apierror.ForbiddenError{
+			Message: "You must accept the current terms of service and privacy policy",
+			Order:   consent.ErrCodeConsentRequired,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// verificationGated lists permissions that require a verified email
+	verificationGated = map[string]bool{"post:create": true}
+	// verificationGrace is how long new accounts may use gated permissions unverified
+	verificationGrace = 24 * time.Hour
+)
+
+// InitEmailVerificationGate configures gated permissions and the grace period
+func InitEmailVerificationGate(permissions []string, grace time.Duration) {
+	if len(permissions) > 0 {
+		verificationGated = make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			verificationGated[permission] = true
+		}
+	}
+	if grace >= 0 {
+		verificationGrace = grace
+	}
+}
+
+// IsVerificationRequired reports whether the claims may not use permission yet
+func IsVerificationRequired(claims *user.CustomClaims, permission string, now time.Time) bool {
+	if claims.EmailVerified || !verificationGated[permission] {
+		return false
+	}
+
+	registeredAt := time.Unix(claims.RegisteredAt, 0)
+	return now.After(registeredAt.Add(verificationGrace))
+}
+
+// RequireVerifiedEmail blocks unverified users from a gated permission once
+// their grace period has passed. Routes checking permissions through
+// LoadAuthzMiddleware are gated already, use it after AuthMiddleware on
+// routes without a permission check.
+func RequireVerifiedEmail(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := crypto.GetUserFromToken(c)
+		if err != nil {
+			return err
+		}
+
+		if IsVerificationRequired(claims, permission, time.Now()) {
+			return &apierror.ForbiddenError{
+				Message: "Email verification required",
+				Order:   "EV1",
+			}
+		}
+
+		return c.Next()
+	}
+}
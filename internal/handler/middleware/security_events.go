@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/infrastructure/auth"
+	"starter-gofiber/pkg/crypto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// securityEvents records security events from middleware, nil disables recording
+var securityEvents security.Service
+
+// InitSecurityEvents sets the security event recorder
+func InitSecurityEvents(s security.Service) {
+	securityEvents = s
+}
+
+// recordPermissionDenied records a Casbin permission denial
+func recordPermissionDenied(c *fiber.Ctx) {
+	if securityEvents == nil {
+		return
+	}
+
+	event := &security.Event{
+		Type:      security.EventPermissionDenied,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Subject:   c.Method() + " " + c.Path(),
+	}
+	if claims, err := crypto.GetUserFromToken(c); err == nil {
+		event.UserID = &claims.ID
+		event.Email = claims.Email
+	}
+	securityEvents.Record(event)
+}
+
+// recordAPIKeyUse records the first use of an API key from a new IP
+func recordAPIKeyUse(c *fiber.Ctx, key *auth.APIKeyRecord) {
+	if securityEvents == nil {
+		return
+	}
+	securityEvents.RecordAPIKeyUse(key.ID, key.UserID, c.IP(), c.Get("User-Agent"))
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// suspensionChecker reports whether a user is suspended, without it access tokens
+// issued before a suspension keep working until they expire
+var suspensionChecker func(userID uint) (bool, error)
+
+// InitSuspensionCheck sets the suspension checker used after authentication
+func InitSuspensionCheck(checker func(userID uint) (bool, error)) {
+	suspensionChecker = checker
+}
+
+// errSuspended is returned to suspended users on every authenticated route
+func errSuspended() error {
+	return &apierror.ForbiddenError{
+		Message: "Account suspended",
+		Order:   "SUS1",
+	}
+}
+
+// rejectSuspended rejects authenticated users suspended by a moderator
+func rejectSuspended(c *fiber.Ctx) error {
+	claims, err := crypto.GetUserFromToken(c)
+	if err != nil {
+		return nil
+	}
+	return rejectSuspendedUser(claims.ID)
+}
+
+// rejectSuspendedUser is rejectSuspended for a user authenticated without a token,
+// like the owner of an API key
+func rejectSuspendedUser(userID uint) error {
+	if suspensionChecker == nil {
+		return nil
+	}
+
+	suspended, err := suspensionChecker(userID)
+	if err != nil {
+		// Fail open like the consent check, a lookup failure must not lock everyone out
+		logger.Error("Failed to check user suspension", zap.Error(err), zap.Uint("user_id", userID))
+		return nil
+	}
+	if suspended {
+		return errSuspended()
+	}
+	return nil
+}
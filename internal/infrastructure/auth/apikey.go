@@ -10,28 +10,50 @@ import (
 	"gorm.io/gorm"
 )
 
+// APIKeyRecord is the subset of api_keys columns needed for authentication
+type APIKeyRecord struct {
+	ID               uint
+	UserID           uint
+	IsActive         bool
+	KeyHash          string
+	SigningSecret    string
+	RequireSignature bool
+}
+
 // ValidateAPIKey checks if API key is valid
 // Returns (isValid, userID)
 func ValidateAPIKey(db *gorm.DB, apiKey string) (bool, uint) {
-	type APIKey struct {
-		ID         uint
-		UserID     uint
-		IsActive   bool
-		KeyHash    string
-		LastUsedAt *string
+	key, ok := LookupAPIKey(db, apiKey)
+	if !ok {
+		return false, 0
 	}
 
-	var key APIKey
+	return true, key.UserID
+}
+
+// LookupAPIKey finds an active API key and decrypts its signing secret
+// Returns (record, found)
+func LookupAPIKey(db *gorm.DB, apiKey string) (*APIKeyRecord, bool) {
+	var key APIKeyRecord
 	result := db.Table("api_keys").
 		Where("key_hash = ? AND is_active = ?", crypto.HashString(apiKey), true).
 		First(&key)
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return false, 0
+			return nil, false
 		}
 		logger.Error("API key validation error: " + result.Error.Error())
-		return false, 0
+		return nil, false
+	}
+
+	if key.SigningSecret != "" {
+		secret, err := crypto.Decrypt(key.SigningSecret)
+		if err != nil {
+			logger.Error("API key signing secret decryption error: " + err.Error())
+			return nil, false
+		}
+		key.SigningSecret = secret
 	}
 
 	// Update last used timestamp async
@@ -41,7 +63,7 @@ func ValidateAPIKey(db *gorm.DB, apiKey string) (bool, uint) {
 			Update("last_used_at", utils.TimeNow())
 	}()
 
-	return true, key.UserID
+	return &key, true
 }
 
 // GenerateAPIKey creates a new API key for user
@@ -92,7 +114,7 @@ func RevokeAPIKey(db *gorm.DB, keyID uint) error {
 func ListAPIKeys(db *gorm.DB, userID uint) ([]map[string]interface{}, error) {
 	var keys []map[string]interface{}
 	result := db.Table("api_keys").
-		Select("id", "user_id", "name", "is_active", "last_used_at", "created_at").
+		Select("id", "user_id", "name", "is_active", "require_signature", "last_used_at", "created_at").
 		Where("user_id = ?", userID).
 		Find(&keys)
 
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStorage adapts the global Redis client to the fiber.Storage interface
+// so Fiber middlewares (session, limiter, csrf) can share the same connection
+type RedisStorage struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStorage creates a fiber.Storage backed by Redis with a key prefix
+func NewRedisStorage(client *redis.Client, prefix string) *RedisStorage {
+	return &RedisStorage{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Get returns the value for key, nil if it does not exist
+func (s *RedisStorage) Get(key string) ([]byte, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	val, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return val, err
+}
+
+// Set stores value for key with expiration, 0 means no expiration
+func (s *RedisStorage) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" || len(val) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	return s.client.Set(ctx, s.prefix+key, val, exp).Err()
+}
+
+// Delete removes key from storage
+func (s *RedisStorage) Delete(key string) error {
+	if key == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
+
+// Reset removes all keys under the storage prefix
+func (s *RedisStorage) Reset() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys, err := s.client.Keys(ctx, s.prefix+"*").Result()
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return s.client.Del(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// Close is a no-op, the shared client is closed by CloseRedis
+func (s *RedisStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,214 @@
+package fulltext
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"starter-gofiber/internal/domain/search"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// bleveDocument is the stored shape of a document in the Bleve index
+type bleveDocument struct {
+	Type      string  `json:"type"`
+	DocID     float64 `json:"doc_id"`
+	UserID    float64 `json:"user_id"`
+	Body      string  `json:"body"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// BleveEngine is an embedded index for deployments without database full-text support.
+// An on-disk index can only be opened by one process, so run the embedded worker with it.
+type BleveEngine struct {
+	mu    sync.RWMutex
+	path  string
+	index bleve.Index
+}
+
+// NewBleveEngine opens or creates the index at path, an empty path keeps it in memory
+func NewBleveEngine(path string) (*BleveEngine, error) {
+	e := &BleveEngine{path: path}
+	index, err := e.open()
+	if err != nil {
+		return nil, err
+	}
+	e.index = index
+	return e, nil
+}
+
+func (e *BleveEngine) open() (bleve.Index, error) {
+	if e.path == "" {
+		return bleve.NewMemOnly(bleveMapping())
+	}
+	// Fail instead of blocking forever when another process holds the index
+	index, err := bleve.OpenUsing(e.path, map[string]interface{}{"bolt_timeout": "5s"})
+	if err == bleve.ErrorIndexPathDoesNotExist {
+		return bleve.New(e.path, bleveMapping())
+	}
+	return index, err
+}
+
+func bleveMapping() mapping.IndexMapping {
+	keyword := bleve.NewKeywordFieldMapping()
+	numeric := bleve.NewNumericFieldMapping()
+	body := bleve.NewTextFieldMapping()
+	body.Analyzer = "standard"
+	body.IncludeTermVectors = true // Needed for highlighting
+
+	doc := bleve.NewDocumentMapping()
+	doc.AddFieldMappingsAt("type", keyword)
+	doc.AddFieldMappingsAt("doc_id", numeric)
+	doc.AddFieldMappingsAt("user_id", numeric)
+	doc.AddFieldMappingsAt("body", body)
+	doc.AddFieldMappingsAt("created_at", bleve.NewDateTimeFieldMapping())
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	return m
+}
+
+func bleveID(docType string, id uint) string {
+	return docType + ":" + strconv.FormatUint(uint64(id), 10)
+}
+
+func (e *BleveEngine) Name() string {
+	return BackendBleve
+}
+
+func (e *BleveEngine) Index(docs ...search.Document) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	batch := e.index.NewBatch()
+	for _, d := range docs {
+		err := batch.Index(bleveID(d.Type, d.ID), bleveDocument{
+			Type:      d.Type,
+			DocID:     float64(d.ID),
+			UserID:    float64(d.UserID),
+			Body:      d.Body,
+			CreatedAt: d.CreatedAt.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return e.index.Batch(batch)
+}
+
+func (e *BleveEngine) Delete(docType string, ids ...uint) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	batch := e.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(bleveID(docType, id))
+	}
+	return e.index.Batch(batch)
+}
+
+func (e *BleveEngine) Search(q search.Query) ([]search.Hit, error) {
+	if len(terms(q.Text)) == 0 {
+		return nil, nil
+	}
+
+	match := bleve.NewMatchQuery(q.Text)
+	match.SetField("body")
+	match.SetOperator(query.MatchQueryOperatorAnd)
+	queries := []query.Query{match}
+
+	if len(q.Types) > 0 {
+		types := make([]query.Query, len(q.Types))
+		for i, t := range q.Types {
+			term := bleve.NewTermQuery(t)
+			term.SetField("type")
+			types[i] = term
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(types...))
+	}
+	if q.AuthorID > 0 {
+		author := float64(q.AuthorID)
+		inclusive := true
+		byAuthor := bleve.NewNumericRangeInclusiveQuery(&author, &author, &inclusive, &inclusive)
+		byAuthor.SetField("user_id")
+		queries = append(queries, byAuthor)
+	}
+	if q.Since != nil || q.Until != nil {
+		// A zero time leaves that end of the range open
+		var since, until time.Time
+		if q.Since != nil {
+			since = *q.Since
+		}
+		if q.Until != nil {
+			until = *q.Until
+		}
+		inclusive, exclusive := true, false
+		byDate := bleve.NewDateRangeInclusiveQuery(since, until, &inclusive, &exclusive)
+		byDate.SetField("created_at")
+		queries = append(queries, byDate)
+	}
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(queries...), q.Limit, q.Offset, false)
+	if q.Sort == search.SortRecent {
+		req.SortBy([]string{"-created_at", "-doc_id"})
+	} else {
+		req.SortBy([]string{"-_score", "-doc_id"})
+	}
+	// The html highlighter escapes the text and wraps matches in <mark>
+	req.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	req.Highlight.AddField("body")
+
+	e.mu.RLock()
+	result, err := e.index.Search(req)
+	e.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]search.Hit, 0, len(result.Hits))
+	for _, h := range result.Hits {
+		docType, rawID, ok := strings.Cut(h.ID, ":")
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseUint(rawID, 10, 64)
+		if err != nil {
+			continue
+		}
+		hits = append(hits, search.Hit{
+			Type:      docType,
+			ID:        uint(id),
+			Score:     h.Score,
+			Highlight: strings.Join(h.Fragments["body"], " … "),
+		})
+	}
+	return hits, nil
+}
+
+// Reset drops and recreates the index
+func (e *BleveEngine) Reset() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.index.Close(); err != nil {
+		return err
+	}
+	if e.path != "" {
+		if err := os.RemoveAll(e.path); err != nil {
+			return fmt.Errorf("failed to remove bleve index: %w", err)
+		}
+	}
+	index, err := e.open()
+	if err != nil {
+		return err
+	}
+	e.index = index
+	return nil
+}
@@ -0,0 +1,108 @@
+package fulltext
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"starter-gofiber/internal/domain/search"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+	BackendBleve    = "bleve"
+)
+
+var (
+	mu     sync.RWMutex
+	engine search.Engine
+)
+
+// SetEngine sets the engine used for indexing and queries, nil disables search
+func SetEngine(e search.Engine) {
+	mu.Lock()
+	engine = e
+	mu.Unlock()
+}
+
+// Engine returns the configured engine, nil when search is disabled
+func Engine() search.Engine {
+	mu.RLock()
+	defer mu.RUnlock()
+	return engine
+}
+
+// New builds the engine for backend. An empty backend picks the database's
+// own full-text support and falls back to Bleve for other databases.
+func New(backend string, db *gorm.DB, blevePath string) (search.Engine, error) {
+	if backend == "" {
+		switch db.Dialector.Name() {
+		case "postgres":
+			backend = BackendPostgres
+		case "sqlite":
+			backend = BackendSQLite
+		default:
+			backend = BackendBleve
+		}
+	}
+
+	switch backend {
+	case BackendPostgres:
+		return NewPostgresEngine(db)
+	case BackendSQLite:
+		return NewSQLiteEngine(db)
+	case BackendBleve:
+		return NewBleveEngine(blevePath)
+	default:
+		return nil, fmt.Errorf("unknown search backend %q", backend)
+	}
+}
+
+// applyFilters adds the query filters shared by the SQL engines, timeValue converts a time to the stored format
+func applyFilters(tx *gorm.DB, q search.Query, timeValue func(time.Time) interface{}) *gorm.DB {
+	if len(q.Types) > 0 {
+		tx = tx.Where("doc_type IN ?", q.Types)
+	}
+	if q.AuthorID > 0 {
+		tx = tx.Where("user_id = ?", q.AuthorID)
+	}
+	if q.Since != nil {
+		tx = tx.Where("created_at >= ?", timeValue(*q.Since))
+	}
+	if q.Until != nil {
+		tx = tx.Where("created_at < ?", timeValue(*q.Until))
+	}
+	return tx.Offset(q.Offset).Limit(q.Limit)
+}
+
+// sqlHit is the row shape returned by the SQL engines
+type sqlHit struct {
+	DocType   string
+	DocID     uint
+	Score     float64
+	Highlight string
+}
+
+func toHits(rows []sqlHit) []search.Hit {
+	hits := make([]search.Hit, len(rows))
+	for i, r := range rows {
+		hits[i] = search.Hit{
+			Type:      r.DocType,
+			ID:        r.DocID,
+			Score:     r.Score,
+			Highlight: search.HighlightHTML(r.Highlight),
+		}
+	}
+	return hits
+}
+
+// terms splits user input into letter/digit words, dropping any query syntax
+func terms(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !isTermRune(r)
+	})
+}
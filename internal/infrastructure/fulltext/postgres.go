@@ -0,0 +1,96 @@
+package fulltext
+
+import (
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/search"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchDocument backs the Postgres engine, tsv is a generated tsvector column with a GIN index
+type searchDocument struct {
+	DocType   string    `gorm:"primaryKey;type:varchar(20)"`
+	DocID     uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index"`
+	Body      string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (searchDocument) TableName() string {
+	return "search_documents"
+}
+
+// headlineOptions marks matches with the engine-neutral markers, see search.HighlightHTML
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MinWords=5, MaxWords=20, FragmentDelimiter=\" … \"",
+	search.HighlightStart, search.HighlightEnd)
+
+// PostgresEngine ranks tsvector matches with ts_rank_cd
+type PostgresEngine struct {
+	db *gorm.DB
+}
+
+func NewPostgresEngine(db *gorm.DB) (*PostgresEngine, error) {
+	if err := db.AutoMigrate(&searchDocument{}); err != nil {
+		return nil, err
+	}
+	// The simple configuration doesn't stem, so it works the same for every language
+	statements := []string{
+		`ALTER TABLE search_documents ADD COLUMN IF NOT EXISTS tsv tsvector
+			GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_search_documents_tsv ON search_documents USING GIN (tsv)`,
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &PostgresEngine{db: db}, nil
+}
+
+func (e *PostgresEngine) Name() string {
+	return BackendPostgres
+}
+
+func (e *PostgresEngine) Index(docs ...search.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	rows := make([]searchDocument, len(docs))
+	for i, d := range docs {
+		rows[i] = searchDocument{DocType: d.Type, DocID: d.ID, UserID: d.UserID, Body: d.Body, CreatedAt: d.CreatedAt}
+	}
+	return e.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows).Error
+}
+
+func (e *PostgresEngine) Delete(docType string, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return e.db.Where("doc_type = ? AND doc_id IN ?", docType, ids).Delete(&searchDocument{}).Error
+}
+
+func (e *PostgresEngine) Search(q search.Query) ([]search.Hit, error) {
+	// websearch_to_tsquery accepts free text, quotes and -exclusions without syntax errors
+	tx := e.db.Table("search_documents, websearch_to_tsquery('simple', ?) AS q", q.Text).
+		Select("doc_type, doc_id, ts_rank_cd(tsv, q) AS score, ts_headline('simple', body, q, ?) AS highlight", headlineOptions).
+		Where("tsv @@ q")
+	tx = applyFilters(tx, q, func(t time.Time) interface{} { return t })
+	if q.Sort == search.SortRecent {
+		tx = tx.Order("created_at DESC").Order("doc_id DESC")
+	} else {
+		tx = tx.Order("score DESC").Order("doc_id DESC")
+	}
+
+	var rows []sqlHit
+	if err := tx.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toHits(rows), nil
+}
+
+func (e *PostgresEngine) Reset() error {
+	return e.db.Exec("TRUNCATE search_documents").Error
+}
@@ -0,0 +1,117 @@
+package fulltext
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"starter-gofiber/internal/domain/search"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteEngine uses an FTS5 virtual table ranked with bm25.
+// mattn/go-sqlite3 only includes FTS5 when built with -tags sqlite_fts5.
+type SQLiteEngine struct {
+	db *gorm.DB
+}
+
+func NewSQLiteEngine(db *gorm.DB) (*SQLiteEngine, error) {
+	err := db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS search_fts USING fts5(
+		body, doc_type UNINDEXED, doc_id UNINDEXED, user_id UNINDEXED, created_at UNINDEXED,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`).Error
+	if err != nil {
+		return nil, fmt.Errorf("sqlite fts5 unavailable, build with -tags sqlite_fts5: %w", err)
+	}
+	return &SQLiteEngine{db: db}, nil
+}
+
+func (e *SQLiteEngine) Name() string {
+	return BackendSQLite
+}
+
+// rowID maps a document to a stable FTS5 rowid so updates replace in place
+func rowID(docType string, id uint) int64 {
+	for i, t := range search.Types {
+		if t == docType {
+			return int64(id)*int64(len(search.Types)) + int64(i)
+		}
+	}
+	return -1
+}
+
+func (e *SQLiteEngine) Index(docs ...search.Document) error {
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		for _, d := range docs {
+			id := rowID(d.Type, d.ID)
+			if err := tx.Exec("DELETE FROM search_fts WHERE rowid = ?", id).Error; err != nil {
+				return err
+			}
+			err := tx.Exec("INSERT INTO search_fts (rowid, body, doc_type, doc_id, user_id, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+				id, d.Body, d.Type, d.ID, d.UserID, d.CreatedAt.Unix()).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *SQLiteEngine) Delete(docType string, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	rowIDs := make([]int64, len(ids))
+	for i, id := range ids {
+		rowIDs[i] = rowID(docType, id)
+	}
+	return e.db.Exec("DELETE FROM search_fts WHERE rowid IN ?", rowIDs).Error
+}
+
+func (e *SQLiteEngine) Search(q search.Query) ([]search.Hit, error) {
+	match := matchExpression(q.Text)
+	if match == "" {
+		return nil, nil
+	}
+
+	tx := e.db.Table("search_fts").
+		Select("doc_type, doc_id, -bm25(search_fts) AS score, snippet(search_fts, 0, ?, ?, '…', 16) AS highlight",
+			search.HighlightStart, search.HighlightEnd).
+		Where("search_fts MATCH ?", match)
+	tx = applyFilters(tx, q, func(t time.Time) interface{} { return t.Unix() })
+	if q.Sort == search.SortRecent {
+		tx = tx.Order("created_at DESC").Order("rowid DESC")
+	} else {
+		tx = tx.Order("score DESC").Order("rowid DESC")
+	}
+
+	var rows []sqlHit
+	if err := tx.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toHits(rows), nil
+}
+
+func (e *SQLiteEngine) Reset() error {
+	return e.db.Exec("DELETE FROM search_fts").Error
+}
+
+// matchExpression quotes every term so user input can't use FTS5 query syntax.
+// All terms must match, the last one as a prefix for search-as-you-type.
+func matchExpression(text string) string {
+	words := terms(text)
+	for i, w := range words {
+		words[i] = `"` + w + `"`
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] += "*"
+	return strings.Join(words, " ")
+}
+
+func isTermRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
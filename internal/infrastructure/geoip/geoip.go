@@ -0,0 +1,147 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Location is the approximate position of an IP address
+type Location struct {
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type entry struct {
+	network  *net.IPNet
+	location Location
+}
+
+var (
+	mu      sync.RWMutex
+	entries []entry
+)
+
+// Load reads a local GeoIP CSV file with the columns:
+// network,country,latitude,longitude (e.g. 203.0.113.0/24,AU,-33.86,151.20)
+// Lines starting with # and a "network" header line are ignored.
+func Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	loaded, err := parse(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse GeoIP file %s: %w", path, err)
+	}
+
+	mu.Lock()
+	entries = loaded
+	mu.Unlock()
+	return nil
+}
+
+func parse(r io.Reader) ([]entry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	var loaded []entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record[0] == "network" {
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(record[0])
+		if err != nil {
+			return nil, err
+		}
+		lat, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, err
+		}
+		lon, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = append(loaded, entry{
+			network: network,
+			location: Location{
+				Country:   strings.ToUpper(record[1]),
+				Latitude:  lat,
+				Longitude: lon,
+			},
+		})
+	}
+
+	// Most specific network first so Lookup returns the best match
+	sort.SliceStable(loaded, func(i, j int) bool {
+		a, _ := loaded[i].network.Mask.Size()
+		b, _ := loaded[j].network.Mask.Size()
+		return a > b
+	})
+	return loaded, nil
+}
+
+// Enabled reports whether a GeoIP file has been loaded
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(entries) > 0
+}
+
+// Reset unloads the GeoIP data
+func Reset() {
+	mu.Lock()
+	entries = nil
+	mu.Unlock()
+}
+
+// Lookup returns the location of ip, ok is false when the IP is unknown
+func Lookup(ip string) (*Location, bool) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, false
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, e := range entries {
+		if e.network.Contains(parsed) {
+			location := e.location
+			return &location, true
+		}
+	}
+	return nil, false
+}
+
+// DistanceKm returns the great-circle distance between two locations
+func DistanceKm(a, b Location) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(b.Latitude - a.Latitude)
+	dLon := toRad(b.Longitude - a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Latitude))*math.Cos(toRad(b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"image/color"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
+	_ "golang.org/x/image/webp" // Registers the WebP decoder, DefaultImageConfig accepts WebP uploads
 )
 
 // ImageProcessConfig represents image processing configuration
@@ -125,6 +127,29 @@ func ProcessImage(file *multipart.FileHeader, dirPath string, config ImageProces
 	return result, nil
 }
 
+// NewFileHeader wraps downloaded bytes in a file header, so they are validated and processed like an upload
+func NewFileHeader(filename string, data []byte) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	// Kept in memory, the form holds the file only
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(body.Len()))
+	if err != nil {
+		return nil, err
+	}
+	return form.File["file"][0], nil
+}
+
 // ResizeImage resizes an image to specific dimensions
 func ResizeImage(imagePath string, width, height int, outputPath string) error {
 	img, err := imaging.Open(imagePath)
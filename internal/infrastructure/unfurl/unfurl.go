@@ -0,0 +1,289 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Config limits what a Fetcher downloads
+type Config struct {
+	Timeout       time.Duration // Whole request, redirects included
+	MaxPageBytes  int64         // Read from a page, metadata past it is ignored
+	MaxImageBytes int64         // Images over it are rejected
+	MaxRedirects  int
+	UserAgent     string
+	// AllowPrivate lets requests reach loopback and private networks, for tests only
+	AllowPrivate bool
+}
+
+// DefaultConfig returns the limits used by the worker
+func DefaultConfig() Config {
+	return Config{
+		Timeout:       5 * time.Second,
+		MaxPageBytes:  512 << 10,
+		MaxImageBytes: 5 << 20,
+		MaxRedirects:  3,
+		UserAgent:     "starter-gofiber-unfurl/1.0",
+	}
+}
+
+// ErrBlockedAddress is returned for URLs resolving to loopback, private or otherwise internal addresses
+var ErrBlockedAddress = errors.New("address is not allowed")
+
+// ErrTooLarge is returned for responses over the configured size
+var ErrTooLarge = errors.New("response too large")
+
+// Metadata is what a page tells about itself in its OpenGraph, Twitter card and HTML head tags
+type Metadata struct {
+	URL         string // Final URL after redirects
+	Title       string
+	Description string
+	SiteName    string
+	ImageURL    string // Absolute
+}
+
+// Fetcher downloads pages and images from untrusted URLs. Every connection, redirects included, is
+// checked against the resolved IP so DNS cannot point it at internal services.
+type Fetcher struct {
+	config Config
+	client *http.Client
+}
+
+func New(config Config) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if config.AllowPrivate {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || IsBlocked(ip) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &Fetcher{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			Transport: &http.Transport{
+				// Environment proxies would bypass the address check
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   config.Timeout,
+				ResponseHeaderTimeout: config.Timeout,
+				MaxIdleConns:          10,
+				IdleConnTimeout:       30 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > config.MaxRedirects {
+					return fmt.Errorf("stopped after %d redirects", config.MaxRedirects)
+				}
+				return checkScheme(req.URL)
+			},
+		},
+	}
+}
+
+// blockedNets are special purpose ranges not covered by the net.IP predicates
+var blockedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "This" network
+		"100.64.0.0/10", // Carrier-grade NAT
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // Benchmarking
+		"240.0.0.0/4",   // Reserved
+		"64:ff9b::/96",  // NAT64, may map to internal IPv4 addresses
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsBlocked reports whether ip is loopback, private, link-local (cloud metadata services live there)
+// or otherwise not a public unicast address
+func IsBlocked(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+// get requests rawURL and checks the status and content type
+func (f *Fetcher) get(ctx context.Context, rawURL string, accept func(mediaType string) bool) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.config.UserAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !accept(mediaType) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected content type %q", mediaType)
+	}
+	return resp, nil
+}
+
+// Page fetches an HTML page and extracts its metadata, only the first MaxPageBytes are read
+func (f *Fetcher) Page(ctx context.Context, rawURL string) (*Metadata, error) {
+	resp, err := f.get(ctx, rawURL, func(mediaType string) bool {
+		return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	meta := parse(io.LimitReader(resp.Body, f.config.MaxPageBytes), resp.Request.URL)
+	return &meta, nil
+}
+
+// Image downloads an image, rejecting responses over MaxImageBytes
+func (f *Fetcher) Image(ctx context.Context, rawURL string) ([]byte, error) {
+	resp, err := f.get(ctx, rawURL, func(mediaType string) bool {
+		return strings.HasPrefix(mediaType, "image/")
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.ContentLength > f.config.MaxImageBytes {
+		return nil, ErrTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > f.config.MaxImageBytes {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// parse reads the head of a page. OpenGraph tags win over Twitter card tags, which win over
+// <title> and <meta name="description">.
+func parse(r io.Reader, base *url.URL) Metadata {
+	values := make(map[string]string)
+	var title strings.Builder
+	inTitle := false
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = true
+			case "meta":
+				var key, content string
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					switch string(k) {
+					case "property", "name":
+						key = strings.ToLower(strings.TrimSpace(string(v)))
+					case "content":
+						content = strings.TrimSpace(string(v))
+					}
+				}
+				if key != "" && content != "" {
+					if _, seen := values[key]; !seen {
+						values[key] = content
+					}
+				}
+			case "body":
+				break loop
+			}
+		case html.TextToken:
+			if inTitle {
+				title.Write(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := values[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	meta := Metadata{
+		URL:         base.String(),
+		Title:       first("og:title", "twitter:title"),
+		Description: first("og:description", "twitter:description", "description"),
+		SiteName:    first("og:site_name"),
+	}
+	if meta.Title == "" {
+		meta.Title = strings.Join(strings.Fields(title.String()), " ")
+	}
+	if image := first("og:image", "og:image:url", "og:image:secure_url", "twitter:image", "twitter:image:src"); image != "" {
+		if u, err := base.Parse(image); err == nil && checkScheme(u) == nil {
+			meta.ImageURL = u.String()
+		}
+	}
+	return meta
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/reaction"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(d *gorm.DB) analytics.Repository {
+	return &AnalyticsRepository{
+		db: d,
+	}
+}
+
+func (r *AnalyticsRepository) RecordView(postID uint, viewer string, at time.Time, window time.Duration) (bool, error) {
+	// The first open of the day creates the viewer's row, the unique index settles concurrent ones
+	v := analytics.View{
+		PostID:       postID,
+		Day:          analytics.Day(at),
+		ViewerKey:    viewer,
+		Views:        1,
+		LastViewedAt: at,
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&v)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	// Later opens only count once the window since the last counted one passed
+	result = r.db.Model(&analytics.View{}).
+		Where("post_id = ? AND day = ? AND viewer_key = ? AND last_viewed_at <= ?", postID, v.Day, viewer, at.Add(-window)).
+		Updates(map[string]interface{}{
+			"views":          gorm.Expr("views + 1"),
+			"last_viewed_at": at,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *AnalyticsRepository) CountViews(day time.Time) ([]analytics.DailyStat, error) {
+	var stats []analytics.DailyStat
+	err := r.db.Model(&analytics.View{}).
+		Select("post_id, SUM(views) AS views, COUNT(*) AS unique_viewers").
+		Where("day = ?", day).
+		Group("post_id").
+		Scan(&stats).Error
+	for i := range stats {
+		stats[i].Day = day
+	}
+	return stats, err
+}
+
+func (r *AnalyticsRepository) CountActivity(day time.Time) ([]analytics.DailyStat, error) {
+	type count struct {
+		PostID uint
+		Count  int
+	}
+	end := day.AddDate(0, 0, 1)
+
+	var reactions, comments []count
+	if err := r.db.Model(&reaction.Reaction{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("created_at >= ? AND created_at < ?", day, end).
+		Group("post_id").
+		Scan(&reactions).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&comment.Comment{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("created_at >= ? AND created_at < ?", day, end).
+		Group("post_id").
+		Scan(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	byPost := make(map[uint]*analytics.DailyStat)
+	var stats []analytics.DailyStat
+	stat := func(postID uint) *analytics.DailyStat {
+		if s, ok := byPost[postID]; ok {
+			return s
+		}
+		s := &analytics.DailyStat{PostID: postID, Day: day}
+		byPost[postID] = s
+		return s
+	}
+	for _, c := range reactions {
+		stat(c.PostID).Reactions = c.Count
+	}
+	for _, c := range comments {
+		stat(c.PostID).Comments = c.Count
+	}
+	for _, s := range byPost {
+		stats = append(stats, *s)
+	}
+	return stats, nil
+}
+
+func (r *AnalyticsRepository) SaveDaily(stats []analytics.DailyStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "day"}},
+		DoUpdates: clause.AssignmentColumns([]string{"views", "unique_viewers", "reactions", "comments", "updated_at"}),
+	}).Create(&stats).Error
+}
+
+func (r *AnalyticsRepository) FindDaily(postID uint, from, to time.Time) ([]analytics.DailyStat, error) {
+	var stats []analytics.DailyStat
+	err := r.db.Where("post_id = ? AND day >= ? AND day <= ?", postID, from, to).
+		Order("day ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+func (r *AnalyticsRepository) PurgeViews(before time.Time) (int64, error) {
+	result := r.db.Where("day < ?", before).Delete(&analytics.View{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"errors"
+
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/database"
+	"starter-gofiber/pkg/pagination"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	// Bookmarks of a deleted post go with it
+	database.OnSoftDelete("posts", func(tx *gorm.DB, ids []uint) error {
+		return tx.Where("post_id IN ?", ids).Delete(&bookmark.Bookmark{}).Error
+	})
+}
+
+type BookmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(d *gorm.DB) bookmark.Repository {
+	return &BookmarkRepository{
+		db: d,
+	}
+}
+
+func (r *BookmarkRepository) Save(b *bookmark.Bookmark) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing bookmark.Bookmark
+		err := tx.Where("user_id = ? AND post_id = ?", b.UserID, b.PostID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(b).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&existing).Update("collection_id", b.CollectionID).Error; err != nil {
+			return err
+		}
+		existing.CollectionID = b.CollectionID
+		*b = existing
+		return nil
+	})
+}
+
+func (r *BookmarkRepository) Remove(postID, userID uint) (bool, error) {
+	result := r.db.Where("post_id = ? AND user_id = ?", postID, userID).Delete(&bookmark.Bookmark{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *BookmarkRepository) FindBookmarkedPostIDs(postIDs []uint, userID uint) ([]uint, error) {
+	var ids []uint
+	if len(postIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&bookmark.Bookmark{}).
+		Where("post_id IN ? AND user_id = ?", postIDs, userID).
+		Pluck("post_id", &ids).Error
+	return ids, err
+}
+
+func (r *BookmarkRepository) FindByUser(userID uint, collectionID *uint, page pagination.CursorPagination) ([]bookmark.Bookmark, error) {
+	// Bookmarks of posts that were unpublished, hidden or no longer shared with the user stay,
+	// they are listed again once the user may see the post
+	query := r.db.Where("user_id = ? AND post_id IN (?)", userID,
+		r.db.Model(&post.Post{}).Scopes(public, sharedWith(userID)).Select("id"))
+	if collectionID != nil {
+		query = query.Where("collection_id = ?", *collectionID)
+	}
+
+	query, err := pagination.ApplyCursorPagination(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []bookmark.Bookmark
+	err = query.Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+func (r *BookmarkRepository) CreateCollection(c *bookmark.Collection) error {
+	return r.db.Create(c).Error
+}
+
+func (r *BookmarkRepository) FindCollection(id uint) (*bookmark.Collection, error) {
+	var c bookmark.Collection
+	if err := r.db.First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *BookmarkRepository) FindCollections(userID uint) ([]bookmark.Collection, error) {
+	var collections []bookmark.Collection
+	err := r.db.Where("user_id = ?", userID).Order("position, id").Find(&collections).Error
+	return collections, err
+}
+
+func (r *BookmarkRepository) CountByCollections(userID uint) (map[uint]int, error) {
+	var rows []struct {
+		CollectionID uint
+		Count        int
+	}
+	err := r.db.Model(&bookmark.Bookmark{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("user_id = ? AND collection_id IS NOT NULL", userID).
+		Group("collection_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *BookmarkRepository) UpdateCollection(c *bookmark.Collection) error {
+	return r.db.Save(c).Error
+}
+
+func (r *BookmarkRepository) DeleteCollection(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&bookmark.Bookmark{}).Where("collection_id = ?", id).Update("collection_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&bookmark.Collection{}, id).Error
+	})
+}
+
+func (r *BookmarkRepository) SetPositions(userID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			err := tx.Model(&bookmark.Collection{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				Update("position", i).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/pagination"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(d *gorm.DB) comment.Repository {
+	return &CommentRepository{
+		db: d,
+	}
+}
+
+// Create stores the comment and bumps the post comment_count and parent reply_count
+func (r *CommentRepository) Create(c *comment.Comment) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(c).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&post.Post{}).Where("id = ?", c.PostID).
+			UpdateColumn("comment_count", gorm.Expr("comment_count + 1")).Error; err != nil {
+			return err
+		}
+		if c.ParentID != nil {
+			return tx.Unscoped().Model(&comment.Comment{}).Where("id = ?", *c.ParentID).
+				UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return r.db.Preload("User").First(c, c.ID).Error
+}
+
+func (r *CommentRepository) FindByID(id uint) (*comment.Comment, error) {
+	var c comment.Comment
+	err := r.db.Preload("User").Where("id = ?", id).First(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindThreadParent returns a comment whose replies can be listed, deleted comments count while
+// they still have replies, as in FindThread
+func (r *CommentRepository) FindThreadParent(id uint) (*comment.Comment, error) {
+	var c comment.Comment
+	err := r.db.Unscoped().
+		Where("id = ?", id).
+		Where("deleted_at IS NULL OR reply_count > 0").
+		First(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindThread returns the direct children of parentID (top-level comments when nil).
+// Deleted comments are kept when they still have replies so the thread stays intact.
+func (r *CommentRepository) FindThread(postID uint, parentID *uint, page pagination.CursorPagination) ([]comment.Comment, error) {
+	query := r.db.Unscoped().Preload("User").
+		Where("post_id = ?", postID).
+		Where("deleted_at IS NULL OR reply_count > 0")
+	if parentID != nil {
+		query = query.Where("parent_id = ?", *parentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+
+	query, err := pagination.ApplyCursorPagination(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []comment.Comment
+	err = query.Find(&comments).Error
+	return comments, err
+}
+
+func (r *CommentRepository) Update(c *comment.Comment) error {
+	return r.db.Model(c).Select("body", "edited_at").Updates(c).Error
+}
+
+// Delete soft-deletes the comment and decrements the post and parent counters
+func (r *CommentRepository) Delete(c *comment.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(c).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&post.Post{}).Where("id = ? AND comment_count > 0", c.PostID).
+			UpdateColumn("comment_count", gorm.Expr("comment_count - 1")).Error; err != nil {
+			return err
+		}
+		if c.ParentID != nil {
+			return tx.Unscoped().Model(&comment.Comment{}).Where("id = ? AND reply_count > 0", *c.ParentID).
+				UpdateColumn("reply_count", gorm.Expr("reply_count - 1")).Error
+		}
+		return nil
+	})
+}
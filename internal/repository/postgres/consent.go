@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/consent"
+
+	"gorm.io/gorm"
+)
+
+type ConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(d *gorm.DB) consent.Repository {
+	return &ConsentRepository{
+		db: d,
+	}
+}
+
+func (r *ConsentRepository) CreatePolicyVersion(policy *consent.PolicyVersion) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *ConsentRepository) FindPolicyVersion(policyType consent.PolicyType, version string) (*consent.PolicyVersion, error) {
+	var policy consent.PolicyVersion
+	err := r.db.Where("type = ? AND version = ?", policyType, version).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindCurrentPolicies returns the latest version of each policy type published before now
+func (r *ConsentRepository) FindCurrentPolicies(now time.Time) ([]consent.PolicyVersion, error) {
+	var policies []consent.PolicyVersion
+	latest := r.db.Model(&consent.PolicyVersion{}).
+		Select("type, MAX(published_at) AS published_at").
+		Where("published_at <= ?", now).
+		Group("type")
+
+	err := r.db.Model(&consent.PolicyVersion{}).
+		Joins("JOIN (?) AS latest ON latest.type = policy_versions.type AND latest.published_at = policy_versions.published_at", latest).
+		Order("policy_versions.type, policy_versions.id DESC").
+		Find(&policies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a single row per type if two versions share the same publish time
+	seen := make(map[consent.PolicyType]bool, len(policies))
+	current := make([]consent.PolicyVersion, 0, len(policies))
+	for _, p := range policies {
+		if seen[p.Type] {
+			continue
+		}
+		seen[p.Type] = true
+		current = append(current, p)
+	}
+	return current, nil
+}
+
+func (r *ConsentRepository) CreateUserConsents(consents []consent.UserConsent) error {
+	return r.db.Create(&consents).Error
+}
+
+// FindLatestUserConsents returns the most recent acceptance per policy type
+func (r *ConsentRepository) FindLatestUserConsents(userID uint) ([]consent.UserConsent, error) {
+	consents, err := r.FindUserConsents(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[consent.PolicyType]bool, len(consent.RequiredPolicies))
+	latest := make([]consent.UserConsent, 0, len(consent.RequiredPolicies))
+	for _, c := range consents {
+		if seen[c.PolicyType] {
+			continue
+		}
+		seen[c.PolicyType] = true
+		latest = append(latest, c)
+	}
+	return latest, nil
+}
+
+func (r *ConsentRepository) FindUserConsents(userID uint) ([]consent.UserConsent, error) {
+	var consents []consent.UserConsent
+	err := r.db.Where("user_id = ?", userID).
+		Order("accepted_at DESC, id DESC").
+		Find(&consents).Error
+	return consents, err
+}
+
+func (r *ConsentRepository) CreateMarketingConsent(c *consent.MarketingConsent) error {
+	return r.db.Create(c).Error
+}
+
+func (r *ConsentRepository) FindMarketingConsents(userID uint) ([]consent.MarketingConsent, error) {
+	var consents []consent.MarketingConsent
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&consents).Error
+	return consents, err
+}
+
+func (r *ConsentRepository) FindAllUserConsents(from, to *time.Time) ([]consent.UserConsent, error) {
+	var consents []consent.UserConsent
+	query := r.db.Preload("User")
+	if from != nil {
+		query = query.Where("accepted_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("accepted_at <= ?", *to)
+	}
+	err := query.Order("accepted_at ASC, id ASC").Find(&consents).Error
+	return consents, err
+}
+
+func (r *ConsentRepository) FindAllMarketingConsents(from, to *time.Time) ([]consent.MarketingConsent, error) {
+	var consents []consent.MarketingConsent
+	query := r.db.Preload("User")
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at <= ?", *to)
+	}
+	err := query.Order("created_at ASC, id ASC").Find(&consents).Error
+	return consents, err
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(d *gorm.DB) dataimport.Repository {
+	return &ImportRepository{
+		db: d,
+	}
+}
+
+func (r *ImportRepository) Create(i *dataimport.Import) error {
+	return r.db.Create(i).Error
+}
+
+func (r *ImportRepository) FindByID(id uint) (*dataimport.Import, error) {
+	var i dataimport.Import
+	err := r.db.Where("id = ?", id).First(&i).Error
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *ImportRepository) FindAll(limit, offset int) ([]dataimport.Import, int64, error) {
+	var imports []dataimport.Import
+	var total int64
+	if err := r.db.Model(&dataimport.Import{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := r.db.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&imports).Error
+	return imports, total, err
+}
+
+func (r *ImportRepository) Save(i *dataimport.Import) error {
+	return r.db.Save(i).Error
+}
+
+func (r *ImportRepository) FindUsers(emails, usernames []string) ([]user.User, error) {
+	var users []user.User
+	if len(emails) == 0 && len(usernames) == 0 {
+		return users, nil
+	}
+	// Deleted users keep their email and username until they are purged
+	// Emails of the file are lower case, registered ones keep the case they were given
+	err := r.db.Unscoped().Select("id", "email", "username").
+		Where("LOWER(email) IN ?", emails).Or("username IN ?", usernames).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *ImportRepository) FindAuthors(emails []string, ids []uint) ([]user.User, error) {
+	var users []user.User
+	if len(emails) == 0 && len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("LOWER(email) IN ?", emails).Or("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *ImportRepository) Insert(records []interface{}) *database.BulkCreateResult {
+	return database.BulkCreateWithValidation(r.db, records)
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/pkg/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(d *gorm.DB) follow.Repository {
+	return &FollowRepository{
+		db: d,
+	}
+}
+
+func (r *FollowRepository) Create(f *follow.Follow) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(f)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *FollowRepository) Delete(followerID, followeeID uint) (bool, error) {
+	result := r.db.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&follow.Follow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *FollowRepository) Exists(followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&follow.Follow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *FollowRepository) FindFollowers(userID uint, page pagination.CursorPagination) ([]follow.Follow, error) {
+	query, err := pagination.ApplyCursorPagination(
+		r.db.Preload("Follower").Where("followee_id = ?", userID), page)
+	if err != nil {
+		return nil, err
+	}
+
+	var follows []follow.Follow
+	err = query.Find(&follows).Error
+	return follows, err
+}
+
+func (r *FollowRepository) FindFollowing(userID uint, page pagination.CursorPagination) ([]follow.Follow, error) {
+	query, err := pagination.ApplyCursorPagination(
+		r.db.Preload("Followee").Where("follower_id = ?", userID), page)
+	if err != nil {
+		return nil, err
+	}
+
+	var follows []follow.Follow
+	err = query.Find(&follows).Error
+	return follows, err
+}
+
+func (r *FollowRepository) CountFollowers(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&follow.Follow{}).Where("followee_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *FollowRepository) CountFollowing(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&follow.Follow{}).Where("follower_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *FollowRepository) FollowingIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&follow.Follow{}).Where("follower_id = ?", userID).Pluck("followee_id", &ids).Error
+	return ids, err
+}
+
+func (r *FollowRepository) FollowerIDsAfter(userID, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&follow.Follow{}).
+		Where("followee_id = ? AND follower_id > ?", userID, afterID).
+		Order("follower_id ASC").
+		Limit(limit).
+		Pluck("follower_id", &ids).Error
+	return ids, err
+}
+
+func (r *FollowRepository) PopularIDs(userIDs []uint, minFollowers int64) ([]uint, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&follow.Follow{}).
+		Where("followee_id IN ?", userIDs).
+		Group("followee_id").
+		Having("COUNT(*) >= ?", minFollowers).
+		Pluck("followee_id", &ids).Error
+	return ids, err
+}
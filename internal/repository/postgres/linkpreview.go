@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"errors"
+
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/post"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LinkPreviewRepository struct {
+	db *gorm.DB
+}
+
+func NewLinkPreviewRepository(d *gorm.DB) linkpreview.Repository {
+	return &LinkPreviewRepository{
+		db: d,
+	}
+}
+
+func (r *LinkPreviewRepository) FindByURL(url string) (*linkpreview.LinkPreview, error) {
+	var p linkpreview.LinkPreview
+	err := r.db.Where("url_hash = ?", linkpreview.HashURL(url)).First(&p).Error
+	return &p, err
+}
+
+func (r *LinkPreviewRepository) Save(p *linkpreview.LinkPreview) ([]string, error) {
+	var replaced []string
+	p.URLHash = linkpreview.HashURL(p.URL)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var old linkpreview.LinkPreview
+		err := tx.Where("url_hash = ?", p.URLHash).First(&old).Error
+		if err == nil {
+			// Posts keep pointing at the same row
+			p.ID, p.CreatedAt = old.ID, old.CreatedAt
+			replaced = old.Files()
+			return tx.Save(p).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		// The unique index settles concurrent fetches of the same URL, the last one wins
+		return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "url_hash"}}, UpdateAll: true}).Create(p).Error
+	})
+	return replaced, err
+}
+
+func (r *LinkPreviewRepository) AttachToPost(postID uint, previewID *uint) error {
+	// UpdateColumn leaves updated_at alone, the author did not change the post
+	return r.db.Model(&post.Post{}).Where("id = ?", postID).UpdateColumn("link_preview_id", previewID).Error
+}
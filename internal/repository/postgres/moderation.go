@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+type ModerationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(d *gorm.DB) moderation.Repository {
+	return &ModerationRepository{
+		db: d,
+	}
+}
+
+func (r *ModerationRepository) Create(report *moderation.Report) error {
+	return r.db.Create(report).Error
+}
+
+func (r *ModerationRepository) FindByID(id uint) (*moderation.Report, error) {
+	var report moderation.Report
+	err := r.db.Preload("Reporter").Preload("Assignee").First(&report, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *ModerationRepository) FindPendingByReporter(reporterID uint, targetType string, targetID uint) (*moderation.Report, error) {
+	var report moderation.Report
+	err := r.db.Where("reporter_id = ? AND target_type = ? AND target_id = ? AND status IN ?",
+		reporterID, targetType, targetID, []string{moderation.StatusOpen, moderation.StatusInReview}).
+		First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *ModerationRepository) FindAll(filter moderation.ReportFilter, limit, offset int) ([]moderation.Report, int64, error) {
+	var reports []moderation.Report
+	var total int64
+
+	query := r.db.Model(&moderation.Report{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else {
+		query = query.Where("status IN ?", []string{moderation.StatusOpen, moderation.StatusInReview})
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Reporter").Preload("Assignee").
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error
+
+	return reports, total, err
+}
+
+func (r *ModerationRepository) Update(report *moderation.Report) error {
+	return r.db.Omit("Reporter", "Assignee").Save(report).Error
+}
+
+func (r *ModerationRepository) ResolvePending(targetType string, targetID uint, status, resolution, note string, resolverID uint, at time.Time) error {
+	return r.db.Model(&moderation.Report{}).
+		Where("target_type = ? AND target_id = ? AND status IN ?", targetType, targetID, []string{moderation.StatusOpen, moderation.StatusInReview}).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"resolution":      resolution,
+			"resolution_note": note,
+			"resolved_by_id":  resolverID,
+			"resolved_at":     at,
+		}).Error
+}
+
+func (r *ModerationRepository) LogAction(moderatorID *uint, entityType string, entityID uint, description string, data interface{}) error {
+	if !moderation.AuditLogEnabled() {
+		return nil
+	}
+
+	logger := database.NewAuditLogger(r.db)
+	if moderatorID != nil {
+		var moderator user.User
+		if err := r.db.Select("id", "name").First(&moderator, *moderatorID).Error; err == nil {
+			logger.WithUser(moderator.ID, moderator.Name)
+		} else {
+			logger.WithUser(*moderatorID, "")
+		}
+	}
+	return logger.LogModeration(entityType, entityID, description, data)
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PollRepository struct {
+	db *gorm.DB
+}
+
+func NewPollRepository(d *gorm.DB) poll.Repository {
+	return &PollRepository{
+		db: d,
+	}
+}
+
+// withOptions preloads the options in display order
+func withOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+func (r *PollRepository) FindByPostID(postID uint) (*poll.Poll, error) {
+	var p poll.Poll
+	err := r.db.Preload("Options", withOptions).Where("post_id = ?", postID).First(&p).Error
+	return &p, err
+}
+
+func (r *PollRepository) CastBallot(b *poll.Ballot) (bool, error) {
+	cast := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The unique index settles concurrent ballots of the same user
+		votes := b.Votes
+		b.Votes = nil
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(b)
+		b.Votes = votes
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		for i := range b.Votes {
+			b.Votes[i].BallotID = b.ID
+			b.Votes[i].PollID = b.PollID
+		}
+		cast = true
+		return tx.Create(&b.Votes).Error
+	})
+	return cast, err
+}
+
+func (r *PollRepository) FindBallots(pollIDs []uint, userID uint) ([]poll.Ballot, error) {
+	var ballots []poll.Ballot
+	if len(pollIDs) == 0 || userID == 0 {
+		return ballots, nil
+	}
+	err := r.db.Preload("Votes").Where("poll_id IN ? AND user_id = ?", pollIDs, userID).Find(&ballots).Error
+	return ballots, err
+}
+
+func (r *PollRepository) CountVotes(pollIDs []uint) (map[uint]poll.Tally, error) {
+	tallies := make(map[uint]poll.Tally, len(pollIDs))
+	if len(pollIDs) == 0 {
+		return tallies, nil
+	}
+	for _, id := range pollIDs {
+		tallies[id] = poll.Tally{Options: make(map[uint]int)}
+	}
+
+	var votes []struct {
+		PollID   uint
+		OptionID uint
+		Count    int
+	}
+	err := r.db.Model(&poll.Vote{}).Select("poll_id, option_id, COUNT(*) AS count").
+		Where("poll_id IN ?", pollIDs).Group("poll_id, option_id").Scan(&votes).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range votes {
+		tallies[v.PollID].Options[v.OptionID] = v.Count
+	}
+
+	var voters []struct {
+		PollID uint
+		Count  int
+	}
+	err = r.db.Model(&poll.Ballot{}).Select("poll_id, COUNT(*) AS count").
+		Where("poll_id IN ?", pollIDs).Group("poll_id").Scan(&voters).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range voters {
+		t := tallies[v.PollID]
+		t.Voters = v.Count
+		tallies[v.PollID] = t
+	}
+	return tallies, nil
+}
+
+func (r *PollRepository) FindVoterIDs(pollID uint, userIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(userIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&poll.Ballot{}).Where("poll_id = ? AND user_id IN ?", pollID, userIDs).Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *PollRepository) FindDue(now time.Time, limit int) ([]poll.Poll, error) {
+	// Polls of deleted posts are left open, they close once the post is restored
+	var polls []poll.Poll
+	err := r.db.Preload("Options", withOptions).
+		Where("closed_at IS NULL AND expires_at <= ?", now).
+		Where("post_id IN (?)", r.db.Model(&post.Post{}).Select("id")).
+		Order("expires_at ASC, id ASC").Limit(limit).Find(&polls).Error
+	return polls, err
+}
+
+func (r *PollRepository) Close(pollID uint, tally poll.Tally, at time.Time) (bool, error) {
+	closed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&poll.Poll{}).Where("id = ? AND closed_at IS NULL", pollID).
+			Updates(map[string]interface{}{"closed_at": at, "voter_count": tally.Voters})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		closed = true
+		for optionID, count := range tally.Options {
+			err := tx.Model(&poll.Option{}).Where("id = ? AND poll_id = ?", optionID, pollID).
+				UpdateColumn("vote_count", count).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return closed, err
+}
@@ -1,11 +1,45 @@
 package postgres
 
 import (
+	"time"
+
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/poll"
 	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/pkg/database"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+func init() {
+	// A deleted quote no longer counts on the post it quoted
+	database.OnSoftDelete("posts", func(tx *gorm.DB, ids []uint) error {
+		var quoted []uint
+		err := tx.Unscoped().Model(&post.Post{}).
+			Where("id IN ? AND quoted_post_id IS NOT NULL", ids).
+			Pluck("quoted_post_id", &quoted).Error
+		if err != nil {
+			return err
+		}
+		for _, id := range quoted {
+			err := tx.Model(&post.Post{}).Where("id = ? AND quote_count > 0", id).
+				UpdateColumn("quote_count", gorm.Expr("quote_count - 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 type PostRepository struct {
 	db *gorm.DB
 }
@@ -16,36 +50,104 @@ func NewPostRepository(d *gorm.DB) post.Repository {
 	}
 }
 
+// withPostRelations preloads the relations rendered by PostResponse
+func withPostRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").Preload("Mentions").Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+		return db.Where("detached_at IS NULL").Order("position")
+	}).Preload("Poll.Options", withOptions).Preload("LinkPreview")
+}
+
+// withQuoted preloads the post a quote embeds when the viewer may open it. Only one level is
+// loaded, the embedded post shows its own quoted_post_id without the post.
+func withQuoted(viewerID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload("QuotedPost", visibleTo(viewerID)).
+			Preload("QuotedPost.User").
+			Preload("QuotedPost.Mentions").
+			Preload("QuotedPost.Attachments", func(db *gorm.DB) *gorm.DB {
+				return db.Where("detached_at IS NULL").Order("position")
+			}).
+			Preload("QuotedPost.Poll.Options", withOptions).
+			Preload("QuotedPost.LinkPreview")
+	}
+}
+
+// public limits a query to posts that went live and were not flagged or hidden by moderation
+func public(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ? AND moderation_state = ?", post.StatusPublished, post.ModerationVisible)
+}
+
+// audience is the condition matching posts whose visibility shares them with the viewer, 0 for anonymous viewers
+func audience(viewerID uint) (string, []interface{}) {
+	if viewerID == 0 {
+		return "visibility = ?", []interface{}{post.VisibilityPublic}
+	}
+	return `visibility = ? OR user_id = ?
+		OR (visibility = ? AND user_id IN (SELECT followee_id FROM follows WHERE follower_id = ?))
+		OR (visibility <> ? AND id IN (SELECT post_id FROM post_mentions WHERE user_id = ?))`,
+		[]interface{}{
+			post.VisibilityPublic, viewerID,
+			post.VisibilityFollowers, viewerID,
+			post.VisibilityPrivate, viewerID,
+		}
+}
+
+// sharedWith limits a query to posts whose visibility shares them with the viewer, use it with public for listings
+func sharedWith(viewerID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		cond, args := audience(viewerID)
+		return db.Where("("+cond+")", args...)
+	}
+}
+
+// visibleTo limits a query to posts the viewer may open: their own in any state and others' live posts shared with them
+func visibleTo(viewerID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		cond, args := audience(viewerID)
+		args = append([]interface{}{viewerID, post.StatusPublished, post.ModerationVisible}, args...)
+		return db.Where("(user_id = ? OR (status = ? AND moderation_state = ? AND ("+cond+")))", args...)
+	}
+}
+
 func (r *PostRepository) Create(p *post.Post) error {
-	err := r.db.Create(p).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(p).Error; err != nil {
+			return err
+		}
+		if p.QuotedPostID == nil {
+			return nil
+		}
+		return tx.Model(&post.Post{}).Where("id = ?", *p.QuotedPostID).
+			UpdateColumn("quote_count", gorm.Expr("quote_count + 1")).Error
+	})
 	if err != nil {
 		return err
 	}
-	// Preload User relation
-	err = r.db.Model(p).Preload("User").First(p).Error
+	// Preload relations rendered in the response
+	err = withPostRelations(r.db).Scopes(withQuoted(p.UserID)).Model(p).First(p).Error
 	return err
 }
 
 func (r *PostRepository) FindByID(id uint) (*post.Post, error) {
 	var p post.Post
-	err := r.db.Preload("User").Where("id = ?", id).First(&p).Error
+	err := withPostRelations(r.db).Where("id = ?", id).First(&p).Error
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *PostRepository) FindAll(limit, offset int) ([]post.Post, int64, error) {
+func (r *PostRepository) FindAll(viewerID uint, limit, offset int) ([]post.Post, int64, error) {
 	var posts []post.Post
 	var total int64
 
 	// Count total
-	if err := r.db.Model(&post.Post{}).Count(&total).Error; err != nil {
+	if err := r.db.Model(&post.Post{}).Scopes(public, sharedWith(viewerID)).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
-	err := r.db.Preload("User").
+	err := withPostRelations(r.db).Scopes(withQuoted(viewerID), public, sharedWith(viewerID)).
 		Limit(limit).
 		Offset(offset).
 		Order("created_at DESC").
@@ -54,25 +156,97 @@ func (r *PostRepository) FindAll(limit, offset int) ([]post.Post, int64, error)
 	return posts, total, err
 }
 
+func (r *PostRepository) FindPage(filter post.ListFilter, viewerID uint, page pagination.CursorPagination) ([]post.Post, error) {
+	filters := filter.Filters
+	if filter.UserID > 0 {
+		filters = append(filters, utils.Filter{Field: "user_id", Operator: utils.OpEqual, Value: filter.UserID})
+	}
+	if filter.QuotedPostID > 0 {
+		filters = append(filters, utils.Filter{Field: "quoted_post_id", Operator: utils.OpEqual, Value: filter.QuotedPostID})
+	}
+	if filter.CreatedFrom != nil {
+		filters = append(filters, utils.Filter{Field: "created_at", Operator: utils.OpGreaterThanOrEqual, Value: *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		filters = append(filters, utils.Filter{Field: "created_at", Operator: utils.OpLessThanOrEqual, Value: *filter.CreatedTo})
+	}
+
+	query := utils.ApplyFilters(withPostRelations(r.db).Scopes(withQuoted(viewerID), public, sharedWith(viewerID)), filters)
+	if filter.HasPhoto != nil {
+		withPhoto := r.db.Model(&post.Post{}).Select("id").
+			Where("photo IS NOT NULL AND photo <> ''").
+			Or("id IN (?)", r.db.Model(&post.Attachment{}).Select("post_id").
+				Where("kind = ? AND detached_at IS NULL AND post_id IS NOT NULL", post.AttachmentImage))
+		if *filter.HasPhoto {
+			query = query.Where("id IN (?)", withPhoto)
+		} else {
+			query = query.Where("id NOT IN (?)", withPhoto)
+		}
+	}
+
+	query, err := pagination.ApplyCursorPagination(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []post.Post
+	err = query.Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) FindVisibleByID(id, viewerID uint) (*post.Post, error) {
+	var p post.Post
+	err := withPostRelations(r.db).Scopes(withQuoted(viewerID), visibleTo(viewerID)).Where("id = ?", id).First(&p).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PostRepository) FindVisibleBySlug(slug string, authorID, viewerID uint) (*post.Post, error) {
+	query := withPostRelations(r.db).Scopes(withQuoted(viewerID), visibleTo(viewerID)).Where("slug = ?", slug)
+	if authorID > 0 {
+		query = query.Where("user_id = ?", authorID)
+	}
+
+	var p post.Post
+	if err := query.Order("id ASC").First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PostRepository) SlugTaken(userID uint, slug string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&post.Post{}).Where("user_id = ? AND slug = ?", userID, slug).Count(&count).Error
+	return count > 0, err
+}
+
 func (r *PostRepository) Update(p *post.Post) error {
-	return r.db.Model(p).Updates(p).Error
+	// Counters are only changed atomically by their owning modules, the moderation
+	// state by moderators, the link preview by the unfurl job and the quoted post never.
+	// Zero values are written too, clearing PublishAt is how a schedule is cancelled.
+	return r.db.Model(p).Select("*").
+		Omit("comment_count", "repost_count", "quote_count", "quoted_post_id", "link_preview_id", "moderation_state", "created_at", clause.Associations).
+		Updates(p).Error
 }
 
 func (r *PostRepository) Delete(id uint) error {
-	return r.db.Delete(&post.Post{}, id).Error
+	// Keyed by the model so delete callbacks (e.g. search indexing) see the ID
+	return r.db.Delete(&post.Post{ID: id}).Error
 }
 
-func (r *PostRepository) FindByUserID(userID uint, limit, offset int) ([]post.Post, int64, error) {
+func (r *PostRepository) FindByUserID(userID, viewerID uint, limit, offset int) ([]post.Post, int64, error) {
 	var posts []post.Post
 	var total int64
 
 	// Count total for this user
-	if err := r.db.Model(&post.Post{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := r.db.Model(&post.Post{}).Scopes(public, sharedWith(viewerID)).Where("user_id = ?", userID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
-	err := r.db.Preload("User").
+	err := withPostRelations(r.db).Scopes(withQuoted(viewerID), public, sharedWith(viewerID)).
 		Where("user_id = ?", userID).
 		Limit(limit).
 		Offset(offset).
@@ -81,3 +255,422 @@ func (r *PostRepository) FindByUserID(userID uint, limit, offset int) ([]post.Po
 
 	return posts, total, err
 }
+
+func (r *PostRepository) FindByIDs(ids []uint, viewerID uint) ([]post.Post, error) {
+	var posts []post.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	err := withPostRelations(r.db).Scopes(withQuoted(viewerID), public, sharedWith(viewerID)).Where("id IN ?", ids).Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) FindTimeline(userIDs []uint, viewerID uint, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	entries := []post.TimelineEntry{}
+	if len(userIDs) == 0 {
+		return entries, nil
+	}
+
+	// Scheduled posts take their place by when they went live, not by ID
+	const publishedAt = "COALESCE(publish_at, created_at)"
+	query := r.db.Model(&post.Post{}).Select("id", "publish_at", "created_at").
+		Scopes(public, sharedWith(viewerID)).Where("user_id IN ?", userIDs)
+	if before != nil {
+		at := time.UnixMicro(before.Score)
+		query = query.Where(publishedAt+" < ? OR ("+publishedAt+" = ? AND id < ?)", at, at, before.ID)
+	}
+
+	var posts []post.Post
+	if err := query.Order(publishedAt + " DESC, id DESC").Limit(limit).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range posts {
+		entries = append(entries, post.TimelineEntry{ID: p.ID, Score: p.PublishedScore()})
+	}
+	return entries, nil
+}
+
+func (r *PostRepository) FindUnpublishedByUserID(userID uint, limit, offset int) ([]post.Post, int64, error) {
+	var posts []post.Post
+	var total int64
+
+	query := r.db.Model(&post.Post{}).Where("user_id = ? AND status <> ?", userID, post.StatusPublished)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Scheduled posts first by when they go live, then drafts by last edit
+	err := withPostRelations(r.db).Scopes(withQuoted(userID)).
+		Where("user_id = ? AND status <> ?", userID, post.StatusPublished).
+		Order("CASE WHEN publish_at IS NULL THEN 1 ELSE 0 END, publish_at, updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
+func (r *PostRepository) Publish(id uint, at time.Time) (bool, error) {
+	// Conditional on the status so a post is only published once, keyed by the model for the search index
+	result := r.db.Model(&post.Post{ID: id}).
+		Where("status = ?", post.StatusScheduled).
+		Updates(map[string]interface{}{"status": post.StatusPublished, "publish_at": at})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostRepository) SetModerationState(id uint, state string) error {
+	// Keyed by the model so the search index drops or restores the post
+	return r.db.Model(&post.Post{ID: id}).Update("moderation_state", state).Error
+}
+
+func (r *PostRepository) SyncTags(postID uint, names []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", postID).Delete(&post.PostTag{}).Error; err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		tags := make([]post.Tag, len(names))
+		for i, name := range names {
+			tags[i] = post.Tag{Name: name}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+
+		// IDs are not returned for rows that already existed, so look them all up
+		var ids []uint
+		if err := tx.Model(&post.Tag{}).Where("name IN ?", names).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		links := make([]post.PostTag, len(ids))
+		for i, id := range ids {
+			links[i] = post.PostTag{PostID: postID, TagID: id}
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+func (r *PostRepository) SyncMentions(postID uint, mentions []post.PostMention) ([]post.PostMention, error) {
+	var added []post.PostMention
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []post.PostMention
+		if err := tx.Where("post_id = ?", postID).Find(&existing).Error; err != nil {
+			return err
+		}
+		old := make(map[uint]bool, len(existing))
+		for _, m := range existing {
+			old[m.UserID] = true
+		}
+
+		if err := tx.Where("post_id = ?", postID).Delete(&post.PostMention{}).Error; err != nil {
+			return err
+		}
+		if len(mentions) == 0 {
+			return nil
+		}
+		for i := range mentions {
+			mentions[i].PostID = postID
+			if !old[mentions[i].UserID] {
+				added = append(added, mentions[i])
+			}
+		}
+		return tx.Create(&mentions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+func (r *PostRepository) FindByTag(name string, viewerID uint, page pagination.CursorPagination) ([]post.Post, error) {
+	// Subquery keeps "id" unambiguous for the cursor condition
+	tagged := r.db.Model(&post.PostTag{}).
+		Select("post_tags.post_id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("tags.name = ?", name)
+
+	query, err := pagination.ApplyCursorPagination(
+		withPostRelations(r.db).Scopes(withQuoted(viewerID), public, sharedWith(viewerID)).Where("id IN (?)", tagged), page)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []post.Post
+	err = query.Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) CreateAttachment(a *post.Attachment) error {
+	return r.db.Create(a).Error
+}
+
+func (r *PostRepository) FindAttachmentsByIDs(ids []uint) ([]post.Attachment, error) {
+	var attachments []post.Attachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *PostRepository) SetAttachments(postID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&post.Attachment{}).Where("post_id = ? AND detached_at IS NULL", postID)
+		if len(ids) > 0 {
+			query = query.Where("id NOT IN ?", ids)
+		}
+		if err := query.Update("detached_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			err := tx.Model(&post.Attachment{}).Where("id = ?", id).
+				Updates(map[string]interface{}{"post_id": postID, "position": i, "detached_at": nil}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostRepository) FindPostAttachments(postID uint) ([]post.Attachment, error) {
+	var attachments []post.Attachment
+	err := r.db.Where("post_id = ?", postID).Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *PostRepository) DeletePostAttachments(postID uint) ([]post.Attachment, error) {
+	var attachments []post.Attachment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", postID).Find(&attachments).Error; err != nil {
+			return err
+		}
+		if len(attachments) == 0 {
+			return nil
+		}
+		return tx.Delete(&attachments).Error
+	})
+	return attachments, err
+}
+
+func (r *PostRepository) DeleteAttachments(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", ids).Delete(&post.Attachment{}).Error
+}
+
+func (r *PostRepository) CreateRevision(rev *post.Revision) error {
+	return r.db.Create(rev).Error
+}
+
+func (r *PostRepository) FindRevision(id uint) (*post.Revision, error) {
+	var rev post.Revision
+	err := r.db.Preload("User").Where("id = ?", id).First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (r *PostRepository) FindRevisions(postID uint, page pagination.CursorPagination) ([]post.Revision, error) {
+	page.SortBy = "id"
+	page.SortOrder = "desc"
+	query, err := pagination.ApplyCursorPagination(r.db.Preload("User").Where("post_id = ?", postID), page)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []post.Revision
+	err = query.Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *PostRepository) DeleteRevisions(postID uint) ([]post.Revision, error) {
+	var revisions []post.Revision
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", postID).Find(&revisions).Error; err != nil {
+			return err
+		}
+		return tx.Where("post_id = ?", postID).Delete(&post.Revision{}).Error
+	})
+	return revisions, err
+}
+
+func (r *PostRepository) FindTrashed(userID uint, limit, offset int) ([]post.Post, int64, error) {
+	var posts []post.Post
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		if userID != 0 {
+			return db.Where("user_id = ?", userID)
+		}
+		return db
+	}
+	total, err := database.CountTrashed(r.db.Scopes(scope), &post.Post{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = withPostRelations(r.db).Scopes(database.OnlyTrashed, scope).
+		Order("deleted_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
+func (r *PostRepository) FindTrashedByID(id uint) (*post.Post, error) {
+	var p post.Post
+	err := r.db.Scopes(database.OnlyTrashed).Where("id = ?", id).First(&p).Error
+	return &p, err
+}
+
+func (r *PostRepository) FindTrashedBefore(before time.Time, limit int) ([]post.Post, error) {
+	var posts []post.Post
+	err := r.db.Scopes(database.OnlyTrashed).
+		Where("deleted_at < ?", before).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) Restore(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Keyed by the model so update callbacks (e.g. search indexing) see the ID
+		if err := database.RestoreByID(tx, &post.Post{ID: id}, id); err != nil {
+			return err
+		}
+
+		// Reposts went with the post, the quote counts again while the quoted post exists
+		var p post.Post
+		if err := tx.Select("id", "quoted_post_id").Where("id = ?", id).First(&p).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&post.Post{}).Where("id = ?", id).UpdateColumn("repost_count", 0).Error; err != nil {
+			return err
+		}
+		if p.QuotedPostID == nil {
+			return nil
+		}
+		return tx.Model(&post.Post{}).Where("id = ?", *p.QuotedPostID).
+			UpdateColumn("quote_count", gorm.Expr("quote_count + 1")).Error
+	})
+}
+
+func (r *PostRepository) ForceDelete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ballots := tx.Model(&poll.Ballot{}).Select("id").Where("poll_id IN (?)", tx.Model(&poll.Poll{}).Select("id").Where("post_id = ?", id))
+		if err := tx.Where("ballot_id IN (?)", ballots).Delete(&poll.Vote{}).Error; err != nil {
+			return err
+		}
+		polls := tx.Model(&poll.Poll{}).Select("id").Where("post_id = ?", id)
+		for _, model := range []interface{}{&poll.Ballot{}, &poll.Option{}} {
+			if err := tx.Where("poll_id IN (?)", polls).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, model := range []interface{}{
+			&poll.Poll{},
+			&comment.Comment{},
+			&reaction.Reaction{},
+			&reaction.Count{},
+			&bookmark.Bookmark{},
+			&repost.Repost{},
+			&post.PostMention{},
+			&post.PostTag{},
+			&post.Revision{},
+			&post.Attachment{},
+			&analytics.View{},
+			&analytics.DailyStat{},
+		} {
+			if err := tx.Unscoped().Where("post_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("target_type = ? AND target_id = ?", moderation.TargetPost, id).Delete(&moderation.Report{}).Error; err != nil {
+			return err
+		}
+
+		// Keyed by the model so delete callbacks (e.g. search indexing) see the ID
+		return database.ForceDeleteByID(tx, &post.Post{ID: id}, id)
+	})
+}
+
+func (r *PostRepository) FindForBulk(ids []uint, trashed bool) ([]post.Post, error) {
+	var posts []post.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	query := r.db
+	if trashed {
+		query = query.Scopes(database.OnlyTrashed)
+	}
+	err := query.Select("id", "user_id", "status", "publish_at", "quoted_post_id").Where("id IN ?", ids).Find(&posts).Error
+	return posts, err
+}
+
+// postKeys returns models carrying only the IDs, for writes whose callbacks read the IDs from the model
+func postKeys(ids []uint) []post.Post {
+	keys := make([]post.Post, len(ids))
+	for i, id := range ids {
+		keys[i].ID = id
+	}
+	return keys
+}
+
+func (r *PostRepository) BulkDelete(ids []uint) (int, error) {
+	// Keyed by the models so soft delete hooks and search indexing see the IDs
+	keys := postKeys(ids)
+	result, err := database.BulkDelete(r.db, &keys, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *PostRepository) BulkRestore(ids []uint) (int, error) {
+	restored := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var posts []post.Post
+		if err := tx.Scopes(database.OnlyTrashed).Select("id", "quoted_post_id").Where("id IN ?", ids).Find(&posts).Error; err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+
+		// Keyed by the models so update callbacks (e.g. search indexing) see the IDs
+		keys := postKeys(ids)
+		result, err := database.BulkRestore(tx, &keys, ids)
+		if err != nil {
+			return err
+		}
+		restored = result.UpdatedCount
+
+		// Reposts went with the posts, the quotes count again while the quoted posts exist
+		if err := tx.Model(&post.Post{}).Where("id IN ?", ids).UpdateColumn("repost_count", 0).Error; err != nil {
+			return err
+		}
+		quotes := make(map[uint]int)
+		for _, p := range posts {
+			if p.QuotedPostID != nil {
+				quotes[*p.QuotedPostID]++
+			}
+		}
+		for quotedID, n := range quotes {
+			if err := tx.Model(&post.Post{}).Where("id = ?", quotedID).
+				UpdateColumn("quote_count", gorm.Expr("quote_count + ?", n)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return restored, err
+}
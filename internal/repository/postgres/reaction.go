@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"errors"
+
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/pkg/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(d *gorm.DB) reaction.Repository {
+	return &ReactionRepository{
+		db: d,
+	}
+}
+
+func (r *ReactionRepository) Set(e *reaction.Reaction) (*reaction.Type, error) {
+	var previous *reaction.Type
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The unique index settles concurrent reactions of the same user, the loser changes the winner's row
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(e)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+
+		var existing reaction.Reaction
+		if err := tx.Where("post_id = ? AND user_id = ?", e.PostID, e.UserID).First(&existing).Error; err != nil {
+			return err
+		}
+
+		prevType := existing.Type
+		previous = &prevType
+		if existing.Type != e.Type {
+			if err := tx.Model(&existing).Update("type", e.Type).Error; err != nil {
+				return err
+			}
+		}
+		*e = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+func (r *ReactionRepository) Remove(postID, userID uint) (*reaction.Type, error) {
+	var previous *reaction.Type
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing reaction.Reaction
+		err := tx.Where("post_id = ? AND user_id = ?", postID, userID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		previous = &existing.Type
+		return tx.Delete(&existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+func (r *ReactionRepository) FindByUser(postIDs []uint, userID uint) ([]reaction.Reaction, error) {
+	var reactions []reaction.Reaction
+	err := r.db.Where("post_id IN ? AND user_id = ?", postIDs, userID).Find(&reactions).Error
+	return reactions, err
+}
+
+func (r *ReactionRepository) FindReactors(postID uint, reactionType reaction.Type, page pagination.CursorPagination) ([]reaction.Reaction, error) {
+	query := r.db.Preload("User").Where("post_id = ?", postID)
+	if reactionType != "" {
+		query = query.Where("type = ?", reactionType)
+	}
+
+	query, err := pagination.ApplyCursorPagination(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var reactions []reaction.Reaction
+	err = query.Find(&reactions).Error
+	return reactions, err
+}
+
+func (r *ReactionRepository) CountByPosts(postIDs []uint) ([]reaction.Count, error) {
+	var counts []reaction.Count
+	err := r.db.Model(&reaction.Reaction{}).
+		Select("post_id, type, COUNT(*) AS count").
+		Where("post_id IN ?", postIDs).
+		Group("post_id, type").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *ReactionRepository) FindCounts(postIDs []uint) ([]reaction.Count, error) {
+	var counts []reaction.Count
+	err := r.db.Where("post_id IN ?", postIDs).Find(&counts).Error
+	return counts, err
+}
+
+func (r *ReactionRepository) SaveCounts(postIDs []uint, counts []reaction.Count) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id IN ?", postIDs).Delete(&reaction.Count{}).Error; err != nil {
+			return err
+		}
+		if len(counts) == 0 {
+			return nil
+		}
+		return tx.Create(&counts).Error
+	})
+}
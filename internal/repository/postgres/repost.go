@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/pkg/database"
+	"starter-gofiber/pkg/pagination"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	// Reposts of a deleted post go with it
+	database.OnSoftDelete("posts", func(tx *gorm.DB, ids []uint) error {
+		return tx.Where("post_id IN ?", ids).Delete(&repost.Repost{}).Error
+	})
+}
+
+type RepostRepository struct {
+	db *gorm.DB
+}
+
+func NewRepostRepository(d *gorm.DB) repost.Repository {
+	return &RepostRepository{
+		db: d,
+	}
+}
+
+func (r *RepostRepository) Save(rp *repost.Repost) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The unique index settles concurrent reposts of the same post
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(rp)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Where("user_id = ? AND post_id = ?", rp.UserID, rp.PostID).First(rp).Error
+		}
+
+		created = true
+		return tx.Model(&post.Post{}).Where("id = ?", rp.PostID).
+			UpdateColumn("repost_count", gorm.Expr("repost_count + 1")).Error
+	})
+	return created, err
+}
+
+func (r *RepostRepository) Remove(postID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("post_id = ? AND user_id = ?", postID, userID).Delete(&repost.Repost{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		removed = true
+		return tx.Model(&post.Post{}).Where("id = ? AND repost_count > 0", postID).
+			UpdateColumn("repost_count", gorm.Expr("repost_count - 1")).Error
+	})
+	return removed, err
+}
+
+func (r *RepostRepository) FindByUser(userID, viewerID uint, page pagination.CursorPagination) ([]repost.Repost, error) {
+	// Reposts of posts that were hidden or are no longer shared with the viewer stay, they are
+	// listed again once the viewer may see the post
+	query := r.db.Where("user_id = ? AND post_id IN (?)", userID,
+		r.db.Model(&post.Post{}).Scopes(public, sharedWith(viewerID)).Select("id"))
+
+	query, err := pagination.ApplyCursorPagination(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var reposts []repost.Repost
+	err = query.Find(&reposts).Error
+	return reposts, err
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+
+	"gorm.io/gorm"
+)
+
+type SecurityEventRepository struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventRepository(d *gorm.DB) security.Repository {
+	return &SecurityEventRepository{
+		db: d,
+	}
+}
+
+func (r *SecurityEventRepository) Create(event *security.Event) error {
+	return r.db.Create(event).Error
+}
+
+func (r *SecurityEventRepository) FindAll(filter security.EventFilter, limit, offset int) ([]security.Event, int64, error) {
+	var events []security.Event
+	var total int64
+
+	query := r.db.Model(&security.Event{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Email != "" {
+		query = query.Where("email = ?", filter.Email)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("created_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("created_at <= ?", *filter.EndDate)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+	return events, total, err
+}
+
+func (r *SecurityEventRepository) FindByUserID(userID uint, limit int) ([]security.Event, error) {
+	var events []security.Event
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *SecurityEventRepository) CountByEmailSince(eventType security.EventType, email string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&security.Event{}).
+		Where("type = ? AND email = ? AND created_at >= ?", eventType, email, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *SecurityEventRepository) FindLatestByEmail(eventType security.EventType, email string) (*security.Event, error) {
+	var event security.Event
+	err := r.db.Where("type = ? AND email = ?", eventType, email).
+		Order("created_at DESC, id DESC").
+		First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *SecurityEventRepository) CountDistinctEmailsByIPSince(eventType security.EventType, ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&security.Event{}).
+		Where("type = ? AND ip_address = ? AND created_at >= ?", eventType, ip, since).
+		Distinct("email").
+		Count(&count).Error
+	return count, err
+}
+
+// ExistsSince checks for an event with the given email or ip_address since a point in time
+func (r *SecurityEventRepository) ExistsSince(eventType security.EventType, field, value string, since time.Time) (bool, error) {
+	if field != "email" && field != "ip_address" {
+		return false, fmt.Errorf("unsupported field %q", field)
+	}
+
+	var count int64
+	err := r.db.Model(&security.Event{}).
+		Where("type = ? AND "+field+" = ? AND created_at >= ?", eventType, value, since).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *SecurityEventRepository) ExistsBySubjectAndIP(eventType security.EventType, subject, ip string) (bool, error) {
+	var count int64
+	err := r.db.Model(&security.Event{}).
+		Where("type = ? AND subject = ? AND ip_address = ?", eventType, subject, ip).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindPreviousLocated returns the user's latest event of a type with a known location before beforeID
+func (r *SecurityEventRepository) FindPreviousLocated(userID uint, eventType security.EventType, beforeID uint) (*security.Event, error) {
+	var event security.Event
+	err := r.db.Where("user_id = ? AND type = ? AND id < ? AND latitude IS NOT NULL AND longitude IS NOT NULL", userID, eventType, beforeID).
+		Order("id DESC").
+		First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *SecurityEventRepository) FindAdmins() ([]user.User, error) {
+	var admins []user.User
+	err := r.db.Where("role = ?", variables.ADMIN_ROLE).Find(&admins).Error
+	return admins, err
+}
@@ -48,6 +48,24 @@ func (u *UserRepository) Update(usr *user.User) error {
 	return u.db.Save(usr).Error
 }
 
+func (u *UserRepository) FindByUsernames(usernames []string) ([]user.User, error) {
+	var users []user.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err := u.db.Where("username IN ?", usernames).Find(&users).Error
+	return users, err
+}
+
+func (u *UserRepository) FindByIDs(ids []uint) ([]user.User, error) {
+	var users []user.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := u.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 // RefreshToken operations
 func (u *UserRepository) CreateRefreshToken(token *user.RefreshToken) error {
 	return u.db.Create(token).Error
@@ -64,6 +82,18 @@ func (u *UserRepository) FindRefreshTokenByToken(tokenStr string) (*user.Refresh
 	return &token, nil
 }
 
+// FindRevokedRefreshToken finds a rotated or revoked token, used to detect token reuse
+func (u *UserRepository) FindRevokedRefreshToken(tokenStr string) (*user.RefreshToken, error) {
+	var token user.RefreshToken
+	err := u.db.Where("token = ? AND is_revoked = ?", tokenStr, true).
+		Preload("User").
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
 func (u *UserRepository) RevokeRefreshToken(tokenStr string) error {
 	return u.db.Model(&user.RefreshToken{}).
 		Where("token = ?", tokenStr).
@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+type AnalyticsService struct {
+	repo     analytics.Repository
+	postRepo post.Repository
+}
+
+func NewAnalyticsService(repo analytics.Repository, postRepo post.Repository) analytics.Service {
+	return &AnalyticsService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+// RecordView counts a view of the post unless the author opened it. Views are counted in Redis, a key
+// per viewer and post marks the window in which further opens are not counted again, and per day a
+// counter and a HyperLogLog of the viewers. The roll-up job persists them into the daily stats.
+// Without Redis the views are recorded in the views table.
+func (s *AnalyticsService) RecordView(postID, authorID uint, viewer analytics.Viewer) {
+	if viewer.UserID != 0 && viewer.UserID == authorID {
+		return
+	}
+
+	now := time.Now()
+	key := viewer.Key()
+
+	client := cache.RedisClient
+	if client == nil {
+		if _, err := s.repo.RecordView(postID, key, now, analytics.ViewWindow); err != nil {
+			logger.Warn("Failed to record post view", zap.Uint("post_id", postID), zap.Error(err))
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	day := analytics.Day(now)
+	fresh, err := client.SetNX(ctx, analytics.SeenKey(postID, day, key), 1, analytics.ViewWindow).Result()
+	if err != nil {
+		logger.Warn("Failed to record post view", zap.Uint("post_id", postID), zap.Error(err))
+		return
+	}
+	if !fresh {
+		return
+	}
+
+	viewsKey, viewersKey, viewedKey := analytics.ViewsKey(postID, day), analytics.ViewersKey(postID, day), analytics.ViewedKey(day)
+	pipe := client.TxPipeline()
+	pipe.Incr(ctx, viewsKey)
+	pipe.PFAdd(ctx, viewersKey, key)
+	pipe.SAdd(ctx, viewedKey, postID)
+	pipe.Expire(ctx, viewsKey, analytics.KeyTTL)
+	pipe.Expire(ctx, viewersKey, analytics.KeyTTL)
+	pipe.Expire(ctx, viewedKey, analytics.KeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("Failed to record post view", zap.Uint("post_id", postID), zap.Error(err))
+	}
+}
+
+func (s *AnalyticsService) Stats(postID, userID uint, readAny bool, from, to *time.Time) (*analytics.PostStatsResponse, error) {
+	p, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	// Check ownership, admins may read the stats of any post
+	if p.UserID != userID && !readAny {
+		return nil, &apierror.ForbiddenError{
+			Message: "You don't have permission to read the stats of this post",
+			Order:   "S2",
+		}
+	}
+
+	end := analytics.Day(time.Now())
+	if to != nil {
+		end = analytics.Day(*to)
+	}
+	start := end.AddDate(0, 0, -(analytics.DefaultRangeDays - 1))
+	if from != nil {
+		start = analytics.Day(*from)
+	}
+	if start.After(end) {
+		return nil, &apierror.BadRequestError{
+			Message: "from must not be after to",
+			Order:   "S3",
+		}
+	}
+	if end.Sub(start) >= analytics.MaxRangeDays*24*time.Hour {
+		return nil, &apierror.BadRequestError{
+			Message: fmt.Sprintf("The range must not exceed %d days", analytics.MaxRangeDays),
+			Order:   "S4",
+		}
+	}
+
+	stats, err := s.repo.FindDaily(postID, start, end)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+
+	byDay := make(map[string]analytics.DailyStat, len(stats))
+	for _, st := range stats {
+		byDay[st.Day.UTC().Format(analytics.DayFormat)] = st
+	}
+
+	resp := &analytics.PostStatsResponse{
+		PostID: postID,
+		From:   start.Format(analytics.DayFormat),
+		To:     end.Format(analytics.DayFormat),
+		Days:   []analytics.DailyStatResponse{},
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		st, ok := byDay[day.Format(analytics.DayFormat)]
+		if !ok {
+			st = analytics.DailyStat{PostID: postID, Day: day}
+		}
+		resp.Days = append(resp.Days, analytics.DailyStatResponse{}.FromEntity(st))
+		resp.Totals.Views += st.Views
+		resp.Totals.Reactions += st.Reactions
+		resp.Totals.Comments += st.Comments
+	}
+	return resp, nil
+}
@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"fmt"
 	"time"
 
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/domain/security"
 	"starter-gofiber/internal/domain/user"
 	"starter-gofiber/internal/worker"
 	"starter-gofiber/pkg/apierror"
@@ -10,20 +13,29 @@ import (
 )
 
 type AuthService struct {
-	userRepo user.Repository
+	userRepo  user.Repository
+	consentS  consent.Service
+	securityS security.Service
 }
 
-func NewAuthService(userRepo user.Repository) user.Service {
+func NewAuthService(userRepo user.Repository, consentS consent.Service, securityS security.Service) user.Service {
 	return &AuthService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		consentS:  consentS,
+		securityS: securityS,
 	}
 }
 
-func (s *AuthService) Register(req *user.RegisterRequest) error {
+func (s *AuthService) Register(req *user.RegisterRequest, ipAddress, userAgent string) error {
 	if err := s.userRepo.ExistEmail(req.Email); err == nil {
 		return &apierror.BadRequestError{Message: "Email already exists", Order: "S1"}
 	}
 
+	// Registration requires accepting the current ToS and privacy policy
+	if err := s.consentS.ValidateAcceptance(req.TosVersion, req.PrivacyVersion); err != nil {
+		return err
+	}
+
 	password, err := crypto.HashPassword(req.Password)
 	if err != nil {
 		return &apierror.BadRequestError{Message: "Failed to hash password", Order: "S2"}
@@ -31,12 +43,27 @@ func (s *AuthService) Register(req *user.RegisterRequest) error {
 
 	userEntity := req.ToEntity()
 	userEntity.Password = password
+	if req.Username != "" {
+		username, err := s.checkUsername(req.Username, 0)
+		if err != nil {
+			return err
+		}
+		userEntity.Username = &username
+	}
 
 	err = s.userRepo.Create(&userEntity)
 	if err != nil {
 		return &apierror.InternalServerError{Message: err.Error(), Order: "S3"}
 	}
 
+	acceptReq := &consent.AcceptConsentRequest{TosVersion: req.TosVersion, PrivacyVersion: req.PrivacyVersion}
+	if err := s.consentS.Accept(userEntity.ID, acceptReq, ipAddress, userAgent); err != nil {
+		return err
+	}
+	if err := s.consentS.SetMarketingConsent(userEntity.ID, req.MarketingOptIn, ipAddress, userAgent); err != nil {
+		return err
+	}
+
 	// Create email verification token (optional, untuk kirim email nanti)
 	// Create email verification token
 	verificationToken, err := crypto.GenerateRandomToken()
@@ -69,9 +96,23 @@ func (s *AuthService) Register(req *user.RegisterRequest) error {
 }
 
 func (s *AuthService) Login(req *user.LoginRequest, ipAddress, userAgent string) (resp *user.LoginResponse, err error) {
+	if s.securityS.IsLocked(req.Email) {
+		return nil, &apierror.TooManyRequestsError{
+			Message: "Account temporarily locked due to too many failed login attempts",
+			Order:   "S-Login-Locked",
+		}
+	}
+
 	usr, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
 		// Record failed login attempt
+		s.securityS.Record(&security.Event{
+			Email:     req.Email,
+			Type:      security.EventLoginFailed,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Subject:   "unknown_email",
+		})
 		return nil, &apierror.UnauthorizedError{
 			Message: "Email not registered!",
 			Order:   "S1",
@@ -80,12 +121,27 @@ func (s *AuthService) Login(req *user.LoginRequest, ipAddress, userAgent string)
 
 	if err := crypto.VerifyPassword(usr.Password, req.Password); err != nil {
 		// Record failed login attempt
+		s.securityS.Record(&security.Event{
+			UserID:    &usr.ID,
+			Email:     usr.Email,
+			Type:      security.EventLoginFailed,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Subject:   "wrong_password",
+		})
 		return nil, &apierror.UnauthorizedError{
 			Message: "Password is wrong!",
 			Order:   "S2",
 		}
 	}
 
+	if usr.SuspendedAt != nil {
+		return nil, &apierror.ForbiddenError{
+			Message: "Account suspended",
+			Order:   "S-Login-Suspended",
+		}
+	}
+
 	userClaims := user.UserClaims{}.FromEntity(*usr)
 	token, err := crypto.GenerateJWT(userClaims)
 	if err != nil {
@@ -119,6 +175,13 @@ func (s *AuthService) Login(req *user.LoginRequest, ipAddress, userAgent string)
 	}
 
 	// Record successful login
+	s.securityS.Record(&security.Event{
+		UserID:    &usr.ID,
+		Email:     usr.Email,
+		Type:      security.EventLoginSuccess,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
 
 	return &user.LoginResponse{
 		Token:        token,
@@ -131,12 +194,32 @@ func (s *AuthService) RefreshToken(req *user.RefreshTokenRequest, ipAddress, use
 	// Validate refresh token
 	tokenEntity, err := s.userRepo.FindRefreshTokenByToken(req.RefreshToken)
 	if err != nil {
+		// A rotated token being presented again means it leaked, revoke every session
+		if revoked, err := s.userRepo.FindRevokedRefreshToken(req.RefreshToken); err == nil {
+			s.userRepo.RevokeAllUserTokens(revoked.UserID)
+			s.securityS.Record(&security.Event{
+				UserID:    &revoked.UserID,
+				Email:     revoked.User.Email,
+				Type:      security.EventTokenReuse,
+				IPAddress: ipAddress,
+				UserAgent: userAgent,
+				Subject:   fmt.Sprintf("refresh_token:%d", revoked.ID),
+			})
+		}
 		return nil, &apierror.UnauthorizedError{
 			Message: "Invalid refresh token",
 			Order:   "S1",
 		}
 	}
 
+	// Suspension revokes every session, this catches a token presented meanwhile
+	if tokenEntity.User.SuspendedAt != nil {
+		return nil, &apierror.UnauthorizedError{
+			Message: "Account suspended",
+			Order:   "S-Refresh-Suspended",
+		}
+	}
+
 	// Generate new tokens
 	userClaims := user.UserClaims{}.FromEntity(tokenEntity.User)
 	newToken, err := crypto.GenerateJWT(userClaims)
@@ -242,7 +325,7 @@ func (s *AuthService) ForgotPassword(req *user.ForgotPasswordRequest) error {
 	return nil
 }
 
-func (s *AuthService) ResetPassword(req *user.ResetPasswordRequest) error {
+func (s *AuthService) ResetPassword(req *user.ResetPasswordRequest, ipAddress, userAgent string) error {
 	// Find reset token
 	resetToken, err := s.userRepo.FindPasswordResetByToken(req.Token)
 	if err != nil {
@@ -282,10 +365,18 @@ func (s *AuthService) ResetPassword(req *user.ResetPasswordRequest) error {
 	// Revoke all user sessions for security
 	s.userRepo.RevokeAllUserTokens(usr.ID)
 
+	s.securityS.Record(&security.Event{
+		UserID:    &usr.ID,
+		Email:     usr.Email,
+		Type:      security.EventPasswordReset,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
+
 	return nil
 }
 
-func (s *AuthService) ChangePassword(userID uint, req *user.ChangePasswordRequest) error {
+func (s *AuthService) ChangePassword(userID uint, req *user.ChangePasswordRequest, ipAddress, userAgent string) error {
 	// Get user
 	usr, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -324,6 +415,14 @@ func (s *AuthService) ChangePassword(userID uint, req *user.ChangePasswordReques
 	// Revoke all sessions except current
 	s.userRepo.RevokeAllUserTokens(userID)
 
+	s.securityS.Record(&security.Event{
+		UserID:    &usr.ID,
+		Email:     usr.Email,
+		Type:      security.EventPasswordChanged,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
+
 	return nil
 }
 
@@ -462,6 +561,13 @@ func (s *AuthService) UpdateProfile(userID uint, req *user.UpdateProfileRequest)
 	if req.Bio != "" {
 		usr.Bio = req.Bio
 	}
+	if req.Username != "" {
+		username, err := s.checkUsername(req.Username, usr.ID)
+		if err != nil {
+			return nil, err
+		}
+		usr.Username = &username
+	}
 
 	if err := s.userRepo.Update(usr); err != nil {
 		return nil, &apierror.InternalServerError{
@@ -474,6 +580,34 @@ func (s *AuthService) UpdateProfile(userID uint, req *user.UpdateProfileRequest)
 	return &response, nil
 }
 
+// checkUsername normalizes a requested username and makes sure no other user holds it
+func (s *AuthService) checkUsername(raw string, userID uint) (string, error) {
+	username, ok := user.NormalizeUsername(raw)
+	if !ok {
+		return "", &apierror.BadRequestError{
+			Message: "Username must be 3-30 letters, digits or underscores",
+			Order:   "S-Username-1",
+		}
+	}
+
+	users, err := s.userRepo.FindByUsernames([]string{username})
+	if err != nil {
+		return "", &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Username-2",
+		}
+	}
+	for _, u := range users {
+		if u.ID != userID {
+			return "", &apierror.BadRequestError{
+				Message: "Username already taken",
+				Order:   "S-Username-3",
+			}
+		}
+	}
+	return username, nil
+}
+
 func (s *AuthService) UpdateAvatar(userID uint, avatarPath string) (*user.GetProfileResponse, error) {
 	usr, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -0,0 +1,341 @@
+package bookmark
+
+import (
+	"slices"
+	"strings"
+
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type BookmarkService struct {
+	repo     bookmark.Repository
+	postRepo post.Repository
+}
+
+func NewBookmarkService(repo bookmark.Repository, postRepo post.Repository) bookmark.Service {
+	return &BookmarkService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+func validate(req interface{}) error {
+	err := iValidator.Validator.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var errors []*iValidator.IError
+	for _, err := range err.(validator.ValidationErrors) {
+		var el iValidator.IError
+		el.Field = err.Field()
+		el.Tag = err.Tag()
+		el.Value = err.Param()
+		errors = append(errors, &el)
+	}
+	return &apierror.UnprocessableEntityError{
+		Message: err.Error(),
+		Data:    errors,
+		Order:   "S1",
+	}
+}
+
+func (s *BookmarkService) Bookmark(postID uint, req *bookmark.BookmarkRequest, userID uint) (*bookmark.BookmarkResponse, error) {
+	// Only live posts the user may see are bookmarked, the list would not show the others
+	p, err := s.postRepo.FindVisibleByID(postID, userID)
+	if err != nil || !p.IsPublic() {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	if req.CollectionID != nil {
+		if _, err := s.ownCollection(*req.CollectionID, userID, "S2"); err != nil {
+			return nil, err
+		}
+	}
+
+	b := bookmark.Bookmark{UserID: userID, PostID: postID, CollectionID: req.CollectionID}
+	if err := s.repo.Save(&b); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	resp := bookmark.BookmarkResponse{}.FromEntity(b)
+	return &resp, nil
+}
+
+func (s *BookmarkService) Unbookmark(postID uint, userID uint) error {
+	if _, err := s.repo.Remove(postID, userID); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+	return nil
+}
+
+func (s *BookmarkService) Bookmarked(postIDs []uint, viewerID uint) (map[uint]bool, error) {
+	result := make(map[uint]bool, len(postIDs))
+	if viewerID == 0 || len(postIDs) == 0 {
+		return result, nil
+	}
+
+	ids, err := s.repo.FindBookmarkedPostIDs(postIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+func (s *BookmarkService) List(userID uint, collectionID *uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	if collectionID != nil {
+		if _, err := s.ownCollection(*collectionID, userID, "S1"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Pages follow the bookmarks, newest first, not the posts
+	page.SortBy, page.SortOrder = "", "desc"
+	bookmarks, err := s.repo.FindByUser(userID, collectionID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	limit := pageLimit(page)
+	resp := &pagination.CursorResponse{HasMore: len(bookmarks) > limit}
+	if resp.HasMore {
+		bookmarks = bookmarks[:limit]
+		resp.NextCursor = pagination.EncodeCursor(bookmarks[len(bookmarks)-1].ID, "")
+	}
+
+	postIDs := make([]uint, len(bookmarks))
+	for i, b := range bookmarks {
+		postIDs[i] = b.PostID
+	}
+	posts, err := s.postRepo.FindByIDs(postIDs, userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+	byID := make(map[uint]post.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+
+	result := make([]post.PostResponse, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		p, ok := byID[b.PostID]
+		if !ok {
+			continue
+		}
+		item := post.PostResponse{}.FromEntity(p)
+		item.Bookmarked = true
+		result = append(result, item)
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+func (s *BookmarkService) Collections(userID uint) ([]bookmark.CollectionResponse, error) {
+	collections, err := s.repo.FindCollections(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+	counts, err := s.repo.CountByCollections(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	result := make([]bookmark.CollectionResponse, 0, len(collections))
+	for _, c := range collections {
+		item := bookmark.CollectionResponse{}.FromEntity(c)
+		item.BookmarkCount = counts[c.ID]
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (s *BookmarkService) CreateCollection(req *bookmark.CollectionRequest, userID uint) (*bookmark.CollectionResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	collections, err := s.repo.FindCollections(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+	if err := uniqueName(collections, req.Name, 0, "S3"); err != nil {
+		return nil, err
+	}
+
+	// New collections go last
+	c := bookmark.Collection{UserID: userID, Name: req.Name}
+	if len(collections) > 0 {
+		c.Position = collections[len(collections)-1].Position + 1
+	}
+	if err := s.repo.CreateCollection(&c); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+
+	resp := bookmark.CollectionResponse{}.FromEntity(c)
+	return &resp, nil
+}
+
+func (s *BookmarkService) RenameCollection(id uint, req *bookmark.CollectionRequest, userID uint) (*bookmark.CollectionResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	c, err := s.ownCollection(id, userID, "S2")
+	if err != nil {
+		return nil, err
+	}
+	collections, err := s.repo.FindCollections(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+	if err := uniqueName(collections, req.Name, c.ID, "S4"); err != nil {
+		return nil, err
+	}
+
+	c.Name = req.Name
+	if err := s.repo.UpdateCollection(c); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+
+	counts, err := s.repo.CountByCollections(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S6",
+		}
+	}
+	resp := bookmark.CollectionResponse{}.FromEntity(*c)
+	resp.BookmarkCount = counts[c.ID]
+	return &resp, nil
+}
+
+func (s *BookmarkService) DeleteCollection(id uint, userID uint) error {
+	if _, err := s.ownCollection(id, userID, "S1"); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteCollection(id); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+	return nil
+}
+
+func (s *BookmarkService) ReorderCollections(req *bookmark.ReorderRequest, userID uint) ([]bookmark.CollectionResponse, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	collections, err := s.repo.FindCollections(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	// The new order has to name each of the user's collections exactly once
+	current := make([]uint, len(collections))
+	for i, c := range collections {
+		current[i] = c.ID
+	}
+	requested := slices.Clone(req.IDs)
+	slices.Sort(current)
+	slices.Sort(requested)
+	if !slices.Equal(current, requested) {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "ids must list each of your collections once",
+			Order:   "S3",
+		}
+	}
+
+	if err := s.repo.SetPositions(userID, req.IDs); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	return s.Collections(userID)
+}
+
+// ownCollection loads one of the user's collections, other users' collections are not found
+func (s *BookmarkService) ownCollection(id, userID uint, order string) (*bookmark.Collection, error) {
+	c, err := s.repo.FindCollection(id)
+	if err != nil || c.UserID != userID {
+		return nil, &apierror.NotFoundError{
+			Message: "Collection not found",
+			Order:   order,
+		}
+	}
+	return c, nil
+}
+
+// uniqueName rejects a name another of the user's collections already has, ignoring case
+func uniqueName(collections []bookmark.Collection, name string, exceptID uint, order string) error {
+	for _, c := range collections {
+		if c.ID != exceptID && strings.EqualFold(c.Name, name) {
+			return &apierror.BadRequestError{
+				Message: "You already have a collection with this name",
+				Order:   order,
+			}
+		}
+	}
+	return nil
+}
+
+// pageLimit mirrors the limit bounds applied by ApplyCursorPagination
+func pageLimit(page pagination.CursorPagination) int {
+	if page.Limit <= 0 {
+		return 10
+	}
+	if page.Limit > 100 {
+		return 100
+	}
+	return page.Limit
+}
@@ -0,0 +1,236 @@
+package comment
+
+import (
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/utils"
+	iValidator "starter-gofiber/pkg/validator"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CommentService struct {
+	repo     comment.Repository
+	postRepo post.Repository
+}
+
+func NewCommentService(repo comment.Repository, postRepo post.Repository) comment.Service {
+	return &CommentService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+func validate(req interface{}) error {
+	err := iValidator.Validator.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var errors []*iValidator.IError
+	for _, err := range err.(validator.ValidationErrors) {
+		var el iValidator.IError
+		el.Field = err.Field()
+		el.Tag = err.Tag()
+		el.Value = err.Param()
+		errors = append(errors, &el)
+	}
+	return &apierror.UnprocessableEntityError{
+		Message: err.Error(),
+		Data:    errors,
+		Order:   "S1",
+	}
+}
+
+func (s *CommentService) Create(postID uint, req *comment.CreateCommentRequest, userID uint) (*comment.CommentResponse, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	postEntity, err := s.postRepo.FindVisibleByID(postID, userID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S2",
+		}
+	}
+
+	commentEntity := comment.Comment{
+		PostID: postID,
+		Body:   req.Body,
+		UserID: userID,
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.repo.FindByID(*req.ParentID)
+		if err != nil || parent.PostID != postID {
+			return nil, &apierror.NotFoundError{
+				Message: "Parent comment not found",
+				Order:   "S3",
+			}
+		}
+		if parent.Depth+1 > comment.MaxDepth {
+			return nil, &apierror.BadRequestError{
+				Message: "Maximum reply depth reached",
+				Order:   "S4",
+			}
+		}
+		commentEntity.ParentID = &parent.ID
+		commentEntity.Depth = parent.Depth + 1
+	}
+
+	if err := s.repo.Create(&commentEntity); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+
+	// Notify the post author, but not about their own comments
+	if postEntity.UserID != userID {
+		utils.NotifyUser(postEntity.UserID, "new_comment", fiber.Map{
+			"post_id":    postID,
+			"comment_id": commentEntity.ID,
+			"parent_id":  commentEntity.ParentID,
+			"user_id":    userID,
+		})
+	}
+
+	resp := comment.CommentResponse{}.FromEntity(commentEntity)
+	return &resp, nil
+}
+
+func (s *CommentService) ListByPost(postID, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	if _, err := s.postRepo.FindVisibleByID(postID, viewerID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	return s.thread(postID, nil, page)
+}
+
+func (s *CommentService) ListReplies(commentID, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	parent, err := s.repo.FindThreadParent(commentID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Comment not found",
+			Order:   "S1",
+		}
+	}
+	// Comments of a post the viewer may not open are not found either
+	if _, err := s.postRepo.FindVisibleByID(parent.PostID, viewerID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Comment not found",
+			Order:   "S1",
+		}
+	}
+
+	return s.thread(parent.PostID, &parent.ID, page)
+}
+
+// thread loads one page of direct children and builds the cursor response
+func (s *CommentService) thread(postID uint, parentID *uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	comments, err := s.repo.FindThread(postID, parentID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+
+	result := make([]comment.CommentResponse, 0, len(comments))
+	for _, c := range comments {
+		result = append(result, comment.CommentResponse{}.FromEntity(c))
+	}
+
+	resp := &pagination.CursorResponse{
+		Data:    result,
+		HasMore: hasMore,
+		Count:   len(result),
+	}
+	if hasMore {
+		resp.NextCursor = pagination.EncodeCursor(comments[len(comments)-1].ID, "")
+	}
+	return resp, nil
+}
+
+func (s *CommentService) Update(id uint, req *comment.UpdateCommentRequest, userID uint, moderator bool) (*comment.CommentResponse, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	commentEntity, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Comment not found",
+			Order:   "S2",
+		}
+	}
+
+	// Check ownership, moderators may edit any comment
+	if commentEntity.UserID != userID && !moderator {
+		return nil, &apierror.ForbiddenError{
+			Message: "You don't have permission to update this comment",
+			Order:   "S3",
+		}
+	}
+
+	now := time.Now()
+	commentEntity.Body = req.Body
+	commentEntity.EditedAt = &now
+
+	if err := s.repo.Update(commentEntity); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+
+	resp := comment.CommentResponse{}.FromEntity(*commentEntity)
+	return &resp, nil
+}
+
+func (s *CommentService) Delete(id uint, userID uint, moderator bool) error {
+	commentEntity, err := s.repo.FindByID(id)
+	if err != nil {
+		return &apierror.NotFoundError{
+			Message: "Comment not found",
+			Order:   "S1",
+		}
+	}
+
+	// Check ownership, moderators may delete any comment
+	if commentEntity.UserID != userID && !moderator {
+		return &apierror.ForbiddenError{
+			Message: "You don't have permission to delete this comment",
+			Order:   "S2",
+		}
+	}
+
+	if err := s.repo.Delete(commentEntity); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package consent
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/apierror"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	cacheKeyCurrent = "consent:current"
+	cacheKeyUser    = "consent:user:%d"
+	cacheTTL        = 5 * time.Minute
+)
+
+type ConsentService struct {
+	repo consent.Repository
+}
+
+func NewConsentService(repo consent.Repository) consent.Service {
+	return &ConsentService{
+		repo: repo,
+	}
+}
+
+func validate(req interface{}) error {
+	err := iValidator.Validator.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var errs []*iValidator.IError
+	for _, err := range err.(validator.ValidationErrors) {
+		var el iValidator.IError
+		el.Field = err.Field()
+		el.Tag = err.Tag()
+		el.Value = err.Param()
+		errs = append(errs, &el)
+	}
+	return &apierror.UnprocessableEntityError{
+		Message: err.Error(),
+		Data:    errs,
+		Order:   "S-Consent-V1",
+	}
+}
+
+func (s *ConsentService) PublishVersion(req *consent.PublishPolicyRequest) (*consent.PolicyVersionResponse, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	publishedAt := time.Now()
+	if req.PublishedAt != "" {
+		t, err := time.Parse(time.RFC3339, req.PublishedAt)
+		if err != nil {
+			return nil, &apierror.BadRequestError{
+				Message: "published_at must be an RFC3339 timestamp",
+				Order:   "S-Consent-P1",
+			}
+		}
+		publishedAt = t
+	}
+
+	policyType := consent.PolicyType(req.Type)
+	if _, err := s.repo.FindPolicyVersion(policyType, req.Version); err == nil {
+		return nil, &apierror.BadRequestError{
+			Message: fmt.Sprintf("Version %s of %s already exists", req.Version, req.Type),
+			Order:   "S-Consent-P2",
+		}
+	}
+
+	policy := &consent.PolicyVersion{
+		Type:        policyType,
+		Version:     req.Version,
+		URL:         req.URL,
+		PublishedAt: publishedAt,
+	}
+	if err := s.repo.CreatePolicyVersion(policy); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-P3",
+		}
+	}
+
+	// Every user's consent state may have changed
+	_ = cache.CacheInvalidateByPattern("consent:*")
+
+	resp := consent.PolicyVersionResponse{}.FromEntity(*policy)
+	return &resp, nil
+}
+
+func (s *ConsentService) currentPolicies() ([]consent.PolicyVersion, error) {
+	var policies []consent.PolicyVersion
+	err := cache.CacheGetOrSet(cacheKeyCurrent, &policies, cacheTTL, func() (interface{}, error) {
+		return s.repo.FindCurrentPolicies(time.Now())
+	})
+	return policies, err
+}
+
+func (s *ConsentService) CurrentVersions() ([]consent.PolicyVersionResponse, error) {
+	policies, err := s.currentPolicies()
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-C1",
+		}
+	}
+
+	resp := make([]consent.PolicyVersionResponse, 0, len(policies))
+	for _, p := range policies {
+		resp = append(resp, consent.PolicyVersionResponse{}.FromEntity(p))
+	}
+	return resp, nil
+}
+
+// ValidateAcceptance checks that the given versions are the current ones
+// Policy types that were never published are not required
+func (s *ConsentService) ValidateAcceptance(tosVersion, privacyVersion string) error {
+	policies, err := s.currentPolicies()
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-A1",
+		}
+	}
+
+	given := map[consent.PolicyType]string{
+		consent.PolicyTerms:   tosVersion,
+		consent.PolicyPrivacy: privacyVersion,
+	}
+	for _, p := range policies {
+		if given[p.Type] != p.Version {
+			return &apierror.BadRequestError{
+				Message: fmt.Sprintf("You must accept the current %s version %s", p.Type, p.Version),
+				Order:   consent.ErrCodeConsentRequired,
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ConsentService) Accept(userID uint, req *consent.AcceptConsentRequest, ipAddress, userAgent string) error {
+	if err := validate(req); err != nil {
+		return err
+	}
+	if err := s.ValidateAcceptance(req.TosVersion, req.PrivacyVersion); err != nil {
+		return err
+	}
+
+	policies, _ := s.currentPolicies()
+	if len(policies) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	consents := make([]consent.UserConsent, 0, len(policies))
+	for _, p := range policies {
+		consents = append(consents, consent.UserConsent{
+			UserID:     userID,
+			PolicyType: p.Type,
+			Version:    p.Version,
+			AcceptedAt: now,
+			IPAddress:  ipAddress,
+			UserAgent:  userAgent,
+		})
+	}
+	if err := s.repo.CreateUserConsents(consents); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-A2",
+		}
+	}
+
+	_ = cache.CacheDelete(fmt.Sprintf(cacheKeyUser, userID))
+	return nil
+}
+
+// RequiresConsent reports whether the user has not accepted a current policy version
+func (s *ConsentService) RequiresConsent(userID uint) (bool, error) {
+	var required bool
+	err := cache.CacheGetOrSet(fmt.Sprintf(cacheKeyUser, userID), &required, cacheTTL, func() (interface{}, error) {
+		return s.requiresConsent(userID)
+	})
+	return required, err
+}
+
+func (s *ConsentService) requiresConsent(userID uint) (bool, error) {
+	policies, err := s.currentPolicies()
+	if err != nil || len(policies) == 0 {
+		return false, err
+	}
+
+	accepted, err := s.repo.FindLatestUserConsents(userID)
+	if err != nil {
+		return false, err
+	}
+
+	versions := make(map[consent.PolicyType]string, len(accepted))
+	for _, c := range accepted {
+		versions[c.PolicyType] = c.Version
+	}
+	for _, p := range policies {
+		if versions[p.Type] != p.Version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *ConsentService) SetMarketingConsent(userID uint, optIn bool, ipAddress, userAgent string) error {
+	err := s.repo.CreateMarketingConsent(&consent.MarketingConsent{
+		UserID:    userID,
+		OptIn:     optIn,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-M1",
+		}
+	}
+	return nil
+}
+
+func (s *ConsentService) GetStatus(userID uint) (*consent.ConsentStatusResponse, error) {
+	current, err := s.CurrentVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := s.requiresConsent(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-G1",
+		}
+	}
+
+	accepted, err := s.repo.FindLatestUserConsents(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-G2",
+		}
+	}
+
+	marketing, err := s.repo.FindMarketingConsents(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-G3",
+		}
+	}
+
+	resp := &consent.ConsentStatusResponse{
+		ConsentRequired:  required,
+		Current:          current,
+		Accepted:         make([]consent.UserConsentResponse, 0, len(accepted)),
+		MarketingHistory: make([]consent.MarketingConsentResponse, 0, len(marketing)),
+	}
+	for _, c := range accepted {
+		resp.Accepted = append(resp.Accepted, consent.UserConsentResponse{}.FromEntity(c))
+	}
+	for _, m := range marketing {
+		resp.MarketingHistory = append(resp.MarketingHistory, consent.MarketingConsentResponse{}.FromEntity(m))
+	}
+	if len(marketing) > 0 {
+		resp.MarketingOptIn = marketing[0].OptIn
+	}
+	return resp, nil
+}
+
+// Export flattens policy acceptances and marketing history into rows ordered by time
+func (s *ConsentService) Export(from, to *time.Time) ([]consent.ConsentExportRow, error) {
+	accepted, err := s.repo.FindAllUserConsents(from, to)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-E1",
+		}
+	}
+
+	marketing, err := s.repo.FindAllMarketingConsents(from, to)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Consent-E2",
+		}
+	}
+
+	// Rows are kept with their time until both kinds are merged in order
+	type timedRow struct {
+		at  time.Time
+		row consent.ConsentExportRow
+	}
+	timed := make([]timedRow, 0, len(accepted)+len(marketing))
+	for _, c := range accepted {
+		row := consent.ConsentExportRow{
+			UserID:    c.UserID,
+			Type:      c.PolicyType.String(),
+			Value:     c.Version,
+			At:        c.AcceptedAt.Format(time.RFC3339),
+			IPAddress: c.IPAddress,
+			UserAgent: c.UserAgent,
+		}
+		if c.User != nil {
+			row.Email = c.User.Email
+		}
+		timed = append(timed, timedRow{at: c.AcceptedAt, row: row})
+	}
+	for _, m := range marketing {
+		value := "opt_out"
+		if m.OptIn {
+			value = "opt_in"
+		}
+		row := consent.ConsentExportRow{
+			UserID:    m.UserID,
+			Type:      "marketing",
+			Value:     value,
+			At:        m.CreatedAt.Format(time.RFC3339),
+			IPAddress: m.IPAddress,
+			UserAgent: m.UserAgent,
+		}
+		if m.User != nil {
+			row.Email = m.User.Email
+		}
+		timed = append(timed, timedRow{at: m.CreatedAt, row: row})
+	}
+
+	slices.SortStableFunc(timed, func(a, b timedRow) int {
+		return a.at.Compare(b.at)
+	})
+	rows := make([]consent.ConsentExportRow, len(timed))
+	for i, t := range timed {
+		rows[i] = t.row
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,440 @@
+package dataimport
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/logger"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// unusablePassword matches no password, imported users without one set theirs through the password reset
+const unusablePassword = "!"
+
+// row is a row read from the file with its values by field
+type row struct {
+	line   int
+	raw    []string
+	fields map[string]string
+}
+
+// importer validates the rows of one kind and inserts them a chunk at a time
+type importer interface {
+	// add validates a row, keeping it for the next chunk or rejecting it
+	add(r row)
+	// pending returns the number of rows waiting for the next chunk
+	pending() int
+	// flush inserts the pending rows, errors stop the import
+	flush() error
+}
+
+// job is the import being processed and its error report
+type job struct {
+	imp       *dataimport.Import
+	report    *report
+	reportErr error
+}
+
+// accept counts rows as imported
+func (j *job) accept(n int) {
+	j.imp.ImportedRows += n
+}
+
+// reject counts a row as rejected and writes it to the error report
+func (j *job) reject(r row, message string) {
+	j.imp.RejectedRows++
+	if j.reportErr == nil {
+		j.reportErr = j.report.write(r, message)
+	}
+}
+
+// report is the CSV of rejected rows, created with the first one
+type report struct {
+	header []string
+	path   string
+	file   *os.File
+	writer *csv.Writer
+}
+
+func (r *report) write(rw row, message string) error {
+	if r.file == nil {
+		if err := os.MkdirAll(dataimport.ReportDir(), 0o750); err != nil {
+			return err
+		}
+		file, err := os.Create(r.path)
+		if err != nil {
+			return err
+		}
+		r.file = file
+		r.writer = csv.NewWriter(file)
+		r.writer.Write(append([]string{"line", "error"}, r.header...))
+	}
+	r.writer.Write(append([]string{strconv.Itoa(rw.line), message}, rw.raw...))
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *report) close() {
+	if r.file != nil {
+		r.file.Close()
+	}
+}
+
+// validationMessage validates a row struct, describing the failed rules by field
+func validationMessage(s interface{}) string {
+	err := iValidator.Validator.Struct(s)
+	if err == nil {
+		return ""
+	}
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err.Error()
+	}
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		message := fmt.Sprintf("%s: %s", fieldNames[e.StructField()], e.Tag())
+		if e.Param() != "" {
+			message += "=" + e.Param()
+		}
+		messages = append(messages, message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// fieldNames maps the struct fields of the rows to their field names
+var fieldNames = map[string]string{
+	"Name":          "name",
+	"Email":         "email",
+	"Username":      "username",
+	"Password":      "password",
+	"EmailVerified": "email_verified",
+	"Bio":           "bio",
+	"AuthorEmail":   "author_email",
+	"UserID":        "user_id",
+	"Tweet":         "tweet",
+	"Title":         "title",
+	"Body":          "body",
+	"Visibility":    "visibility",
+	"Status":        "status",
+	"CreatedAt":     "created_at",
+}
+
+// joinFields lists fields for an error message
+func joinFields(fields []string) string {
+	return strings.Join(fields, ", ")
+}
+
+// userImporter creates users, rows of an email or username already taken are rejected
+type userImporter struct {
+	job  *job
+	repo dataimport.Repository
+	rows []row
+	data []dataimport.UserRow
+	// seen holds the emails and usernames of the file, repeats are rejected
+	seen map[string]bool
+}
+
+func newUserImporter(j *job, repo dataimport.Repository) *userImporter {
+	return &userImporter{job: j, repo: repo, seen: map[string]bool{}}
+}
+
+func (im *userImporter) add(r row) {
+	data := dataimport.NewUserRow(r.fields)
+	if message := validationMessage(data); message != "" {
+		im.job.reject(r, message)
+		return
+	}
+	if data.Username != "" {
+		username, ok := user.NormalizeUsername(data.Username)
+		if !ok {
+			im.job.reject(r, "username: may only contain letters, digits and underscores")
+			return
+		}
+		data.Username = username
+		if im.seen["@"+username] {
+			im.job.reject(r, "username: repeated in the file")
+			return
+		}
+	}
+	if im.seen[data.Email] {
+		im.job.reject(r, "email: repeated in the file")
+		return
+	}
+
+	im.seen[data.Email] = true
+	if data.Username != "" {
+		im.seen["@"+data.Username] = true
+	}
+	im.rows = append(im.rows, r)
+	im.data = append(im.data, data)
+}
+
+func (im *userImporter) pending() int {
+	return len(im.rows)
+}
+
+func (im *userImporter) flush() error {
+	if len(im.rows) == 0 {
+		return nil
+	}
+	defer func() { im.rows, im.data = nil, nil }()
+
+	emails := make([]string, 0, len(im.data))
+	usernames := make([]string, 0, len(im.data))
+	for _, data := range im.data {
+		emails = append(emails, data.Email)
+		if data.Username != "" {
+			usernames = append(usernames, data.Username)
+		}
+	}
+	existing, err := im.repo.FindUsers(emails, usernames)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing users: %w", err)
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, u := range existing {
+		taken[strings.ToLower(u.Email)] = true
+		if u.Username != nil {
+			taken["@"+*u.Username] = true
+		}
+	}
+
+	var rows []row
+	var records []interface{}
+	for i, data := range im.data {
+		switch {
+		case taken[data.Email]:
+			im.job.reject(im.rows[i], "email: a user with this email already exists")
+			continue
+		case data.Username != "" && taken["@"+data.Username]:
+			im.job.reject(im.rows[i], "username: a user with this username already exists")
+			continue
+		}
+		if im.job.imp.DryRun {
+			im.job.accept(1)
+			continue
+		}
+
+		password := unusablePassword
+		if data.Password != "" {
+			if password, err = crypto.HashPassword(data.Password); err != nil {
+				return fmt.Errorf("failed to hash a password: %w", err)
+			}
+		}
+		u := &user.User{
+			Name:          data.Name,
+			Email:         data.Email,
+			Password:      password,
+			EmailVerified: data.Verified(),
+			Bio:           data.Bio,
+			Role:          user.UserR,
+		}
+		if data.Username != "" {
+			username := data.Username
+			u.Username = &username
+		}
+		rows = append(rows, im.rows[i])
+		records = append(records, u)
+	}
+	failed := insert(im.job, im.repo, rows, records)
+
+	// Inserted users are given their role like registration does, through the API holding the enforcer
+	for i, record := range records {
+		if failed[i] {
+			continue
+		}
+		u := record.(*user.User)
+		if err := worker.GrantRole(context.Background(), u.Email, u.Role.String()); err != nil {
+			logger.Warn("Failed to assign role to imported user", zap.Uint("user_id", u.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// postImporter creates posts of existing authors, given by email or user ID
+type postImporter struct {
+	job   *job
+	repo  dataimport.Repository
+	posts post.Repository
+	rows  []row
+	data  []dataimport.PostRow
+	// slugs holds the slugs given in the import by author, their posts are not inserted yet
+	slugs map[uint]map[string]bool
+}
+
+func newPostImporter(j *job, repo dataimport.Repository, posts post.Repository) *postImporter {
+	return &postImporter{job: j, repo: repo, posts: posts, slugs: map[uint]map[string]bool{}}
+}
+
+func (im *postImporter) add(r row) {
+	data := dataimport.NewPostRow(r.fields)
+	if message := validationMessage(data); message != "" {
+		im.job.reject(r, message)
+		return
+	}
+	if _, err := data.CreatedTime(); err != nil {
+		im.job.reject(r, err.Error())
+		return
+	}
+	im.rows = append(im.rows, r)
+	im.data = append(im.data, data)
+}
+
+func (im *postImporter) pending() int {
+	return len(im.rows)
+}
+
+func (im *postImporter) flush() error {
+	if len(im.rows) == 0 {
+		return nil
+	}
+	defer func() { im.rows, im.data = nil, nil }()
+
+	var emails []string
+	var ids []uint
+	for _, data := range im.data {
+		if data.AuthorEmail != "" {
+			emails = append(emails, data.AuthorEmail)
+		} else if id, err := strconv.ParseUint(data.UserID, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	authors, err := im.repo.FindAuthors(emails, ids)
+	if err != nil {
+		return fmt.Errorf("failed to look up the authors: %w", err)
+	}
+	byEmail := make(map[string]uint, len(authors))
+	byID := make(map[uint]bool, len(authors))
+	for _, u := range authors {
+		byEmail[strings.ToLower(u.Email)] = u.ID
+		byID[u.ID] = true
+	}
+
+	var rows []row
+	var records []interface{}
+	for i, data := range im.data {
+		authorID := byEmail[data.AuthorEmail]
+		if data.AuthorEmail == "" {
+			if id, err := strconv.ParseUint(data.UserID, 10, 32); err == nil && byID[uint(id)] {
+				authorID = uint(id)
+			}
+		}
+		if authorID == 0 {
+			im.job.reject(im.rows[i], "author: no user with this email or ID")
+			continue
+		}
+
+		p, err := im.build(data, authorID)
+		if err != nil {
+			return err
+		}
+		if im.job.imp.DryRun {
+			im.job.accept(1)
+			continue
+		}
+		rows = append(rows, im.rows[i])
+		records = append(records, p)
+	}
+	insert(im.job, im.repo, rows, records)
+	return nil
+}
+
+// build makes the post of a row, imported posts keep their original date and skip moderation
+func (im *postImporter) build(data dataimport.PostRow, authorID uint) (*post.Post, error) {
+	p := &post.Post{
+		UserID:          authorID,
+		Tweet:           data.Tweet,
+		Body:            data.Body,
+		Status:          post.StatusPublished,
+		ModerationState: post.ModerationVisible,
+		Visibility:      post.VisibilityPublic,
+	}
+	if data.Status != "" {
+		p.Status = data.Status
+	}
+	if data.Visibility != "" {
+		p.Visibility = data.Visibility
+	}
+	if data.Title != "" {
+		title := data.Title
+		p.Title = &title
+	} else {
+		p.Body = ""
+	}
+
+	created, _ := data.CreatedTime()
+	if !created.IsZero() {
+		p.CreatedAt = created
+		p.UpdatedAt = created
+	}
+	if p.Status == post.StatusPublished {
+		publishAt := created
+		if publishAt.IsZero() {
+			publishAt = time.Now()
+		}
+		p.PublishAt = &publishAt
+	}
+
+	if err := p.RenderBody(); err != nil {
+		return nil, fmt.Errorf("failed to render a body: %w", err)
+	}
+	if p.IsLongForm() {
+		slug, err := im.uniqueSlug(authorID, *p.Title)
+		if err != nil {
+			return nil, err
+		}
+		p.Slug = &slug
+	}
+	return p, nil
+}
+
+// uniqueSlug derives a slug the author has not used yet, in the database or earlier in the import
+func (im *postImporter) uniqueSlug(userID uint, title string) (string, error) {
+	if im.slugs[userID] == nil {
+		im.slugs[userID] = map[string]bool{}
+	}
+	slug, err := post.UniqueSlug(title, func(slug string) (bool, error) {
+		if im.slugs[userID][slug] {
+			return true, nil
+		}
+		taken, err := im.posts.SlugTaken(userID, slug)
+		if err != nil {
+			return false, fmt.Errorf("failed to check a slug: %w", err)
+		}
+		return taken, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	im.slugs[userID][slug] = true
+	return slug, nil
+}
+
+// insert creates the records of a chunk, rejecting the rows of those the database refuses.
+// It returns the indexes of the refused records.
+func insert(j *job, repo dataimport.Repository, rows []row, records []interface{}) map[int]bool {
+	failed := map[int]bool{}
+	if len(records) == 0 {
+		return failed
+	}
+	result := repo.Insert(records)
+	j.accept(result.SuccessCount)
+	for _, e := range result.Errors {
+		failed[e.Index] = true
+		j.reject(rows[e.Index], "insert failed: "+e.Message)
+	}
+	return failed
+}
@@ -0,0 +1,269 @@
+package dataimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"starter-gofiber/internal/domain/dataimport"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxLineSize is the longest NDJSON line read, a long-form post body fits several times
+const maxLineSize = 1024 * 1024
+
+// rowReader yields the rows of an uploaded file with their values by column name
+type rowReader interface {
+	// Header returns the columns written to the error report
+	Header() []string
+	// Next returns the row's line number, its values by column and the raw cells for the error
+	// report, io.EOF after the last row. A malformed row is returned with a *malformedRow error and
+	// reading goes on, other errors stop it.
+	Next() (line int, values map[string]string, raw []string, err error)
+	Close() error
+}
+
+// openRows opens a file of the given format, reading the header row of CSV and XLSX files
+func openRows(path, format string) (rowReader, error) {
+	switch format {
+	case dataimport.FormatCSV:
+		return openCSV(path)
+	case dataimport.FormatXLSX:
+		return openXLSX(path)
+	case dataimport.FormatNDJSON:
+		return openNDJSON(path)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// malformedRow is a row that could not be read, it is rejected and the next row is read
+type malformedRow struct {
+	err error
+}
+
+func (e *malformedRow) Error() string {
+	return e.err.Error()
+}
+
+// byColumn pairs cells with the header, cells beyond it are dropped and missing ones are empty
+func byColumn(header, cells []string) map[string]string {
+	values := make(map[string]string, len(header))
+	for i, column := range header {
+		if i < len(cells) {
+			values[column] = cells[i]
+		} else {
+			values[column] = ""
+		}
+	}
+	return values
+}
+
+// isBlank reports whether every cell of a row is empty, blank rows are skipped
+func isBlank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+type csvRows struct {
+	file   *os.File
+	reader *csv.Reader
+	header []string
+}
+
+func openCSV(path string) (*csvRows, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(file)
+	// Rows may have fewer or more cells than the header
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		if err == io.EOF {
+			return nil, fmt.Errorf("the file is empty")
+		}
+		return nil, fmt.Errorf("failed to read the header row: %w", err)
+	}
+	// Spreadsheet programs often save CSV files with a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	return &csvRows{file: file, reader: reader, header: header}, nil
+}
+
+func (r *csvRows) Header() []string {
+	return r.header
+}
+
+func (r *csvRows) Next() (int, map[string]string, []string, error) {
+	for {
+		cells, err := r.reader.Read()
+		if err == io.EOF {
+			return 0, nil, nil, io.EOF
+		}
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			return parseErr.StartLine, nil, cells, &malformedRow{err: parseErr.Err}
+		}
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if isBlank(cells) {
+			continue
+		}
+		line, _ := r.reader.FieldPos(0)
+		return line, byColumn(r.header, cells), cells, nil
+	}
+}
+
+func (r *csvRows) Close() error {
+	return r.file.Close()
+}
+
+type xlsxRows struct {
+	file   *excelize.File
+	rows   *excelize.Rows
+	header []string
+	line   int
+}
+
+// openXLSX reads the first sheet of a workbook
+func openXLSX(path string) (*xlsxRows, error) {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the workbook: %w", err)
+	}
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		file.Close()
+		return nil, fmt.Errorf("the workbook has no sheets")
+	}
+	rows, err := file.Rows(sheets[0])
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read the sheet: %w", err)
+	}
+
+	r := &xlsxRows{file: file, rows: rows}
+	for rows.Next() {
+		r.line++
+		cells, err := rows.Columns()
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to read the header row: %w", err)
+		}
+		if !isBlank(cells) {
+			r.header = cells
+			return r, nil
+		}
+	}
+	r.Close()
+	return nil, fmt.Errorf("the file is empty")
+}
+
+func (r *xlsxRows) Header() []string {
+	return r.header
+}
+
+func (r *xlsxRows) Next() (int, map[string]string, []string, error) {
+	for r.rows.Next() {
+		r.line++
+		cells, err := r.rows.Columns()
+		if err != nil {
+			return r.line, nil, cells, &malformedRow{err: err}
+		}
+		if isBlank(cells) {
+			continue
+		}
+		return r.line, byColumn(r.header, cells), cells, nil
+	}
+	if err := r.rows.Error(); err != nil {
+		return 0, nil, nil, err
+	}
+	return 0, nil, nil, io.EOF
+}
+
+func (r *xlsxRows) Close() error {
+	r.rows.Close()
+	return r.file.Close()
+}
+
+// ndjsonRows reads one JSON object per line, the keys are the columns
+type ndjsonRows struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	line    int
+}
+
+func openNDJSON(path string) (*ndjsonRows, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &ndjsonRows{file: file, scanner: scanner}, nil
+}
+
+// Header is a single column holding the line, since lines may have different keys
+func (r *ndjsonRows) Header() []string {
+	return []string{"data"}
+}
+
+func (r *ndjsonRows) Next() (int, map[string]string, []string, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		raw := []string{string(line)}
+
+		var object map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return r.line, nil, raw, &malformedRow{err: fmt.Errorf("invalid JSON: %w", err)}
+		}
+		values := make(map[string]string, len(object))
+		for key, value := range object {
+			values[key] = stringValue(value)
+		}
+		return r.line, values, raw, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to read line %d: %w", r.line+1, err)
+	}
+	return 0, nil, nil, io.EOF
+}
+
+func (r *ndjsonRows) Close() error {
+	return r.file.Close()
+}
+
+// stringValue formats a JSON value like the cell of a CSV file
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,341 @@
+package dataimport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"time"
+
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ImportService struct {
+	repo  dataimport.Repository
+	posts post.Repository
+}
+
+func NewImportService(repo dataimport.Repository, posts post.Repository) dataimport.Service {
+	return &ImportService{
+		repo:  repo,
+		posts: posts,
+	}
+}
+
+func (s *ImportService) Start(req *dataimport.ImportRequest, file *multipart.FileHeader, userID uint) (*dataimport.ImportResponse, error) {
+	var errors []*iValidator.IError
+
+	err := iValidator.Validator.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var el iValidator.IError
+			el.Field = err.Field()
+			el.Tag = err.Tag()
+			el.Value = err.Param()
+			errors = append(errors, &el)
+		}
+		return nil, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Data:    errors,
+			Order:   "S1",
+		}
+	}
+	if err := dataimport.CheckMapping(req.Kind, req.Mapping); err != nil {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	format := dataimport.FormatOf(file.Filename)
+	if format == "" {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "Only .csv, .xlsx and .ndjson files can be imported",
+			Order:   "S3",
+		}
+	}
+	if file.Size > dataimport.MaxFileSize() {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: fmt.Sprintf("The file is larger than %d MB", dataimport.MaxFileSize()/1024/1024),
+			Order:   "S4",
+		}
+	}
+
+	path, err := saveUpload(file, format)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+
+	imp := &dataimport.Import{
+		UserID:   userID,
+		Kind:     req.Kind,
+		Format:   format,
+		FileName: filepath.Base(file.Filename),
+		FilePath: path,
+		Mapping:  req.Mapping,
+		DryRun:   req.DryRun,
+		Status:   dataimport.StatusPending,
+	}
+	if err := s.repo.Create(imp); err != nil {
+		os.Remove(path)
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S6",
+		}
+	}
+
+	// Without a queue the file is processed during the request
+	if worker.AsynqClientInstance == nil {
+		err = s.Process(imp.ID)
+	} else {
+		err = worker.EnqueueImport(imp.ID)
+	}
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S7",
+		}
+	}
+	return s.FindByID(imp.ID)
+}
+
+// saveUpload copies the upload next to the other pending imports, outside the public storage
+func saveUpload(file *multipart.FileHeader, format string) (string, error) {
+	if err := os.MkdirAll(dataimport.UploadDir(), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create the upload directory: %w", err)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open the upload: %w", err)
+	}
+	defer src.Close()
+
+	path := filepath.Join(dataimport.UploadDir(), uuid.NewString()+"."+format)
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", fmt.Errorf("failed to store the upload: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("failed to store the upload: %w", err)
+	}
+	return path, dst.Close()
+}
+
+func (s *ImportService) Process(id uint) error {
+	imp, err := s.repo.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("import %d not found: %w", id, err)
+	}
+	// A task delivered twice finds the import already processed
+	if imp.Status != dataimport.StatusPending {
+		return nil
+	}
+
+	started := time.Now()
+	imp.Status = dataimport.StatusProcessing
+	imp.StartedAt = &started
+	if err := s.repo.Save(imp); err != nil {
+		return err
+	}
+
+	// Problems with the file fail the import, they are reported on it rather than retried
+	if err := s.run(imp); err != nil {
+		imp.Status = dataimport.StatusFailed
+		imp.Error = err.Error()
+	} else {
+		imp.Status = dataimport.StatusCompleted
+	}
+	finished := time.Now()
+	imp.FinishedAt = &finished
+
+	if err := os.Remove(imp.FilePath); err != nil && !os.IsNotExist(err) {
+		logger.Warn("Failed to delete imported file", zap.Uint("import_id", imp.ID), zap.Error(err))
+	}
+	imp.FilePath = ""
+
+	logger.Info("Import finished",
+		zap.Uint("import_id", imp.ID),
+		zap.String("status", imp.Status),
+		zap.Int("imported", imp.ImportedRows),
+		zap.Int("rejected", imp.RejectedRows),
+	)
+	return s.repo.Save(imp)
+}
+
+// run reads the rows of the file, rejecting invalid ones to the report and inserting the others
+// a chunk at a time. The counts are saved after every chunk for clients following the import.
+func (s *ImportService) run(imp *dataimport.Import) error {
+	rows, err := openRows(imp.FilePath, imp.Format)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// NDJSON lines have their own keys, rows missing a field are rejected one by one instead
+	if imp.Format != dataimport.FormatNDJSON {
+		if missing := dataimport.MissingFields(imp.Kind, rows.Header(), imp.Mapping); len(missing) > 0 {
+			return fmt.Errorf("no column maps to %s", joinFields(missing))
+		}
+	}
+
+	j := &job{imp: imp, report: &report{header: rows.Header(), path: reportPath(imp)}}
+	defer j.report.close()
+
+	var rowImporter importer
+	switch imp.Kind {
+	case dataimport.KindUsers:
+		rowImporter = newUserImporter(j, s.repo)
+	case dataimport.KindPosts:
+		rowImporter = newPostImporter(j, s.repo, s.posts)
+	default:
+		return fmt.Errorf("unknown kind %q", imp.Kind)
+	}
+
+	for {
+		line, values, raw, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		var malformed *malformedRow
+		if errors.As(err, &malformed) {
+			imp.TotalRows++
+			j.reject(row{line: line, raw: raw}, malformed.Error())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		imp.TotalRows++
+		fields := make(map[string]string, len(values))
+		for column, value := range values {
+			if field := dataimport.FieldOf(imp.Kind, column, imp.Mapping); field != "" {
+				fields[field] = value
+			}
+		}
+		rowImporter.add(row{line: line, raw: raw, fields: fields})
+
+		if rowImporter.pending() >= dataimport.ChunkSize {
+			if err := s.flush(j, rowImporter); err != nil {
+				return err
+			}
+		}
+	}
+	return s.flush(j, rowImporter)
+}
+
+// flush inserts the pending rows and saves the counts
+func (s *ImportService) flush(j *job, rowImporter importer) error {
+	if err := rowImporter.flush(); err != nil {
+		return err
+	}
+	if j.reportErr != nil {
+		return fmt.Errorf("failed to write the error report: %w", j.reportErr)
+	}
+	if j.report.file != nil {
+		path := j.report.path
+		j.imp.ReportPath = &path
+	}
+	return s.repo.Save(j.imp)
+}
+
+// reportPath is where the rejected rows of an import are written
+func reportPath(imp *dataimport.Import) string {
+	return filepath.Join(dataimport.ReportDir(), reportName(imp))
+}
+
+// reportName is the file name of the error report of an import
+func reportName(imp *dataimport.Import) string {
+	return fmt.Sprintf("import_%d_errors.csv", imp.ID)
+}
+
+func (s *ImportService) FindByID(id uint) (*dataimport.ImportResponse, error) {
+	imp, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Import not found",
+			Order:   "S1",
+		}
+	}
+	resp := dataimport.ImportResponse{}.FromEntity(*imp)
+	return &resp, nil
+}
+
+func (s *ImportService) FindAll(page, limit int) ([]dataimport.ImportResponse, *dataimport.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	imports, total, err := s.repo.FindAll(limit, offset)
+	if err != nil {
+		return nil, nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	result := make([]dataimport.ImportResponse, 0, len(imports))
+	for _, imp := range imports {
+		result = append(result, dataimport.ImportResponse{}.FromEntity(imp))
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	meta := &dataimport.PaginationMeta{
+		Total:       total,
+		Page:        page,
+		Limit:       limit,
+		TotalPages:  totalPages,
+		HasNextPage: page < totalPages,
+		HasPrevPage: page > 1,
+	}
+
+	return result, meta, nil
+}
+
+func (s *ImportService) Report(id uint) (string, string, error) {
+	imp, err := s.repo.FindByID(id)
+	if err != nil {
+		return "", "", &apierror.NotFoundError{
+			Message: "Import not found",
+			Order:   "S1",
+		}
+	}
+	if imp.ReportPath == nil {
+		return "", "", &apierror.NotFoundError{
+			Message: "The import has no rejected rows",
+			Order:   "S2",
+		}
+	}
+	if _, err := os.Stat(*imp.ReportPath); err != nil {
+		return "", "", &apierror.NotFoundError{
+			Message: "The error report is no longer available",
+			Order:   "S3",
+		}
+	}
+	return *imp.ReportPath, reportName(imp), nil
+}
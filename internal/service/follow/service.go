@@ -0,0 +1,158 @@
+package follow
+
+import (
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FollowService struct {
+	repo     follow.Repository
+	userRepo user.Repository
+	postRepo post.Repository
+}
+
+func NewFollowService(repo follow.Repository, userRepo user.Repository, postRepo post.Repository) follow.Service {
+	return &FollowService{
+		repo:     repo,
+		userRepo: userRepo,
+		postRepo: postRepo,
+	}
+}
+
+func (s *FollowService) Follow(followerID, followeeID uint) error {
+	if followerID == followeeID {
+		return &apierror.BadRequestError{
+			Message: "You cannot follow yourself",
+			Order:   "S1",
+		}
+	}
+
+	if _, err := s.userRepo.FindByID(followeeID); err != nil {
+		return &apierror.NotFoundError{
+			Message: "User not found",
+			Order:   "S2",
+		}
+	}
+
+	created, err := s.repo.Create(&follow.Follow{FollowerID: followerID, FolloweeID: followeeID})
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	// Following again is a no-op
+	if created {
+		s.invalidateTimeline(followerID)
+		utils.NotifyUser(followeeID, "new_follower", fiber.Map{
+			"user_id": followerID,
+		})
+	}
+	return nil
+}
+
+func (s *FollowService) Unfollow(followerID, followeeID uint) error {
+	removed, err := s.repo.Delete(followerID, followeeID)
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	if removed {
+		s.invalidateTimeline(followerID)
+	}
+	return nil
+}
+
+func (s *FollowService) Stats(userID, viewerID uint) (*follow.FollowStats, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "User not found",
+			Order:   "S1",
+		}
+	}
+
+	var stats follow.FollowStats
+	var err error
+	if stats.Followers, err = s.repo.CountFollowers(userID); err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S2"}
+	}
+	if stats.Following, err = s.repo.CountFollowing(userID); err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S3"}
+	}
+	if viewerID != 0 && viewerID != userID {
+		if stats.IsFollowing, err = s.repo.Exists(viewerID, userID); err != nil {
+			return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S4"}
+		}
+	}
+	return &stats, nil
+}
+
+func (s *FollowService) Followers(userID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	follows, err := s.repo.FindFollowers(userID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	follows, resp := trimPage(follows, page)
+	result := make([]follow.FollowResponse, 0, len(follows))
+	for _, f := range follows {
+		result = append(result, follow.FollowResponse{}.FromFollower(f))
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+func (s *FollowService) Following(userID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	follows, err := s.repo.FindFollowing(userID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	follows, resp := trimPage(follows, page)
+	result := make([]follow.FollowResponse, 0, len(follows))
+	for _, f := range follows {
+		result = append(result, follow.FollowResponse{}.FromFollowee(f))
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+// trimPage drops the look-ahead row fetched by ApplyCursorPagination and sets the next cursor
+func trimPage(follows []follow.Follow, page pagination.CursorPagination) ([]follow.Follow, *pagination.CursorResponse) {
+	limit := pageLimit(page)
+	resp := &pagination.CursorResponse{HasMore: len(follows) > limit}
+	if resp.HasMore {
+		follows = follows[:limit]
+		resp.NextCursor = pagination.EncodeCursor(follows[len(follows)-1].ID, "")
+	}
+	return follows, resp
+}
+
+// pageLimit mirrors the limit bounds applied by ApplyCursorPagination
+func pageLimit(page pagination.CursorPagination) int {
+	if page.Limit <= 0 {
+		return 10
+	}
+	if page.Limit > 100 {
+		return 100
+	}
+	return page.Limit
+}
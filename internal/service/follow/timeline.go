@@ -0,0 +1,244 @@
+package follow
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/pkg/pagination"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// timelineTTL expires timelines of inactive users, they are rebuilt on their next read
+const timelineTTL = 7 * 24 * time.Hour
+
+// Timeline merges two sources, newest first:
+//   - fan-out-on-write: posts of regular authors are pushed by the timeline:fanout job
+//     into the Redis sorted set of each follower
+//   - fan-out-on-read: posts of authors with at least follow.FanoutThreshold() followers
+//     are queried at read time
+//
+// Without Redis every followed author is read from the database. Either way only posts
+// whose visibility shares them with the user are returned.
+func (s *FollowService) Timeline(userID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	before, err := decodeTimelineCursor(page.Cursor)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: "Invalid cursor",
+			Order:   "S1",
+		}
+	}
+
+	following, err := s.repo.FollowingIDs(userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S2"}
+	}
+	authors := append(following, userID)
+
+	limit := pageLimit(page)
+	var entries []post.TimelineEntry
+	if cache.RedisClient == nil {
+		entries, err = s.postRepo.FindTimeline(authors, userID, before, limit+1)
+	} else {
+		entries, err = s.mergedTimeline(userID, following, before, limit+1)
+	}
+	if err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S3"}
+	}
+
+	resp := &pagination.CursorResponse{HasMore: len(entries) > limit}
+	if resp.HasMore {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		resp.NextCursor = pagination.EncodeCursor(last.ID, strconv.FormatInt(last.Score, 10))
+	}
+
+	ids := make([]uint, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	posts, err := s.postRepo.FindByIDs(ids, userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S4"}
+	}
+	byID := make(map[uint]post.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+
+	// Posts deleted after fan-out are skipped
+	result := make([]post.PostResponse, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			result = append(result, post.PostResponse{}.FromEntity(p))
+		}
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+// decodeTimelineCursor reads the position of the last post of the previous page, nil for the first page
+func decodeTimelineCursor(value string) (*post.TimelineEntry, error) {
+	cursor, err := pagination.DecodeCursor(value)
+	if err != nil || cursor == nil {
+		return nil, err
+	}
+	score, err := strconv.ParseInt(cursor.LastValue, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &post.TimelineEntry{ID: cursor.LastID, Score: score}, nil
+}
+
+func (s *FollowService) mergedTimeline(userID uint, following []uint, before *post.TimelineEntry, n int) ([]post.TimelineEntry, error) {
+	popular, err := s.repo.PopularIDs(following, follow.FanoutThreshold())
+	if err != nil {
+		return nil, err
+	}
+
+	isPopular := make(map[uint]bool, len(popular))
+	for _, id := range popular {
+		isPopular[id] = true
+	}
+	regular := []uint{userID}
+	for _, id := range following {
+		if !isPopular[id] {
+			regular = append(regular, id)
+		}
+	}
+
+	entries, err := s.readTimeline(userID, regular, before, n)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(popular) > 0 {
+		pulled, err := s.postRepo.FindTimeline(popular, userID, before, n)
+		if err != nil {
+			return nil, err
+		}
+		entries = mergeNewestFirst(entries, pulled)
+	}
+
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
+
+// readTimeline reads posts of regular authors from the user's Redis timeline,
+// rebuilding it when missing and continuing from the database past its oldest entry
+func (s *FollowService) readTimeline(userID uint, regular []uint, before *post.TimelineEntry, n int) ([]post.TimelineEntry, error) {
+	client := cache.RedisClient
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	key := follow.TimelineKey(userID)
+	if client.Exists(ctx, key).Val() == 0 {
+		if err := s.rebuildTimeline(ctx, userID, key, regular); err != nil {
+			logger.Warn("Failed to rebuild timeline", zap.Uint("user_id", userID), zap.Error(err))
+			return s.postRepo.FindTimeline(regular, userID, before, n)
+		}
+	}
+
+	maxScore := "+inf"
+	var sameScore int64
+	if before != nil {
+		// Posts sharing the cursor's score are read again and kept when their ID is lower
+		maxScore = strconv.FormatInt(before.Score, 10)
+		sameScore = client.ZCount(ctx, key, maxScore, maxScore).Val()
+	}
+	members, err := client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   maxScore,
+		Count: int64(n) + sameScore,
+	}).Result()
+	if err != nil {
+		logger.Warn("Failed to read timeline", zap.Uint("user_id", userID), zap.Error(err))
+		return s.postRepo.FindTimeline(regular, userID, before, n)
+	}
+	client.Expire(ctx, key, timelineTTL)
+
+	entries := make([]post.TimelineEntry, 0, len(members))
+	for _, m := range members {
+		member, _ := m.Member.(string)
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		entry := post.TimelineEntry{ID: uint(id), Score: int64(m.Score)}
+		if before == nil || entry.OlderThan(*before) {
+			entries = append(entries, entry)
+		}
+	}
+	// Redis orders equal scores by member, not by ID
+	sortNewestFirst(entries)
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+
+	// The sorted set is capped, older pages come from the database
+	if len(entries) < n && client.ZCard(ctx, key).Val() >= follow.TimelineMaxLen {
+		oldest := before
+		if len(entries) > 0 {
+			oldest = &entries[len(entries)-1]
+		}
+		older, err := s.postRepo.FindTimeline(regular, userID, oldest, n-len(entries))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, older...)
+	}
+
+	return entries, nil
+}
+
+func (s *FollowService) rebuildTimeline(ctx context.Context, userID uint, key string, regular []uint) error {
+	entries, err := s.postRepo.FindTimeline(regular, userID, nil, follow.TimelineMaxLen)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	members := make([]redis.Z, len(entries))
+	for i, e := range entries {
+		members[i] = redis.Z{Score: float64(e.Score), Member: e.ID}
+	}
+
+	pipe := cache.RedisClient.TxPipeline()
+	pipe.ZAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, timelineTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// invalidateTimeline drops the materialised timeline after the follow graph changed
+func (s *FollowService) invalidateTimeline(userID uint) {
+	_ = cache.CacheDelete(follow.TimelineKey(userID))
+}
+
+// mergeNewestFirst merges two timelines into one, newest first and without duplicates
+func mergeNewestFirst(a, b []post.TimelineEntry) []post.TimelineEntry {
+	seen := make(map[uint]bool, len(a)+len(b))
+	merged := make([]post.TimelineEntry, 0, len(a)+len(b))
+	for _, e := range append(a, b...) {
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			merged = append(merged, e)
+		}
+	}
+	sortNewestFirst(merged)
+	return merged
+}
+
+func sortNewestFirst(entries []post.TimelineEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[j].OlderThan(entries[i]) })
+}
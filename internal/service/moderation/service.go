@@ -0,0 +1,344 @@
+package moderation
+
+import (
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// auditEntities are the audit log entity types of report targets
+var auditEntities = map[string]string{
+	moderation.TargetPost:    "posts",
+	moderation.TargetComment: "comments",
+	moderation.TargetUser:    "users",
+}
+
+type ModerationService struct {
+	repo        moderation.Repository
+	postRepo    post.Repository
+	postS       post.Service
+	commentRepo comment.Repository
+	commentS    comment.Service
+	userRepo    user.Repository
+}
+
+func NewModerationService(repo moderation.Repository, postRepo post.Repository, postS post.Service, commentRepo comment.Repository, commentS comment.Service, userRepo user.Repository) moderation.Service {
+	return &ModerationService{
+		repo:        repo,
+		postRepo:    postRepo,
+		postS:       postS,
+		commentRepo: commentRepo,
+		commentS:    commentS,
+		userRepo:    userRepo,
+	}
+}
+
+func validate(req interface{}) error {
+	err := iValidator.Validator.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var errors []*iValidator.IError
+	for _, err := range err.(validator.ValidationErrors) {
+		var el iValidator.IError
+		el.Field = err.Field()
+		el.Tag = err.Tag()
+		el.Value = err.Param()
+		errors = append(errors, &el)
+	}
+	return &apierror.UnprocessableEntityError{
+		Message: err.Error(),
+		Data:    errors,
+		Order:   "S1",
+	}
+}
+
+// targetUser returns the author of a reported post or comment, or the reported user.
+// Reporters only see public posts, so those are the only ones they can report.
+func (s *ModerationService) targetUser(targetType string, targetID uint) (uint, bool) {
+	switch targetType {
+	case moderation.TargetPost:
+		p, err := s.postRepo.FindByID(targetID)
+		if err != nil || !p.IsPublic() {
+			return 0, false
+		}
+		return p.UserID, true
+	case moderation.TargetComment:
+		c, err := s.commentRepo.FindByID(targetID)
+		if err != nil {
+			return 0, false
+		}
+		return c.UserID, true
+	case moderation.TargetUser:
+		u, err := s.userRepo.FindByID(targetID)
+		if err != nil {
+			return 0, false
+		}
+		return u.ID, true
+	}
+	return 0, false
+}
+
+func (s *ModerationService) Report(req *moderation.ReportRequest, reporterID uint) (*moderation.ReportResponse, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	targetUserID, ok := s.targetUser(req.TargetType, req.TargetID)
+	if !ok {
+		return nil, &apierror.NotFoundError{
+			Message: fmt.Sprintf("Reported %s not found", req.TargetType),
+			Order:   "S2",
+		}
+	}
+	if targetUserID == reporterID {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "You cannot report yourself or your own content",
+			Order:   "S3",
+		}
+	}
+
+	if _, err := s.repo.FindPendingByReporter(reporterID, req.TargetType, req.TargetID); err == nil {
+		return nil, &apierror.BadRequestError{
+			Message: fmt.Sprintf("You already reported this %s", req.TargetType),
+			Order:   "S4",
+		}
+	}
+
+	report := moderation.Report{
+		ReporterID:   &reporterID,
+		TargetType:   req.TargetType,
+		TargetID:     req.TargetID,
+		TargetUserID: targetUserID,
+		Reason:       req.Reason,
+		Note:         req.Note,
+		Status:       moderation.StatusOpen,
+	}
+	if err := s.repo.Create(&report); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+
+	resp := moderation.ReportResponse{}.FromEntity(report)
+	return &resp, nil
+}
+
+func (s *ModerationService) Queue(filter moderation.ReportFilter, page, limit int) ([]moderation.ReportResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	reports, total, err := s.repo.FindAll(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	result := make([]moderation.ReportResponse, len(reports))
+	for i, r := range reports {
+		result[i] = moderation.ReportResponse{}.FromEntity(r)
+	}
+	return result, total, nil
+}
+
+func (s *ModerationService) FindByID(id uint) (*moderation.ReportResponse, error) {
+	report, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Report not found",
+			Order:   "S1",
+		}
+	}
+
+	resp := moderation.ReportResponse{}.FromEntity(*report)
+	return &resp, nil
+}
+
+func (s *ModerationService) Assign(id uint, req *moderation.AssignRequest, moderatorID uint) (*moderation.ReportResponse, error) {
+	report, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Report not found",
+			Order:   "S1",
+		}
+	}
+	if !report.Pending() {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "Report is already closed",
+			Order:   "S2",
+		}
+	}
+
+	assigneeID := moderatorID
+	if req.AssigneeID != nil {
+		assigneeID = *req.AssigneeID
+	}
+	if _, err := s.userRepo.FindByID(assigneeID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Assignee not found",
+			Order:   "S3",
+		}
+	}
+
+	report.AssigneeID = &assigneeID
+	report.Status = moderation.StatusInReview
+	if err := s.repo.Update(report); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	s.audit(moderatorID, "reports", report.ID, fmt.Sprintf("Assigned report #%d to user #%d", report.ID, assigneeID), req)
+
+	return s.FindByID(report.ID)
+}
+
+func (s *ModerationService) Resolve(id uint, req *moderation.ResolveRequest, moderatorID uint) (*moderation.ReportResponse, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Report not found",
+			Order:   "S2",
+		}
+	}
+	if !report.Pending() {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "Report is already closed",
+			Order:   "S3",
+		}
+	}
+
+	status := moderation.StatusResolved
+	switch req.Action {
+	case moderation.ActionDismiss:
+		status = moderation.StatusDismissed
+		s.approve(report)
+	case moderation.ActionHide, moderation.ActionDelete:
+		if err := s.remove(report, req.Action, moderatorID); err != nil {
+			return nil, err
+		}
+	case moderation.ActionSuspend:
+		if err := s.suspend(report.TargetUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Other reports on the same target are settled by the same decision
+	if err := s.repo.ResolvePending(report.TargetType, report.TargetID, status, req.Action, req.Note, moderatorID, time.Now()); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+	s.audit(moderatorID, auditEntities[report.TargetType], report.TargetID,
+		fmt.Sprintf("Resolved report #%d on %s #%d: %s", report.ID, report.TargetType, report.TargetID, req.Action), req)
+
+	return s.FindByID(report.ID)
+}
+
+// approve releases a post the filter held back, dismissing reports leaves other targets as they are
+func (s *ModerationService) approve(report *moderation.Report) {
+	if report.TargetType != moderation.TargetPost {
+		return
+	}
+	p, err := s.postRepo.FindByID(report.TargetID)
+	if err != nil || p.ModerationState != post.ModerationFlagged {
+		return
+	}
+	if err := s.postRepo.SetModerationState(p.ID, post.ModerationVisible); err != nil {
+		logger.Error("Failed to release flagged post", zap.Uint("post_id", p.ID), zap.Error(err))
+		return
+	}
+
+	// Held back posts were never announced
+	p.ModerationState = post.ModerationVisible
+	if p.IsPublic() {
+		worker.AnnouncePost(*p, p.Mentions)
+	}
+}
+
+// remove hides or deletes a reported post or comment. Hidden comments are deleted,
+// they stay in their thread as placeholders either way.
+func (s *ModerationService) remove(report *moderation.Report, action string, moderatorID uint) error {
+	switch report.TargetType {
+	case moderation.TargetPost:
+		// Deleted posts are hidden too, so they stay hidden when their author restores them from the trash
+		if err := s.postRepo.SetModerationState(report.TargetID, post.ModerationHidden); err != nil {
+			return &apierror.InternalServerError{
+				Message: err.Error(),
+				Order:   "S-Remove-1",
+			}
+		}
+		if action == moderation.ActionDelete {
+			return s.postS.Delete(report.TargetID, moderatorID, true)
+		}
+		return nil
+	case moderation.TargetComment:
+		return s.commentS.Delete(report.TargetID, moderatorID, true)
+	default:
+		return &apierror.UnprocessableEntityError{
+			Message: fmt.Sprintf("A reported user cannot be removed with %s, suspend them instead", action),
+			Order:   "S-Remove-2",
+		}
+	}
+}
+
+// suspend blocks a user from signing in and ends their sessions
+func (s *ModerationService) suspend(userID uint) error {
+	usr, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return &apierror.NotFoundError{
+			Message: "User not found",
+			Order:   "S-Suspend-1",
+		}
+	}
+	if usr.SuspendedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	usr.SuspendedAt = &now
+	if err := s.userRepo.Update(usr); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Suspend-2",
+		}
+	}
+	if err := s.userRepo.RevokeAllUserTokens(userID); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Suspend-3",
+		}
+	}
+	return nil
+}
+
+// audit records a moderator's action, failures are logged so the action still goes through
+func (s *ModerationService) audit(moderatorID uint, entityType string, entityID uint, description string, data interface{}) {
+	if err := s.repo.LogAction(&moderatorID, entityType, entityID, description, data); err != nil {
+		logger.Warn("Failed to record moderation action", zap.String("description", description), zap.Error(err))
+	}
+}
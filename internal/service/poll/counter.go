@@ -0,0 +1,120 @@
+package poll
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Tallies of open polls live in one Redis hash per poll, keyed by option ID plus the voter count,
+// so busy polls are not counted from the votes table on every read. The hash is warmed from the
+// votes table when missing and removed when the closing job persists the final tallies.
+// Without Redis the tallies are counted straight from the votes table.
+
+// countBallot adds a stored ballot to the poll's counters. A missing hash is left alone, it is
+// warmed from the votes table including the ballot on the next read.
+func (s *PollService) countBallot(pollID uint, b poll.Ballot) {
+	client := cache.RedisClient
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	key := poll.CounterKey(pollID)
+	if client.Exists(ctx, key).Val() == 0 {
+		return
+	}
+
+	pipe := client.TxPipeline()
+	for _, v := range b.Votes {
+		pipe.HIncrBy(ctx, key, strconv.FormatUint(uint64(v.OptionID), 10), 1)
+	}
+	pipe.HIncrBy(ctx, key, poll.VotersField, 1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		// The votes table stays the source of truth, drop the hash so it is warmed again
+		logger.Warn("Failed to update poll counters", zap.Uint("poll_id", pollID), zap.Error(err))
+		client.Del(context.Background(), key)
+	}
+}
+
+// loadTallies returns the live tallies of each poll
+func (s *PollService) loadTallies(pollIDs []uint) (map[uint]poll.Tally, error) {
+	client := cache.RedisClient
+	if client == nil {
+		return s.repo.CountVotes(pollIDs)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pipe := client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(pollIDs))
+	for i, id := range pollIDs {
+		cmds[i] = pipe.HGetAll(ctx, poll.CounterKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		logger.Warn("Failed to read poll counters, falling back to database", zap.Error(err))
+		return s.repo.CountVotes(pollIDs)
+	}
+
+	result := make(map[uint]poll.Tally, len(pollIDs))
+	var missing []uint
+	for i, id := range pollIDs {
+		values := cmds[i].Val()
+		if len(values) == 0 {
+			missing = append(missing, id)
+			continue
+		}
+		tally := poll.Tally{Options: make(map[uint]int, len(values))}
+		for field, v := range values {
+			n, _ := strconv.Atoi(v)
+			if field == poll.VotersField {
+				tally.Voters = n
+				continue
+			}
+			if optionID, err := strconv.ParseUint(field, 10, 64); err == nil {
+				tally.Options[uint(optionID)] = n
+			}
+		}
+		result[id] = tally
+	}
+
+	if len(missing) > 0 {
+		counted, err := s.repo.CountVotes(missing)
+		if err != nil {
+			return nil, err
+		}
+		for id, tally := range counted {
+			result[id] = tally
+		}
+		s.storeCounters(ctx, client, counted)
+	}
+
+	return result, nil
+}
+
+// storeCounters writes counted tallies into the Redis hashes. They expire after the longest a
+// poll runs, in case the closing job never removes them.
+func (s *PollService) storeCounters(ctx context.Context, client *redis.Client, tallies map[uint]poll.Tally) {
+	pipe := client.Pipeline()
+	for id, tally := range tallies {
+		key := poll.CounterKey(id)
+		pipe.HSet(ctx, key, poll.VotersField, tally.Voters)
+		for optionID, n := range tally.Options {
+			pipe.HSet(ctx, key, strconv.FormatUint(uint64(optionID), 10), n)
+		}
+		pipe.Expire(ctx, key, poll.MaxDuration)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("Failed to warm poll counters", zap.Error(err))
+	}
+}
@@ -0,0 +1,192 @@
+package poll
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type PollService struct {
+	repo     poll.Repository
+	postRepo post.Repository
+}
+
+func NewPollService(repo poll.Repository, postRepo post.Repository) poll.Service {
+	return &PollService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+func (s *PollService) Find(postID, viewerID uint) (*poll.PollResponse, error) {
+	p, err := s.postRepo.FindVisibleByID(postID, viewerID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+	if p.Poll == nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post has no poll",
+			Order:   "S2",
+		}
+	}
+
+	resp := poll.PollResponse{}.FromEntity(*p.Poll, p.UserID)
+	if err := s.Attach([]*poll.PollResponse{&resp}, viewerID); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+	return &resp, nil
+}
+
+func (s *PollService) Vote(postID uint, req *poll.VoteRequest, userID uint) (*poll.PollResponse, error) {
+	var errors []*iValidator.IError
+
+	err := iValidator.Validator.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var el iValidator.IError
+			el.Field = err.Field()
+			el.Tag = err.Tag()
+			el.Value = err.Param()
+			errors = append(errors, &el)
+		}
+		return nil, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Data:    errors,
+			Order:   "S1",
+		}
+	}
+
+	// Only polls of live posts the user may see take votes, deleted ones are not found
+	p, err := s.postRepo.FindVisibleByID(postID, userID)
+	if err != nil || !p.IsPublic() {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S2",
+		}
+	}
+	if p.Poll == nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post has no poll",
+			Order:   "S3",
+		}
+	}
+	pl := *p.Poll
+	if pl.IsClosed(time.Now()) {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "Poll is closed",
+			Order:   "S4",
+		}
+	}
+
+	ballot := poll.Ballot{PollID: pl.ID, UserID: userID}
+	for _, id := range req.OptionIDs {
+		valid := slices.ContainsFunc(pl.Options, func(o poll.Option) bool { return o.ID == id })
+		duplicate := slices.ContainsFunc(ballot.Votes, func(v poll.Vote) bool { return v.OptionID == id })
+		if !valid || duplicate {
+			return nil, &apierror.UnprocessableEntityError{
+				Message: "Votes must be distinct options of the poll",
+				Order:   "S5",
+			}
+		}
+		ballot.Votes = append(ballot.Votes, poll.Vote{OptionID: id})
+	}
+	if !pl.MultipleChoice && len(ballot.Votes) != 1 {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "Poll takes a single choice",
+			Order:   "S6",
+		}
+	}
+
+	cast, err := s.repo.CastBallot(&ballot)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S7",
+		}
+	}
+	if !cast {
+		return nil, &apierror.BadRequestError{
+			Message: "You already voted in this poll",
+			Order:   "S8",
+		}
+	}
+	s.countBallot(pl.ID, ballot)
+
+	tallies, err := s.loadTallies([]uint{pl.ID})
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S9",
+		}
+	}
+	tally := tallies[pl.ID]
+	worker.PushPollResults(context.Background(), s.repo, pl, p.UserID, tally)
+
+	resp := poll.PollResponse{}.FromEntity(pl, p.UserID)
+	resp.WithBallot(ballot)
+	resp.WithResults(tally)
+	return &resp, nil
+}
+
+func (s *PollService) Attach(polls []*poll.PollResponse, viewerID uint) error {
+	if len(polls) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(polls))
+	for _, p := range polls {
+		ids = append(ids, p.ID)
+	}
+	ballots, err := s.repo.FindBallots(ids, viewerID)
+	if err != nil {
+		return err
+	}
+	byPoll := make(map[uint]poll.Ballot, len(ballots))
+	for _, b := range ballots {
+		byPoll[b.PollID] = b
+	}
+
+	// Results are hidden until the viewer voted or the poll closed, authors always see them
+	var live []uint
+	for _, p := range polls {
+		if b, ok := byPoll[p.ID]; ok {
+			p.WithBallot(b)
+		}
+		if !p.Voted && !p.Closed && (viewerID == 0 || viewerID != p.AuthorID) {
+			continue
+		}
+		if final := p.Final(); final != nil {
+			p.WithResults(*final)
+			continue
+		}
+		live = append(live, p.ID)
+	}
+	if len(live) == 0 {
+		return nil
+	}
+
+	tallies, err := s.loadTallies(live)
+	if err != nil {
+		return err
+	}
+	for _, p := range polls {
+		if slices.Contains(live, p.ID) {
+			p.WithResults(tallies[p.ID])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package post
+
+import (
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/storage"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/variables"
+
+	"go.uber.org/zap"
+)
+
+func (s *PostService) UploadAttachment(file *multipart.FileHeader, alt string, userID uint) (*post.AttachmentResponse, error) {
+	alt = strings.TrimSpace(alt)
+	if utf8.RuneCountInString(alt) > post.MaxAltTextLength {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: fmt.Sprintf("Alt text must be at most %d characters", post.MaxAltTextLength),
+			Order:   "S1",
+		}
+	}
+
+	// Only images are accepted for now, other kinds get their own validation config and processing
+	if err := storage.ValidateFile(file, storage.DefaultImageConfig()); err != nil {
+		return nil, err
+	}
+
+	// Images are re-encoded, which also strips metadata such as EXIF location.
+	// PNG stays PNG to keep transparency, everything else becomes JPEG.
+	config := storage.DefaultImageProcessConfig()
+	mimeType := "image/jpeg"
+	if strings.ToLower(filepath.Ext(file.Filename)) == ".png" {
+		config.Format = "png"
+		mimeType = "image/png"
+	}
+
+	processed, err := storage.ProcessImage(file, variables.ATTACHMENT_PATH, config)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := post.Attachment{
+		UserID:   userID,
+		Kind:     post.AttachmentImage,
+		Path:     processed.OriginalURL,
+		MimeType: mimeType,
+		Size:     file.Size,
+		Width:    processed.Width,
+		Height:   processed.Height,
+		AltText:  alt,
+	}
+	if processed.ThumbnailURL != "" {
+		attachment.ThumbnailPath = &processed.ThumbnailURL
+	}
+	if processed.MediumURL != "" {
+		attachment.MediumPath = &processed.MediumURL
+	}
+
+	if err := s.repo.CreateAttachment(&attachment); err != nil {
+		removeAttachmentFiles([]post.Attachment{attachment})
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	resp := post.AttachmentResponse{}.FromEntity(attachment)
+	return &resp, nil
+}
+
+func (s *PostService) DeleteAttachment(id uint, userID uint) error {
+	found, err := s.repo.FindAttachmentsByIDs([]uint{id})
+	if err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+	if len(found) == 0 || found[0].UserID != userID {
+		return &apierror.NotFoundError{
+			Message: "Attachment not found",
+			Order:   "S2",
+		}
+	}
+	if found[0].PostID != nil {
+		return &apierror.BadRequestError{
+			Message: "Attachment is used by a post, update the post to remove it",
+			Order:   "S3",
+		}
+	}
+
+	if err := s.repo.DeleteAttachments([]uint{id}); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	removeAttachmentFiles(found)
+	return nil
+}
+
+// claimableAttachments loads the attachments in the order of ids and checks that each
+// one is an upload of the user that is unused or already belongs to the post
+func (s *PostService) claimableAttachments(ids []uint, userID, postID uint) ([]post.Attachment, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > post.MaxAttachments() {
+		return nil, &apierror.UnprocessableEntityError{
+			Message: fmt.Sprintf("A post can have at most %d attachments", post.MaxAttachments()),
+			Order:   "S-Attachments-1",
+		}
+	}
+
+	found, err := s.repo.FindAttachmentsByIDs(ids)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Attachments-2",
+		}
+	}
+	byID := make(map[uint]post.Attachment, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+
+	attachments := make([]post.Attachment, 0, len(ids))
+	for _, id := range ids {
+		a, ok := byID[id]
+		if !ok || a.UserID != userID || (a.PostID != nil && *a.PostID != postID) {
+			return nil, &apierror.UnprocessableEntityError{
+				Message: fmt.Sprintf("Attachment %d not found", id),
+				Order:   "S-Attachments-3",
+			}
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+// attach stores the post's attachments in order, the ones it no longer lists are
+// detached and kept for its revisions until the post is deleted
+func (s *PostService) attach(p *post.Post, attachments []post.Attachment) error {
+	if err := s.repo.SetAttachments(p.ID, attachmentIDs(attachments)); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Attachments-4",
+		}
+	}
+
+	for i := range attachments {
+		attachments[i].PostID = &p.ID
+		attachments[i].Position = i
+		attachments[i].DetachedAt = nil
+	}
+	p.Attachments = attachments
+	return nil
+}
+
+// removeAttachmentFiles deletes stored files, failures only leave orphaned files behind
+func removeAttachmentFiles(attachments []post.Attachment) {
+	for _, a := range attachments {
+		for _, path := range a.Files() {
+			if err := storage.DeleteFile(&path, ""); err != nil {
+				logger.Warn("Failed to delete attachment file", zap.Uint("attachment_id", a.ID), zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+}
+
+func attachmentIDs(attachments []post.Attachment) []uint {
+	ids := make([]uint, len(attachments))
+	for i, a := range attachments {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
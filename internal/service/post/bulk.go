@@ -0,0 +1,212 @@
+package post
+
+import (
+	"fmt"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// bulkChunkSize is the number of posts deleted or restored per query
+const bulkChunkSize = 100
+
+func (s *PostService) Bulk(req *post.BulkRequest, userID uint) (*post.BulkResult, error) {
+	var errors []*iValidator.IError
+
+	err := iValidator.Validator.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var el iValidator.IError
+			el.Field = err.Field()
+			el.Tag = err.Tag()
+			el.Value = err.Param()
+			errors = append(errors, &el)
+		}
+		return nil, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Data:    errors,
+			Order:   "S1",
+		}
+	}
+
+	total := req.Len()
+	if total == 0 {
+		return nil, &apierror.BadRequestError{
+			Message: "The batch has no items for the " + req.Action + " action",
+			Order:   "S2",
+		}
+	}
+	if total > post.BulkMaxItems {
+		return nil, &apierror.BadRequestError{
+			Message: fmt.Sprintf("A batch may hold at most %d items", post.BulkMaxItems),
+			Order:   "S3",
+		}
+	}
+
+	// Without a queue every batch runs during the request
+	if total <= post.BulkSyncLimit || worker.AsynqClientInstance == nil {
+		return s.RunBulk(req, userID, nil), nil
+	}
+
+	jobID := uuid.NewString()
+	if err := worker.EnqueuePostBulk(jobID, userID, req); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	result := post.NewBulkResult(req.Action, total)
+	result.JobID = jobID
+	result.Status = post.BulkStatusPending
+	return result, nil
+}
+
+func (s *PostService) RunBulk(req *post.BulkRequest, userID uint, progress func(done, total int)) *post.BulkResult {
+	if progress == nil {
+		progress = func(done, total int) {}
+	}
+
+	total := req.Len()
+	result := post.NewBulkResult(req.Action, total)
+	// Creates and updates go through Create and Update item by item, they need the filter,
+	// slugs, mentions, tags, revisions and scheduling that database.BulkCreate/BulkUpdate skip
+	switch req.Action {
+	case post.BulkCreate:
+		for i := range req.Posts {
+			item := req.Posts[i]
+			item.UserID = userID
+			// A batch carries no files, attachments are uploaded beforehand and referenced
+			item.Photo = ""
+			resp, err := s.Create(&item, userID)
+			if err != nil {
+				result.Fail(i, 0, err.Error())
+			} else {
+				result.Succeed(resp.ID)
+			}
+			progress(i+1, total)
+		}
+	case post.BulkUpdate:
+		for i := range req.Updates {
+			item := req.Updates[i]
+			item.UserID = userID
+			item.Photo = nil
+			item.UploadedIDs = nil
+			resp, err := s.Update(item.ID, &item, userID)
+			if err != nil {
+				result.Fail(i, item.ID, err.Error())
+			} else {
+				result.Succeed(resp.ID)
+			}
+			progress(i+1, total)
+		}
+	case post.BulkDelete, post.BulkRestore:
+		s.bulkTrash(req, userID, result, progress)
+	}
+	return result
+}
+
+// bulkTrash moves the user's posts among req.IDs to the trash or out of it, a chunk at a time
+func (s *PostService) bulkTrash(req *post.BulkRequest, userID uint, result *post.BulkResult, progress func(done, total int)) {
+	restore := req.Action == post.BulkRestore
+	apply := s.repo.BulkDelete
+	missing := "Post not found"
+	if restore {
+		apply = s.repo.BulkRestore
+		missing = "Post not found in the trash"
+	}
+
+	seen := make(map[uint]bool, len(req.IDs))
+	for start := 0; start < len(req.IDs); start += bulkChunkSize {
+		end := min(start+bulkChunkSize, len(req.IDs))
+		chunk := req.IDs[start:end]
+
+		posts, err := s.repo.FindForBulk(chunk, restore)
+		if err != nil {
+			for i, id := range chunk {
+				result.Fail(start+i, id, err.Error())
+			}
+			progress(end, len(req.IDs))
+			continue
+		}
+		found := make(map[uint]*post.Post, len(posts))
+		for i := range posts {
+			found[posts[i].ID] = &posts[i]
+		}
+
+		var ids []uint
+		var indexes []int
+		for i, id := range chunk {
+			p, ok := found[id]
+			switch {
+			case seen[id]:
+				result.Fail(start+i, id, "Post is listed more than once")
+			case !ok:
+				result.Fail(start+i, id, missing)
+			case p.UserID != userID:
+				result.Fail(start+i, id, "You don't have permission to manage this post")
+			default:
+				ids = append(ids, id)
+				indexes = append(indexes, start+i)
+			}
+			seen[id] = true
+		}
+
+		if len(ids) > 0 {
+			if _, err := apply(ids); err != nil {
+				for i, id := range ids {
+					result.Fail(indexes[i], id, err.Error())
+				}
+			} else {
+				for _, id := range ids {
+					s.rescheduleBulk(found[id], restore)
+					result.Succeed(id)
+				}
+			}
+		}
+		progress(end, len(req.IDs))
+	}
+}
+
+// rescheduleBulk drops the pending publish of a deleted post, or queues it again for a restored one
+func (s *PostService) rescheduleBulk(p *post.Post, restored bool) {
+	if restored {
+		s.reschedule(p, post.StatusDraft, nil)
+		return
+	}
+	oldStatus := p.Status
+	p.Status = post.StatusDraft
+	s.reschedule(p, oldStatus, p.PublishAt)
+}
+
+func (s *PostService) BulkJob(jobID string, userID uint) (*post.BulkResult, error) {
+	job, err := worker.FindPostBulk(jobID)
+	if err != nil {
+		logger.Warn("Failed to look up bulk job", zap.String("job_id", jobID), zap.Error(err))
+		return nil, &apierror.InternalServerError{
+			Message: "Failed to look up the bulk job",
+			Order:   "S1",
+		}
+	}
+	// Other users' jobs are not revealed
+	if job == nil || job.UserID != userID {
+		return nil, &apierror.NotFoundError{
+			Message: "Bulk job not found",
+			Order:   "S2",
+		}
+	}
+
+	if job.Result != nil {
+		return job.Result, nil
+	}
+	result := post.NewBulkResult(job.Request.Action, job.Request.Len())
+	result.JobID = jobID
+	result.Status = job.Status
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package post
+
+import (
+	"fmt"
+
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// flag queues a post held back by the filter for review
+func (s *PostService) flag(p *post.Post, rule moderation.Rule) {
+	report := moderation.Report{
+		TargetType:   moderation.TargetPost,
+		TargetID:     p.ID,
+		TargetUserID: p.UserID,
+		Reason:       moderation.ReasonFilter,
+		Note:         fmt.Sprintf("Matched %q", rule.Pattern),
+		Status:       moderation.StatusOpen,
+	}
+	if err := s.reports.Create(&report); err != nil {
+		// The post stays flagged and out of public listings
+		logger.Error("Failed to queue flagged post", zap.Uint("post_id", p.ID), zap.Error(err))
+		return
+	}
+	s.logFilter("reports", report.ID, fmt.Sprintf("Flagged post #%d matching %q", p.ID, rule.Pattern), report)
+}
+
+// logFilter records what the filter did in the audit log, failures never block the post
+func (s *PostService) logFilter(entityType string, entityID uint, description string, data interface{}) {
+	if err := s.reports.LogAction(nil, entityType, entityID, description, data); err != nil {
+		logger.Warn("Failed to record filter action", zap.String("description", description), zap.Error(err))
+	}
+}
@@ -0,0 +1,175 @@
+package post
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+)
+
+func (s *PostService) ListRevisions(postID uint, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	postEntity, err := s.repo.FindVisibleByID(postID, viewerID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	cursor, err := pagination.DecodeCursor(page.Cursor)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: "Invalid cursor",
+			Order:   "S2",
+		}
+	}
+
+	revisions, err := s.repo.FindRevisions(postID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	limit := pageLimit(page)
+	resp := &pagination.CursorResponse{HasMore: len(revisions) > limit}
+	if resp.HasMore {
+		revisions = revisions[:limit]
+		resp.NextCursor = pagination.EncodeCursor(revisions[len(revisions)-1].ID, "")
+	}
+
+	attachments, err := s.repo.FindPostAttachments(postID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	byID := make(map[uint]post.Attachment, len(attachments))
+	for _, a := range attachments {
+		byID[a.ID] = a
+	}
+
+	// Each revision was replaced by the next newer one, the newest by the current text.
+	// A later page starts after the cursor's revision, which replaced its first row.
+	replacedBy := postEntity.Tweet
+	if cursor != nil {
+		if newer, err := s.repo.FindRevision(cursor.LastID); err == nil && newer.PostID == postID {
+			replacedBy = newer.Tweet
+		}
+	}
+
+	result := make([]post.RevisionResponse, 0, len(revisions))
+	for _, rev := range revisions {
+		result = append(result, post.RevisionResponse{}.FromEntity(rev, byID, replacedBy))
+		replacedBy = rev.Tweet
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+func (s *PostService) RestoreRevision(postID, revisionID uint, userID uint, moderator bool) (*post.PostResponse, error) {
+	postEntity, err := s.repo.FindByID(postID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	// Check ownership, moderators may restore any post
+	if postEntity.UserID != userID && !moderator {
+		return nil, &apierror.ForbiddenError{
+			Message: "You don't have permission to restore this post",
+			Order:   "S2",
+		}
+	}
+
+	revision, err := s.repo.FindRevision(revisionID)
+	if err != nil || revision.PostID != postID {
+		return nil, &apierror.NotFoundError{
+			Message: "Revision not found",
+			Order:   "S3",
+		}
+	}
+
+	found, err := s.repo.FindPostAttachments(postID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	byID := make(map[uint]post.Attachment, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+	attachments := make([]post.Attachment, 0, len(found))
+	for _, id := range revision.Attachments() {
+		if a, ok := byID[id]; ok {
+			attachments = append(attachments, a)
+		}
+	}
+
+	// Restoring is an edit too, so it can be undone
+	if err := s.recordRevision(postEntity, userID); err != nil {
+		return nil, err
+	}
+	oldLink := postEntity.LinkURL()
+	postEntity.Tweet = revision.Tweet
+	postEntity.Photo = revision.Photo
+	postEntity.Title = revision.Title
+	postEntity.Body = revision.Body
+	hadSlug := postEntity.Slug != nil
+	if err := s.renderLongForm(postEntity); err != nil {
+		return nil, err
+	}
+
+	err = s.saveWithSlug(postEntity, !hadSlug, func() error {
+		return s.repo.Update(postEntity)
+	})
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S5",
+		}
+	}
+
+	added, err := s.syncEntities(postEntity)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attach(postEntity, attachments); err != nil {
+		return nil, err
+	}
+	if postEntity.IsPublic() {
+		worker.NotifyMentions(*postEntity, added)
+	}
+	s.unfurl(postEntity, oldLink)
+
+	s.loadQuoted(postEntity, userID)
+	resp := post.PostResponse{}.FromEntity(*postEntity)
+	return &resp, nil
+}
+
+// recordRevision keeps the post's current content before an edit replaces it,
+// posts that are live are marked as edited
+func (s *PostService) recordRevision(p *post.Post, editorID uint) error {
+	revision := post.NewRevision(*p, editorID)
+	if err := s.repo.CreateRevision(&revision); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Revision-1",
+		}
+	}
+
+	if p.Status == post.StatusPublished {
+		now := time.Now()
+		p.EditedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package post
+
+import (
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// resolveSchedule works out the status and publish time a request asks for, current is nil for a new post.
+// An empty status keeps the current one, or means scheduled when a publish time is given.
+func resolveSchedule(status string, publishAt *time.Time, current *post.Post) (string, *time.Time, error) {
+	if status == "" {
+		switch {
+		case publishAt != nil:
+			status = post.StatusScheduled
+		case current != nil:
+			return current.Status, current.PublishAt, nil
+		default:
+			status = post.StatusPublished
+		}
+	}
+
+	if current != nil && current.Status == post.StatusPublished {
+		if status != post.StatusPublished {
+			return "", nil, &apierror.UnprocessableEntityError{
+				Message: "A published post cannot become a draft or be scheduled",
+				Order:   "S-Schedule-1",
+			}
+		}
+		return current.Status, current.PublishAt, nil
+	}
+
+	switch status {
+	case post.StatusScheduled:
+		if publishAt == nil && current != nil && current.Status == post.StatusScheduled {
+			publishAt = current.PublishAt
+		}
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return "", nil, &apierror.UnprocessableEntityError{
+				Message: "publish_at must be in the future to schedule a post",
+				Order:   "S-Schedule-2",
+			}
+		}
+		// Tasks identify a schedule by its Unix time
+		at := publishAt.Truncate(time.Second)
+		return status, &at, nil
+	case post.StatusPublished:
+		now := time.Now()
+		return status, &now, nil
+	default:
+		return post.StatusDraft, nil, nil
+	}
+}
+
+// reschedule replaces the pending publish task when the post's schedule changed
+func (s *PostService) reschedule(p *post.Post, oldStatus string, oldPublishAt *time.Time) {
+	wasScheduled := oldStatus == post.StatusScheduled && oldPublishAt != nil
+	isScheduled := p.Status == post.StatusScheduled && p.PublishAt != nil
+	if wasScheduled && isScheduled && oldPublishAt.Equal(*p.PublishAt) {
+		return
+	}
+
+	if wasScheduled {
+		if err := worker.CancelPostPublish(p.ID, *oldPublishAt); err != nil {
+			logger.Warn("Failed to cancel scheduled publish", zap.Uint("post_id", p.ID), zap.Error(err))
+		}
+	}
+	if isScheduled {
+		// A lost task is caught by the periodic sweep of due posts
+		if err := worker.EnqueuePostPublish(p.ID, *p.PublishAt); err != nil {
+			logger.Warn("Failed to enqueue scheduled publish", zap.Uint("post_id", p.ID), zap.Error(err))
+		}
+	}
+}
@@ -1,30 +1,45 @@
 package post
 
 import (
+	"fmt"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/poll"
 	"starter-gofiber/internal/domain/post"
-	"starter-gofiber/internal/infrastructure/storage"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/worker"
 	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/pkg/pagination"
 	iValidator "starter-gofiber/pkg/validator"
-	"starter-gofiber/variables"
 
 	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
 )
 
 type PostService struct {
-	repo post.Repository
+	repo     post.Repository
+	userRepo user.Repository
+	reports  moderation.Repository
 }
 
-func NewPostService(repo post.Repository) post.Service {
+func NewPostService(repo post.Repository, userRepo user.Repository, reports moderation.Repository) post.Service {
 	return &PostService{
-		repo: repo,
+		repo:     repo,
+		userRepo: userRepo,
+		reports:  reports,
 	}
 }
 
 func (s *PostService) Create(req *post.PostRequest, userID uint) (*post.PostResponse, error) {
 	var errors []*iValidator.IError
 
+	req.Title = strings.TrimSpace(req.Title)
 	err := iValidator.Validator.Struct(req)
 	if err != nil {
 		for _, err := range err.(validator.ValidationErrors) {
@@ -41,23 +56,89 @@ func (s *PostService) Create(req *post.PostRequest, userID uint) (*post.PostResp
 		}
 	}
 
+	// Screened before anything is stored, a blocking rule rejects the post
+	text := strings.TrimSpace(strings.Join([]string{req.Tweet, req.Title, req.Body}, "\n"))
+	rule, matched := moderation.Screen(text)
+	if matched && rule.Action == moderation.FilterBlock {
+		s.logFilter("users", userID, fmt.Sprintf("Blocked a post by user #%d matching %q", userID, rule.Pattern), text)
+		return nil, &apierror.UnprocessableEntityError{
+			Message: "Post contains blocked content",
+			Order:   "S-Filter-1",
+		}
+	}
+
+	status, publishAt, err := resolveSchedule(req.Status, req.PublishAt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.QuotedPostID != nil {
+		if err := s.checkQuoted(*req.QuotedPostID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Poll != nil {
+		if err := checkPoll(req.Poll, publishAt); err != nil {
+			return nil, err
+		}
+	}
+
+	attachments, err := s.claimableAttachments(req.AttachmentIDs, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
 	postEntity := req.ToEntity()
 	postEntity.UserID = userID
+	postEntity.Status = status
+	postEntity.PublishAt = publishAt
+	postEntity.Visibility = req.Visibility
+	if postEntity.Visibility == "" {
+		postEntity.Visibility = s.defaultVisibility(userID)
+	}
+	postEntity.ModerationState = post.ModerationVisible
+	if matched {
+		postEntity.ModerationState = post.ModerationFlagged
+	}
+	if err := s.renderLongForm(&postEntity); err != nil {
+		return nil, err
+	}
 
-	err = s.repo.Create(&postEntity)
+	err = s.saveWithSlug(&postEntity, postEntity.IsLongForm(), func() error {
+		return s.repo.Create(&postEntity)
+	})
 	if err != nil {
 		return nil, &apierror.BadRequestError{
 			Message: err.Error(),
 			Order:   "S2",
 		}
 	}
+	if matched {
+		s.flag(&postEntity, rule)
+	}
+
+	if _, err := s.syncEntities(&postEntity); err != nil {
+		return nil, err
+	}
+	if err := s.attach(&postEntity, attachments); err != nil {
+		return nil, err
+	}
+
+	// Drafts and scheduled posts reach timelines and mentioned users once they go live,
+	// flagged posts once a moderator approves them
+	if postEntity.IsPublic() {
+		worker.AnnouncePost(postEntity, postEntity.Mentions)
+	}
+	s.reschedule(&postEntity, "", nil)
+	s.unfurl(&postEntity, "")
 
 	resp := post.PostResponse{}.FromEntity(postEntity)
 	return &resp, nil
 }
 
-func (s *PostService) FindByID(id uint) (*post.PostResponse, error) {
-	postEntity, err := s.repo.FindByID(id)
+func (s *PostService) FindByID(id uint, viewerID uint) (*post.PostResponse, error) {
+	postEntity, err := s.repo.FindVisibleByID(id, viewerID)
 	if err != nil {
 		return nil, &apierror.NotFoundError{
 			Message: "Post not found",
@@ -69,7 +150,34 @@ func (s *PostService) FindByID(id uint) (*post.PostResponse, error) {
 	return &resp, nil
 }
 
-func (s *PostService) FindAll(page, limit int) ([]post.PostResponse, *post.PaginationMeta, error) {
+// FindBySlug resolves a long-form post by slug, author is a username narrowing the lookup when
+// several authors use the slug. The oldest post the viewer may open wins otherwise.
+func (s *PostService) FindBySlug(slug, author string, viewerID uint) (*post.PostResponse, error) {
+	var authorID uint
+	if author != "" {
+		users, err := s.userRepo.FindByUsernames([]string{author})
+		if err != nil || len(users) == 0 {
+			return nil, &apierror.NotFoundError{
+				Message: "Post not found",
+				Order:   "S1",
+			}
+		}
+		authorID = users[0].ID
+	}
+
+	postEntity, err := s.repo.FindVisibleBySlug(slug, authorID, viewerID)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S2",
+		}
+	}
+
+	resp := post.PostResponse{}.FromEntity(*postEntity)
+	return &resp, nil
+}
+
+func (s *PostService) FindAll(viewerID uint, page, limit int) ([]post.PostResponse, *post.PaginationMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -81,7 +189,7 @@ func (s *PostService) FindAll(page, limit int) ([]post.PostResponse, *post.Pagin
 	}
 
 	offset := (page - 1) * limit
-	posts, total, err := s.repo.FindAll(limit, offset)
+	posts, total, err := s.repo.FindAll(viewerID, limit, offset)
 	if err != nil {
 		return nil, nil, &apierror.InternalServerError{
 			Message: err.Error(),
@@ -107,6 +215,40 @@ func (s *PostService) FindAll(page, limit int) ([]post.PostResponse, *post.Pagin
 	return result, meta, nil
 }
 
+func (s *PostService) List(filter post.ListFilter, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedTo.Before(*filter.CreatedFrom) {
+		return nil, &apierror.BadRequestError{
+			Message: "created_to must not be before created_from",
+			Order:   "S-List-1",
+		}
+	}
+	page.AllowedFields = post.SortFields
+
+	posts, err := s.repo.FindPage(filter, viewerID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S-List-2",
+		}
+	}
+
+	limit := pageLimit(page)
+	resp := &pagination.CursorResponse{HasMore: len(posts) > limit}
+	if resp.HasMore {
+		posts = posts[:limit]
+		last := posts[len(posts)-1]
+		resp.NextCursor = pagination.EncodeCursor(last.ID, cursorValue(last, page.SortBy))
+	}
+
+	result := make([]post.PostResponse, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, post.PostResponse{}.FromEntity(p))
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
 func (s *PostService) Update(id uint, req *post.PostUpdateRequest, userID uint) (*post.PostResponse, error) {
 	var errors []*iValidator.IError
 
@@ -142,29 +284,134 @@ func (s *PostService) Update(id uint, req *post.PostUpdateRequest, userID uint)
 		}
 	}
 
+	title, body := postEntity.Title, postEntity.Body
+	if req.Title != nil {
+		title = nil
+		if t := strings.TrimSpace(*req.Title); t != "" {
+			title = &t
+		}
+	}
+	if req.Body != nil {
+		body = *req.Body
+	}
+	if err := checkContent(req.Tweet, title, body); err != nil {
+		return nil, err
+	}
+
+	// Edited text is screened like a new post, unchanged text keeps the moderator's decision
+	var flagRule *moderation.Rule
+	if req.Tweet != postEntity.Tweet || stringValue(title) != stringValue(postEntity.Title) || body != postEntity.Body {
+		text := strings.TrimSpace(strings.Join([]string{req.Tweet, stringValue(title), body}, "\n"))
+		rule, matched := moderation.Screen(text)
+		if matched && rule.Action == moderation.FilterBlock {
+			s.logFilter("posts", postEntity.ID, fmt.Sprintf("Blocked an edit of post #%d matching %q", postEntity.ID, rule.Pattern), text)
+			return nil, &apierror.UnprocessableEntityError{
+				Message: "Post contains blocked content",
+				Order:   "S-Filter-1",
+			}
+		}
+		// Posts already held back are not queued again
+		if matched && postEntity.ModerationState != post.ModerationFlagged && postEntity.ModerationState != post.ModerationHidden {
+			flagRule = &rule
+		}
+	}
+
+	oldStatus, oldPublishAt := postEntity.Status, postEntity.PublishAt
+	status, publishAt, err := resolveSchedule(req.Status, req.PublishAt, postEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attachments are only touched when listed or uploaded with the request
+	var attachments []post.Attachment
+	syncAttachments := req.AttachmentIDs != nil || len(req.UploadedIDs) > 0
+	if syncAttachments {
+		ids := req.AttachmentIDs
+		if ids == nil {
+			for _, a := range postEntity.Attachments {
+				ids = append(ids, a.ID)
+			}
+		}
+		ids = append(append([]uint{}, ids...), req.UploadedIDs...)
+		if attachments, err = s.claimableAttachments(ids, userID, postEntity.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the content being replaced, including the old photo file
+	edited := req.Tweet != postEntity.Tweet || req.Photo != nil ||
+		stringValue(title) != stringValue(postEntity.Title) || body != postEntity.Body ||
+		(syncAttachments && !slices.Equal(attachmentIDs(attachments), attachmentIDs(postEntity.Attachments)))
+	if edited {
+		if err := s.recordRevision(postEntity, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields
+	oldLink := postEntity.LinkURL()
 	postEntity.Tweet = req.Tweet
+	postEntity.Title = title
+	postEntity.Body = body
+	hadSlug := postEntity.Slug != nil
+	if err := s.renderLongForm(postEntity); err != nil {
+		return nil, err
+	}
+	postEntity.Status = status
+	postEntity.PublishAt = publishAt
+	if req.Visibility != "" {
+		postEntity.Visibility = req.Visibility
+	}
 	if req.Photo != nil {
-		// Delete old photo if exists
-		if postEntity.Photo != nil {
-			_ = storage.DeleteFile(postEntity.Photo, variables.POST_PATH)
-		}
 		postEntity.Photo = req.Photo
 	}
 
-	err = s.repo.Update(postEntity)
+	err = s.saveWithSlug(postEntity, !hadSlug, func() error {
+		return s.repo.Update(postEntity)
+	})
 	if err != nil {
 		return nil, &apierror.InternalServerError{
 			Message: err.Error(),
 			Order:   "S4",
 		}
 	}
+	if flagRule != nil {
+		if err := s.repo.SetModerationState(postEntity.ID, post.ModerationFlagged); err != nil {
+			return nil, &apierror.InternalServerError{
+				Message: err.Error(),
+				Order:   "S5",
+			}
+		}
+		postEntity.ModerationState = post.ModerationFlagged
+		s.flag(postEntity, *flagRule)
+	}
+
+	added, err := s.syncEntities(postEntity)
+	if err != nil {
+		return nil, err
+	}
+	if syncAttachments {
+		if err := s.attach(postEntity, attachments); err != nil {
+			return nil, err
+		}
+	}
+
+	s.reschedule(postEntity, oldStatus, oldPublishAt)
+	s.unfurl(postEntity, oldLink)
+	s.loadQuoted(postEntity, userID)
+	switch {
+	case oldStatus != post.StatusPublished && postEntity.IsPublic():
+		worker.AnnouncePost(*postEntity, postEntity.Mentions)
+	case postEntity.IsPublic():
+		// Edits only notify users mentioned for the first time
+		worker.NotifyMentions(*postEntity, added)
+	}
 
 	resp := post.PostResponse{}.FromEntity(*postEntity)
 	return &resp, nil
 }
 
-func (s *PostService) Delete(id uint, userID uint) error {
+func (s *PostService) Delete(id uint, userID uint, moderator bool) error {
 	postEntity, err := s.repo.FindByID(id)
 	if err != nil {
 		return &apierror.NotFoundError{
@@ -173,23 +420,25 @@ func (s *PostService) Delete(id uint, userID uint) error {
 		}
 	}
 
-	// Check ownership
-	if postEntity.UserID != userID {
+	// Check ownership, moderators may delete any post
+	if postEntity.UserID != userID && !moderator {
 		return &apierror.ForbiddenError{
 			Message: "You don't have permission to delete this post",
 			Order:   "S2",
 		}
 	}
 
-	// Delete photo if exists
-	if postEntity.Photo != nil {
-		_ = storage.DeleteFile(postEntity.Photo, variables.POST_PATH)
-	}
+	// Files, attachments and revisions stay with the post in the trash until it is purged
+
+	// Drop a pending publish, the task would skip the deleted post anyway
+	oldStatus := postEntity.Status
+	postEntity.Status = post.StatusDraft
+	s.reschedule(postEntity, oldStatus, postEntity.PublishAt)
 
 	return s.repo.Delete(id)
 }
 
-func (s *PostService) FindByUserID(userID uint, page, limit int) ([]post.PostResponse, *post.PaginationMeta, error) {
+func (s *PostService) FindByUserID(userID, viewerID uint, page, limit int) ([]post.PostResponse, *post.PaginationMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -201,7 +450,7 @@ func (s *PostService) FindByUserID(userID uint, page, limit int) ([]post.PostRes
 	}
 
 	offset := (page - 1) * limit
-	posts, total, err := s.repo.FindByUserID(userID, limit, offset)
+	posts, total, err := s.repo.FindByUserID(userID, viewerID, limit, offset)
 	if err != nil {
 		return nil, nil, &apierror.InternalServerError{
 			Message: err.Error(),
@@ -226,3 +475,283 @@ func (s *PostService) FindByUserID(userID uint, page, limit int) ([]post.PostRes
 
 	return result, meta, nil
 }
+
+func (s *PostService) FindUnpublished(userID uint, page, limit int) ([]post.PostResponse, *post.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	posts, total, err := s.repo.FindUnpublishedByUserID(userID, limit, offset)
+	if err != nil {
+		return nil, nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	result := make([]post.PostResponse, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, post.PostResponse{}.FromEntity(p))
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	meta := &post.PaginationMeta{
+		Total:       total,
+		Page:        page,
+		Limit:       limit,
+		TotalPages:  totalPages,
+		HasNextPage: page < totalPages,
+		HasPrevPage: page > 1,
+	}
+
+	return result, meta, nil
+}
+
+func (s *PostService) FindByTag(tag string, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	posts, err := s.repo.FindByTag(strings.ToLower(strings.TrimPrefix(tag, "#")), viewerID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	limit := pageLimit(page)
+	resp := &pagination.CursorResponse{HasMore: len(posts) > limit}
+	if resp.HasMore {
+		posts = posts[:limit]
+		resp.NextCursor = pagination.EncodeCursor(posts[len(posts)-1].ID, "")
+	}
+
+	result := make([]post.PostResponse, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, post.PostResponse{}.FromEntity(p))
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+// checkContent requires a tweet or a long-form body, and a title exactly when there is a body
+func checkContent(tweet string, title *string, body string) error {
+	switch {
+	case tweet == "" && body == "":
+		return &apierror.UnprocessableEntityError{
+			Message: "A post needs a tweet or a body",
+			Order:   "S-Content-1",
+		}
+	case (title != nil) != (body != ""):
+		return &apierror.UnprocessableEntityError{
+			Message: "Long-form posts need both a title and a body",
+			Order:   "S-Content-2",
+		}
+	}
+	return nil
+}
+
+// renderLongForm renders the Markdown body and gives a post that became long-form its slug.
+// Posts without a title lose their slug and body.
+func (s *PostService) renderLongForm(p *post.Post) error {
+	if !p.IsLongForm() {
+		p.Title, p.Slug, p.Body = nil, nil, ""
+	}
+	if err := p.RenderBody(); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Content-3",
+		}
+	}
+
+	if p.IsLongForm() && p.Slug == nil {
+		slug, err := s.uniqueSlug(p.UserID, *p.Title)
+		if err != nil {
+			return &apierror.InternalServerError{
+				Message: err.Error(),
+				Order:   "S-Content-4",
+			}
+		}
+		p.Slug = &slug
+	}
+	return nil
+}
+
+// uniqueSlug derives a slug from the title that the author has not used yet, numbering repeats
+func (s *PostService) uniqueSlug(userID uint, title string) (string, error) {
+	return post.UniqueSlug(title, func(slug string) (bool, error) {
+		return s.repo.SlugTaken(userID, slug)
+	})
+}
+
+// slugRetries bounds how often a save is retried after losing its slug to a concurrent post
+const slugRetries = 3
+
+// saveWithSlug runs save, which stores p. When p was just given its slug and a concurrent post of the
+// author took the slug first, the unique index fails the save and it is retried with the next free slug.
+func (s *PostService) saveWithSlug(p *post.Post, freshSlug bool, save func() error) error {
+	err := save()
+	for i := 0; err != nil && freshSlug && p.Slug != nil && i < slugRetries; i++ {
+		taken, checkErr := s.repo.SlugTaken(p.UserID, *p.Slug)
+		if checkErr != nil || !taken {
+			return err
+		}
+		slug, slugErr := s.uniqueSlug(p.UserID, *p.Title)
+		if slugErr != nil {
+			return err
+		}
+		p.Slug = &slug
+		err = save()
+	}
+	return err
+}
+
+// checkQuoted requires the quoted post to be live and shared with the author, deleted posts are not found
+func (s *PostService) checkQuoted(id, userID uint) error {
+	quoted, err := s.repo.FindVisibleByID(id, userID)
+	if err != nil || !quoted.IsPublic() {
+		return &apierror.NotFoundError{
+			Message: "Quoted post not found",
+			Order:   "S-Quote-1",
+		}
+	}
+	return nil
+}
+
+// checkPoll requires the poll to run between poll.MinDuration and poll.MaxDuration after the post
+// goes live, drafts count from now, and its options to differ
+func checkPoll(req *poll.PollRequest, publishAt *time.Time) error {
+	start := time.Now()
+	if publishAt != nil && publishAt.After(start) {
+		start = *publishAt
+	}
+	duration := req.ExpiresAt.Sub(start)
+	if duration < poll.MinDuration || duration > poll.MaxDuration {
+		return &apierror.UnprocessableEntityError{
+			Message: fmt.Sprintf("Poll must expire between %s and %s after the post goes live", poll.MinDuration, poll.MaxDuration),
+			Order:   "S-Poll-1",
+		}
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	for i, option := range req.Options {
+		option = strings.TrimSpace(option)
+		key := strings.ToLower(option)
+		if option == "" || seen[key] {
+			return &apierror.UnprocessableEntityError{
+				Message: "Poll options must be distinct and not blank",
+				Order:   "S-Poll-2",
+			}
+		}
+		seen[key] = true
+		req.Options[i] = option
+	}
+	return nil
+}
+
+// unfurl queues the link preview of a post whose first URL changed
+func (s *PostService) unfurl(p *post.Post, oldLink string) {
+	if p.LinkURL() == oldLink {
+		return
+	}
+	if err := worker.EnqueueLinkUnfurl(p.ID); err != nil {
+		logger.Warn("Failed to enqueue link unfurl", zap.Uint("post_id", p.ID), zap.Error(err))
+	}
+}
+
+// loadQuoted embeds the quoted post of a post loaded by FindByID, while the viewer may open it
+func (s *PostService) loadQuoted(p *post.Post, viewerID uint) {
+	if !p.IsQuote() {
+		return
+	}
+	quoted, err := s.repo.FindVisibleByID(*p.QuotedPostID, viewerID)
+	if err != nil {
+		p.QuotedPost = nil
+		return
+	}
+	// One level deep, like the listings
+	quoted.QuotedPost = nil
+	p.QuotedPost = quoted
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// syncEntities stores the post's hashtags and resolved mentions and returns the users mentioned for the first time
+func (s *PostService) syncEntities(p *post.Post) ([]post.PostMention, error) {
+	entities := post.ParseEntities(p.Tweet)
+
+	if err := s.repo.SyncTags(p.ID, post.Hashtags(entities)); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Entities-1",
+		}
+	}
+
+	users, err := s.userRepo.FindByUsernames(post.Mentions(entities))
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Entities-2",
+		}
+	}
+	mentions := make([]post.PostMention, 0, len(users))
+	for _, u := range users {
+		mentions = append(mentions, post.PostMention{UserID: u.ID, Username: *u.Username})
+	}
+
+	added, err := s.repo.SyncMentions(p.ID, mentions)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Entities-3",
+		}
+	}
+	p.Mentions = mentions
+	return added, nil
+}
+
+// defaultVisibility is the visibility of new posts set by the author's profile_visibility preference
+func (s *PostService) defaultVisibility(userID uint) string {
+	prefs, err := s.userRepo.FindPreferencesByUserID(userID)
+	if err != nil {
+		return post.DefaultVisibility("")
+	}
+	data, err := prefs.GetData()
+	if err != nil {
+		return post.DefaultVisibility("")
+	}
+	return post.DefaultVisibility(data.ProfileVisibility)
+}
+
+// cursorValue is the post's value of the sort field for the next cursor, empty when sorting by id.
+// Times use the layout the database drivers store and parse, so the cursor compares like the column.
+func cursorValue(p post.Post, sortBy string) string {
+	switch sortBy {
+	case "created_at":
+		return p.CreatedAt.Format("2006-01-02 15:04:05.999999999-07:00")
+	case "comment_count":
+		return strconv.Itoa(p.CommentCount)
+	}
+	return ""
+}
+
+// pageLimit mirrors the limit bounds applied by ApplyCursorPagination
+func pageLimit(page pagination.CursorPagination) int {
+	if page.Limit <= 0 {
+		return 10
+	}
+	if page.Limit > 100 {
+		return 100
+	}
+	return page.Limit
+}
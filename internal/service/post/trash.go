@@ -0,0 +1,109 @@
+package post
+
+import (
+	"math"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+)
+
+func (s *PostService) FindTrashed(userID uint, page, limit int) ([]post.TrashedPostResponse, *post.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	posts, total, err := s.repo.FindTrashed(userID, limit, offset)
+	if err != nil {
+		return nil, nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	result := make([]post.TrashedPostResponse, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, post.TrashedPostResponse{}.FromEntity(p))
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	meta := &post.PaginationMeta{
+		Total:       total,
+		Page:        page,
+		Limit:       limit,
+		TotalPages:  totalPages,
+		HasNextPage: page < totalPages,
+		HasPrevPage: page > 1,
+	}
+
+	return result, meta, nil
+}
+
+func (s *PostService) Restore(id uint, userID uint, moderator bool) (*post.PostResponse, error) {
+	postEntity, err := s.findTrashed(id, userID, moderator)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(id); err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	// Scheduled posts lost their publish task when deleted, overdue ones are published by the sweep
+	s.reschedule(postEntity, post.StatusDraft, nil)
+
+	restored, err := s.repo.FindVisibleByID(id, postEntity.UserID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S4",
+		}
+	}
+	resp := post.PostResponse{}.FromEntity(*restored)
+	return &resp, nil
+}
+
+func (s *PostService) PermanentDelete(id uint, userID uint, moderator bool) error {
+	postEntity, err := s.findTrashed(id, userID, moderator)
+	if err != nil {
+		return err
+	}
+
+	if err := worker.PurgePost(s.repo, postEntity); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+	return nil
+}
+
+// findTrashed loads a post in the trash the user may restore or purge
+func (s *PostService) findTrashed(id uint, userID uint, moderator bool) (*post.Post, error) {
+	postEntity, err := s.repo.FindTrashedByID(id)
+	if err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found in the trash",
+			Order:   "S1",
+		}
+	}
+
+	// Check ownership, moderators may manage any post
+	if postEntity.UserID != userID && !moderator {
+		return nil, &apierror.ForbiddenError{
+			Message: "You don't have permission to manage this post",
+			Order:   "S2",
+		}
+	}
+	return postEntity, nil
+}
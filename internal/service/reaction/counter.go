@@ -0,0 +1,140 @@
+package reaction
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Counters live in one Redis hash per post so popular posts never lock a database row.
+// Every change marks the post dirty, the reconciliation job recomputes dirty posts
+// from the reactions table and persists them to reaction_counts.
+// Without Redis the counts are read straight from the reactions table.
+
+// adjustCounters applies deltas to the post's hot counters
+func (s *ReactionService) adjustCounters(postID uint, deltas map[reaction.Type]int) {
+	client := cache.RedisClient
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	key := reaction.CounterKey(postID)
+	if client.Exists(ctx, key).Val() == 0 {
+		// Start from the last reconciled snapshot instead of zero
+		counts, err := s.repo.FindCounts([]uint{postID})
+		if err == nil {
+			s.storeCounters(ctx, client, counts)
+		}
+	}
+
+	pipe := client.TxPipeline()
+	for t, delta := range deltas {
+		pipe.HIncrBy(ctx, key, string(t), int64(delta))
+	}
+	pipe.SAdd(ctx, reaction.DirtyKey, postID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		// The reactions table stays the source of truth, reconciliation repairs the counters
+		logger.Warn("Failed to update reaction counters", zap.Uint("post_id", postID), zap.Error(err))
+	}
+}
+
+// loadCounts returns per-type counts for each post
+func (s *ReactionService) loadCounts(postIDs []uint) (map[uint]map[reaction.Type]int, error) {
+	result := make(map[uint]map[reaction.Type]int, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	client := cache.RedisClient
+	if client == nil {
+		counts, err := s.repo.CountByPosts(postIDs)
+		if err != nil {
+			return nil, err
+		}
+		addCounts(result, counts)
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pipe := client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(postIDs))
+	for i, id := range postIDs {
+		cmds[i] = pipe.HGetAll(ctx, reaction.CounterKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		logger.Warn("Failed to read reaction counters, falling back to database", zap.Error(err))
+		counts, err := s.repo.CountByPosts(postIDs)
+		if err != nil {
+			return nil, err
+		}
+		addCounts(result, counts)
+		return result, nil
+	}
+
+	var missing []uint
+	for i, id := range postIDs {
+		values := cmds[i].Val()
+		if len(values) == 0 {
+			missing = append(missing, id)
+			continue
+		}
+		for t, v := range values {
+			n, _ := strconv.Atoi(v)
+			if n > 0 {
+				if result[id] == nil {
+					result[id] = make(map[reaction.Type]int)
+				}
+				result[id][reaction.Type(t)] = n
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		counts, err := s.repo.FindCounts(missing)
+		if err != nil {
+			return nil, err
+		}
+		addCounts(result, counts)
+		s.storeCounters(ctx, client, counts)
+	}
+
+	return result, nil
+}
+
+// storeCounters writes reconciled counts into the Redis hashes
+func (s *ReactionService) storeCounters(ctx context.Context, client *redis.Client, counts []reaction.Count) {
+	if len(counts) == 0 {
+		return
+	}
+	pipe := client.Pipeline()
+	for _, c := range counts {
+		pipe.HSet(ctx, reaction.CounterKey(c.PostID), string(c.Type), c.Count)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("Failed to warm reaction counters", zap.Error(err))
+	}
+}
+
+func addCounts(result map[uint]map[reaction.Type]int, counts []reaction.Count) {
+	for _, c := range counts {
+		if c.Count <= 0 {
+			continue
+		}
+		if result[c.PostID] == nil {
+			result[c.PostID] = make(map[reaction.Type]int)
+		}
+		result[c.PostID][c.Type] = c.Count
+	}
+}
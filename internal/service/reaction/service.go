@@ -0,0 +1,198 @@
+package reaction
+
+import (
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ReactionService struct {
+	repo     reaction.Repository
+	postRepo post.Repository
+}
+
+func NewReactionService(repo reaction.Repository, postRepo post.Repository) reaction.Service {
+	return &ReactionService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+func (s *ReactionService) React(postID uint, req *reaction.ReactRequest, userID uint) (*reaction.Summary, error) {
+	var errors []*iValidator.IError
+
+	err := iValidator.Validator.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var el iValidator.IError
+			el.Field = err.Field()
+			el.Tag = err.Tag()
+			el.Value = err.Param()
+			errors = append(errors, &el)
+		}
+		return nil, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Data:    errors,
+			Order:   "S1",
+		}
+	}
+
+	if _, err := s.postRepo.FindVisibleByID(postID, userID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S2",
+		}
+	}
+
+	newType := reaction.Type(req.Type)
+	previous, err := s.repo.Set(&reaction.Reaction{PostID: postID, UserID: userID, Type: newType})
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	// Repeating the same reaction is a no-op
+	switch {
+	case previous == nil:
+		s.adjustCounters(postID, map[reaction.Type]int{newType: 1})
+	case *previous != newType:
+		s.adjustCounters(postID, map[reaction.Type]int{*previous: -1, newType: 1})
+	}
+
+	return s.summary(postID, userID, "S4")
+}
+
+func (s *ReactionService) Unreact(postID uint, userID uint) (*reaction.Summary, error) {
+	if _, err := s.postRepo.FindVisibleByID(postID, userID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	previous, err := s.repo.Remove(postID, userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+
+	// Removing a reaction that does not exist is a no-op
+	if previous != nil {
+		s.adjustCounters(postID, map[reaction.Type]int{*previous: -1})
+	}
+
+	return s.summary(postID, userID, "S3")
+}
+
+func (s *ReactionService) summary(postID, userID uint, order string) (*reaction.Summary, error) {
+	summaries, err := s.Summaries([]uint{postID}, userID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   order,
+		}
+	}
+	result := summaries[postID]
+	return &result, nil
+}
+
+func (s *ReactionService) Summaries(postIDs []uint, viewerID uint) (map[uint]reaction.Summary, error) {
+	counts, err := s.loadCounts(postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	mine := make(map[uint]reaction.Type)
+	if viewerID != 0 && len(postIDs) > 0 {
+		reactions, err := s.repo.FindByUser(postIDs, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reactions {
+			mine[r.PostID] = r.Type
+		}
+	}
+
+	result := make(map[uint]reaction.Summary, len(postIDs))
+	for _, id := range postIDs {
+		summary := reaction.Summary{Counts: make(map[reaction.Type]int)}
+		for t, n := range counts[id] {
+			summary.Counts[t] = n
+			summary.Total += n
+		}
+		if t, ok := mine[id]; ok {
+			summary.MyReaction = &t
+		}
+		result[id] = summary
+	}
+	return result, nil
+}
+
+func (s *ReactionService) Reactors(postID, viewerID uint, reactionType string, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	if reactionType != "" && !isValidType(reaction.Type(reactionType)) {
+		return nil, &apierror.BadRequestError{
+			Message: "Invalid reaction type",
+			Order:   "S1",
+		}
+	}
+
+	if _, err := s.postRepo.FindVisibleByID(postID, viewerID); err != nil {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S2",
+		}
+	}
+
+	reactions, err := s.repo.FindReactors(postID, reaction.Type(reactionType), page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	hasMore := len(reactions) > limit
+	if hasMore {
+		reactions = reactions[:limit]
+	}
+
+	result := make([]reaction.ReactorResponse, 0, len(reactions))
+	for _, r := range reactions {
+		result = append(result, reaction.ReactorResponse{}.FromEntity(r))
+	}
+
+	resp := &pagination.CursorResponse{
+		Data:    result,
+		HasMore: hasMore,
+		Count:   len(result),
+	}
+	if hasMore {
+		resp.NextCursor = pagination.EncodeCursor(reactions[len(reactions)-1].ID, "")
+	}
+	return resp, nil
+}
+
+func isValidType(t reaction.Type) bool {
+	for _, valid := range reaction.Types {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
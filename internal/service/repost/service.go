@@ -0,0 +1,128 @@
+package repost
+
+import (
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/pkg/pagination"
+
+	"go.uber.org/zap"
+)
+
+type RepostService struct {
+	repo     repost.Repository
+	postRepo post.Repository
+}
+
+func NewRepostService(repo repost.Repository, postRepo post.Repository) repost.Service {
+	return &RepostService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+func (s *RepostService) Repost(postID uint, userID uint) (*repost.RepostResponse, error) {
+	// Only live posts the user may see are reposted, deleted ones are not found
+	p, err := s.postRepo.FindVisibleByID(postID, userID)
+	if err != nil || !p.IsPublic() {
+		return nil, &apierror.NotFoundError{
+			Message: "Post not found",
+			Order:   "S1",
+		}
+	}
+
+	rp := repost.Repost{UserID: userID, PostID: postID}
+	created, err := s.repo.Save(&rp)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+	if created {
+		p.RepostCount++
+		if p.UserID != userID {
+			if err := worker.SendRepostNotificationJob(p.UserID, p.ID, userID); err != nil {
+				logger.Warn("Failed to enqueue repost notification", zap.Uint("post_id", p.ID), zap.Error(err))
+			}
+		}
+	}
+
+	resp := repost.RepostResponse{}.FromEntity(rp)
+	item := post.PostResponse{}.FromEntity(*p)
+	resp.Post = &item
+	return &resp, nil
+}
+
+func (s *RepostService) Unrepost(postID uint, userID uint) error {
+	if _, err := s.repo.Remove(postID, userID); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+	return nil
+}
+
+func (s *RepostService) ListByUser(userID, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	// Pages follow the reposts, newest first, not the posts
+	page.SortBy, page.SortOrder = "", "desc"
+	reposts, err := s.repo.FindByUser(userID, viewerID, page)
+	if err != nil {
+		return nil, &apierror.BadRequestError{
+			Message: err.Error(),
+			Order:   "S1",
+		}
+	}
+
+	limit := pageLimit(page)
+	resp := &pagination.CursorResponse{HasMore: len(reposts) > limit}
+	if resp.HasMore {
+		reposts = reposts[:limit]
+		resp.NextCursor = pagination.EncodeCursor(reposts[len(reposts)-1].ID, "")
+	}
+
+	postIDs := make([]uint, len(reposts))
+	for i, rp := range reposts {
+		postIDs[i] = rp.PostID
+	}
+	posts, err := s.postRepo.FindByIDs(postIDs, viewerID)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+	byID := make(map[uint]post.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+
+	result := make([]repost.RepostResponse, 0, len(reposts))
+	for _, rp := range reposts {
+		p, ok := byID[rp.PostID]
+		if !ok {
+			continue
+		}
+		item := repost.RepostResponse{}.FromEntity(rp)
+		embedded := post.PostResponse{}.FromEntity(p)
+		item.Post = &embedded
+		result = append(result, item)
+	}
+	resp.Data = result
+	resp.Count = len(result)
+	return resp, nil
+}
+
+// pageLimit mirrors the limit bounds applied by ApplyCursorPagination
+func pageLimit(page pagination.CursorPagination) int {
+	if page.Limit <= 0 {
+		return 10
+	}
+	if page.Limit > 100 {
+		return 100
+	}
+	return page.Limit
+}
@@ -0,0 +1,236 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/search"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/infrastructure/fulltext"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+	iValidator "starter-gofiber/pkg/validator"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+type SearchService struct {
+	postRepo post.Repository
+	userRepo user.Repository
+	// db rebuilds the index in-process when no queue is configured
+	db *gorm.DB
+}
+
+func NewSearchService(postRepo post.Repository, userRepo user.Repository, db *gorm.DB) search.Service {
+	return &SearchService{
+		postRepo: postRepo,
+		userRepo: userRepo,
+		db:       db,
+	}
+}
+
+func (s *SearchService) Search(req *search.SearchRequest) (*pagination.CursorResponse, error) {
+	if err := iValidator.Validator.Struct(req); err != nil {
+		var errors []*iValidator.IError
+		for _, err := range err.(validator.ValidationErrors) {
+			var el iValidator.IError
+			el.Field = err.Field()
+			el.Tag = err.Tag()
+			el.Value = err.Param()
+			errors = append(errors, &el)
+		}
+		return nil, &apierror.UnprocessableEntityError{
+			Message: err.Error(),
+			Data:    errors,
+			Order:   "S1",
+		}
+	}
+
+	q, err := buildQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := fulltext.Engine()
+	if engine == nil {
+		return nil, &apierror.InternalServerError{
+			Message: "Search is not configured",
+			Order:   "S2",
+		}
+	}
+
+	// Fetch one extra hit to know whether there is a next page
+	limit := q.Limit
+	q.Limit++
+	hits, err := engine.Search(q)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S3",
+		}
+	}
+
+	resp := &pagination.CursorResponse{HasMore: len(hits) > limit}
+	if resp.HasMore {
+		hits = hits[:limit]
+		// Ranked results have no stable sort key, so the cursor carries the offset
+		resp.NextCursor = pagination.EncodeCursor(0, strconv.Itoa(q.Offset+limit))
+	}
+
+	results, err := s.hydrate(hits)
+	if err != nil {
+		return nil, err
+	}
+	resp.Data = results
+	resp.Count = len(results)
+	return resp, nil
+}
+
+// buildQuery validates the filters and cursor of a search request
+func buildQuery(req *search.SearchRequest) (search.Query, error) {
+	q := search.Query{
+		Text:     req.Q,
+		AuthorID: req.AuthorID,
+		Sort:     req.Sort,
+		Limit:    req.Limit,
+	}
+	if q.Sort == "" {
+		q.Sort = search.SortRelevance
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+
+	if req.Type != "" {
+		for _, t := range strings.Split(req.Type, ",") {
+			t = strings.TrimSpace(t)
+			if t != search.TypePost && t != search.TypeUser {
+				return q, &apierror.BadRequestError{
+					Message: "type must be post, user or both",
+					Order:   "S-Query-1",
+				}
+			}
+			q.Types = append(q.Types, t)
+		}
+	}
+
+	var err error
+	if q.Since, err = parseTime(req.Since); err != nil {
+		return q, &apierror.BadRequestError{Message: "Invalid since: " + err.Error(), Order: "S-Query-2"}
+	}
+	if q.Until, err = parseTime(req.Until); err != nil {
+		return q, &apierror.BadRequestError{Message: "Invalid until: " + err.Error(), Order: "S-Query-3"}
+	}
+
+	cursor, err := pagination.DecodeCursor(req.Cursor)
+	if err != nil {
+		return q, &apierror.BadRequestError{Message: err.Error(), Order: "S-Query-4"}
+	}
+	if cursor != nil {
+		offset, err := strconv.Atoi(cursor.LastValue)
+		if err != nil || offset < 0 || offset > search.MaxOffset {
+			return q, &apierror.BadRequestError{Message: "Invalid cursor", Order: "S-Query-5"}
+		}
+		q.Offset = offset
+	}
+	return q, nil
+}
+
+// parseTime accepts RFC 3339 timestamps or plain dates within the range every engine supports
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, value); err != nil {
+			return nil, err
+		}
+	}
+	if t.Year() < 1970 || t.Year() > 2200 {
+		return nil, fmt.Errorf("year must be between 1970 and 2200")
+	}
+	return &t, nil
+}
+
+// hydrate loads the matching rows in hit order, hits whose row is gone are dropped
+func (s *SearchService) hydrate(hits []search.Hit) ([]search.Result, error) {
+	var postIDs, userIDs []uint
+	for _, h := range hits {
+		if h.Type == search.TypePost {
+			postIDs = append(postIDs, h.ID)
+		} else {
+			userIDs = append(userIDs, h.ID)
+		}
+	}
+
+	// Only public posts are indexed, loaded as an anonymous viewer to skip ones that changed since
+	posts, err := s.postRepo.FindByIDs(postIDs, 0)
+	if err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S-Hydrate-1"}
+	}
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return nil, &apierror.InternalServerError{Message: err.Error(), Order: "S-Hydrate-2"}
+	}
+
+	postByID := make(map[uint]post.Post, len(posts))
+	for _, p := range posts {
+		postByID[p.ID] = p
+	}
+	userByID := make(map[uint]user.User, len(users))
+	for _, u := range users {
+		userByID[u.ID] = u
+	}
+
+	results := make([]search.Result, 0, len(hits))
+	for _, h := range hits {
+		r := search.Result{Type: h.Type, Score: h.Score, Highlight: h.Highlight}
+		switch h.Type {
+		case search.TypePost:
+			p, ok := postByID[h.ID]
+			if !ok {
+				continue
+			}
+			resp := post.PostResponse{}.FromEntity(p)
+			r.Post = &resp
+		case search.TypeUser:
+			u, ok := userByID[h.ID]
+			if !ok {
+				continue
+			}
+			r.User = &search.UserResult{ID: u.ID, Name: u.Name, Username: u.Username, Avatar: u.Avatar}
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func (s *SearchService) Reindex() error {
+	if fulltext.Engine() == nil {
+		return &apierror.InternalServerError{
+			Message: "Search is not configured",
+			Order:   "S1",
+		}
+	}
+	if err := worker.EnqueueSearchReindex(s.db); err != nil {
+		return &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S2",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/internal/infrastructure/geoip"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// SSEEventSecurityAlert is the SSE event name used for admin alerts
+const SSEEventSecurityAlert = "security_alert"
+
+var (
+	policy = security.DefaultPolicy()
+
+	// defaultSeverity is used when an event is recorded without a severity
+	defaultSeverity = map[security.EventType]security.Severity{
+		security.EventLoginSuccess:            security.SeverityInfo,
+		security.EventLoginFailed:             security.SeverityWarning,
+		security.EventAccountLocked:           security.SeverityCritical,
+		security.EventTokenReuse:              security.SeverityCritical,
+		security.EventPasswordChanged:         security.SeverityInfo,
+		security.EventPasswordReset:           security.SeverityInfo,
+		security.EventAPIKeyNewIP:             security.SeverityWarning,
+		security.EventPermissionDenied:        security.SeverityWarning,
+		security.EventAnomalyIPFailures:       security.SeverityCritical,
+		security.EventAnomalyImpossibleTravel: security.SeverityCritical,
+	}
+)
+
+// SetPolicy overrides the lockout and anomaly detection policy
+func SetPolicy(p security.Policy) {
+	policy = p
+}
+
+type SecurityService struct {
+	repo security.Repository
+}
+
+func NewSecurityService(repo security.Repository) security.Service {
+	return &SecurityService{
+		repo: repo,
+	}
+}
+
+// Record stores a security event and runs the anomaly rules for it.
+// Critical events are pushed to admins over SSE and email.
+// Failures are logged and never returned so recording cannot break a request.
+func (s *SecurityService) Record(event *security.Event) {
+	if event.Severity == "" {
+		event.Severity = defaultSeverity[event.Type]
+		if event.Severity == "" {
+			event.Severity = security.SeverityInfo
+		}
+	}
+
+	if location, ok := geoip.Lookup(event.IPAddress); ok {
+		event.Country = location.Country
+		event.Latitude = &location.Latitude
+		event.Longitude = &location.Longitude
+	}
+
+	if err := s.repo.Create(event); err != nil {
+		logger.Error("Failed to record security event",
+			zap.Error(err),
+			zap.String("type", event.Type.String()),
+		)
+		return
+	}
+
+	switch event.Type {
+	case security.EventLoginFailed:
+		s.checkLockout(event)
+		s.checkIPFailures(event)
+	case security.EventLoginSuccess:
+		s.checkImpossibleTravel(event)
+	}
+
+	if event.Severity == security.SeverityCritical {
+		s.alert(event)
+	}
+}
+
+// IsLocked reports whether the account reached the failed login threshold within the lockout window,
+// counting only the failures since its last successful login
+func (s *SecurityService) IsLocked(email string) bool {
+	if policy.LockoutThreshold <= 0 || email == "" {
+		return false
+	}
+
+	count, err := s.repo.CountByEmailSince(security.EventLoginFailed, email, s.lockoutStart(email))
+	if err != nil {
+		logger.Error("Failed to count failed logins", zap.Error(err))
+		return false
+	}
+	return count >= int64(policy.LockoutThreshold)
+}
+
+// lockoutStart is when the failures counted towards a lockout begin: the start of the window,
+// or the last successful login within it
+func (s *SecurityService) lockoutStart(email string) time.Time {
+	since := time.Now().Add(-policy.LockoutWindow)
+	if last, err := s.repo.FindLatestByEmail(security.EventLoginSuccess, email); err == nil && last.CreatedAt.After(since) {
+		return last.CreatedAt
+	}
+	return since
+}
+
+func (s *SecurityService) checkLockout(event *security.Event) {
+	if !s.IsLocked(event.Email) {
+		return
+	}
+
+	since := s.lockoutStart(event.Email)
+	if exists, err := s.repo.ExistsSince(security.EventAccountLocked, "email", event.Email, since); err != nil || exists {
+		return
+	}
+
+	s.Record(&security.Event{
+		UserID:    event.UserID,
+		Email:     event.Email,
+		Type:      security.EventAccountLocked,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		Metadata: encodeMetadata(map[string]interface{}{
+			"failures":       policy.LockoutThreshold,
+			"window_minutes": policy.LockoutWindow.Minutes(),
+		}),
+	})
+}
+
+// checkIPFailures flags one IP failing logins across many accounts (credential stuffing)
+func (s *SecurityService) checkIPFailures(event *security.Event) {
+	if policy.IPFailureAccounts <= 0 || event.IPAddress == "" {
+		return
+	}
+
+	since := time.Now().Add(-policy.IPFailureWindow)
+	accounts, err := s.repo.CountDistinctEmailsByIPSince(security.EventLoginFailed, event.IPAddress, since)
+	if err != nil || accounts < int64(policy.IPFailureAccounts) {
+		return
+	}
+
+	if exists, err := s.repo.ExistsSince(security.EventAnomalyIPFailures, "ip_address", event.IPAddress, since); err != nil || exists {
+		return
+	}
+
+	s.Record(&security.Event{
+		Type:      security.EventAnomalyIPFailures,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		Metadata: encodeMetadata(map[string]interface{}{
+			"accounts":       accounts,
+			"window_minutes": policy.IPFailureWindow.Minutes(),
+		}),
+	})
+}
+
+// checkImpossibleTravel compares a login location with the user's previous located login
+func (s *SecurityService) checkImpossibleTravel(event *security.Event) {
+	if policy.ImpossibleTravelKmh <= 0 || event.UserID == nil || event.Latitude == nil || event.Longitude == nil {
+		return
+	}
+
+	previous, err := s.repo.FindPreviousLocated(*event.UserID, security.EventLoginSuccess, event.ID)
+	if err != nil {
+		return
+	}
+
+	distance := geoip.DistanceKm(
+		geoip.Location{Latitude: *previous.Latitude, Longitude: *previous.Longitude},
+		geoip.Location{Latitude: *event.Latitude, Longitude: *event.Longitude},
+	)
+	hours := event.CreatedAt.Sub(previous.CreatedAt).Hours()
+	if distance == 0 {
+		return
+	}
+
+	speed := math.Inf(1)
+	if hours > 0 {
+		speed = distance / hours
+	}
+	if speed <= policy.ImpossibleTravelKmh {
+		return
+	}
+
+	s.Record(&security.Event{
+		UserID:    event.UserID,
+		Email:     event.Email,
+		Type:      security.EventAnomalyImpossibleTravel,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		Metadata: encodeMetadata(map[string]interface{}{
+			"previous_ip":      previous.IPAddress,
+			"previous_country": previous.Country,
+			"country":          event.Country,
+			"distance_km":      math.Round(distance),
+			"elapsed_minutes":  math.Round(hours * 60),
+		}),
+	})
+}
+
+// RecordAPIKeyUse records an event the first time an API key is used from an IP
+func (s *SecurityService) RecordAPIKeyUse(keyID, userID uint, ipAddress, userAgent string) {
+	subject := "api_key:" + strconv.FormatUint(uint64(keyID), 10)
+	cacheKey := fmt.Sprintf("security:apikey_ip:%d:%s", keyID, ipAddress)
+	if cache.CacheExists(cacheKey) {
+		return
+	}
+
+	seen, err := s.repo.ExistsBySubjectAndIP(security.EventAPIKeyNewIP, subject, ipAddress)
+	if err != nil {
+		logger.Error("Failed to check API key IP history", zap.Error(err))
+		return
+	}
+	if !seen {
+		s.Record(&security.Event{
+			UserID:    &userID,
+			Type:      security.EventAPIKeyNewIP,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Subject:   subject,
+		})
+	}
+
+	_ = cache.CacheSet(cacheKey, true, 24*time.Hour)
+}
+
+func (s *SecurityService) alert(event *security.Event) {
+	payload := security.AlertPayload{
+		Event:     security.EventResponse{}.FromEntity(*event),
+		Message:   alertMessage(event),
+		Timestamp: time.Now().Unix(),
+	}
+
+	admins, err := s.repo.FindAdmins()
+	if err != nil {
+		logger.Error("Failed to load admins for security alert", zap.Error(err))
+	}
+
+	recipients := policy.AlertEmailRecipients
+	for _, admin := range admins {
+		utils.NotifyUser(admin.ID, SSEEventSecurityAlert, payload)
+		if len(policy.AlertEmailRecipients) == 0 {
+			recipients = append(recipients, admin.Email)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return
+	}
+	if _, err := worker.EnqueueEmailCustom(&worker.EmailCustomPayload{
+		To:       recipients,
+		Subject:  "[Security] " + payload.Message,
+		TextBody: alertBody(payload),
+	}); err != nil {
+		logger.Warn("Failed to enqueue security alert email", zap.Error(err))
+	}
+}
+
+func alertMessage(event *security.Event) string {
+	switch event.Type {
+	case security.EventAccountLocked:
+		return fmt.Sprintf("Account %s locked after repeated failed logins", event.Email)
+	case security.EventTokenReuse:
+		return fmt.Sprintf("Refresh token reuse detected for %s", event.Email)
+	case security.EventAnomalyIPFailures:
+		return fmt.Sprintf("Failed logins across many accounts from %s", event.IPAddress)
+	case security.EventAnomalyImpossibleTravel:
+		return fmt.Sprintf("Impossible travel detected for %s", event.Email)
+	}
+	return fmt.Sprintf("Security event %s", event.Type)
+}
+
+func alertBody(payload security.AlertPayload) string {
+	lines := []string{
+		payload.Message,
+		"",
+		"Type: " + payload.Event.Type,
+		"Severity: " + payload.Event.Severity,
+		"IP: " + payload.Event.IPAddress,
+		"Time: " + payload.Event.CreatedAt,
+	}
+	if payload.Event.Email != "" {
+		lines = append(lines, "Account: "+payload.Event.Email)
+	}
+	for key, value := range payload.Event.Metadata {
+		lines = append(lines, fmt.Sprintf("%s: %v", key, value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *SecurityService) Query(filter security.EventFilter, page, limit int) ([]security.EventResponse, int64, error) {
+	events, total, err := s.repo.FindAll(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Security-Q1",
+		}
+	}
+
+	return toResponses(events), total, nil
+}
+
+func (s *SecurityService) RecentActivity(userID uint, limit int) ([]security.EventResponse, error) {
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	events, err := s.repo.FindByUserID(userID, limit)
+	if err != nil {
+		return nil, &apierror.InternalServerError{
+			Message: err.Error(),
+			Order:   "S-Security-R1",
+		}
+	}
+
+	return toResponses(events), nil
+}
+
+func toResponses(events []security.Event) []security.EventResponse {
+	resp := make([]security.EventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, security.EventResponse{}.FromEntity(e))
+	}
+	return resp
+}
+
+func encodeMetadata(data map[string]interface{}) string {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
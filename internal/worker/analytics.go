@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const TaskRollupPostStats = "analytics:rollup"
+
+// rollupBatchSize is the number of posts whose Redis counters are read per round trip
+const rollupBatchSize = 500
+
+// HandleRollupPostStats recomputes the daily stats of today and yesterday, so views counted
+// before midnight are persisted after it, and deletes recorded views of older days.
+// Each run overwrites the day's stats, they lag behind by at most the schedule's interval.
+func HandleRollupPostStats(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	repo := postgres.NewAnalyticsRepository(DB)
+	today := analytics.Day(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	rolled := 0
+	for _, day := range []time.Time{yesterday, today} {
+		stats, err := collectDailyStats(ctx, repo, day)
+		if err != nil {
+			return err
+		}
+		if err := repo.SaveDaily(stats); err != nil {
+			return fmt.Errorf("failed to save daily stats: %w", err)
+		}
+		rolled += len(stats)
+	}
+
+	if _, err := repo.PurgeViews(yesterday); err != nil {
+		return fmt.Errorf("failed to purge recorded views: %w", err)
+	}
+
+	logger.Info("Post stats rolled up", zap.Int("rows", rolled))
+	return nil
+}
+
+// collectDailyStats adds up the views counted in Redis and recorded in the views table with the
+// reactions and comments of the day
+func collectDailyStats(ctx context.Context, repo analytics.Repository, day time.Time) ([]analytics.DailyStat, error) {
+	byPost := make(map[uint]*analytics.DailyStat)
+	stat := func(postID uint) *analytics.DailyStat {
+		if s, ok := byPost[postID]; ok {
+			return s
+		}
+		s := &analytics.DailyStat{PostID: postID, Day: day}
+		byPost[postID] = s
+		return s
+	}
+
+	recorded, err := repo.CountViews(day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recorded views: %w", err)
+	}
+	for _, r := range recorded {
+		s := stat(r.PostID)
+		s.Views += r.Views
+		s.UniqueViewers += r.UniqueViewers
+	}
+
+	if client := cache.RedisClient; client != nil {
+		members, err := client.SMembers(ctx, analytics.ViewedKey(day)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read viewed posts: %w", err)
+		}
+		for start := 0; start < len(members); start += rollupBatchSize {
+			end := min(start+rollupBatchSize, len(members))
+			if err := readViewCounters(ctx, client, day, members[start:end], stat); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	activity, err := repo.CountActivity(day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count post activity: %w", err)
+	}
+	for _, a := range activity {
+		s := stat(a.PostID)
+		s.Reactions = a.Reactions
+		s.Comments = a.Comments
+	}
+
+	stats := make([]analytics.DailyStat, 0, len(byPost))
+	for _, s := range byPost {
+		stats = append(stats, *s)
+	}
+	return stats, nil
+}
+
+func readViewCounters(ctx context.Context, client *redis.Client, day time.Time, members []string, stat func(uint) *analytics.DailyStat) error {
+	postIDs := make([]uint, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseUint(m, 10, 64); err == nil {
+			postIDs = append(postIDs, uint(id))
+		}
+	}
+
+	pipe := client.Pipeline()
+	views := make([]*redis.StringCmd, len(postIDs))
+	viewers := make([]*redis.IntCmd, len(postIDs))
+	for i, id := range postIDs {
+		views[i] = pipe.Get(ctx, analytics.ViewsKey(id, day))
+		viewers[i] = pipe.PFCount(ctx, analytics.ViewersKey(id, day))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to read view counters: %w", err)
+	}
+
+	for i, id := range postIDs {
+		n, _ := views[i].Int()
+		s := stat(id)
+		s.Views += n
+		s.UniqueViewers += int(viewers[i].Val())
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/storage"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const TaskPurgeOrphanAttachments = "cleanup:orphan_attachments"
+
+// orphanAttachmentAge is how long an upload may wait to be used by a post
+const orphanAttachmentAge = 24 * time.Hour
+
+// HandlePurgeOrphanAttachments deletes uploads that no post claimed in time, with their files
+func HandlePurgeOrphanAttachments(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	var attachments []post.Attachment
+	err := DB.Where("post_id IS NULL AND created_at < ?", time.Now().Add(-orphanAttachmentAge)).
+		Find(&attachments).Error
+	if err != nil {
+		return fmt.Errorf("failed to query orphan attachments: %w", err)
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	if err := DB.Delete(&attachments).Error; err != nil {
+		return fmt.Errorf("failed to delete orphan attachments: %w", err)
+	}
+	deleteAttachmentFiles(attachments)
+
+	logger.Info("Orphan attachment purge completed", zap.Int("deleted", len(attachments)))
+	return nil
+}
+
+// deleteAttachmentFiles removes stored files, failures only leave the file behind
+func deleteAttachmentFiles(attachments []post.Attachment) {
+	for _, a := range attachments {
+		for _, path := range a.Files() {
+			if err := storage.DeleteFile(&path, ""); err != nil {
+				logger.Warn("Failed to delete attachment file", zap.Uint("attachment_id", a.ID), zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const TaskPostBulk = "post:bulk"
+
+// bulkJobRetention is how long a finished batch and its result can be looked up
+const bulkJobRetention = 24 * time.Hour
+
+// progressChannel relays job progress from the process running a job to the API, which holds the SSE clients
+const progressChannel = "jobs:progress"
+
+// PostBulkPayload is a queued batch and the user who sent it, the job ID is the task ID
+type PostBulkPayload struct {
+	UserID  uint             `json:"user_id"`
+	Request post.BulkRequest `json:"request"`
+}
+
+// PostBulkRunner runs a batch of post operations, calling progress after each item
+type PostBulkRunner func(req *post.BulkRequest, userID uint, progress func(done, total int)) *post.BulkResult
+
+// ProgressNotifier streams the progress of a job to its user
+type ProgressNotifier func(userID uint, jobID string, progress int, status string)
+
+// JobProgress is a progress update of a job, as relayed between processes
+type JobProgress struct {
+	UserID   uint   `json:"user_id"`
+	JobID    string `json:"job_id"`
+	Progress int    `json:"progress"`
+	Status   string `json:"status"`
+}
+
+var (
+	postBulkRunner   PostBulkRunner
+	progressNotifier ProgressNotifier
+)
+
+// SetPostBulkRunner sets the function running queued batches, the post service cannot be
+// imported here since it queues them
+func SetPostBulkRunner(r PostBulkRunner) {
+	postBulkRunner = r
+}
+
+// SetProgressNotifier sets how the API streams job progress to users
+func SetProgressNotifier(n ProgressNotifier) {
+	progressNotifier = n
+}
+
+// EnqueuePostBulk queues a batch under jobID, its result is kept for bulkJobRetention.
+// A failing batch is not retried, its items may have been applied already.
+func EnqueuePostBulk(jobID string, userID uint, req *post.BulkRequest) error {
+	return EnqueueTask(TaskPostBulk, PostBulkPayload{UserID: userID, Request: *req},
+		asynq.Queue(QueueDefault), asynq.MaxRetry(0), asynq.TaskID(jobID), asynq.Retention(bulkJobRetention))
+}
+
+// PostBulkJob is a queued batch as stored with its task, Result is set once it completed
+type PostBulkJob struct {
+	PostBulkPayload
+	Status string
+	Result *post.BulkResult
+}
+
+// FindPostBulk looks up a queued batch, it is nil when there is no such batch or its
+// result is no longer kept
+func FindPostBulk(jobID string) (*PostBulkJob, error) {
+	if AsynqClientInstance == nil {
+		return nil, nil
+	}
+	info, err := GetTaskInfo(QueueDefault, jobID)
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Type != TaskPostBulk {
+		return nil, nil
+	}
+
+	job := &PostBulkJob{}
+	if err := json.Unmarshal(info.Payload, &job.PostBulkPayload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	switch info.State {
+	case asynq.TaskStateActive:
+		job.Status = post.BulkStatusActive
+	case asynq.TaskStateCompleted:
+		job.Status = post.BulkStatusCompleted
+		job.Result = &post.BulkResult{}
+		if err := json.Unmarshal(info.Result, job.Result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	case asynq.TaskStateArchived:
+		// Batches are not retried, a failed one is archived right away
+		job.Status = post.BulkStatusFailed
+	default:
+		job.Status = post.BulkStatusPending
+	}
+	return job, nil
+}
+
+// HandlePostBulk runs a queued batch, streaming its progress to the user, and stores the result
+// with the task for GetTaskInfo
+func HandlePostBulk(ctx context.Context, t *asynq.Task) error {
+	if postBulkRunner == nil {
+		return fmt.Errorf("post bulk runner not initialized")
+	}
+
+	var payload PostBulkPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	jobID := t.ResultWriter().TaskID()
+	NotifyProgress(ctx, payload.UserID, jobID, 0, post.BulkStatusActive)
+
+	last := 0
+	result := postBulkRunner(&payload.Request, payload.UserID, func(done, total int) {
+		// Completion is sent once the result is stored
+		progress := done * 100 / total
+		if progress > last && progress < 100 {
+			last = progress
+			NotifyProgress(ctx, payload.UserID, jobID, progress, post.BulkStatusActive)
+		}
+	})
+	result.JobID = jobID
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	if _, err := t.ResultWriter().Write(data); err != nil {
+		NotifyProgress(ctx, payload.UserID, jobID, last, post.BulkStatusFailed)
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+
+	NotifyProgress(ctx, payload.UserID, jobID, 100, post.BulkStatusCompleted)
+	logger.Info("Post bulk job completed",
+		zap.String("job_id", jobID),
+		zap.String("action", result.Action),
+		zap.Int("succeeded", result.Succeeded),
+		zap.Int("failed", result.Failed),
+	)
+	return nil
+}
+
+// NotifyProgress sends a progress update of a job to its user. With Redis it is published for
+// RelayProgress, so it reaches the user whichever process runs the job.
+func NotifyProgress(ctx context.Context, userID uint, jobID string, progress int, status string) {
+	update := JobProgress{UserID: userID, JobID: jobID, Progress: progress, Status: status}
+	if cache.RedisClient == nil {
+		if progressNotifier != nil {
+			progressNotifier(userID, jobID, progress, status)
+		}
+		return
+	}
+
+	data, err := json.Marshal(update)
+	if err == nil {
+		err = cache.RedisClient.Publish(ctx, progressChannel, data).Err()
+	}
+	if err != nil {
+		logger.Warn("Failed to publish job progress", zap.String("job_id", jobID), zap.Error(err))
+	}
+}
+
+// RelayProgress passes the progress published by every process to the notifier until ctx is done.
+// Run it in the API process.
+func RelayProgress(ctx context.Context) {
+	if cache.RedisClient == nil {
+		return
+	}
+
+	sub := cache.RedisClient.Subscribe(ctx, progressChannel)
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			var update JobProgress
+			if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+				logger.Warn("Skipping malformed job progress", zap.Error(err))
+				continue
+			}
+			if progressNotifier != nil {
+				progressNotifier(update.UserID, update.JobID, update.Progress, update.Status)
+			}
+		}
+	}
+}
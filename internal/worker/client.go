@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
 )
 
 var (
@@ -16,6 +17,8 @@ var (
 	RedisAddr     string
 	RedisPassword string
 	RedisDB       int
+	// Database connection for handlers that query data - set from main
+	DB *gorm.DB
 )
 
 // SetAsynqClient sets global Asynq client instance
@@ -30,6 +33,11 @@ func SetRedisConfig(addr, password string, db int) {
 	RedisDB = db
 }
 
+// SetDB sets database connection used by task handlers
+func SetDB(db *gorm.DB) {
+	DB = db
+}
+
 // Task type constants - similar to Laravel job names
 const (
 	TaskSendEmail            = "email:send"
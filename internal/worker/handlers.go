@@ -58,6 +58,21 @@ type ReportPayload struct {
 	Filters    map[string]interface{} `json:"filters"`
 }
 
+// NotificationMention is the notification type sent to users @mentioned in a post
+const NotificationMention = "mention"
+
+// NotificationPostPublished is the notification type sent to authors when a scheduled post goes live
+const NotificationPostPublished = "post_published"
+
+// NotificationRepost is the notification type sent to authors when another user reposts their post
+const NotificationRepost = "repost"
+
+// NotificationQuote is the notification type sent to authors when another user quotes their post
+const NotificationQuote = "quote"
+
+// NotificationPollClosed is the notification type sent to authors when their poll closes
+const NotificationPollClosed = "poll_closed"
+
 // NotificationPayload payload for notification job
 type NotificationPayload struct {
 	UserID  uint                   `json:"user_id"`
@@ -199,6 +214,13 @@ func HandleSendNotification(ctx context.Context, t *asynq.Task) error {
 
 	logger.Info(fmt.Sprintf("Sending %s notification to user %d: %s", payload.Type, payload.UserID, payload.Title))
 
+	switch payload.Type {
+	case NotificationMention, NotificationPostPublished, NotificationRepost, NotificationQuote, NotificationPollClosed:
+		// Delivered over SSE by the API process
+		NotifyUser(ctx, payload.UserID, payload.Type, payload.Data)
+		return nil
+	}
+
 	// Notification implementation based on type:
 	// switch payload.Type {
 	// case "email":
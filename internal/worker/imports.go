@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskProcessImport = "import:process"
+
+// importTimeout bounds the processing of a file, large ones take longer than the default task timeout
+const importTimeout = 2 * time.Hour
+
+type ProcessImportPayload struct {
+	ImportID uint `json:"import_id"`
+}
+
+// ImportRunner processes a pending import
+type ImportRunner func(id uint) error
+
+var importRunner ImportRunner
+
+// SetImportRunner sets the function processing queued imports, the import service cannot be
+// imported here since it queues them
+func SetImportRunner(r ImportRunner) {
+	importRunner = r
+}
+
+// EnqueueImport queues an uploaded file for processing. A failing import is not retried, its
+// chunks may have been inserted already.
+func EnqueueImport(id uint) error {
+	return EnqueueTask(TaskProcessImport, ProcessImportPayload{ImportID: id},
+		asynq.Queue(QueueLow), asynq.MaxRetry(0), asynq.Timeout(importTimeout))
+}
+
+// HandleProcessImport processes a queued import, problems with the file are recorded on the import
+func HandleProcessImport(ctx context.Context, t *asynq.Task) error {
+	if importRunner == nil {
+		return fmt.Errorf("import runner not initialized")
+	}
+
+	var payload ProcessImportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return importRunner(payload.ImportID)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"starter-gofiber/pkg/logger"
+	"starter-gofiber/pkg/utils"
 )
 
 // SendEmailJob dispatch email job to queue (Laravel style)
@@ -122,6 +123,80 @@ func SendNotificationJob(userID uint, notifType, title, message string, data map
 	)
 }
 
+// SendMentionNotificationJob notifies a user they were @mentioned in a post.
+// Without a queue the SSE event is sent directly.
+func SendMentionNotificationJob(userID, postID, authorID uint, tweet string) error {
+	data := map[string]interface{}{
+		"post_id":   postID,
+		"author_id": authorID,
+		"tweet":     tweet,
+	}
+	if AsynqClientInstance == nil {
+		utils.NotifyUser(userID, NotificationMention, data)
+		return nil
+	}
+	return SendNotificationJob(userID, NotificationMention, "You were mentioned in a post", tweet, data)
+}
+
+// SendPostPublishedNotificationJob tells an author their scheduled post went live.
+// Without a queue the SSE event is sent directly.
+func SendPostPublishedNotificationJob(userID, postID uint, tweet string) error {
+	data := map[string]interface{}{
+		"post_id": postID,
+		"tweet":   tweet,
+	}
+	if AsynqClientInstance == nil {
+		utils.NotifyUser(userID, NotificationPostPublished, data)
+		return nil
+	}
+	return SendNotificationJob(userID, NotificationPostPublished, "Your scheduled post is live", tweet, data)
+}
+
+// SendRepostNotificationJob tells an author another user reposted their post.
+// Without a queue the SSE event is sent directly.
+func SendRepostNotificationJob(userID, postID, reposterID uint) error {
+	data := map[string]interface{}{
+		"post_id":     postID,
+		"reposter_id": reposterID,
+	}
+	if AsynqClientInstance == nil {
+		utils.NotifyUser(userID, NotificationRepost, data)
+		return nil
+	}
+	return SendNotificationJob(userID, NotificationRepost, "Your post was reposted", "", data)
+}
+
+// SendQuoteNotificationJob tells an author another user quoted their post.
+// Without a queue the SSE event is sent directly.
+func SendQuoteNotificationJob(userID, postID, quoteID, authorID uint, tweet string) error {
+	data := map[string]interface{}{
+		"post_id":   postID,
+		"quote_id":  quoteID,
+		"author_id": authorID,
+		"tweet":     tweet,
+	}
+	if AsynqClientInstance == nil {
+		utils.NotifyUser(userID, NotificationQuote, data)
+		return nil
+	}
+	return SendNotificationJob(userID, NotificationQuote, "Your post was quoted", tweet, data)
+}
+
+// SendPollClosedNotificationJob tells an author their poll closed.
+// Without a queue the SSE event is sent directly.
+func SendPollClosedNotificationJob(userID, postID, pollID uint, voters int) error {
+	data := map[string]interface{}{
+		"post_id": postID,
+		"poll_id": pollID,
+		"voters":  voters,
+	}
+	if AsynqClientInstance == nil {
+		utils.NotifyUser(userID, NotificationPollClosed, data)
+		return nil
+	}
+	return SendNotificationJob(userID, NotificationPollClosed, "Your poll has closed", "", data)
+}
+
 // SendDelayedEmailJob send email with delay (Laravel style: dispatch()->delay())
 func SendDelayedEmailJob(to, subject, body string, delay time.Duration) error {
 	payload := EmailPayload{
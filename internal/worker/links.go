@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/storage"
+	"starter-gofiber/internal/infrastructure/unfurl"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/variables"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const TaskUnfurlLink = "post:unfurl"
+
+// Longest texts kept from a page, matching the link_previews columns
+const (
+	previewTitleLength       = 300
+	previewDescriptionLength = 500
+	previewSiteNameLength    = 100
+)
+
+// UnfurlLinkPayload names the post to refresh, the URL is read from its current text
+type UnfurlLinkPayload struct {
+	PostID uint `json:"post_id"`
+}
+
+var linkFetcher = unfurl.New(unfurl.DefaultConfig())
+
+// SetLinkFetcher replaces the fetcher used to unfurl links
+func SetLinkFetcher(f *unfurl.Fetcher) {
+	linkFetcher = f
+}
+
+// EnqueueLinkUnfurl refreshes the link preview of a post whose link changed. Without a queue
+// posts get no previews.
+func EnqueueLinkUnfurl(postID uint) error {
+	if AsynqClientInstance == nil {
+		return nil
+	}
+	return EnqueueTask(TaskUnfurlLink, UnfurlLinkPayload{PostID: postID},
+		asynq.Queue(QueueLow), asynq.MaxRetry(3))
+}
+
+// HandleUnfurlLink attaches the preview of the first URL in a post, or removes the preview of
+// a post that no longer links anywhere. Pages that cannot be unfurled leave the post without one.
+func HandleUnfurlLink(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	var payload UnfurlLinkPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	posts := postgres.NewPostRepository(DB)
+	p, err := posts.FindByID(payload.PostID)
+	if err != nil {
+		logger.Info("Skipping unfurl of missing post", zap.Uint("post_id", payload.PostID))
+		return nil
+	}
+
+	previews := postgres.NewLinkPreviewRepository(DB)
+	link := p.LinkURL()
+	if link == "" {
+		return previews.AttachToPost(p.ID, nil)
+	}
+
+	preview, err := UnfurlURL(ctx, previews, link)
+	if err != nil {
+		return err
+	}
+
+	// An edit while the page was fetched queued its own job
+	if current, err := posts.FindByID(p.ID); err != nil || current.LinkURL() != link {
+		return nil
+	}
+	var previewID *uint
+	if preview.IsReady() {
+		previewID = &preview.ID
+	}
+	return previews.AttachToPost(p.ID, previewID)
+}
+
+// UnfurlURL returns the cached preview of a URL, fetching the page again when it is missing or
+// stale. Every post linking to the URL shares the preview.
+func UnfurlURL(ctx context.Context, repo linkpreview.Repository, url string) (*linkpreview.LinkPreview, error) {
+	if cached, err := repo.FindByURL(url); err == nil && cached.IsFresh(time.Now()) {
+		return cached, nil
+	}
+
+	preview := fetchPreview(ctx, url)
+	replaced, err := repo.Save(preview)
+	if err != nil {
+		deleteFiles(preview.Files())
+		return nil, fmt.Errorf("failed to save link preview: %w", err)
+	}
+	deleteFiles(replaced)
+	return preview, nil
+}
+
+// fetchPreview unfurls a URL, failures are returned as a failed preview so they are cached too
+func fetchPreview(ctx context.Context, url string) *linkpreview.LinkPreview {
+	preview := &linkpreview.LinkPreview{URL: url, Status: linkpreview.StatusFailed, FetchedAt: time.Now()}
+
+	meta, err := linkFetcher.Page(ctx, url)
+	if err != nil {
+		logger.Info("Failed to unfurl link", zap.String("url", url), zap.Error(err))
+		return preview
+	}
+	if meta.Title == "" {
+		logger.Info("Link has no title to show", zap.String("url", url))
+		return preview
+	}
+
+	preview.Status = linkpreview.StatusReady
+	preview.TargetURL = meta.URL
+	preview.Title = post.Excerpt(meta.Title, previewTitleLength-1)
+	preview.Description = post.Excerpt(meta.Description, previewDescriptionLength-1)
+	preview.SiteName = post.Excerpt(meta.SiteName, previewSiteNameLength-1)
+
+	if meta.ImageURL != "" {
+		image, thumbnail, err := storePreviewImage(ctx, meta.ImageURL)
+		if err != nil {
+			// The card is shown without an image
+			logger.Info("Failed to store link preview image", zap.String("url", meta.ImageURL), zap.Error(err))
+		} else {
+			preview.ImagePath, preview.ThumbnailPath = &image, &thumbnail
+		}
+	}
+	return preview
+}
+
+// imageExts names downloaded images by their detected type, so they pass the upload validation
+var imageExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// storePreviewImage downloads a preview image and processes it like an uploaded attachment,
+// keeping the medium size and the thumbnail
+func storePreviewImage(ctx context.Context, url string) (string, string, error) {
+	data, err := linkFetcher.Image(ctx, url)
+	if err != nil {
+		return "", "", err
+	}
+	ext, ok := imageExts[http.DetectContentType(data)]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported image type")
+	}
+
+	file, err := storage.NewFileHeader("preview"+ext, data)
+	if err != nil {
+		return "", "", err
+	}
+	if err := storage.ValidateFile(file, storage.DefaultImageConfig()); err != nil {
+		return "", "", err
+	}
+	processed, err := storage.ProcessImage(file, variables.LINK_PREVIEW_PATH, storage.DefaultImageProcessConfig())
+	if err != nil {
+		return "", "", err
+	}
+
+	deleteFiles([]string{processed.OriginalURL})
+	return processed.MediumURL, processed.ThumbnailURL, nil
+}
+
+// deleteFiles removes stored files, failures only leave the file behind
+func deleteFiles(paths []string) {
+	for _, path := range paths {
+		if err := storage.DeleteFile(&path, ""); err != nil {
+			logger.Warn("Failed to delete file", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// notificationChannel relays SSE notifications from the process running a task to the API, which holds the SSE clients
+const notificationChannel = "notifications:sse"
+
+// UserNotification is an SSE event for a user, as relayed between processes. With PollID set it
+// also goes to the connected users who voted in that poll.
+type UserNotification struct {
+	UserID uint            `json:"user_id"`
+	Event  string          `json:"event"`
+	Data   json.RawMessage `json:"data"`
+	PollID uint            `json:"poll_id,omitempty"`
+}
+
+// NotifyUser sends an SSE event to a user. With Redis it is published for RelayNotifications,
+// so it reaches the user whichever process sends it.
+func NotifyUser(ctx context.Context, userID uint, event string, data interface{}) {
+	publishNotification(ctx, nil, UserNotification{UserID: userID, Event: event}, data)
+}
+
+// publishNotification publishes n with data, without Redis it is delivered to the clients of this process
+func publishNotification(ctx context.Context, polls poll.Repository, n UserNotification, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		logger.Warn("Failed to marshal notification", zap.String("event", n.Event), zap.Error(err))
+		return
+	}
+	n.Data = raw
+
+	if cache.RedisClient == nil {
+		deliverNotification(polls, n)
+		return
+	}
+
+	msg, err := json.Marshal(n)
+	if err == nil {
+		err = cache.RedisClient.Publish(ctx, notificationChannel, msg).Err()
+	}
+	if err != nil {
+		logger.Warn("Failed to publish notification", zap.String("event", n.Event), zap.Error(err))
+	}
+}
+
+// RelayNotifications delivers the notifications published by every process to the SSE clients of
+// this process until ctx is done, polls finds the voters of poll results. Run it in the API process.
+func RelayNotifications(ctx context.Context, polls poll.Repository) {
+	if cache.RedisClient == nil {
+		return
+	}
+
+	sub := cache.RedisClient.Subscribe(ctx, notificationChannel)
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			var n UserNotification
+			if err := json.Unmarshal([]byte(msg.Payload), &n); err != nil {
+				logger.Warn("Skipping malformed notification", zap.Error(err))
+				continue
+			}
+			deliverNotification(polls, n)
+		}
+	}
+}
+
+// deliverNotification sends n to its user and, for poll results, to the connected voters
+func deliverNotification(polls poll.Repository, n UserNotification) {
+	recipients := []uint{n.UserID}
+	if n.PollID != 0 && polls != nil {
+		if connected := utils.GetSSEHub().ConnectedUserIDs(); len(connected) > 0 {
+			voters, err := polls.FindVoterIDs(n.PollID, connected)
+			if err != nil {
+				logger.Warn("Failed to find connected voters", zap.Uint("poll_id", n.PollID), zap.Error(err))
+			}
+			for _, id := range voters {
+				if id != n.UserID {
+					recipients = append(recipients, id)
+				}
+			}
+		}
+	}
+
+	for _, id := range recipients {
+		utils.NotifyUser(id, n.Event, n.Data)
+	}
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const TaskClosePolls = "polls:close_due"
+
+// closeBatchSize is the number of expired polls closed per sweep
+const closeBatchSize = 100
+
+// HandleClosePolls closes expired polls, persisting their final tallies counted from the votes,
+// tells the authors and pushes the results to the voters
+func HandleClosePolls(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	repo := postgres.NewPollRepository(DB)
+	now := time.Now()
+	polls, err := repo.FindDue(now, closeBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to query due polls: %w", err)
+	}
+	if len(polls) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(polls))
+	for i, p := range polls {
+		ids[i] = p.ID
+	}
+	tallies, err := repo.CountVotes(ids)
+	if err != nil {
+		return fmt.Errorf("failed to count poll votes: %w", err)
+	}
+
+	posts := postgres.NewPostRepository(DB)
+	closed := 0
+	for _, p := range polls {
+		tally := tallies[p.ID]
+		ok, err := repo.Close(p.ID, tally, now)
+		if err != nil {
+			return fmt.Errorf("failed to close poll: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		closed++
+
+		if client := cache.RedisClient; client != nil {
+			client.Del(ctx, poll.CounterKey(p.ID))
+		}
+
+		author, err := posts.FindByID(p.PostID)
+		if err != nil {
+			continue
+		}
+		if err := SendPollClosedNotificationJob(author.UserID, p.PostID, p.ID, tally.Voters); err != nil {
+			logger.Warn("Failed to notify poll author", zap.Uint("poll_id", p.ID), zap.Error(err))
+		}
+		p.ClosedAt = &now
+		PushPollResults(ctx, repo, p, author.UserID, tally)
+	}
+
+	if closed > 0 {
+		logger.Info("Closed expired polls", zap.Int("count", closed))
+	}
+	return nil
+}
+
+// PushPollResults sends the tallies of a poll to its author and to the connected users who voted,
+// the only viewers allowed to see them while the poll is open. With Redis they are published for
+// RelayNotifications, otherwise repo finds the voters connected to this process.
+func PushPollResults(ctx context.Context, repo poll.Repository, p poll.Poll, authorID uint, tally poll.Tally) {
+	options := make([]map[string]interface{}, 0, len(p.Options))
+	for _, o := range p.Options {
+		options = append(options, map[string]interface{}{"id": o.ID, "votes": tally.Options[o.ID]})
+	}
+	data := map[string]interface{}{
+		"poll_id": p.ID,
+		"post_id": p.PostID,
+		"closed":  p.IsClosed(time.Now()),
+		"voters":  tally.Voters,
+		"options": options,
+	}
+	publishNotification(ctx, repo, UserNotification{UserID: authorID, Event: poll.ResultsEvent, PollID: p.ID}, data)
+}
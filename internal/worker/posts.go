@@ -0,0 +1,188 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const (
+	TaskPublishPost     = "post:publish"
+	TaskPublishDuePosts = "post:publish_due"
+)
+
+// dueBatchSize is the number of overdue scheduled posts published per sweep
+const dueBatchSize = 100
+
+// PublishPostPayload carries the schedule the task was created for, a post that was
+// rescheduled since no longer matches it and the task does nothing
+type PublishPostPayload struct {
+	PostID    uint  `json:"post_id"`
+	PublishAt int64 `json:"publish_at"`
+}
+
+// publishTaskID is unique per post and schedule, so the pending task can be found again to cancel it
+func publishTaskID(postID uint, publishAt time.Time) string {
+	return fmt.Sprintf("%s:%d:%d", TaskPublishPost, postID, publishAt.Unix())
+}
+
+// EnqueuePostPublish schedules a post to go live at publishAt. Without a queue scheduled
+// posts stay unpublished until a worker sweeps them.
+func EnqueuePostPublish(postID uint, publishAt time.Time) error {
+	if AsynqClientInstance == nil {
+		return nil
+	}
+	err := EnqueueTaskAt(TaskPublishPost, PublishPostPayload{PostID: postID, PublishAt: publishAt.Unix()}, publishAt,
+		asynq.Queue(QueueDefault), asynq.MaxRetry(5), asynq.TaskID(publishTaskID(postID, publishAt)))
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+// CancelPostPublish deletes the pending task of a schedule. A task that already started
+// is left alone, it sees the changed schedule and does nothing.
+func CancelPostPublish(postID uint, publishAt time.Time) error {
+	if AsynqClientInstance == nil {
+		return nil
+	}
+	err := DeleteTask(QueueDefault, publishTaskID(postID, publishAt))
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return nil
+	}
+	return err
+}
+
+// HandlePublishPost publishes a scheduled post, it is a no-op when the post was
+// deleted, cancelled, rescheduled or already published
+func HandlePublishPost(ctx context.Context, t *asynq.Task) error {
+	if postRepo == nil {
+		return fmt.Errorf("post repository not initialized")
+	}
+
+	var payload PublishPostPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	p, err := postRepo.FindByID(payload.PostID)
+	if err != nil {
+		logger.Info("Skipping publish of missing post", zap.Uint("post_id", payload.PostID))
+		return nil
+	}
+	if p.Status != post.StatusScheduled || p.PublishAt == nil || p.PublishAt.Unix() != payload.PublishAt {
+		return nil
+	}
+	return PublishPost(p)
+}
+
+// HandlePublishDuePosts publishes scheduled posts whose time has passed, catching
+// tasks that were lost or scheduled while no queue was configured
+func HandlePublishDuePosts(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if postRepo == nil {
+		return fmt.Errorf("post repository not initialized")
+	}
+
+	var ids []uint
+	err := DB.Model(&post.Post{}).
+		Where("status = ? AND publish_at <= ?", post.StatusScheduled, time.Now()).
+		Order("publish_at").Limit(dueBatchSize).Pluck("id", &ids).Error
+	if err != nil {
+		return fmt.Errorf("failed to query due posts: %w", err)
+	}
+
+	for _, id := range ids {
+		p, err := postRepo.FindByID(id)
+		if err != nil {
+			continue
+		}
+		if err := PublishPost(p); err != nil {
+			return err
+		}
+	}
+
+	if len(ids) > 0 {
+		logger.Info("Published due scheduled posts", zap.Int("count", len(ids)))
+	}
+	return nil
+}
+
+// PublishPost makes a scheduled post live, announces it and tells the author.
+// Only the first caller publishes, repeated calls do nothing.
+func PublishPost(p *post.Post) error {
+	now := time.Now()
+	published, err := postRepo.Publish(p.ID, now)
+	if err != nil {
+		return fmt.Errorf("failed to publish post: %w", err)
+	}
+	if !published {
+		return nil
+	}
+	p.Status = post.StatusPublished
+	p.PublishAt = &now
+
+	// Posts flagged by the filter reach timelines once a moderator approves them
+	if p.IsPublic() {
+		AnnouncePost(*p, p.Mentions)
+	}
+	if err := SendPostPublishedNotificationJob(p.UserID, p.ID, p.Tweet); err != nil {
+		logger.Warn("Failed to enqueue post published notification", zap.Uint("post_id", p.ID), zap.Error(err))
+	}
+
+	logger.Info("Scheduled post published", zap.Uint("post_id", p.ID))
+	return nil
+}
+
+// AnnouncePost runs what follows a post going live: the timeline fan-out and notifying the mentioned
+// users and the author of a quoted post
+func AnnouncePost(p post.Post, mentions []post.PostMention) {
+	if err := EnqueueTimelineFanout(p); err != nil {
+		logger.Warn("Failed to enqueue timeline fan-out", zap.Uint("post_id", p.ID), zap.Error(err))
+	}
+	NotifyMentions(p, mentions)
+	NotifyQuote(p)
+}
+
+// NotifyQuote notifies the author of the quoted post, unless they quoted themselves or the quote is private.
+// The quoted post is looked up when p was loaded without it, a quoted post that is gone is not notified.
+func NotifyQuote(p post.Post) {
+	if !p.IsQuote() || p.Visibility == post.VisibilityPrivate {
+		return
+	}
+	quoted := p.QuotedPost
+	if quoted == nil && postRepo != nil {
+		quoted, _ = postRepo.FindVisibleByID(*p.QuotedPostID, p.UserID)
+	}
+	if quoted == nil || quoted.UserID == p.UserID {
+		return
+	}
+	if err := SendQuoteNotificationJob(quoted.UserID, quoted.ID, p.ID, p.UserID, p.Tweet); err != nil {
+		logger.Warn("Failed to enqueue quote notification", zap.Uint("post_id", p.ID), zap.Uint("user_id", quoted.UserID), zap.Error(err))
+	}
+}
+
+// NotifyMentions notifies mentioned users other than the author, who see the post unless it is private
+func NotifyMentions(p post.Post, mentions []post.PostMention) {
+	if p.Visibility == post.VisibilityPrivate {
+		return
+	}
+	for _, m := range mentions {
+		if m.UserID == p.UserID {
+			continue
+		}
+		if err := SendMentionNotificationJob(m.UserID, p.ID, p.UserID, p.Tweet); err != nil {
+			logger.Warn("Failed to enqueue mention notification", zap.Uint("post_id", p.ID), zap.Uint("user_id", m.UserID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const TaskReconcileReactions = "reactions:reconcile"
+
+// reconcileBatchSize is the number of posts recomputed per query
+const reconcileBatchSize = 500
+
+// ReconcileReactionsPayload selects between dirty posts only and every post with reactions
+type ReconcileReactionsPayload struct {
+	Full bool `json:"full"`
+}
+
+// HandleReconcileReactions recomputes reaction counters from the reactions table,
+// persists them to reaction_counts and overwrites the Redis counters to remove drift
+func HandleReconcileReactions(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if cache.RedisClient == nil {
+		return fmt.Errorf("redis not initialized")
+	}
+
+	var payload ReconcileReactionsPayload
+	if len(t.Payload()) > 0 {
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+	}
+
+	repo := postgres.NewReactionRepository(DB)
+	reconciled := 0
+
+	if payload.Full {
+		// Posts whose last reaction was removed only exist in reaction_counts
+		var postIDs, countedIDs []uint
+		if err := DB.Model(&reaction.Reaction{}).Distinct().Pluck("post_id", &postIDs).Error; err != nil {
+			return fmt.Errorf("failed to query reacted posts: %w", err)
+		}
+		if err := DB.Model(&reaction.Count{}).Distinct().Pluck("post_id", &countedIDs).Error; err != nil {
+			return fmt.Errorf("failed to query counted posts: %w", err)
+		}
+		postIDs = mergeIDs(postIDs, countedIDs)
+
+		for start := 0; start < len(postIDs); start += reconcileBatchSize {
+			end := min(start+reconcileBatchSize, len(postIDs))
+			if err := reconcileReactionBatch(ctx, repo, postIDs[start:end]); err != nil {
+				return err
+			}
+		}
+		reconciled = len(postIDs)
+	} else {
+		for {
+			members, err := cache.RedisClient.SPopN(ctx, reaction.DirtyKey, reconcileBatchSize).Result()
+			if err != nil {
+				return fmt.Errorf("failed to pop dirty posts: %w", err)
+			}
+			if len(members) == 0 {
+				break
+			}
+
+			postIDs := make([]uint, 0, len(members))
+			for _, m := range members {
+				if id, err := strconv.ParseUint(m, 10, 64); err == nil {
+					postIDs = append(postIDs, uint(id))
+				}
+			}
+			if err := reconcileReactionBatch(ctx, repo, postIDs); err != nil {
+				// Keep the posts dirty so the next run retries them
+				cache.RedisClient.SAdd(ctx, reaction.DirtyKey, members)
+				return err
+			}
+			reconciled += len(postIDs)
+		}
+	}
+
+	logger.Info("Reaction counters reconciled", zap.Int("posts", reconciled), zap.Bool("full", payload.Full))
+	return nil
+}
+
+func reconcileReactionBatch(ctx context.Context, repo reaction.Repository, postIDs []uint) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	counts, err := repo.CountByPosts(postIDs)
+	if err != nil {
+		return fmt.Errorf("failed to count reactions: %w", err)
+	}
+	if err := repo.SaveCounts(postIDs, counts); err != nil {
+		return fmt.Errorf("failed to save reaction counts: %w", err)
+	}
+
+	pipe := cache.RedisClient.TxPipeline()
+	for _, id := range postIDs {
+		pipe.Del(ctx, reaction.CounterKey(id))
+	}
+	for _, c := range counts {
+		pipe.HSet(ctx, reaction.CounterKey(c.PostID), string(c.Type), c.Count)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to refresh reaction counters: %w", err)
+	}
+	return nil
+}
+
+func mergeIDs(a, b []uint) []uint {
+	seen := make(map[uint]bool, len(a)+len(b))
+	result := make([]uint, 0, len(a)+len(b))
+	for _, id := range append(a, b...) {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+)
+
+// Repositories of the post and timeline jobs, set next to the database by the API and the worker
+var (
+	postRepo   post.Repository
+	followRepo follow.Repository
+)
+
+// SetPostRepository sets the repository used to publish, purge and notify about posts
+func SetPostRepository(r post.Repository) {
+	postRepo = r
+}
+
+// SetFollowRepository sets the repository the timeline fan-out reads followers from
+func SetFollowRepository(r follow.Repository) {
+	followRepo = r
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/casbin/casbin/v2"
+	"go.uber.org/zap"
+)
+
+// rolesChannel relays role changes from the process making them to the API, which holds the casbin enforcer
+const rolesChannel = "roles:changes"
+
+// RoleChange grants Role to Email, or drops every role of Email when Revoke is set
+type RoleChange struct {
+	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
+	Revoke bool   `json:"revoke,omitempty"`
+}
+
+var roleEnforcer *casbin.Enforcer
+
+// SetRoleEnforcer sets the enforcer role changes are applied to, only the API process holds one.
+// Its roles are kept in memory, so changes made by other processes reach it through RelayRoles.
+func SetRoleEnforcer(e *casbin.Enforcer) {
+	roleEnforcer = e
+}
+
+// GrantRole gives role to the account with email
+func GrantRole(ctx context.Context, email, role string) error {
+	return changeRole(ctx, RoleChange{Email: email, Role: role})
+}
+
+// RevokeRoles drops every role of the account with email
+func RevokeRoles(ctx context.Context, email string) error {
+	return changeRole(ctx, RoleChange{Email: email, Revoke: true})
+}
+
+// changeRole publishes change for RelayRoles, so it reaches the enforcer whichever process makes it.
+// Without Redis it is applied to the enforcer of this process.
+func changeRole(ctx context.Context, change RoleChange) error {
+	if cache.RedisClient == nil {
+		return applyRoleChange(change)
+	}
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	return cache.RedisClient.Publish(ctx, rolesChannel, data).Err()
+}
+
+// RelayRoles applies the role changes published by every process to the enforcer until ctx is done.
+// Run it in the API process.
+func RelayRoles(ctx context.Context) {
+	if cache.RedisClient == nil {
+		return
+	}
+
+	sub := cache.RedisClient.Subscribe(ctx, rolesChannel)
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			var change RoleChange
+			if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+				logger.Warn("Skipping malformed role change", zap.Error(err))
+				continue
+			}
+			if err := applyRoleChange(change); err != nil {
+				logger.Warn("Failed to apply role change", zap.String("email", change.Email), zap.Error(err))
+			}
+		}
+	}
+}
+
+func applyRoleChange(change RoleChange) error {
+	if roleEnforcer == nil {
+		return fmt.Errorf("no role enforcer set")
+	}
+	if change.Revoke {
+		_, err := roleEnforcer.DeleteRolesForUser(change.Email)
+		return err
+	}
+	_, err := roleEnforcer.AddRoleForUser(change.Email, change.Role)
+	return err
+}
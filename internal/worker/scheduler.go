@@ -63,6 +63,96 @@ func RegisterPeriodicTasks(scheduler *asynq.Scheduler) error {
 		return fmt.Errorf("failed to register monthly archive: %w", err)
 	}
 
+	// Email verification reminders - every day at 9 AM
+	_, err = scheduler.Register(
+		DailyAt(9, 0),
+		asynq.NewTask(TaskEmailVerificationReminder, nil),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register verification reminder: %w", err)
+	}
+
+	// Purge unverified accounts - every day at 4 AM
+	_, err = scheduler.Register(
+		DailyAt(4, 0),
+		asynq.NewTask(TaskPurgeUnverifiedUsers, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register unverified account purge: %w", err)
+	}
+
+	// Persist dirty reaction counters - every minute
+	_, err = scheduler.Register(
+		"@every 1m",
+		asynq.NewTask(TaskReconcileReactions, nil),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register reaction reconciliation: %w", err)
+	}
+
+	// Full reaction recount, repairs counters lost with Redis data - every day at 3:30 AM
+	_, err = scheduler.Register(
+		DailyAt(3, 30),
+		asynq.NewTask(TaskReconcileReactions, []byte(`{"full":true}`)),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register full reaction reconciliation: %w", err)
+	}
+
+	// Delete attachments no post claimed - every hour
+	_, err = scheduler.Register(
+		"@every 1h",
+		asynq.NewTask(TaskPurgeOrphanAttachments, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register orphan attachment purge: %w", err)
+	}
+
+	// Publish scheduled posts whose task was lost - every 5 minutes
+	_, err = scheduler.Register(
+		"@every 5m",
+		asynq.NewTask(TaskPublishDuePosts, nil),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register due post publishing: %w", err)
+	}
+
+	// Close expired polls - every minute
+	_, err = scheduler.Register(
+		"@every 1m",
+		asynq.NewTask(TaskClosePolls, nil),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register poll closing: %w", err)
+	}
+
+	// Roll up post views, reactions and comments into the daily stats - every 10 minutes
+	_, err = scheduler.Register(
+		"@every 10m",
+		asynq.NewTask(TaskRollupPostStats, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register post stats roll-up: %w", err)
+	}
+
+	// Purge posts that stayed in the trash too long - every day at 4:30 AM
+	_, err = scheduler.Register(
+		DailyAt(4, 30),
+		asynq.NewTask(TaskPurgeTrashedPosts, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register trashed post purge: %w", err)
+	}
+
 	logger.Info("All periodic tasks registered successfully")
 	return nil
 }
@@ -0,0 +1,236 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/search"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/infrastructure/fulltext"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	TaskSearchIndex   = "search:index"
+	TaskSearchReindex = "search:reindex"
+)
+
+// reindexBatchSize is the number of rows loaded and indexed at a time during a full reindex
+const reindexBatchSize = 500
+
+// SearchIndexPayload lists changed rows, they are re-read so the index always matches the database
+type SearchIndexPayload struct {
+	Type string `json:"type"`
+	IDs  []uint `json:"ids"`
+}
+
+// RegisterSearchCallbacks keeps the search index in sync with writes to posts and users.
+// The callbacks run after the transaction commits, so queued jobs read committed rows.
+// Bulk writes without primary keys (e.g. Where(...).Delete) are picked up by the next reindex.
+func RegisterSearchCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:commit_or_rollback_transaction").Register("search:index_create", indexChangedRows); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:commit_or_rollback_transaction").Register("search:index_update", indexChangedRows); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:commit_or_rollback_transaction").Register("search:index_delete", indexChangedRows)
+}
+
+func indexChangedRows(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || fulltext.Engine() == nil {
+		return
+	}
+
+	var docType string
+	switch db.Statement.Schema.Table {
+	case "posts":
+		docType = search.TypePost
+	case "users":
+		docType = search.TypeUser
+	default:
+		return
+	}
+
+	ids := primaryKeys(db)
+	if len(ids) == 0 {
+		return
+	}
+	if err := EnqueueSearchIndex(db.Session(&gorm.Session{NewDB: true}), docType, ids); err != nil {
+		logger.Warn("Failed to update search index", zap.String("type", docType), zap.Error(err))
+	}
+}
+
+// primaryKeys collects the non-zero IDs of the statement's model, a struct or a slice of structs
+func primaryKeys(db *gorm.DB) []uint {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+
+	var ids []uint
+	collect := func(v reflect.Value) {
+		value, zero := field.ValueOf(db.Statement.Context, v)
+		if id, ok := value.(uint); ok && !zero {
+			ids = append(ids, id)
+		}
+	}
+
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Struct:
+		collect(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			collect(reflect.Indirect(rv.Index(i)))
+		}
+	}
+	return ids
+}
+
+// EnqueueSearchIndex re-indexes rows in the background. Without a queue it indexes
+// them right away through db, the connection the change was made on.
+func EnqueueSearchIndex(db *gorm.DB, docType string, ids []uint) error {
+	if AsynqClientInstance == nil {
+		return IndexSearchDocuments(db, docType, ids)
+	}
+	return EnqueueTask(TaskSearchIndex, SearchIndexPayload{Type: docType, IDs: ids},
+		asynq.Queue(QueueDefault), asynq.MaxRetry(5))
+}
+
+// IndexSearchDocuments indexes the given rows and removes the ones that no longer exist, were
+// soft-deleted or are posts that are not published, were held back by moderation or are not public
+func IndexSearchDocuments(db *gorm.DB, docType string, ids []uint) error {
+	engine := fulltext.Engine()
+	if engine == nil || len(ids) == 0 {
+		return nil
+	}
+
+	var docs []search.Document
+	switch docType {
+	case search.TypePost:
+		var posts []post.Post
+		if err := db.Where("id IN ? AND status = ? AND moderation_state = ? AND visibility = ?", ids, post.StatusPublished, post.ModerationVisible, post.VisibilityPublic).Find(&posts).Error; err != nil {
+			return err
+		}
+		for _, p := range posts {
+			docs = append(docs, search.PostDocument(p))
+		}
+	case search.TypeUser:
+		var users []user.User
+		if err := db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+			return err
+		}
+		for _, u := range users {
+			docs = append(docs, search.UserDocument(u))
+		}
+	default:
+		return fmt.Errorf("unknown search document type %q", docType)
+	}
+
+	found := make(map[uint]bool, len(docs))
+	for _, d := range docs {
+		found[d.ID] = true
+	}
+	var gone []uint
+	for _, id := range ids {
+		if !found[id] {
+			gone = append(gone, id)
+		}
+	}
+
+	if err := engine.Index(docs...); err != nil {
+		return err
+	}
+	return engine.Delete(docType, gone...)
+}
+
+// HandleSearchIndex applies queued index updates
+func HandleSearchIndex(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	var payload SearchIndexPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return IndexSearchDocuments(DB, payload.Type, payload.IDs)
+}
+
+// EnqueueSearchReindex schedules a full rebuild. Without a queue it runs in a goroutine on db.
+func EnqueueSearchReindex(db *gorm.DB) error {
+	if AsynqClientInstance == nil {
+		go func() {
+			if err := ReindexSearch(db); err != nil {
+				logger.Error("Search reindex failed", zap.Error(err))
+			}
+		}()
+		return nil
+	}
+	// Unique so repeated requests don't queue several rebuilds
+	err := EnqueueTask(TaskSearchReindex, struct{}{}, asynq.Queue(QueueLow), asynq.Unique(time.Hour))
+	if errors.Is(err, asynq.ErrDuplicateTask) {
+		return nil
+	}
+	return err
+}
+
+// HandleSearchReindex rebuilds the whole search index
+func HandleSearchReindex(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return ReindexSearch(DB)
+}
+
+// ReindexSearch clears the index and indexes every published post and user in batches
+func ReindexSearch(db *gorm.DB) error {
+	engine := fulltext.Engine()
+	if engine == nil {
+		return fmt.Errorf("search is not configured")
+	}
+	if err := engine.Reset(); err != nil {
+		return fmt.Errorf("failed to reset search index: %w", err)
+	}
+
+	indexed := 0
+	var posts []post.Post
+	err := db.Where("status = ? AND moderation_state = ? AND visibility = ?", post.StatusPublished, post.ModerationVisible, post.VisibilityPublic).FindInBatches(&posts, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+		docs := make([]search.Document, len(posts))
+		for i, p := range posts {
+			docs[i] = search.PostDocument(p)
+		}
+		indexed += len(docs)
+		return engine.Index(docs...)
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to index posts: %w", err)
+	}
+
+	var users []user.User
+	err = db.FindInBatches(&users, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+		docs := make([]search.Document, len(users))
+		for i, u := range users {
+			docs[i] = search.UserDocument(u)
+		}
+		indexed += len(docs)
+		return engine.Index(docs...)
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to index users: %w", err)
+	}
+
+	logger.Info("Search reindex completed", zap.String("engine", engine.Name()), zap.Int("documents", indexed))
+	return nil
+}
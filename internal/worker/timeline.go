@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const TaskTimelineFanout = "timeline:fanout"
+
+// fanoutBatchSize is the number of follower timelines updated per Redis round trip
+const fanoutBatchSize = 1000
+
+type TimelineFanoutPayload struct {
+	PostID   uint `json:"post_id"`
+	AuthorID uint `json:"author_id"`
+	// Score is the post's PublishedScore, where it goes on the timelines
+	Score int64 `json:"score"`
+}
+
+// EnqueueTimelineFanout pushes a new post to the author's followers in the background.
+// Without a queue there are no Redis timelines and the timeline is read from the database.
+func EnqueueTimelineFanout(p post.Post) error {
+	if AsynqClientInstance == nil {
+		return nil
+	}
+	return EnqueueTask(TaskTimelineFanout, TimelineFanoutPayload{PostID: p.ID, AuthorID: p.UserID, Score: p.PublishedScore()},
+		asynq.Queue(QueueDefault), asynq.MaxRetry(3))
+}
+
+// HandleTimelineFanout adds a post to the Redis timelines of the author and their followers.
+// Authors at or above the fan-out threshold are skipped, their posts are merged at read time.
+func HandleTimelineFanout(ctx context.Context, t *asynq.Task) error {
+	if followRepo == nil {
+		return fmt.Errorf("follow repository not initialized")
+	}
+	if cache.RedisClient == nil {
+		return fmt.Errorf("redis not initialized")
+	}
+
+	var payload TimelineFanoutPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	if err := pushToTimelines(ctx, []uint{payload.AuthorID}, payload.PostID, payload.Score); err != nil {
+		return err
+	}
+
+	followers, err := followRepo.CountFollowers(payload.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to count followers: %w", err)
+	}
+	if followers >= follow.FanoutThreshold() {
+		logger.Info("Skipping fan-out for popular author",
+			zap.Uint("author_id", payload.AuthorID), zap.Int64("followers", followers))
+		return nil
+	}
+
+	var afterID uint
+	for {
+		ids, err := followRepo.FollowerIDsAfter(payload.AuthorID, afterID, fanoutBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to load followers: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		if err := pushToTimelines(ctx, ids, payload.PostID, payload.Score); err != nil {
+			return err
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	return nil
+}
+
+// pushToTimelines adds the post to timelines that are already materialised.
+// Missing timelines are rebuilt from the database on their next read.
+func pushToTimelines(ctx context.Context, userIDs []uint, postID uint, score int64) error {
+	client := cache.RedisClient
+
+	pipe := client.Pipeline()
+	exists := make([]*redis.IntCmd, len(userIDs))
+	for i, id := range userIDs {
+		exists[i] = pipe.Exists(ctx, follow.TimelineKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to check timelines: %w", err)
+	}
+
+	pipe = client.Pipeline()
+	for i, id := range userIDs {
+		if exists[i].Val() == 0 {
+			continue
+		}
+		key := follow.TimelineKey(id)
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(score), Member: postID})
+		pipe.ZRemRangeByRank(ctx, key, 0, -(follow.TimelineMaxLen + 1))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update timelines: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/infrastructure/storage"
+	"starter-gofiber/pkg/logger"
+	"starter-gofiber/variables"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+const TaskPurgeTrashedPosts = "cleanup:trashed_posts"
+
+// trashPurgeBatchSize is the number of deleted posts purged per query
+const trashPurgeBatchSize = 100
+
+// HandlePurgeTrashedPosts permanently deletes posts that stayed in the trash longer than
+// post.TrashRetention, with their files
+func HandlePurgeTrashedPosts(ctx context.Context, t *asynq.Task) error {
+	if postRepo == nil {
+		return fmt.Errorf("post repository not initialized")
+	}
+
+	before := time.Now().Add(-post.TrashRetention())
+	purged := 0
+	for {
+		posts, err := postRepo.FindTrashedBefore(before, trashPurgeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to query trashed posts: %w", err)
+		}
+		for i := range posts {
+			if err := PurgePost(postRepo, &posts[i]); err != nil {
+				return fmt.Errorf("failed to purge post %d: %w", posts[i].ID, err)
+			}
+		}
+		purged += len(posts)
+		if len(posts) < trashPurgeBatchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		logger.Info("Trashed post purge completed", zap.Int("deleted", purged))
+	}
+	return nil
+}
+
+// PurgePost permanently deletes a post, its attachments and revisions, then their files.
+// Failing deletes of files only leave them behind.
+func PurgePost(repo post.Repository, p *post.Post) error {
+	attachments, err := repo.DeletePostAttachments(p.ID)
+	if err != nil {
+		return err
+	}
+	revisions, err := repo.DeleteRevisions(p.ID)
+	if err != nil {
+		return err
+	}
+	if err := repo.ForceDelete(p.ID); err != nil {
+		return err
+	}
+
+	deleteAttachmentFiles(attachments)
+	photos := make([]*string, 0, len(revisions)+1)
+	photos = append(photos, p.Photo)
+	for _, rev := range revisions {
+		photos = append(photos, rev.Photo)
+	}
+	for _, photo := range photos {
+		if photo != nil && *photo != "" {
+			if err := storage.DeleteFile(photo, variables.POST_PATH); err != nil {
+				logger.Warn("Failed to delete post photo", zap.Uint("post_id", p.ID), zap.String("photo", *photo), zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
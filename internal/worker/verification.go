@@ -0,0 +1,280 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/infrastructure/cache"
+	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/logger"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	TaskEmailVerificationReminder = "email:verification_reminder"
+	TaskPurgeUnverifiedUsers      = "cleanup:unverified_users"
+)
+
+var (
+	// verificationReminderEvery is the interval between reminders for unverified accounts
+	verificationReminderEvery = 3 * 24 * time.Hour
+	// unverifiedPurgeAfter is the account age after which unverified accounts are deleted
+	unverifiedPurgeAfter = 30 * 24 * time.Hour
+)
+
+// SetVerificationPolicy sets reminder interval and purge age in days (0 keeps the default)
+func SetVerificationPolicy(reminderDays, purgeDays int) {
+	if reminderDays > 0 {
+		verificationReminderEvery = time.Duration(reminderDays) * 24 * time.Hour
+	}
+	if purgeDays > 0 {
+		unverifiedPurgeAfter = time.Duration(purgeDays) * 24 * time.Hour
+	}
+}
+
+// HandleEmailVerificationReminder sends a new verification link to unverified
+// accounts that have not been reminded within the reminder interval
+func HandleEmailVerificationReminder(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	logger.Info("Running verification reminder job")
+
+	now := time.Now()
+	remindBefore := now.Add(-verificationReminderEvery)
+
+	var users []user.User
+	err := DB.Where("email_verified = ?", false).
+		Where("created_at < ? AND created_at > ?", remindBefore, now.Add(-unverifiedPurgeAfter)).
+		Where("verification_reminded_at IS NULL OR verification_reminded_at < ?", remindBefore).
+		Find(&users).Error
+	if err != nil {
+		return fmt.Errorf("failed to query unverified users: %w", err)
+	}
+
+	sent := 0
+	for _, usr := range users {
+		token, err := crypto.GenerateRandomToken()
+		if err != nil {
+			return fmt.Errorf("failed to generate verification token: %w", err)
+		}
+
+		verification := &user.EmailVerification{
+			UserID:    usr.ID,
+			Token:     token,
+			ExpiresAt: now.Add(time.Hour * 24),
+		}
+		if err := DB.Create(verification).Error; err != nil {
+			logger.Error("Failed to create verification token", zap.Uint("user_id", usr.ID), zap.Error(err))
+			continue
+		}
+
+		if _, err := EnqueueEmailVerification(usr.Email, token); err != nil {
+			logger.Error("Failed to enqueue verification reminder", zap.Uint("user_id", usr.ID), zap.Error(err))
+			continue
+		}
+
+		DB.Model(&user.User{}).Where("id = ?", usr.ID).Update("verification_reminded_at", now)
+		sent++
+	}
+
+	logger.Info("Verification reminder job completed", zap.Int("sent", sent))
+	return nil
+}
+
+// HandlePurgeUnverifiedUsers permanently deletes accounts that stayed unverified
+// longer than the purge age, together with their posts, activity and roles
+func HandlePurgeUnverifiedUsers(ctx context.Context, t *asynq.Task) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if postRepo == nil {
+		return fmt.Errorf("post repository not initialized")
+	}
+
+	logger.Info("Running unverified account purge")
+
+	var users []user.User
+	err := DB.Select("id", "email").
+		Where("email_verified = ? AND created_at < ?", false, time.Now().Add(-unverifiedPurgeAfter)).
+		Find(&users).Error
+	if err != nil {
+		return fmt.Errorf("failed to query unverified users: %w", err)
+	}
+
+	if len(users) == 0 {
+		logger.Info("No unverified accounts to purge")
+		return nil
+	}
+	userIDs := make([]uint, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	// Posts, trashed ones included, go the way of the trash purge so their files are deleted too
+	var posts []post.Post
+	if err := DB.Unscoped().Select("id", "user_id", "photo").Where("user_id IN ?", userIDs).Find(&posts).Error; err != nil {
+		return fmt.Errorf("failed to query posts of unverified users: %w", err)
+	}
+	for i := range posts {
+		if err := PurgePost(postRepo, &posts[i]); err != nil {
+			return fmt.Errorf("failed to purge post %d: %w", posts[i].ID, err)
+		}
+	}
+
+	// Uploads that were never attached to a post
+	var attachments []post.Attachment
+	if err := DB.Where("user_id IN ?", userIDs).Find(&attachments).Error; err != nil {
+		return fmt.Errorf("failed to query attachments of unverified users: %w", err)
+	}
+
+	var reactedPosts []uint
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := purgeActivity(tx, userIDs, &reactedPosts); err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&user.EmailVerification{},
+			&user.RefreshToken{},
+			&user.PasswordReset{},
+			&user.APIKey{},
+			&user.UserPreferences{},
+			&consent.UserConsent{},
+			&consent.MarketingConsent{},
+			&post.Attachment{},
+			&bookmark.Collection{},
+		} {
+			if err := tx.Unscoped().Where("user_id IN ?", userIDs).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("reporter_id IN ? OR target_user_id IN ?", userIDs, userIDs).Delete(&moderation.Report{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("follower_id IN ? OR followee_id IN ?", userIDs, userIDs).Delete(&follow.Follow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&security.Event{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", userIDs).Delete(&user.User{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge unverified users: %w", err)
+	}
+	deleteAttachmentFiles(attachments)
+
+	if cache.RedisClient != nil {
+		// The reconciliation job recounts the reactions of the posts the purged users reacted to
+		if len(reactedPosts) > 0 {
+			members := make([]interface{}, 0, len(reactedPosts))
+			for _, id := range reactedPosts {
+				members = append(members, id)
+			}
+			cache.RedisClient.SAdd(ctx, reaction.DirtyKey, members...)
+		}
+		keys := make([]string, 0, len(userIDs))
+		for _, id := range userIDs {
+			keys = append(keys, follow.TimelineKey(id))
+		}
+		cache.RedisClient.Del(ctx, keys...)
+	}
+
+	for _, u := range users {
+		if err := RevokeRoles(ctx, u.Email); err != nil {
+			logger.Warn("Failed to revoke roles of purged user", zap.Uint("user_id", u.ID), zap.Error(err))
+		}
+	}
+
+	logger.Info("Unverified account purge completed", zap.Int("deleted", len(users)), zap.Int("posts", len(posts)))
+	return nil
+}
+
+// postCount is a number of rows per post or comment
+type postCount struct {
+	ID uint
+	N  int
+}
+
+// purgeActivity deletes what the users did on other users' posts, keeping the counters of those
+// posts right. reactedPosts receives the posts whose reaction counters must be recounted.
+func purgeActivity(tx *gorm.DB, userIDs []uint, reactedPosts *[]uint) error {
+	comments := tx.Model(&comment.Comment{}).Where("user_id IN ?", userIDs)
+
+	var counts []postCount
+	if err := comments.Session(&gorm.Session{}).Select("post_id AS id, COUNT(*) AS n").Group("post_id").Scan(&counts).Error; err != nil {
+		return err
+	}
+	if err := decrement(tx, &post.Post{}, "comment_count", counts); err != nil {
+		return err
+	}
+	counts = nil
+	if err := comments.Session(&gorm.Session{}).Select("parent_id AS id, COUNT(*) AS n").Where("parent_id IS NOT NULL").Group("parent_id").Scan(&counts).Error; err != nil {
+		return err
+	}
+	if err := decrement(tx.Unscoped(), &comment.Comment{}, "reply_count", counts); err != nil {
+		return err
+	}
+
+	counts = nil
+	if err := tx.Model(&repost.Repost{}).Select("post_id AS id, COUNT(*) AS n").Where("user_id IN ?", userIDs).Group("post_id").Scan(&counts).Error; err != nil {
+		return err
+	}
+	if err := decrement(tx.Unscoped(), &post.Post{}, "repost_count", counts); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&reaction.Reaction{}).Distinct().Where("user_id IN ?", userIDs).Pluck("post_id", reactedPosts).Error; err != nil {
+		return err
+	}
+
+	ballots := tx.Model(&poll.Ballot{}).Select("id").Where("user_id IN ?", userIDs)
+	if err := tx.Where("ballot_id IN (?)", ballots).Delete(&poll.Vote{}).Error; err != nil {
+		return err
+	}
+	for _, model := range []interface{}{&reaction.Reaction{}, &bookmark.Bookmark{}, &repost.Repost{}, &post.PostMention{}, &poll.Ballot{}} {
+		if err := tx.Unscoped().Where("user_id IN ?", userIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	// Comments are soft-deleted with their body cleared, those with replies stay in their threads
+	// as placeholders. The ones nothing replied to are removed.
+	if err := tx.Unscoped().Model(&comment.Comment{}).Where("user_id IN ?", userIDs).UpdateColumn("body", "").Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", userIDs).Delete(&comment.Comment{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id IN ?", userIDs).
+		Where("NOT EXISTS (SELECT 1 FROM comments AS replies WHERE replies.parent_id = comments.id)").
+		Delete(&comment.Comment{}).Error
+}
+
+// decrement lowers a counter column of the rows by their counts, never below zero
+func decrement(tx *gorm.DB, model interface{}, column string, counts []postCount) error {
+	for _, c := range counts {
+		err := tx.Model(model).Where("id = ?", c.ID).
+			UpdateColumn(column, gorm.Expr("CASE WHEN "+column+" > ? THEN "+column+" - ? ELSE 0 END", c.N, c.N)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
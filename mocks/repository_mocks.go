@@ -1,8 +1,11 @@
 package mocks
 
 import (
+	"time"
+
 	postdomain "starter-gofiber/internal/domain/post"
 	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/pagination"
 
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -39,6 +42,16 @@ func (m *MockUserRepository) Update(user *user.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) FindByUsernames(usernames []string) ([]user.User, error) {
+	args := m.Called(usernames)
+	return args.Get(0).([]user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByIDs(ids []uint) ([]user.User, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]user.User), args.Error(1)
+}
+
 func (m *MockUserRepository) ExistEmail(email string) (bool, error) {
 	args := m.Called(email)
 	return args.Bool(0), args.Error(1)
@@ -49,14 +62,35 @@ type MockPostRepository struct {
 	mock.Mock
 }
 
-func (m *MockPostRepository) FindAll(limit, offset int) ([]postdomain.Post, int64, error) {
-	args := m.Called(limit, offset)
+func (m *MockPostRepository) FindAll(viewerID uint, limit, offset int) ([]postdomain.Post, int64, error) {
+	args := m.Called(viewerID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(int64), args.Error(2)
 	}
 	return args.Get(0).([]postdomain.Post), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockPostRepository) FindPage(filter postdomain.ListFilter, viewerID uint, page pagination.CursorPagination) ([]postdomain.Post, error) {
+	args := m.Called(filter, viewerID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) FindVisibleBySlug(slug string, authorID, viewerID uint) (*postdomain.Post, error) {
+	args := m.Called(slug, authorID, viewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postdomain.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) SlugTaken(userID uint, slug string) (bool, error) {
+	args := m.Called(userID, slug)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockPostRepository) Create(post *postdomain.Post) error {
 	args := m.Called(post)
 	return args.Error(0)
@@ -70,6 +104,14 @@ func (m *MockPostRepository) FindByID(id uint) (*postdomain.Post, error) {
 	return args.Get(0).(*postdomain.Post), args.Error(1)
 }
 
+func (m *MockPostRepository) FindVisibleByID(id, viewerID uint) (*postdomain.Post, error) {
+	args := m.Called(id, viewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postdomain.Post), args.Error(1)
+}
+
 func (m *MockPostRepository) Update(post *postdomain.Post) error {
 	args := m.Called(post)
 	return args.Error(0)
@@ -80,7 +122,31 @@ func (m *MockPostRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockPostRepository) FindByUserID(userID uint, limit, offset int) ([]postdomain.Post, int64, error) {
+func (m *MockPostRepository) FindByUserID(userID, viewerID uint, limit, offset int) ([]postdomain.Post, int64, error) {
+	args := m.Called(userID, viewerID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]postdomain.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPostRepository) FindByIDs(ids []uint, viewerID uint) ([]postdomain.Post, error) {
+	args := m.Called(ids, viewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) FindTimeline(userIDs []uint, viewerID uint, before *postdomain.TimelineEntry, limit int) ([]postdomain.TimelineEntry, error) {
+	args := m.Called(userIDs, viewerID, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.TimelineEntry), args.Error(1)
+}
+
+func (m *MockPostRepository) FindUnpublishedByUserID(userID uint, limit, offset int) ([]postdomain.Post, int64, error) {
 	args := m.Called(userID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(int64), args.Error(2)
@@ -88,6 +154,105 @@ func (m *MockPostRepository) FindByUserID(userID uint, limit, offset int) ([]pos
 	return args.Get(0).([]postdomain.Post), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockPostRepository) Publish(id uint, at time.Time) (bool, error) {
+	args := m.Called(id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPostRepository) SetModerationState(id uint, state string) error {
+	args := m.Called(id, state)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) SyncTags(postID uint, names []string) error {
+	args := m.Called(postID, names)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) SyncMentions(postID uint, mentions []postdomain.PostMention) ([]postdomain.PostMention, error) {
+	args := m.Called(postID, mentions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.PostMention), args.Error(1)
+}
+
+func (m *MockPostRepository) FindByTag(name string, viewerID uint, page pagination.CursorPagination) ([]postdomain.Post, error) {
+	args := m.Called(name, viewerID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) CreateAttachment(a *postdomain.Attachment) error {
+	args := m.Called(a)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) FindAttachmentsByIDs(ids []uint) ([]postdomain.Attachment, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Attachment), args.Error(1)
+}
+
+func (m *MockPostRepository) SetAttachments(postID uint, ids []uint) error {
+	args := m.Called(postID, ids)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) FindPostAttachments(postID uint) ([]postdomain.Attachment, error) {
+	args := m.Called(postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Attachment), args.Error(1)
+}
+
+func (m *MockPostRepository) DeletePostAttachments(postID uint) ([]postdomain.Attachment, error) {
+	args := m.Called(postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Attachment), args.Error(1)
+}
+
+func (m *MockPostRepository) DeleteAttachments(ids []uint) error {
+	args := m.Called(ids)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) CreateRevision(r *postdomain.Revision) error {
+	args := m.Called(r)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) FindRevision(id uint) (*postdomain.Revision, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postdomain.Revision), args.Error(1)
+}
+
+func (m *MockPostRepository) FindRevisions(postID uint, page pagination.CursorPagination) ([]postdomain.Revision, error) {
+	args := m.Called(postID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Revision), args.Error(1)
+}
+
+func (m *MockPostRepository) DeleteRevisions(postID uint) ([]postdomain.Revision, error) {
+	args := m.Called(postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]postdomain.Revision), args.Error(1)
+}
+
 // MockRefreshTokenRepository is a mock implementation of RefreshTokenRepository
 // Note: RefreshToken operations are now part of user.Repository interface
 type MockRefreshTokenRepository struct {
@@ -107,6 +272,14 @@ func (m *MockRefreshTokenRepository) FindRefreshTokenByToken(token string) (*use
 	return args.Get(0).(*user.RefreshToken), args.Error(1)
 }
 
+func (m *MockRefreshTokenRepository) FindRevokedRefreshToken(token string) (*user.RefreshToken, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.RefreshToken), args.Error(1)
+}
+
 func (m *MockRefreshTokenRepository) RevokeRefreshToken(token string) error {
 	args := m.Called(token)
 	return args.Error(0)
@@ -3,6 +3,7 @@ package mocks
 import (
 	postdomain "starter-gofiber/internal/domain/post"
 	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/pkg/pagination"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -12,8 +13,8 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) Register(req *user.RegisterRequest) error {
-	args := m.Called(req)
+func (m *MockAuthService) Register(req *user.RegisterRequest, ipAddress, userAgent string) error {
+	args := m.Called(req, ipAddress, userAgent)
 	return args.Error(0)
 }
 
@@ -48,13 +49,13 @@ func (m *MockAuthService) ForgotPassword(req *user.ForgotPasswordRequest) error
 	return args.Error(0)
 }
 
-func (m *MockAuthService) ResetPassword(req *user.ResetPasswordRequest) error {
-	args := m.Called(req)
+func (m *MockAuthService) ResetPassword(req *user.ResetPasswordRequest, ipAddress, userAgent string) error {
+	args := m.Called(req, ipAddress, userAgent)
 	return args.Error(0)
 }
 
-func (m *MockAuthService) ChangePassword(userID uint, req *user.ChangePasswordRequest) error {
-	args := m.Called(userID, req)
+func (m *MockAuthService) ChangePassword(userID uint, req *user.ChangePasswordRequest, ipAddress, userAgent string) error {
+	args := m.Called(userID, req, ipAddress, userAgent)
 	return args.Error(0)
 }
 
@@ -86,14 +87,30 @@ type MockPostService struct {
 	mock.Mock
 }
 
-func (m *MockPostService) FindAll(page, limit int) ([]postdomain.PostResponse, *postdomain.PaginationMeta, error) {
-	args := m.Called(page, limit)
+func (m *MockPostService) FindAll(viewerID uint, page, limit int) ([]postdomain.PostResponse, *postdomain.PaginationMeta, error) {
+	args := m.Called(viewerID, page, limit)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(*postdomain.PaginationMeta), args.Error(2)
 	}
 	return args.Get(0).([]postdomain.PostResponse), args.Get(1).(*postdomain.PaginationMeta), args.Error(2)
 }
 
+func (m *MockPostService) List(filter postdomain.ListFilter, viewerID uint, page pagination.CursorPagination) (*pagination.CursorResponse, error) {
+	args := m.Called(filter, viewerID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.CursorResponse), args.Error(1)
+}
+
+func (m *MockPostService) FindBySlug(slug, author string, viewerID uint) (*postdomain.PostResponse, error) {
+	args := m.Called(slug, author, viewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postdomain.PostResponse), args.Error(1)
+}
+
 func (m *MockPostService) Create(req *postdomain.PostRequest, userID uint) (*postdomain.PostResponse, error) {
 	args := m.Called(req, userID)
 	if args.Get(0) == nil {
@@ -102,8 +119,8 @@ func (m *MockPostService) Create(req *postdomain.PostRequest, userID uint) (*pos
 	return args.Get(0).(*postdomain.PostResponse), args.Error(1)
 }
 
-func (m *MockPostService) FindByID(id uint) (*postdomain.PostResponse, error) {
-	args := m.Called(id)
+func (m *MockPostService) FindByID(id uint, viewerID uint) (*postdomain.PostResponse, error) {
+	args := m.Called(id, viewerID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -123,8 +140,8 @@ func (m *MockPostService) Delete(id uint, userID uint) error {
 	return args.Error(0)
 }
 
-func (m *MockPostService) FindByUserID(userID uint, page, limit int) ([]postdomain.PostResponse, *postdomain.PaginationMeta, error) {
-	args := m.Called(userID, page, limit)
+func (m *MockPostService) FindByUserID(userID, viewerID uint, page, limit int) ([]postdomain.PostResponse, *postdomain.PaginationMeta, error) {
+	args := m.Called(userID, viewerID, page, limit)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(*postdomain.PaginationMeta), args.Error(2)
 	}
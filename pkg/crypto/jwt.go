@@ -68,9 +68,11 @@ func GetUserFromToken(c *fiber.Ctx) (*user.CustomClaims, error) {
 func GenerateJWT(userClaims user.UserClaims) (string, error) {
 	// Create the Claims
 	claims := user.CustomClaims{
-		ID:    userClaims.ID,
-		Email: userClaims.Email,
-		Role:  userClaims.Role,
+		ID:            userClaims.ID,
+		Email:         userClaims.Email,
+		Role:          userClaims.Role,
+		EmailVerified: userClaims.EmailVerified,
+		RegisteredAt:  userClaims.RegisteredAt,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 1)), // Short-lived: 1 hour
 			Issuer:    "Starter-Gofiber",
@@ -87,9 +89,11 @@ func GenerateJWT(userClaims user.UserClaims) (string, error) {
 func GenerateRefreshToken(userClaims user.UserClaims) (string, error) {
 	// Create the Claims for refresh token
 	claims := user.CustomClaims{
-		ID:    userClaims.ID,
-		Email: userClaims.Email,
-		Role:  userClaims.Role,
+		ID:            userClaims.ID,
+		Email:         userClaims.Email,
+		Role:          userClaims.Role,
+		EmailVerified: userClaims.EmailVerified,
+		RegisteredAt:  userClaims.RegisteredAt,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 30)), // Long-lived: 30 days
 			Issuer:    "Starter-Gofiber-Refresh",
@@ -17,6 +17,8 @@ const (
 	AuditActionUpdate  AuditAction = "UPDATE"
 	AuditActionDelete  AuditAction = "DELETE"
 	AuditActionRestore AuditAction = "RESTORE"
+	// AuditActionModerate is a moderator decision such as hiding content or suspending a user
+	AuditActionModerate AuditAction = "MODERATE"
 )
 
 // AuditLog tracks all data changes in the system
@@ -28,7 +30,7 @@ type AuditLog struct {
 	EntityID   uint   `gorm:"not null;index" json:"entity_id"`                     // ID of the affected record
 
 	// Action details
-	Action      AuditAction `gorm:"type:varchar(20);not null;index" json:"action"` // CREATE, UPDATE, DELETE, RESTORE
+	Action      AuditAction `gorm:"type:varchar(20);not null;index" json:"action"` // CREATE, UPDATE, DELETE, RESTORE, MODERATE
 	Description string      `gorm:"type:text" json:"description"`                  // Human-readable description
 
 	// Change tracking
@@ -205,6 +207,29 @@ func (a *AuditLogger) LogRestore(entityType string, entityID uint, data interfac
 	return a.db.Create(&auditLog).Error
 }
 
+// LogModeration logs a MODERATE operation
+func (a *AuditLogger) LogModeration(entityType string, entityID uint, description string, data interface{}) error {
+	newValues, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	auditLog := AuditLog{
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Action:      AuditActionModerate,
+		Description: description,
+		NewValues:   string(newValues),
+		UserID:      a.userID,
+		Username:    a.username,
+		IPAddress:   a.ipAddress,
+		UserAgent:   a.userAgent,
+		RequestID:   a.requestID,
+	}
+
+	return a.db.Create(&auditLog).Error
+}
+
 // getChangedFields compares two structs and returns changed field names
 func getChangedFields(oldData, newData interface{}) []string {
 	var changes []string
@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"reflect"
 
 	"starter-gofiber/pkg/apierror"
 
@@ -81,8 +82,9 @@ func BulkCreateWithValidation(db *gorm.DB, records []interface{}) *BulkCreateRes
 			})
 		} else {
 			result.SuccessCount++
-			// Get ID using reflection if needed
-			// result.CreatedIDs = append(result.CreatedIDs, id)
+			if id := getEntityID(reflect.ValueOf(record)); id > 0 {
+				result.CreatedIDs = append(result.CreatedIDs, id)
+			}
 		}
 	}
 
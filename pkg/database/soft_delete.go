@@ -1,6 +1,8 @@
 package database
 
 import (
+	"reflect"
+
 	"gorm.io/gorm"
 )
 
@@ -59,3 +61,64 @@ func IsTrashed(db *gorm.DB, model interface{}, id interface{}) (bool, error) {
 	err := db.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&count).Error
 	return count > 0, err
 }
+
+// SoftDeleteHook runs inside the transaction of a soft delete with the IDs of the deleted rows
+type SoftDeleteHook func(tx *gorm.DB, ids []uint) error
+
+var softDeleteHooks = map[string][]SoftDeleteHook{}
+
+// OnSoftDelete registers a hook for soft deletes of the table, e.g. to clean up rows referencing the deleted ones.
+// Hooks only run once RegisterSoftDeleteCallbacks was called on the connection.
+func OnSoftDelete(table string, hook SoftDeleteHook) {
+	softDeleteHooks[table] = append(softDeleteHooks[table], hook)
+}
+
+// RegisterSoftDeleteCallbacks runs the OnSoftDelete hooks after soft deletes, a failing hook rolls the delete back.
+// Only deletes keyed by the model's primary key are seen, bulk deletes (e.g. Where(...).Delete) and
+// permanent deletes are left to their callers.
+func RegisterSoftDeleteCallbacks(db *gorm.DB) error {
+	return db.Callback().Delete().After("gorm:delete").Register("soft_delete:hooks", runSoftDeleteHooks)
+}
+
+func runSoftDeleteHooks(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Unscoped || db.RowsAffected == 0 {
+		return
+	}
+	hooks := softDeleteHooks[db.Statement.Schema.Table]
+	if len(hooks) == 0 || db.Statement.Schema.LookUpField("DeletedAt") == nil {
+		return
+	}
+
+	ids := deletedIDs(db)
+	if len(ids) == 0 {
+		return
+	}
+	// A new session on the same connection, which is the delete's transaction
+	tx := db.Session(&gorm.Session{NewDB: true})
+	for _, hook := range hooks {
+		if err := hook(tx, ids); err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+}
+
+// deletedIDs collects the non-zero IDs of the statement's model, a struct or a slice of structs
+func deletedIDs(db *gorm.DB) []uint {
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Struct:
+		if id := getEntityID(rv); id > 0 {
+			return []uint{id}
+		}
+	case reflect.Slice, reflect.Array:
+		var ids []uint
+		for i := 0; i < rv.Len(); i++ {
+			if id := getEntityID(rv.Index(i)); id > 0 {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	return nil
+}
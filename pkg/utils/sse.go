@@ -240,6 +240,17 @@ func (h *SSEHub) GetUserClientCount(userID uint) int {
 	return len(h.userIndex[userID])
 }
 
+// ConnectedUserIDs returns the users with at least one connection
+func (h *SSEHub) ConnectedUserIDs() []uint {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	ids := make([]uint, 0, len(h.userIndex))
+	for id := range h.userIndex {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // SSEHandler handles SSE connections
 func SSEHandler(c *fiber.Ctx, userID uint) error {
 	// Set SSE headers
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	analyticsService "starter-gofiber/internal/service/analytics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewAnalyticsService builds the analytics service shared by the post router, which records views
+func NewAnalyticsService() analytics.Service {
+	return analyticsService.NewAnalyticsService(
+		postgres.NewAnalyticsRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+}
+
+func NewAnalyticsRouter(app fiber.Router, s analytics.Service) {
+	h := http.NewAnalyticsHandler(s, config.Enforcer)
+
+	// Ownership is checked by the service, admins may read the stats of any post
+	app.Get("/posts/:id/stats", middleware.AuthMiddleware(), h.Stats)
+}
@@ -2,6 +2,8 @@ package router
 
 import (
 	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/domain/security"
 	"starter-gofiber/internal/handler/http"
 	"starter-gofiber/internal/handler/middleware"
 	"starter-gofiber/internal/repository/postgres"
@@ -11,10 +13,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func NewAuthentication(app fiber.Router, enforcer *casbin.Enforcer) {
+func NewAuthentication(app fiber.Router, enforcer *casbin.Enforcer, consentS consent.Service, securityS security.Service) {
 	userRepo := postgres.NewUserRepository(config.DB)
 
-	s := auth.NewAuthService(userRepo)
+	s := auth.NewAuthService(userRepo, consentS, securityS)
 	h := http.NewAuthHandler(s)
 
 	// Public routes (no authentication required)
@@ -28,9 +30,25 @@ func NewAuthentication(app fiber.Router, enforcer *casbin.Enforcer) {
 	auth.Post("/resend-verification", h.ResendVerificationEmail)
 
 	// Protected routes (authentication required)
+	middleware.InitSuspensionCheck(func(userID uint) (bool, error) {
+		u, err := userRepo.FindByID(userID)
+		if err != nil {
+			return false, err
+		}
+		return u.SuspendedAt != nil, nil
+	})
 	authMiddleware := middleware.AuthMiddleware()
-	auth.Post("/logout", authMiddleware, h.Logout)
-	auth.Post("/logout-all", authMiddleware, h.LogoutAll)
+
+	// Cookie-session routes for browser clients (SESSION_AUTH_ENABLE)
+	if store := middleware.GetSessionStore(); store != nil {
+		middleware.InitSessionUserLoader(userRepo.FindByID)
+		auth.Post("/session/login", h.SessionLogin(store))
+		auth.Post("/session/logout", middleware.ConsentExempt(), middleware.SessionAuth(), h.SessionLogout(store))
+		auth.Get("/session/csrf", middleware.SessionAuth(), h.SessionCSRFToken)
+	}
+
+	auth.Post("/logout", middleware.ConsentExempt(), authMiddleware, h.Logout)
+	auth.Post("/logout-all", middleware.ConsentExempt(), authMiddleware, h.LogoutAll)
 	auth.Post("/change-password", authMiddleware, h.ChangePassword)
 	auth.Get("/sessions", authMiddleware, h.GetActiveSessions)
 	auth.Delete("/sessions/:sessionId", authMiddleware, h.RevokeSession)
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	bookmarkService "starter-gofiber/internal/service/bookmark"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewBookmarkService builds the bookmark service shared by the routers listing posts
+func NewBookmarkService() bookmark.Service {
+	return bookmarkService.NewBookmarkService(
+		postgres.NewBookmarkRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+}
+
+func NewBookmarkRouter(app fiber.Router, s bookmark.Service, reactionS reaction.Service, pollS poll.Service) {
+	h := http.NewBookmarkHandler(s, reactionS, pollS)
+
+	authMiddleware := middleware.AuthMiddleware()
+
+	app.Put("/posts/:id/bookmark", authMiddleware, h.Bookmark)
+	app.Delete("/posts/:id/bookmark", authMiddleware, h.Unbookmark)
+
+	// Bookmarks are private, every route works on the current user's own
+	bookmarks := app.Group("/bookmarks", authMiddleware)
+	bookmarks.Get("", h.List)
+	bookmarks.Get("/collections", h.Collections)
+	bookmarks.Post("/collections", h.CreateCollection)
+	// Registered before /:id, which would match it
+	bookmarks.Put("/collections/order", h.ReorderCollections)
+	bookmarks.Put("/collections/:id", h.RenameCollection)
+	bookmarks.Delete("/collections/:id", h.DeleteCollection)
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/comment"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewCommentRouter(app fiber.Router) {
+	repo := postgres.NewCommentRepository(config.DB)
+	s := comment.NewCommentService(repo, postgres.NewPostRepository(config.DB))
+	h := http.NewCommentHandler(s, config.Enforcer)
+
+	authMiddleware := middleware.AuthMiddleware()
+
+	// Public routes, signed in viewers also see comments of posts shared with them
+	optionalAuth := middleware.OptionalAuthMiddleware()
+	app.Get("/posts/:id/comments", optionalAuth, h.List)
+	app.Get("/comments/:id/replies", optionalAuth, h.Replies)
+
+	// Ownership is checked in the service, "comment" permissions allow moderating others' comments
+	app.Post("/posts/:id/comments", authMiddleware, middleware.RequireVerifiedEmail("comment:create"), h.Create)
+	app.Put("/comments/:id", authMiddleware, h.Update)
+	app.Delete("/comments/:id", authMiddleware, h.Delete)
+}
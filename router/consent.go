@@ -0,0 +1,40 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	consentService "starter-gofiber/internal/service/consent"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewConsentService builds the consent service shared by the consent and auth routers
+func NewConsentService() consent.Service {
+	return consentService.NewConsentService(postgres.NewConsentRepository(config.DB))
+}
+
+func NewConsentRouter(app fiber.Router, s consent.Service) {
+	h := http.NewConsentHandler(s)
+
+	// Authenticated requests get CONSENT_REQUIRED until the current versions are accepted
+	middleware.InitConsentMiddleware(s.RequiresConsent)
+
+	consents := app.Group("/consent")
+
+	// Public routes
+	consents.Get("/current", h.Current)
+
+	// User routes, exempt from the consent check so users can re-accept
+	authMiddleware := middleware.AuthMiddleware()
+	consents.Get("/status", middleware.ConsentExempt(), authMiddleware, h.Status)
+	consents.Post("/accept", middleware.ConsentExempt(), authMiddleware, h.Accept)
+	consents.Put("/marketing", middleware.ConsentExempt(), authMiddleware, h.Marketing)
+
+	// Admin routes
+	authz := middleware.LoadAuthzMiddleware()
+	consents.Post("/versions", authMiddleware, authz.RequiresPermissions([]string{"consent:create"}), h.Publish)
+	consents.Get("/export", authMiddleware, authz.RequiresPermissions([]string{"consent:read"}), h.Export)
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/follow"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewFollowRouter(app fiber.Router, reactionS reaction.Service, bookmarkS bookmark.Service, pollS poll.Service) {
+	s := follow.NewFollowService(
+		postgres.NewFollowRepository(config.DB),
+		postgres.NewUserRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+	h := http.NewFollowHandler(s, reactionS, bookmarkS, pollS)
+
+	authMiddleware := middleware.AuthMiddleware()
+
+	users := app.Group("/users/:id")
+	users.Get("/followers", h.Followers)
+	users.Get("/following", h.Following)
+	users.Get("/follow-stats", middleware.OptionalAuthMiddleware(), h.Stats)
+	users.Post("/follow", authMiddleware, h.Follow)
+	users.Delete("/follow", authMiddleware, h.Unfollow)
+
+	app.Get("/timeline", authMiddleware, h.Timeline)
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/dataimport"
+	"starter-gofiber/internal/worker"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewImportRouter(app fiber.Router) {
+	s := dataimport.NewImportService(
+		postgres.NewImportRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+	h := http.NewImportHandler(s)
+
+	// The embedded worker processes queued imports through the same service
+	worker.SetImportRunner(s.Process)
+
+	authz := middleware.LoadAuthzMiddleware()
+
+	imports := app.Group("/imports", middleware.AuthMiddleware())
+	imports.Post("", authz.RequiresPermissions([]string{"import:create"}), h.Create)
+	imports.Get("", authz.RequiresPermissions([]string{"import:read"}), h.FindAll)
+	imports.Get("/:id", authz.RequiresPermissions([]string{"import:read"}), h.FindByID)
+	imports.Get("/:id/report", authz.RequiresPermissions([]string{"import:read"}), h.Report)
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/comment"
+	"starter-gofiber/internal/service/moderation"
+	"starter-gofiber/internal/service/post"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewModerationRouter(app fiber.Router) {
+	repo := postgres.NewModerationRepository(config.DB)
+	postRepo := postgres.NewPostRepository(config.DB)
+	commentRepo := postgres.NewCommentRepository(config.DB)
+	userRepo := postgres.NewUserRepository(config.DB)
+	s := moderation.NewModerationService(repo,
+		postRepo, post.NewPostService(postRepo, userRepo, repo),
+		commentRepo, comment.NewCommentService(commentRepo, postRepo),
+		userRepo)
+	h := http.NewModerationHandler(s)
+
+	authMiddleware := middleware.AuthMiddleware()
+	authz := middleware.LoadAuthzMiddleware()
+
+	// Any user can report, the queue is for moderators
+	app.Post("/reports", authMiddleware, h.Report)
+
+	queue := app.Group("/moderation/reports", authMiddleware)
+	queue.Get("", authz.RequiresPermissions([]string{"moderation:read"}), h.Queue)
+	queue.Get("/:id", authz.RequiresPermissions([]string{"moderation:read"}), h.GetByID)
+	queue.Put("/:id/assign", authz.RequiresPermissions([]string{"moderation:update"}), h.Assign)
+	queue.Post("/:id/resolve", authz.RequiresPermissions([]string{"moderation:update"}), h.Resolve)
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	pollService "starter-gofiber/internal/service/poll"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewPollService builds the poll service shared by the routers listing posts
+func NewPollService() poll.Service {
+	return pollService.NewPollService(
+		postgres.NewPollRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+}
+
+func NewPollRouter(app fiber.Router, s poll.Service) {
+	h := http.NewPollHandler(s)
+
+	app.Get("/posts/:id/poll", middleware.OptionalAuthMiddleware(), h.Find)
+	app.Post("/posts/:id/poll/votes", middleware.AuthMiddleware(), h.Vote)
+}
@@ -2,29 +2,60 @@ package router
 
 import (
 	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/reaction"
 	"starter-gofiber/internal/handler/http"
 	"starter-gofiber/internal/handler/middleware"
 	"starter-gofiber/internal/repository/postgres"
 	"starter-gofiber/internal/service/post"
+	"starter-gofiber/internal/worker"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func NewPostRouter(app fiber.Router) {
+func NewPostRouter(app fiber.Router, reactionS reaction.Service, bookmarkS bookmark.Service, pollS poll.Service, analyticsS analytics.Service) {
 	repo := postgres.NewPostRepository(config.DB)
-	s := post.NewPostService(repo)
-	h := http.NewPostHandler(s)
+	s := post.NewPostService(repo, postgres.NewUserRepository(config.DB), postgres.NewModerationRepository(config.DB))
+	h := http.NewPostHandler(s, reactionS, bookmarkS, pollS, analyticsS, config.Enforcer)
+	// Queued batches run through the same service, in this process's embedded worker
+	worker.SetPostBulkRunner(s.RunBulk)
 
 	posts := app.Group("/posts")
 
-	// Public routes
-	posts.Get("", h.All)
-	posts.Get("/:id", h.GetByID)
-
 	// Protected routes with JWT and authorization
 	authMiddleware := middleware.AuthMiddleware()
 	authz := middleware.LoadAuthzMiddleware()
+
+	// Registered before /:id, which would match it
+	posts.Get("/drafts", authMiddleware, authz.RequiresPermissions([]string{"post:create"}), h.Unpublished)
+	// Ownership is checked by the service, admins may manage every user's trash
+	posts.Get("/trash", authMiddleware, h.Trash)
+	// Permissions are checked per action by the handler, ownership per post by the service
+	posts.Post("/bulk", authMiddleware, h.Bulk)
+	posts.Get("/bulk/:jobId", authMiddleware, h.BulkJob)
+
+	// Public routes, personalised when the request is authenticated
+	optionalAuth := middleware.OptionalAuthMiddleware()
+	posts.Get("", optionalAuth, h.All)
+	posts.Get("/by-slug/:slug", optionalAuth, h.BySlug)
+	posts.Get("/:id", optionalAuth, h.GetByID)
+	posts.Get("/:id/revisions", optionalAuth, h.Revisions)
+	posts.Get("/:id/quotes", optionalAuth, h.Quotes)
+	app.Get("/tags/:tag/posts", optionalAuth, h.ByTag)
+	app.Get("/users/:id/posts", optionalAuth, h.ByUser)
+
+	// Writes
 	posts.Post("", authMiddleware, authz.RequiresPermissions([]string{"post:create"}), h.Create)
 	posts.Put("/:id", authMiddleware, authz.RequiresPermissions([]string{"post:update"}), h.Update)
+	// Ownership is checked by the service, admins may restore any post
+	posts.Post("/:id/revisions/:revisionId/restore", authMiddleware, h.RestoreRevision)
 	posts.Delete("/:id", authMiddleware, authz.RequiresPermissions([]string{"post:delete"}), h.Delete)
+	posts.Post("/:id/restore", authMiddleware, h.Restore)
+	posts.Delete("/:id/permanent", authMiddleware, h.PermanentDelete)
+
+	// Uploads for attachment_ids, creating a post is the permission that matters
+	posts.Post("/attachments", authMiddleware, authz.RequiresPermissions([]string{"post:create"}), h.UploadAttachment)
+	posts.Delete("/attachments/:id", authMiddleware, authz.RequiresPermissions([]string{"post:create"}), h.DeleteAttachment)
 }
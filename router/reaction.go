@@ -0,0 +1,32 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	reactionService "starter-gofiber/internal/service/reaction"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewReactionService builds the reaction service shared by the post and reaction routers
+func NewReactionService() reaction.Service {
+	return reactionService.NewReactionService(
+		postgres.NewReactionRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+}
+
+func NewReactionRouter(app fiber.Router, s reaction.Service) {
+	h := http.NewReactionHandler(s)
+
+	reactions := app.Group("/posts/:id/reactions")
+	authMiddleware := middleware.AuthMiddleware()
+
+	reactions.Get("", middleware.OptionalAuthMiddleware(), h.Summary)
+	reactions.Get("/users", middleware.OptionalAuthMiddleware(), h.Reactors)
+	reactions.Put("", authMiddleware, h.React)
+	reactions.Delete("", authMiddleware, h.Unreact)
+}
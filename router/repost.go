@@ -0,0 +1,27 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	repostService "starter-gofiber/internal/service/repost"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewRepostRouter(app fiber.Router, reactionS reaction.Service, pollS poll.Service) {
+	s := repostService.NewRepostService(
+		postgres.NewRepostRepository(config.DB),
+		postgres.NewPostRepository(config.DB),
+	)
+	h := http.NewRepostHandler(s, reactionS, pollS)
+
+	authMiddleware := middleware.AuthMiddleware()
+
+	app.Put("/posts/:id/repost", authMiddleware, h.Repost)
+	app.Delete("/posts/:id/repost", authMiddleware, h.Unrepost)
+	app.Get("/users/:id/reposts", middleware.OptionalAuthMiddleware(), h.ByUser)
+}
@@ -4,6 +4,7 @@ import (
 	"starter-gofiber/internal/config"
 	"starter-gofiber/internal/handler/http"
 	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/worker"
 	"starter-gofiber/pkg/dto"
 	"starter-gofiber/pkg/utils"
 	"starter-gofiber/variables"
@@ -25,6 +26,8 @@ func AppRouter(app *fiber.App) {
 			panic(err)
 		}
 	}
+	// Roles granted and revoked by the workers are applied to the enforcer of this process
+	worker.SetRoleEnforcer(config.Enforcer)
 
 	// Recover middleware for production
 	if config.ENV.ENV_TYPE != "dev" {
@@ -59,9 +62,27 @@ func AppRouter(app *fiber.App) {
 
 	// API routes
 	api := app.Group("/api")
+	consentS := NewConsentService()
+	NewConsentRouter(api, consentS)
+	securityS := NewSecurityService()
+	NewSecurityRouter(api, securityS)
 	auth := api.Group("/auth")
-	NewAuthentication(auth, config.Enforcer)
-	NewPostRouter(api)
+	NewAuthentication(auth, config.Enforcer, consentS, securityS)
+	reactionS := NewReactionService()
+	bookmarkS := NewBookmarkService()
+	pollS := NewPollService()
+	analyticsS := NewAnalyticsService()
+	NewPostRouter(api, reactionS, bookmarkS, pollS, analyticsS)
+	NewReactionRouter(api, reactionS)
+	NewBookmarkRouter(api, bookmarkS, reactionS, pollS)
+	NewRepostRouter(api, reactionS, pollS)
+	NewPollRouter(api, pollS)
+	NewAnalyticsRouter(api, analyticsS)
+	NewFollowRouter(api, reactionS, bookmarkS, pollS)
+	NewCommentRouter(api)
+	NewModerationRouter(api)
+	NewSearchRouter(api, reactionS, bookmarkS, pollS)
+	NewImportRouter(api)
 
 	// SSE routes
 	SSERouter(app)
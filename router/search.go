@@ -0,0 +1,28 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/search"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewSearchRouter(app fiber.Router, reactionS reaction.Service, bookmarkS bookmark.Service, pollS poll.Service) {
+	s := search.NewSearchService(
+		postgres.NewPostRepository(config.DB),
+		postgres.NewUserRepository(config.DB),
+		config.DB,
+	)
+	h := http.NewSearchHandler(s, reactionS, bookmarkS, pollS)
+
+	authz := middleware.LoadAuthzMiddleware()
+
+	app.Get("/search", middleware.OptionalAuthMiddleware(), h.Search)
+	app.Post("/search/reindex", middleware.AuthMiddleware(), authz.RequiresPermissions([]string{"search:update"}), h.Reindex)
+}
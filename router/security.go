@@ -0,0 +1,31 @@
+package router
+
+import (
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/handler/http"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	securityService "starter-gofiber/internal/service/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewSecurityService builds the security event service shared by the auth router and middleware
+func NewSecurityService() security.Service {
+	return securityService.NewSecurityService(postgres.NewSecurityEventRepository(config.DB))
+}
+
+func NewSecurityRouter(app fiber.Router, s security.Service) {
+	h := http.NewSecurityHandler(s)
+
+	// Permission denials and API key usage are recorded from middleware
+	middleware.InitSecurityEvents(s)
+
+	sec := app.Group("/security")
+	authMiddleware := middleware.AuthMiddleware()
+	authz := middleware.LoadAuthzMiddleware()
+
+	sec.Get("/activity", authMiddleware, h.Activity)
+	sec.Get("/events", authMiddleware, authz.RequiresPermissions([]string{"security:read"}), h.Events)
+}
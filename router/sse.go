@@ -3,12 +3,15 @@ package router
 import (
 	"starter-gofiber/internal/handler/http"
 	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/worker"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func SSERouter(app *fiber.App) {
 	sseHandler := http.NewSSEHandler()
+	// Progress of background jobs reaches users through this process's SSE clients
+	worker.SetProgressNotifier(sseHandler.NotifyProgress)
 
 	// SSE endpoint (requires authentication)
 	app.Get("/sse/stream", middleware.AuthMiddleware(), sseHandler.Connect)
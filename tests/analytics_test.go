@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/variables"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnalyticsTestSuite struct {
+	suite.Suite
+	author *user.User
+	viewer *user.User
+	post   *post.Post
+}
+
+func TestAnalyticsTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalyticsTestSuite))
+}
+
+func (s *AnalyticsTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	worker.SetDB(testDB)
+}
+
+func (s *AnalyticsTestSuite) TearDownSuite() {
+	worker.SetDB(nil)
+	CleanupTestDB()
+}
+
+func (s *AnalyticsTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM post_daily_stats")
+	testDB.Exec("DELETE FROM post_views")
+	testDB.Exec("DELETE FROM reactions")
+	testDB.Exec("DELETE FROM comments")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.viewer = CreateTestUser(testDB, "viewer@example.com", "hash", "user")
+	s.post = &post.Post{Tweet: "Counting views", UserID: s.author.ID}
+	s.Require().NoError(testDB.Create(s.post).Error)
+}
+
+func (s *AnalyticsTestSuite) open(u *user.User) {
+	resp, _, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", s.post.ID), nil, AuthHeader(u))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *AnalyticsTestSuite) stats(u *user.User, query string) (int, analytics.PostStatsResponse) {
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/stats%s", s.post.ID, query), nil, AuthHeader(u))
+	s.Require().NoError(err)
+	var result struct {
+		Data analytics.PostStatsResponse `json:"data"`
+	}
+	if resp.StatusCode == http.StatusOK {
+		ParseJSON(s.T(), raw, &result)
+	}
+	return resp.StatusCode, result.Data
+}
+
+func (s *AnalyticsTestSuite) rollup() {
+	s.Require().NoError(worker.HandleRollupPostStats(context.Background(), asynq.NewTask(worker.TaskRollupPostStats, nil)))
+}
+
+func (s *AnalyticsTestSuite) TestViews_RolledUpWithActivity() {
+	// Repeated opens within the window and the author's own opens are not counted
+	s.open(s.viewer)
+	s.open(s.viewer)
+	s.open(nil)
+	s.open(s.author)
+
+	s.Require().NoError(testDB.Create(&reaction.Reaction{PostID: s.post.ID, UserID: s.viewer.ID, Type: reaction.TypeLike}).Error)
+	s.Require().NoError(testDB.Create(&comment.Comment{PostID: s.post.ID, UserID: s.viewer.ID, Body: "Nice"}).Error)
+
+	// Stats only change with the roll-up, which can run again without counting twice
+	code, before := s.stats(s.author, "")
+	s.Require().Equal(http.StatusOK, code)
+	s.Equal(0, before.Totals.Views)
+
+	s.rollup()
+	s.rollup()
+
+	code, stats := s.stats(s.author, "")
+	s.Require().Equal(http.StatusOK, code)
+	s.Len(stats.Days, analytics.DefaultRangeDays)
+	today := stats.Days[len(stats.Days)-1]
+	s.Equal(time.Now().UTC().Format(analytics.DayFormat), today.Day)
+	s.Equal(2, today.Views)
+	s.Equal(2, today.UniqueViewers)
+	s.Equal(1, today.Reactions)
+	s.Equal(1, today.Comments)
+	s.Equal(analytics.TotalsResponse{Views: 2, Reactions: 1, Comments: 1}, stats.Totals)
+}
+
+func (s *AnalyticsTestSuite) TestRecordView_Window() {
+	repo := postgres.NewAnalyticsRepository(testDB)
+	at := analytics.Day(time.Now()).Add(time.Hour)
+
+	for _, c := range []struct {
+		after   time.Duration
+		counted bool
+	}{
+		{0, true},
+		{10 * time.Minute, false},
+		{analytics.ViewWindow + time.Minute, true},
+		{analytics.ViewWindow + 2*time.Minute, false},
+	} {
+		counted, err := repo.RecordView(s.post.ID, "u:1", at.Add(c.after), analytics.ViewWindow)
+		s.Require().NoError(err)
+		s.Equal(c.counted, counted, "view after %s", c.after)
+	}
+
+	stats, err := repo.CountViews(analytics.Day(at))
+	s.Require().NoError(err)
+	s.Require().Len(stats, 1)
+	s.Equal(2, stats[0].Views)
+	s.Equal(1, stats[0].UniqueViewers)
+}
+
+func (s *AnalyticsTestSuite) TestStats_Access() {
+	code, _ := s.stats(s.viewer, "")
+	s.Equal(http.StatusForbidden, code)
+
+	code, _ = s.stats(nil, "")
+	s.Equal(http.StatusUnauthorized, code)
+
+	admin := CreateTestUser(testDB, "admin@example.com", "hash", variables.ADMIN_ROLE)
+	_, err := config.Enforcer.AddRoleForUser(admin.Email, variables.ADMIN_ROLE)
+	s.Require().NoError(err)
+	defer config.Enforcer.DeleteRoleForUser(admin.Email, variables.ADMIN_ROLE)
+	code, _ = s.stats(admin, "")
+	s.Equal(http.StatusOK, code)
+
+	code, stats := s.stats(s.author, "?from=2026-01-30&to=2026-02-02")
+	s.Require().Equal(http.StatusOK, code)
+	s.Equal("2026-01-30", stats.From)
+	s.Len(stats.Days, 4)
+
+	code, _ = s.stats(s.author, "?from=2026-02-02&to=2026-01-30")
+	s.Equal(http.StatusBadRequest, code)
+	code, _ = s.stats(s.author, "?from=2024-01-01&to=2026-01-01")
+	s.Equal(http.StatusBadRequest, code)
+}
+
+func (s *AnalyticsTestSuite) TestStats_Export() {
+	s.open(s.viewer)
+	s.rollup()
+
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/stats?format=csv", s.post.ID), nil, AuthHeader(s.author))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get("Content-Disposition"), "attachment")
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	s.Len(lines, analytics.DefaultRangeDays+1)
+	s.Equal("Day,Views,UniqueViewers,Reactions,Comments", lines[0])
+	s.Equal(time.Now().UTC().Format(analytics.DayFormat)+",1,1,0,0", lines[len(lines)-1])
+
+	resp, _, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/stats?format=xml", s.post.ID), nil, AuthHeader(s.author))
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/infrastructure/auth"
+	"starter-gofiber/internal/repository/postgres"
+	consentService "starter-gofiber/internal/service/consent"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/crypto"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	apiKey string
+	keyID  uint
+}
+
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
+
+func (s *APIKeyTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	s.Require().NoError(crypto.InitEncryption("test-encryption-key"))
+	middleware.InitAPIKeyMiddleware(testDB)
+
+	s.app = fiber.New(fiber.Config{ErrorHandler: apierror.ErrorHelper})
+	s.app.Post("/integration", middleware.APIKeyAuth(), func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"user_id": c.Locals("api_user_id"), "signed": c.Locals("api_key_signed")})
+	})
+}
+
+func (s *APIKeyTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *APIKeyTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM api_keys")
+	testDB.Exec("DELETE FROM users")
+
+	u := CreateTestUser(testDB, "apikey@example.com", "hashed", "user")
+	key, err := auth.GenerateAPIKey(testDB, u.ID, "integration")
+	s.Require().NoError(err)
+	s.apiKey = key
+
+	testDB.Table("api_keys").Select("id").Where("user_id = ?", u.ID).Scan(&s.keyID)
+}
+
+func (s *APIKeyTestSuite) request(body, authHeader string) int {
+	req := httptest.NewRequest("POST", "/integration?source=test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", s.apiKey)
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+
+	resp, err := s.app.Test(req, -1)
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+func (s *APIKeyTestSuite) signedHeader(secret, body string, ts int64, nonce string) string {
+	signature := auth.SignRequest(secret, "POST", "/integration?source=test", []byte(body), ts, nonce)
+	return fmt.Sprintf("HMAC timestamp=%d,nonce=%s,signature=%s", ts, nonce, signature)
+}
+
+func (s *APIKeyTestSuite) TestUnsignedRequest_AllowedWhenNotRequired() {
+	s.Equal(200, s.request(`{}`, ""))
+}
+
+func (s *APIKeyTestSuite) TestUnsignedRequest_RejectedWhenRequired() {
+	_, err := auth.GenerateSigningSecret(testDB, s.keyID, true)
+	s.Require().NoError(err)
+
+	s.Equal(401, s.request(`{}`, ""))
+}
+
+func (s *APIKeyTestSuite) TestSignedRequest_Success() {
+	secret, err := auth.GenerateSigningSecret(testDB, s.keyID, true)
+	s.Require().NoError(err)
+
+	body := `{"event":"sync"}`
+	s.Equal(200, s.request(body, s.signedHeader(secret, body, time.Now().Unix(), "nonce-1")))
+}
+
+func (s *APIKeyTestSuite) TestSignedRequest_TamperedBody() {
+	secret, err := auth.GenerateSigningSecret(testDB, s.keyID, true)
+	s.Require().NoError(err)
+
+	header := s.signedHeader(secret, `{"amount":1}`, time.Now().Unix(), "nonce-2")
+	s.Equal(401, s.request(`{"amount":1000}`, header))
+}
+
+func (s *APIKeyTestSuite) TestSignedRequest_OutsideClockSkew() {
+	secret, err := auth.GenerateSigningSecret(testDB, s.keyID, true)
+	s.Require().NoError(err)
+
+	body := `{}`
+	stale := time.Now().Add(-time.Hour).Unix()
+	s.Equal(401, s.request(body, s.signedHeader(secret, body, stale, "nonce-3")))
+}
+
+func (s *APIKeyTestSuite) TestConsentRequired_BlocksKeyOwner() {
+	service := consentService.NewConsentService(postgres.NewConsentRepository(testDB))
+	_, err := service.PublishVersion(&consent.PublishPolicyRequest{Type: "tos", Version: "1.0"})
+	s.Require().NoError(err)
+	defer testDB.Exec("DELETE FROM policy_versions")
+
+	s.Equal(403, s.request(`{}`, ""))
+}
+
+func (s *APIKeyTestSuite) TestSuspendedOwner_Rejected() {
+	testDB.Table("users").Where("email = ?", "apikey@example.com").Update("suspended_at", time.Now())
+
+	s.Equal(403, s.request(`{}`, ""))
+}
+
+func (s *APIKeyTestSuite) TestParseHMACHeader_Malformed() {
+	_, err := auth.ParseHMACHeader("HMAC timestamp=abc,nonce=x,signature=y")
+	s.ErrorIs(err, auth.ErrMalformedSignature)
+
+	_, err = auth.ParseHMACHeader("Bearer token")
+	s.ErrorIs(err, auth.ErrMalformedSignature)
+}
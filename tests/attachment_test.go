@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"testing"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/variables"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AttachmentTestSuite struct {
+	suite.Suite
+	service post.Service
+	author  *user.User
+	other   *user.User
+}
+
+func TestAttachmentTestSuite(t *testing.T) {
+	suite.Run(t, new(AttachmentTestSuite))
+}
+
+func (s *AttachmentTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *AttachmentTestSuite) TearDownSuite() {
+	CleanupTestDB()
+	os.RemoveAll("./public" + variables.ATTACHMENT_PATH)
+}
+
+func (s *AttachmentTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM attachments")
+	testDB.Exec("DELETE FROM post_revisions")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+}
+
+// fileHeader builds an uploaded file the way Fiber parses a multipart form
+func (s *AttachmentTestSuite) fileHeader(name string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", name)
+	s.Require().NoError(err)
+	_, err = part.Write(content)
+	s.Require().NoError(err)
+	s.Require().NoError(w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	s.Require().NoError(err)
+	return form.File["file"][0]
+}
+
+func (s *AttachmentTestSuite) png(width, height int) []byte {
+	var buf bytes.Buffer
+	s.Require().NoError(png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func (s *AttachmentTestSuite) upload(alt string, userID uint) *post.AttachmentResponse {
+	resp, err := s.service.UploadAttachment(s.fileHeader("photo.png", s.png(640, 480)), alt, userID)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *AttachmentTestSuite) TestUpload_ProcessesImage() {
+	resp := s.upload("  A blank canvas ", s.author.ID)
+
+	s.Equal(post.AttachmentImage, resp.Kind)
+	s.Equal("image/png", resp.MimeType)
+	s.Equal(640, resp.Width)
+	s.Equal(480, resp.Height)
+	s.Equal("A blank canvas", resp.Alt)
+	s.Require().NotNil(resp.ThumbnailURL)
+
+	var stored post.Attachment
+	s.Require().NoError(testDB.First(&stored, resp.ID).Error)
+	s.Nil(stored.PostID)
+	for _, path := range stored.Files() {
+		s.FileExists("./public" + path)
+	}
+}
+
+func (s *AttachmentTestSuite) TestUpload_RejectsMismatchedContent() {
+	// A PNG extension does not make a text file an image
+	_, err := s.service.UploadAttachment(s.fileHeader("photo.png", []byte("not an image at all")), "", s.author.ID)
+	s.Require().Error(err)
+	s.IsType(&apierror.BadRequestError{}, err)
+
+	var count int64
+	testDB.Model(&post.Attachment{}).Count(&count)
+	s.Zero(count)
+}
+
+func (s *AttachmentTestSuite) TestCreate_AttachesInOrder() {
+	first := s.upload("first", s.author.ID)
+	second := s.upload("second", s.author.ID)
+
+	created, err := s.service.Create(&post.PostRequest{
+		Tweet:         "Two pictures",
+		UserID:        s.author.ID,
+		AttachmentIDs: []uint{second.ID, first.ID},
+	}, s.author.ID)
+	s.Require().NoError(err)
+
+	s.Require().Len(created.Attachments, 2)
+	s.Equal(second.ID, created.Attachments[0].ID)
+	s.Equal(first.ID, created.Attachments[1].ID)
+
+	found, err := s.service.FindByID(created.ID, s.author.ID)
+	s.Require().NoError(err)
+	s.Require().Len(found.Attachments, 2)
+	s.Equal("second", found.Attachments[0].Alt)
+	s.Equal(640, found.Attachments[0].Width)
+}
+
+func (s *AttachmentTestSuite) TestCreate_RejectsForeignAndTooMany() {
+	foreign := s.upload("", s.other.ID)
+	_, err := s.service.Create(&post.PostRequest{Tweet: "Not mine", UserID: s.author.ID, AttachmentIDs: []uint{foreign.ID}}, s.author.ID)
+	s.Require().Error(err)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+
+	ids := make([]uint, 0, post.MaxAttachments()+1)
+	for i := 0; i <= post.MaxAttachments(); i++ {
+		ids = append(ids, s.upload("", s.author.ID).ID)
+	}
+	_, err = s.service.Create(&post.PostRequest{Tweet: "Too many", UserID: s.author.ID, AttachmentIDs: ids}, s.author.ID)
+	s.Require().Error(err)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+}
+
+func (s *AttachmentTestSuite) TestUpdate_DetachesUnlistedAttachments() {
+	kept := s.upload("kept", s.author.ID)
+	dropped := s.upload("dropped", s.author.ID)
+	created, err := s.service.Create(&post.PostRequest{Tweet: "Album", UserID: s.author.ID, AttachmentIDs: []uint{kept.ID, dropped.ID}}, s.author.ID)
+	s.Require().NoError(err)
+
+	var droppedEntity post.Attachment
+	s.Require().NoError(testDB.First(&droppedEntity, dropped.ID).Error)
+
+	// Omitting attachment_ids keeps them
+	updated, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, Tweet: "Album v2", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Len(updated.Attachments, 2)
+
+	updated, err = s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, Tweet: "Album v3", UserID: s.author.ID, AttachmentIDs: []uint{kept.ID}}, s.author.ID)
+	s.Require().NoError(err)
+	s.Require().Len(updated.Attachments, 1)
+	s.Equal(kept.ID, updated.Attachments[0].ID)
+
+	// Earlier revisions still show the dropped attachment
+	s.Require().NoError(testDB.First(&droppedEntity, dropped.ID).Error)
+	s.NotNil(droppedEntity.DetachedAt)
+	s.FileExists("./public" + droppedEntity.Path)
+
+	// Deleted posts keep their files in the trash, purging removes them
+	s.Require().NoError(s.service.Delete(created.ID, s.author.ID, false))
+	var count int64
+	testDB.Model(&post.Attachment{}).Where("id = ?", dropped.ID).Count(&count)
+	s.Equal(int64(1), count)
+	s.FileExists("./public" + droppedEntity.Path)
+
+	s.Require().NoError(s.service.PermanentDelete(created.ID, s.author.ID, false))
+	testDB.Model(&post.Attachment{}).Where("id = ?", dropped.ID).Count(&count)
+	s.Zero(count)
+	s.NoFileExists("./public" + droppedEntity.Path)
+}
+
+func (s *AttachmentTestSuite) TestDeleteAttachment_OnlyUnusedOwnUploads() {
+	unused := s.upload("", s.author.ID)
+	used := s.upload("", s.author.ID)
+	_, err := s.service.Create(&post.PostRequest{Tweet: "Used", UserID: s.author.ID, AttachmentIDs: []uint{used.ID}}, s.author.ID)
+	s.Require().NoError(err)
+
+	s.IsType(&apierror.NotFoundError{}, s.service.DeleteAttachment(unused.ID, s.other.ID))
+	s.IsType(&apierror.BadRequestError{}, s.service.DeleteAttachment(used.ID, s.author.ID))
+	s.NoError(s.service.DeleteAttachment(unused.ID, s.author.ID))
+}
@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	bookmarksvc "starter-gofiber/internal/service/bookmark"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BookmarkTestSuite struct {
+	suite.Suite
+	service bookmark.Service
+	author  *user.User
+	reader  *user.User
+}
+
+func TestBookmarkTestSuite(t *testing.T) {
+	suite.Run(t, new(BookmarkTestSuite))
+}
+
+func (s *BookmarkTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *BookmarkTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *BookmarkTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM bookmarks")
+	testDB.Exec("DELETE FROM bookmark_collections")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = bookmarksvc.NewBookmarkService(postgres.NewBookmarkRepository(testDB), postgres.NewPostRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.reader = CreateTestUser(testDB, "reader@example.com", "hash", "user")
+}
+
+func (s *BookmarkTestSuite) createPost(tweet string) *post.Post {
+	p := &post.Post{Tweet: tweet, UserID: s.author.ID}
+	s.Require().NoError(testDB.Create(p).Error)
+	return p
+}
+
+func (s *BookmarkTestSuite) bookmark(postID uint, collectionID *uint) *http.Response {
+	resp, _, err := MakeRequest(testApp, "PUT", fmt.Sprintf("/api/posts/%d/bookmark", postID),
+		bookmark.BookmarkRequest{CollectionID: collectionID}, AuthHeader(s.reader))
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *BookmarkTestSuite) list(query string) ([]post.PostResponse, pagination.CursorResponse) {
+	resp, raw, err := MakeRequest(testApp, "GET", "/api/bookmarks"+query, nil, AuthHeader(s.reader))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			pagination.CursorResponse
+			Data []post.PostResponse `json:"data"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	return result.Data.Data, result.Data.CursorResponse
+}
+
+func (s *BookmarkTestSuite) TestBookmark_ListsNewestFirstAndMarksPosts() {
+	first := s.createPost("First")
+	second := s.createPost("Second")
+	third := s.createPost("Third")
+	for _, p := range []*post.Post{second, first, third} {
+		s.Equal(http.StatusOK, s.bookmark(p.ID, nil).StatusCode)
+	}
+	// Bookmarking again is a no-op
+	s.Equal(http.StatusOK, s.bookmark(first.ID, nil).StatusCode)
+
+	posts, page := s.list("?limit=2")
+	s.Require().Len(posts, 2)
+	s.Equal(third.ID, posts[0].ID)
+	s.Equal(first.ID, posts[1].ID)
+	s.True(posts[0].Bookmarked)
+	s.True(page.HasMore)
+
+	posts, page = s.list("?limit=2&cursor=" + page.NextCursor)
+	s.Require().Len(posts, 1)
+	s.Equal(second.ID, posts[0].ID)
+	s.False(page.HasMore)
+
+	// Post listings carry the flag for the viewer only
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", first.ID), nil, AuthHeader(s.reader))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var found struct {
+		Data post.PostResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &found)
+	s.True(found.Data.Bookmarked)
+
+	_, raw, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", first.ID), nil, AuthHeader(s.author))
+	s.Require().NoError(err)
+	ParseJSON(s.T(), raw, &found)
+	s.False(found.Data.Bookmarked)
+
+	resp, _, err = MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/posts/%d/bookmark", first.ID), nil, AuthHeader(s.reader))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	posts, _ = s.list("")
+	s.Len(posts, 2)
+}
+
+func (s *BookmarkTestSuite) TestBookmark_UnpublishedPostNotFound() {
+	draft := &post.Post{Tweet: "Draft", UserID: s.author.ID, Status: post.StatusDraft}
+	s.Require().NoError(testDB.Create(draft).Error)
+
+	s.Equal(http.StatusNotFound, s.bookmark(draft.ID, nil).StatusCode)
+}
+
+func (s *BookmarkTestSuite) TestCollections_FileAndReorder() {
+	reading, err := s.service.CreateCollection(&bookmark.CollectionRequest{Name: "Reading"}, s.reader.ID)
+	s.Require().NoError(err)
+	recipes, err := s.service.CreateCollection(&bookmark.CollectionRequest{Name: " Recipes "}, s.reader.ID)
+	s.Require().NoError(err)
+	s.Equal("Recipes", recipes.Name)
+	s.Greater(recipes.Position, reading.Position)
+
+	_, err = s.service.CreateCollection(&bookmark.CollectionRequest{Name: "reading"}, s.reader.ID)
+	s.IsType(&apierror.BadRequestError{}, err)
+
+	// Collections of other users can't be used
+	other, err := s.service.CreateCollection(&bookmark.CollectionRequest{Name: "Mine"}, s.author.ID)
+	s.Require().NoError(err)
+	p := s.createPost("Pancakes")
+	s.Equal(http.StatusNotFound, s.bookmark(p.ID, &other.ID).StatusCode)
+
+	s.Equal(http.StatusOK, s.bookmark(p.ID, &recipes.ID).StatusCode)
+	s.Equal(http.StatusOK, s.bookmark(s.createPost("Novel").ID, &reading.ID).StatusCode)
+	s.Equal(http.StatusOK, s.bookmark(s.createPost("Unfiled").ID, nil).StatusCode)
+
+	posts, _ := s.list(fmt.Sprintf("?collection_id=%d", recipes.ID))
+	s.Require().Len(posts, 1)
+	s.Equal(p.ID, posts[0].ID)
+
+	_, err = s.service.ReorderCollections(&bookmark.ReorderRequest{IDs: []uint{recipes.ID}}, s.reader.ID)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+	ordered, err := s.service.ReorderCollections(&bookmark.ReorderRequest{IDs: []uint{recipes.ID, reading.ID}}, s.reader.ID)
+	s.Require().NoError(err)
+	s.Require().Len(ordered, 2)
+	s.Equal(recipes.ID, ordered[0].ID)
+	s.Equal(1, ordered[0].BookmarkCount)
+
+	// Deleting a collection keeps its bookmarks
+	s.Require().NoError(s.service.DeleteCollection(recipes.ID, s.reader.ID))
+	posts, _ = s.list("")
+	s.Len(posts, 3)
+	s.IsType(&apierror.NotFoundError{}, s.service.DeleteCollection(other.ID, s.reader.ID))
+}
+
+func (s *BookmarkTestSuite) TestDeletedPost_RemovesBookmarks() {
+	p := s.createPost("Soon gone")
+	kept := s.createPost("Staying")
+	s.Equal(http.StatusOK, s.bookmark(p.ID, nil).StatusCode)
+	s.Equal(http.StatusOK, s.bookmark(kept.ID, nil).StatusCode)
+
+	posts := postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.Require().NoError(posts.Delete(p.ID, s.author.ID, false))
+
+	var count int64
+	testDB.Model(&bookmark.Bookmark{}).Where("post_id = ?", p.ID).Count(&count)
+	s.Zero(count)
+	testDB.Model(&bookmark.Bookmark{}).Where("post_id = ?", kept.ID).Count(&count)
+	s.Equal(int64(1), count)
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/variables"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BulkTestSuite struct {
+	suite.Suite
+	posts  post.Service
+	author *user.User
+	other  *user.User
+}
+
+func TestBulkTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkTestSuite))
+}
+
+func (s *BulkTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *BulkTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *BulkTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.posts = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+}
+
+func (s *BulkTestSuite) bulk(u *user.User, req post.BulkRequest) (int, *post.BulkResult) {
+	resp, raw, err := MakeRequest(testApp, "POST", "/api/posts/bulk", req, AuthHeader(u))
+	s.Require().NoError(err)
+
+	var result struct {
+		Data *post.BulkResult `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	return resp.StatusCode, result.Data
+}
+
+func (s *BulkTestSuite) TestCreate_ReportsEachItem() {
+	req := post.BulkRequest{Action: post.BulkCreate, Posts: []post.PostRequest{
+		{Tweet: "First"},
+		{Tweet: ""},
+		{Tweet: "Third", UserID: s.other.ID},
+	}}
+
+	code, _ := s.bulk(s.author, req)
+	s.Equal(http.StatusForbidden, code)
+
+	_, err := config.Enforcer.AddRoleForUser(s.author.Email, variables.ADMIN_ROLE)
+	s.Require().NoError(err)
+	defer config.Enforcer.DeleteRoleForUser(s.author.Email, variables.ADMIN_ROLE)
+
+	code, result := s.bulk(s.author, req)
+	s.Require().Equal(http.StatusOK, code)
+	s.Equal(post.BulkStatusCompleted, result.Status)
+	s.Equal(3, result.Total)
+	s.Equal(2, result.Succeeded)
+	s.Equal(1, result.Failed)
+	s.Require().Len(result.Errors, 1)
+	s.Equal(1, result.Errors[0].Index)
+
+	// Posts are always created for the caller
+	var owners []uint
+	testDB.Model(&post.Post{}).Where("id IN ?", result.IDs).Pluck("user_id", &owners)
+	s.Equal([]uint{s.author.ID, s.author.ID}, owners)
+}
+
+func (s *BulkTestSuite) TestDelete_ChecksOwnership() {
+	mine := CreateTestPost(s.posts, s.author, "Mine", nil)
+	theirs := CreateTestPost(s.posts, s.other, "Theirs", nil)
+
+	_, err := config.Enforcer.AddRoleForUser(s.author.Email, variables.ADMIN_ROLE)
+	s.Require().NoError(err)
+	defer config.Enforcer.DeleteRoleForUser(s.author.Email, variables.ADMIN_ROLE)
+
+	code, result := s.bulk(s.author, post.BulkRequest{Action: post.BulkDelete, IDs: []uint{mine.ID, theirs.ID, 999999, mine.ID}})
+	s.Require().Equal(http.StatusOK, code)
+	s.Equal([]uint{mine.ID}, result.IDs)
+	s.Equal(3, result.Failed)
+	s.Equal(theirs.ID, result.Errors[0].ID)
+	s.Equal(3, result.Errors[2].Index)
+
+	var live []uint
+	testDB.Model(&post.Post{}).Pluck("id", &live)
+	s.Equal([]uint{theirs.ID}, live)
+}
+
+func (s *BulkTestSuite) TestRestore_CountsQuotesAgain() {
+	original := CreateTestPost(s.posts, s.other, "Original thought", nil)
+	first := CreateTestPost(s.posts, s.author, "Well said", &original.ID)
+	second := CreateTestPost(s.posts, s.author, "Agreed", &original.ID)
+	s.Require().NoError(s.posts.Delete(first.ID, s.author.ID, false))
+	s.Require().NoError(s.posts.Delete(second.ID, s.author.ID, false))
+
+	var quoted post.Post
+	s.Require().NoError(testDB.First(&quoted, original.ID).Error)
+	s.Zero(quoted.QuoteCount)
+
+	// Restoring needs no permission, only ownership
+	code, result := s.bulk(s.other, post.BulkRequest{Action: post.BulkRestore, IDs: []uint{first.ID}})
+	s.Require().Equal(http.StatusOK, code)
+	s.Zero(result.Succeeded)
+
+	code, result = s.bulk(s.author, post.BulkRequest{Action: post.BulkRestore, IDs: []uint{first.ID, second.ID, original.ID}})
+	s.Require().Equal(http.StatusOK, code)
+	s.ElementsMatch([]uint{first.ID, second.ID}, result.IDs)
+	s.Require().Len(result.Errors, 1)
+	s.Equal(original.ID, result.Errors[0].ID)
+
+	s.Require().NoError(testDB.First(&quoted, original.ID).Error)
+	s.Equal(2, quoted.QuoteCount)
+}
+
+func (s *BulkTestSuite) TestRunBulk_ReportsProgressAndLimits() {
+	ids := make([]uint, 0, 120)
+	for i := 0; i < 120; i++ {
+		ids = append(ids, CreateTestPost(s.posts, s.author, "Batch", nil).ID)
+	}
+
+	var done []int
+	result := s.posts.RunBulk(&post.BulkRequest{Action: post.BulkDelete, IDs: ids}, s.author.ID, func(n, total int) {
+		s.Equal(120, total)
+		done = append(done, n)
+	})
+	s.Equal(120, result.Succeeded)
+	s.Equal([]int{100, 120}, done)
+
+	_, err := s.posts.Bulk(&post.BulkRequest{Action: post.BulkRestore, IDs: make([]uint, post.BulkMaxItems+1)}, s.author.ID)
+	s.Error(err)
+	_, err = s.posts.Bulk(&post.BulkRequest{Action: post.BulkRestore}, s.author.ID)
+	s.Error(err)
+
+	// Without a queue there are no jobs to look up
+	_, err = s.posts.BulkJob("missing", s.author.ID)
+	s.Error(err)
+
+	var notified []int
+	worker.SetProgressNotifier(func(userID uint, jobID string, progress int, status string) {
+		s.Equal(s.author.ID, userID)
+		notified = append(notified, progress)
+	})
+	defer worker.SetProgressNotifier(nil)
+	worker.NotifyProgress(s.T().Context(), s.author.ID, "job", 50, post.BulkStatusActive)
+	s.Equal([]int{50}, notified)
+}
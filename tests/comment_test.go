@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/variables"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CommentTestSuite struct {
+	suite.Suite
+	author    *user.User
+	commenter *user.User
+	post      *post.Post
+}
+
+func TestCommentTestSuite(t *testing.T) {
+	suite.Run(t, new(CommentTestSuite))
+}
+
+func (s *CommentTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *CommentTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *CommentTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM comments")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.commenter = CreateTestUser(testDB, "commenter@example.com", "hash", "user")
+	s.post = &post.Post{Tweet: "Hello", UserID: s.author.ID}
+	testDB.Create(s.post)
+}
+
+func (s *CommentTestSuite) createComment(u *user.User, body string, parentID *uint) (*http.Response, comment.CommentResponse) {
+	resp, raw, err := MakeRequest(testApp, "POST", fmt.Sprintf("/api/posts/%d/comments", s.post.ID),
+		comment.CreateCommentRequest{Body: body, ParentID: parentID}, AuthHeader(u))
+	s.Require().NoError(err)
+
+	var result struct {
+		Data comment.CommentResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	return resp, result.Data
+}
+
+func (s *CommentTestSuite) commentCount() int {
+	var p post.Post
+	testDB.First(&p, s.post.ID)
+	return p.CommentCount
+}
+
+func (s *CommentTestSuite) TestCreate_RepliesAndCounters() {
+	resp, top := s.createComment(s.commenter, "First", nil)
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	s.Equal(0, top.Depth)
+
+	resp, reply := s.createComment(s.author, "Reply", &top.ID)
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	s.Equal(1, reply.Depth)
+	s.Equal(top.ID, *reply.ParentID)
+
+	s.Equal(2, s.commentCount())
+
+	var parent comment.Comment
+	testDB.First(&parent, top.ID)
+	s.Equal(1, parent.ReplyCount)
+
+	// Only top-level comments are listed on the post
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/comments", s.post.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var list struct {
+		Data struct {
+			Data  []comment.CommentResponse `json:"data"`
+			Count int                       `json:"count"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &list)
+	s.Equal(1, list.Data.Count)
+	s.Equal(1, list.Data.Data[0].ReplyCount)
+}
+
+func (s *CommentTestSuite) TestCreate_DepthLimit() {
+	var parentID *uint
+	for depth := 0; depth <= comment.MaxDepth; depth++ {
+		resp, c := s.createComment(s.commenter, "Nested", parentID)
+		s.Require().Equal(http.StatusCreated, resp.StatusCode)
+		parentID = &c.ID
+	}
+
+	resp, _ := s.createComment(s.commenter, "Too deep", parentID)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *CommentTestSuite) TestReplies_CursorPagination() {
+	_, top := s.createComment(s.commenter, "Thread", nil)
+	for i := 0; i < 3; i++ {
+		s.createComment(s.commenter, fmt.Sprintf("Reply %d", i), &top.ID)
+	}
+
+	type page struct {
+		Data struct {
+			Data       []comment.CommentResponse `json:"data"`
+			NextCursor string                    `json:"next_cursor"`
+			HasMore    bool                      `json:"has_more"`
+		} `json:"data"`
+	}
+
+	_, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/comments/%d/replies?limit=2", top.ID), nil, nil)
+	s.Require().NoError(err)
+	var first page
+	ParseJSON(s.T(), raw, &first)
+	s.Len(first.Data.Data, 2)
+	s.True(first.Data.HasMore)
+	s.Equal("Reply 0", first.Data.Data[0].Body)
+
+	_, raw, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/comments/%d/replies?limit=2&cursor=%s", top.ID, first.Data.NextCursor), nil, nil)
+	s.Require().NoError(err)
+	var second page
+	ParseJSON(s.T(), raw, &second)
+	s.Len(second.Data.Data, 1)
+	s.False(second.Data.HasMore)
+	s.Equal("Reply 2", second.Data.Data[0].Body)
+}
+
+func (s *CommentTestSuite) TestDelete_KeepsPlaceholderForReplies() {
+	_, top := s.createComment(s.commenter, "Parent", nil)
+	s.createComment(s.author, "Child", &top.ID)
+
+	resp, _, err := MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/comments/%d", top.ID), nil, AuthHeader(s.commenter))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(1, s.commentCount())
+
+	_, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/comments", s.post.ID), nil, nil)
+	s.Require().NoError(err)
+	var list struct {
+		Data struct {
+			Data []comment.CommentResponse `json:"data"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &list)
+	s.Require().Len(list.Data.Data, 1)
+	s.True(list.Data.Data[0].Deleted)
+	s.Equal(comment.DeletedBody, list.Data.Data[0].Body)
+	s.Nil(list.Data.Data[0].User)
+
+	// The replies of the placeholder can still be loaded
+	resp, raw, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/comments/%d/replies", top.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	ParseJSON(s.T(), raw, &list)
+	s.Require().Len(list.Data.Data, 1)
+	s.Equal("Child", list.Data.Data[0].Body)
+
+	// A deleted comment without replies is gone
+	_, leaf := s.createComment(s.commenter, "Leaf", nil)
+	resp, _, err = MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/comments/%d", leaf.ID), nil, AuthHeader(s.commenter))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	resp, _, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/comments/%d/replies", leaf.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *CommentTestSuite) TestUpdateDelete_OwnerOrModerator() {
+	_, c := s.createComment(s.commenter, "Original", nil)
+
+	// Other users cannot touch the comment, not even the post author
+	resp, _, err := MakeRequest(testApp, "PUT", fmt.Sprintf("/api/comments/%d", c.ID),
+		comment.UpdateCommentRequest{Body: "Hijacked"}, AuthHeader(s.author))
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	resp, raw, err := MakeRequest(testApp, "PUT", fmt.Sprintf("/api/comments/%d", c.ID),
+		comment.UpdateCommentRequest{Body: "Edited"}, AuthHeader(s.commenter))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var updated struct {
+		Data comment.CommentResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &updated)
+	s.True(updated.Data.Edited)
+	s.Equal("Edited", updated.Data.Body)
+
+	moderator := CreateTestUser(testDB, "moderator@example.com", "hash", variables.ADMIN_ROLE)
+	_, err = config.Enforcer.AddRoleForUser(moderator.Email, variables.ADMIN_ROLE)
+	s.Require().NoError(err)
+	defer config.Enforcer.DeleteRoleForUser(moderator.Email, variables.ADMIN_ROLE)
+
+	resp, _, err = MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/comments/%d", c.ID), nil, AuthHeader(moderator))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(0, s.commentCount())
+}
+
+func (s *CommentTestSuite) TestNonPublicPost_HidesComments() {
+	_, created := s.createComment(s.author, "Before going private", nil)
+	testDB.Model(s.post).Update("visibility", post.VisibilityPrivate)
+
+	resp, _ := s.createComment(s.commenter, "Hi", nil)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	for _, url := range []string{
+		fmt.Sprintf("/api/posts/%d/comments", s.post.ID),
+		fmt.Sprintf("/api/comments/%d/replies", created.ID),
+	} {
+		resp, _, err := MakeRequest(testApp, "GET", url, nil, nil)
+		s.Require().NoError(err)
+		s.Equal(http.StatusNotFound, resp.StatusCode, url)
+
+		resp, _, err = MakeRequest(testApp, "GET", url, nil, AuthHeader(s.commenter))
+		s.Require().NoError(err)
+		s.Equal(http.StatusNotFound, resp.StatusCode, url)
+
+		// The author still sees the thread
+		resp, _, err = MakeRequest(testApp, "GET", url, nil, AuthHeader(s.author))
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode, url)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/auth"
+	consentService "starter-gofiber/internal/service/consent"
+	securityService "starter-gofiber/internal/service/security"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsentTestSuite struct {
+	suite.Suite
+	service consent.Service
+}
+
+func TestConsentTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsentTestSuite))
+}
+
+func (s *ConsentTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	s.service = consentService.NewConsentService(postgres.NewConsentRepository(testDB))
+}
+
+func (s *ConsentTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *ConsentTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM user_consents")
+	testDB.Exec("DELETE FROM marketing_consents")
+	testDB.Exec("DELETE FROM policy_versions")
+	testDB.Exec("DELETE FROM email_verifications")
+	testDB.Exec("DELETE FROM users")
+}
+
+func (s *ConsentTestSuite) publish(policyType, version string) {
+	_, err := s.service.PublishVersion(&consent.PublishPolicyRequest{Type: policyType, Version: version})
+	s.Require().NoError(err)
+}
+
+func (s *ConsentTestSuite) TestRegister_RequiresCurrentVersions() {
+	s.publish("tos", "1.0")
+	s.publish("privacy", "1.0")
+	securityS := securityService.NewSecurityService(postgres.NewSecurityEventRepository(testDB))
+	authS := auth.NewAuthService(postgres.NewUserRepository(testDB), s.service, securityS)
+
+	req := &user.RegisterRequest{Name: "Consent", Email: "consent@example.com", Password: "Password123!", Role: "user", TosVersion: "0.9", PrivacyVersion: "1.0"}
+	s.Error(authS.Register(req, "127.0.0.1", "test"))
+
+	req.TosVersion = "1.0"
+	req.MarketingOptIn = true
+	s.Require().NoError(authS.Register(req, "127.0.0.1", "test"))
+
+	var u user.User
+	s.Require().NoError(testDB.Where("email = ?", req.Email).First(&u).Error)
+	status, err := s.service.GetStatus(u.ID)
+	s.Require().NoError(err)
+	s.False(status.ConsentRequired)
+	s.Len(status.Accepted, 2)
+	s.True(status.MarketingOptIn)
+}
+
+func (s *ConsentTestSuite) TestNewVersion_BlocksUntilAccepted() {
+	u := CreateTestUser(testDB, "reaccept@example.com", "hashed", "user")
+	headers := AuthHeader(u)
+
+	// Nothing published yet, nothing to accept
+	resp, _, err := MakeRequest(testApp, "GET", "/api/auth/profile", nil, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+
+	s.publish("tos", "2.0")
+
+	resp, body, err := MakeRequest(testApp, "GET", "/api/auth/profile", nil, headers)
+	s.NoError(err)
+	s.Equal(403, resp.StatusCode)
+	var errResp map[string]interface{}
+	ParseJSON(s.T(), body, &errResp)
+	s.Equal(consent.ErrCodeConsentRequired, errResp["order"])
+
+	// Consent routes stay reachable
+	resp, _, err = MakeRequest(testApp, "GET", "/api/consent/status", nil, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+
+	resp, _, err = MakeRequest(testApp, "POST", "/api/consent/accept", consent.AcceptConsentRequest{TosVersion: "2.0"}, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+
+	resp, _, err = MakeRequest(testApp, "GET", "/api/auth/profile", nil, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+}
+
+func (s *ConsentTestSuite) TestMarketingHistoryAndExport() {
+	u := CreateTestUser(testDB, "marketing@example.com", "hashed", "user")
+	headers := AuthHeader(u)
+	// Accepted before the marketing choices below, it comes first in the export
+	testDB.Create(&consent.UserConsent{UserID: u.ID, PolicyType: consent.PolicyTerms, Version: "1.0", AcceptedAt: time.Now().Add(-time.Hour)})
+
+	optIn, optOut := true, false
+	resp, _, err := MakeRequest(testApp, "PUT", "/api/consent/marketing", consent.MarketingConsentRequest{OptIn: &optIn}, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+	resp, _, err = MakeRequest(testApp, "PUT", "/api/consent/marketing", consent.MarketingConsentRequest{OptIn: &optOut}, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+
+	status, err := s.service.GetStatus(u.ID)
+	s.Require().NoError(err)
+	s.False(status.MarketingOptIn)
+	s.Len(status.MarketingHistory, 2)
+
+	rows, err := s.service.Export(nil, nil)
+	s.Require().NoError(err)
+	s.Require().Len(rows, 3)
+	s.Equal("marketing@example.com", rows[0].Email)
+	s.Equal("tos", rows[0].Type)
+	s.Equal("marketing", rows[1].Type)
+	s.Equal("opt_in", rows[1].Value)
+	s.Equal("opt_out", rows[2].Value)
+}
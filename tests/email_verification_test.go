@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/pagination"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EmailVerificationTestSuite struct {
+	suite.Suite
+}
+
+func TestEmailVerificationTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationTestSuite))
+}
+
+func (s *EmailVerificationTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	worker.SetDB(testDB)
+	worker.SetPostRepository(postgres.NewPostRepository(testDB))
+	middleware.InitEmailVerificationGate([]string{"post:create"}, 24*time.Hour)
+}
+
+func (s *EmailVerificationTestSuite) TearDownSuite() {
+	worker.SetDB(nil)
+	worker.SetPostRepository(nil)
+	CleanupTestDB()
+}
+
+func (s *EmailVerificationTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM comments")
+	testDB.Exec("DELETE FROM follows")
+	testDB.Exec("DELETE FROM security_events")
+	testDB.Exec("DELETE FROM email_verifications")
+	testDB.Exec("DELETE FROM users")
+}
+
+func (s *EmailVerificationTestSuite) TestGate_AllowsWithinGracePeriod() {
+	claims := &user.CustomClaims{RegisteredAt: time.Now().Add(-time.Hour).Unix()}
+	s.False(middleware.IsVerificationRequired(claims, "post:create", time.Now()))
+}
+
+func (s *EmailVerificationTestSuite) TestGate_BlocksAfterGracePeriod() {
+	claims := &user.CustomClaims{RegisteredAt: time.Now().Add(-48 * time.Hour).Unix()}
+	s.True(middleware.IsVerificationRequired(claims, "post:create", time.Now()))
+	s.False(middleware.IsVerificationRequired(claims, "post:update", time.Now()))
+
+	claims.EmailVerified = true
+	s.False(middleware.IsVerificationRequired(claims, "post:create", time.Now()))
+}
+
+func (s *EmailVerificationTestSuite) TestRoutes_GateFollowsCheckedPermission() {
+	middleware.InitEmailVerificationGate([]string{"post:update"}, 24*time.Hour)
+	defer middleware.InitEmailVerificationGate([]string{"post:create"}, 24*time.Hour)
+
+	admin := CreateTestUser(testDB, "admin@example.com", "hashed", "admin")
+	testDB.Model(admin).Updates(map[string]interface{}{
+		"email_verified": false,
+		"created_at":     time.Now().AddDate(0, 0, -2),
+	})
+	testDB.First(admin, admin.ID)
+	token, err := GenerateTestToken(admin)
+	s.Require().NoError(err)
+
+	resp, body, err := MakeRequest(testApp, "PUT", "/api/posts/999999", map[string]interface{}{"tweet": "edited"},
+		map[string]string{"Authorization": "Bearer " + token})
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	s.Contains(string(body), "Email verification required")
+
+	// Verified, the request reaches the permission check
+	admin.EmailVerified = true
+	token, err = GenerateTestToken(admin)
+	s.Require().NoError(err)
+
+	_, body, err = MakeRequest(testApp, "PUT", "/api/posts/999999", map[string]interface{}{"tweet": "edited"},
+		map[string]string{"Authorization": "Bearer " + token})
+	s.Require().NoError(err)
+	s.NotContains(string(body), "Email verification required")
+}
+
+func (s *EmailVerificationTestSuite) TestClaims_FromEntityIncludesVerification() {
+	u := user.User{Email: "claims@example.com", EmailVerified: true}
+	u.CreatedAt = time.Unix(1700000000, 0)
+
+	claims := user.UserClaims{}.FromEntity(u)
+	s.True(claims.EmailVerified)
+	s.Equal(int64(1700000000), claims.RegisteredAt)
+}
+
+func (s *EmailVerificationTestSuite) TestPurgeUnverifiedUsers() {
+	stale := CreateTestUser(testDB, "stale@example.com", "hashed", "user")
+	testDB.Model(stale).Updates(map[string]interface{}{
+		"email_verified": false,
+		"created_at":     time.Now().AddDate(0, 0, -60),
+	})
+	testDB.Create(&post.Post{Tweet: "unverified post", UserID: stale.ID})
+
+	fresh := CreateTestUser(testDB, "fresh@example.com", "hashed", "user")
+	testDB.Model(fresh).Update("email_verified", false)
+
+	verified := CreateTestUser(testDB, "verified@example.com", "hashed", "user")
+	testDB.Model(verified).Update("created_at", time.Now().AddDate(0, 0, -60))
+
+	// Activity of the stale account on someone else's post
+	other := post.Post{Tweet: "verified post", UserID: verified.ID, CommentCount: 3}
+	testDB.Create(&other)
+	testDB.Create(&comment.Comment{PostID: other.ID, Body: "hello", UserID: stale.ID})
+	answered := comment.Comment{PostID: other.ID, Body: "question", UserID: stale.ID, ReplyCount: 1}
+	testDB.Create(&answered)
+	reply := comment.Comment{PostID: other.ID, ParentID: &answered.ID, Depth: 1, Body: "answer", UserID: verified.ID}
+	testDB.Create(&reply)
+	testDB.Create(&follow.Follow{FollowerID: stale.ID, FolloweeID: verified.ID})
+	testDB.Create(&security.Event{UserID: &stale.ID, Email: stale.Email, Type: security.EventLoginSuccess, Severity: security.SeverityInfo})
+	_, err := config.Enforcer.AddRoleForUser(stale.Email, "user")
+	s.Require().NoError(err)
+
+	s.NoError(worker.HandlePurgeUnverifiedUsers(context.Background(), nil))
+
+	var emails []string
+	testDB.Unscoped().Model(&user.User{}).Order("email").Pluck("email", &emails)
+	s.Equal([]string{"fresh@example.com", "verified@example.com"}, emails)
+
+	var posts []post.Post
+	testDB.Unscoped().Find(&posts)
+	s.Require().Len(posts, 1)
+	s.Equal(other.ID, posts[0].ID)
+	s.Equal(1, posts[0].CommentCount)
+
+	// The answered comment stays as a placeholder so the reply can still be reached
+	var comments []comment.Comment
+	testDB.Unscoped().Order("id").Find(&comments)
+	s.Require().Len(comments, 2)
+	s.Equal(answered.ID, comments[0].ID)
+	s.True(comments[0].DeletedAt.Valid)
+	s.Empty(comments[0].Body)
+	s.Equal(reply.ID, comments[1].ID)
+	thread, err := postgres.NewCommentRepository(testDB).FindThread(other.ID, nil, pagination.CursorPagination{Limit: 10})
+	s.NoError(err)
+	s.Require().Len(thread, 1)
+	s.Equal(answered.ID, thread[0].ID)
+
+	var rows int64
+	testDB.Model(&follow.Follow{}).Count(&rows)
+	s.Zero(rows)
+	testDB.Model(&security.Event{}).Where("user_id = ?", stale.ID).Count(&rows)
+	s.Zero(rows)
+
+	roles, err := config.Enforcer.GetRolesForUser(stale.Email)
+	s.NoError(err)
+	s.Empty(roles)
+}
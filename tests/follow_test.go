@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FollowTestSuite struct {
+	suite.Suite
+	alice *user.User
+	bob   *user.User
+	carol *user.User
+}
+
+func TestFollowTestSuite(t *testing.T) {
+	suite.Run(t, new(FollowTestSuite))
+}
+
+func (s *FollowTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *FollowTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *FollowTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM follows")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.alice = CreateTestUser(testDB, "alice@example.com", "hash", "user")
+	s.bob = CreateTestUser(testDB, "bob@example.com", "hash", "user")
+	s.carol = CreateTestUser(testDB, "carol@example.com", "hash", "user")
+}
+
+func (s *FollowTestSuite) follow(follower, followee *user.User) *http.Response {
+	resp, _, err := MakeRequest(testApp, "POST", fmt.Sprintf("/api/users/%d/follow", followee.ID), nil, AuthHeader(follower))
+	s.Require().NoError(err)
+	return resp
+}
+
+type timelinePage struct {
+	Data struct {
+		Data       []post.PostResponse `json:"data"`
+		NextCursor string              `json:"next_cursor"`
+		HasMore    bool                `json:"has_more"`
+	} `json:"data"`
+}
+
+func (s *FollowTestSuite) timeline(u *user.User, query string) timelinePage {
+	resp, raw, err := MakeRequest(testApp, "GET", "/api/timeline"+query, nil, AuthHeader(u))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode, string(raw))
+
+	var page timelinePage
+	ParseJSON(s.T(), raw, &page)
+	return page
+}
+
+func (s *FollowTestSuite) TestFollow_IsIdempotent() {
+	s.Equal(http.StatusOK, s.follow(s.alice, s.bob).StatusCode)
+	s.Equal(http.StatusOK, s.follow(s.alice, s.bob).StatusCode)
+
+	var count int64
+	testDB.Model(&follow.Follow{}).Count(&count)
+	s.Equal(int64(1), count)
+
+	for i := 0; i < 2; i++ {
+		resp, _, err := MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/users/%d/follow", s.bob.ID), nil, AuthHeader(s.alice))
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+	}
+	testDB.Model(&follow.Follow{}).Count(&count)
+	s.Equal(int64(0), count)
+}
+
+func (s *FollowTestSuite) TestFollow_Validation() {
+	s.Equal(http.StatusBadRequest, s.follow(s.alice, s.alice).StatusCode)
+
+	resp, _, err := MakeRequest(testApp, "POST", "/api/users/99999/follow", nil, AuthHeader(s.alice))
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *FollowTestSuite) TestFollowersAndStats() {
+	s.follow(s.alice, s.carol)
+	s.follow(s.bob, s.carol)
+	s.follow(s.carol, s.alice)
+
+	_, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/users/%d/followers?limit=1", s.carol.ID), nil, nil)
+	s.Require().NoError(err)
+	var followers struct {
+		Data struct {
+			Data       []follow.FollowResponse `json:"data"`
+			NextCursor string                  `json:"next_cursor"`
+			HasMore    bool                    `json:"has_more"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &followers)
+	s.Require().Len(followers.Data.Data, 1)
+	s.True(followers.Data.HasMore)
+	// Newest follower first
+	s.Equal(s.bob.ID, followers.Data.Data[0].UserID)
+
+	_, raw, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/users/%d/follow-stats", s.carol.ID), nil, AuthHeader(s.alice))
+	s.Require().NoError(err)
+	var stats struct {
+		Data follow.FollowStats `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &stats)
+	s.Equal(int64(2), stats.Data.Followers)
+	s.Equal(int64(1), stats.Data.Following)
+	s.True(stats.Data.IsFollowing)
+}
+
+func (s *FollowTestSuite) TestTimeline_MergesFollowedAndOwnPosts() {
+	s.follow(s.alice, s.bob)
+	own := InsertTestPost(testDB, s.alice, "mine")
+	followed := InsertTestPost(testDB, s.bob, "from bob")
+	InsertTestPost(testDB, s.carol, "not followed")
+
+	page := s.timeline(s.alice, "")
+	s.Require().Len(page.Data.Data, 2)
+	s.Equal(followed.ID, page.Data.Data[0].ID)
+	s.Equal(own.ID, page.Data.Data[1].ID)
+	s.False(page.Data.HasMore)
+	s.NotNil(page.Data.Data[0].Reactions)
+}
+
+func (s *FollowTestSuite) TestTimeline_CursorPagination() {
+	s.follow(s.alice, s.bob)
+	var ids []uint
+	for i := 0; i < 5; i++ {
+		ids = append(ids, InsertTestPost(testDB, s.bob, fmt.Sprintf("post %d", i)).ID)
+	}
+
+	first := s.timeline(s.alice, "?limit=3")
+	s.Require().Len(first.Data.Data, 3)
+	s.True(first.Data.HasMore)
+	s.Equal(ids[4], first.Data.Data[0].ID)
+
+	second := s.timeline(s.alice, "?limit=3&cursor="+first.Data.NextCursor)
+	s.Require().Len(second.Data.Data, 2)
+	s.False(second.Data.HasMore)
+	s.Equal(ids[1], second.Data.Data[0].ID)
+	s.Equal(ids[0], second.Data.Data[1].ID)
+
+	resp, _, err := MakeRequest(testApp, "GET", "/api/timeline?cursor=not-a-cursor", nil, AuthHeader(s.alice))
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *FollowTestSuite) TestTimeline_OrdersByPublishTime() {
+	s.follow(s.alice, s.bob)
+	// Scheduled first, published after the others were posted
+	scheduled := InsertTestPost(testDB, s.bob, "scheduled")
+	older := InsertTestPost(testDB, s.bob, "older")
+	newer := InsertTestPost(testDB, s.bob, "newer")
+	testDB.Model(older).Update("publish_at", time.Now().Add(-2*time.Hour))
+	testDB.Model(newer).Update("publish_at", time.Now().Add(-time.Hour))
+	testDB.Model(scheduled).Update("publish_at", time.Now())
+
+	var seen []uint
+	cursor := ""
+	for i := 0; i < 3; i++ {
+		page := s.timeline(s.alice, "?limit=1"+cursor)
+		s.Require().Len(page.Data.Data, 1)
+		seen = append(seen, page.Data.Data[0].ID)
+		cursor = "&cursor=" + page.Data.NextCursor
+	}
+	s.Equal([]uint{scheduled.ID, newer.ID, older.ID}, seen)
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestParseEntities(t *testing.T) {
+	entities := post.ParseEntities("Halo @Bob, belajar #GoLang & #café! mail@host.com #123 @ab")
+
+	assert.Equal(t, []post.TextEntity{
+		{Type: post.EntityMention, Text: "bob", Start: 5, End: 9},
+		{Type: post.EntityHashtag, Text: "golang", Start: 19, End: 26},
+		{Type: post.EntityHashtag, Text: "café", Start: 29, End: 34},
+	}, entities)
+}
+
+type HashtagTestSuite struct {
+	suite.Suite
+	service post.Service
+	author  *user.User
+	bob     *user.User
+}
+
+func TestHashtagTestSuite(t *testing.T) {
+	suite.Run(t, new(HashtagTestSuite))
+}
+
+func (s *HashtagTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *HashtagTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *HashtagTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM post_mentions")
+	testDB.Exec("DELETE FROM post_tags")
+	testDB.Exec("DELETE FROM tags")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.bob = CreateTestUser(testDB, "bob@example.com", "hash", "user")
+	s.Require().NoError(testDB.Model(s.bob).Update("username", "bob").Error)
+}
+
+func (s *HashtagTestSuite) create(tweet string) *post.PostResponse {
+	resp, err := s.service.Create(&post.PostRequest{Tweet: tweet, UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	return resp
+}
+
+type postResult struct {
+	Data post.PostResponse `json:"data"`
+}
+
+func (s *HashtagTestSuite) TestCreate_StoresTagsAndResolvesMentions() {
+	created := s.create("Ping @bob and @nobody about #Go")
+
+	s.Require().Len(created.Entities, 2)
+	s.Equal(post.EntityMention, created.Entities[0].Type)
+	s.Require().NotNil(created.Entities[0].UserID)
+	s.Equal(s.bob.ID, *created.Entities[0].UserID)
+	s.Equal(5, created.Entities[0].Start)
+	s.Equal(post.TextEntity{Type: post.EntityHashtag, Text: "go", Start: 28, End: 31}, created.Entities[1])
+
+	var mentions []post.PostMention
+	testDB.Where("post_id = ?", created.ID).Find(&mentions)
+	s.Require().Len(mentions, 1)
+	s.Equal(s.bob.ID, mentions[0].UserID)
+
+	// Entities are also returned when the post is read back
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", created.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var found postResult
+	ParseJSON(s.T(), raw, &found)
+	s.Len(found.Data.Entities, 2)
+}
+
+func (s *HashtagTestSuite) TestUpdate_ReplacesTags() {
+	created := s.create("Hello #go #fiber")
+	_, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, Tweet: "Hello #gorm", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+
+	var names []string
+	testDB.Model(&post.Tag{}).
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Where("post_tags.post_id = ?", created.ID).
+		Pluck("tags.name", &names)
+	s.Equal([]string{"gorm"}, names)
+}
+
+func (s *HashtagTestSuite) TestPostsByTag() {
+	first := s.create("First #Go post")
+	s.create("Unrelated #rust post")
+	second := s.create("Second #go post")
+
+	resp, raw, err := MakeRequest(testApp, "GET", "/api/tags/GO/posts?limit=1", nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode, string(raw))
+
+	var page timelinePage
+	ParseJSON(s.T(), raw, &page)
+	s.Require().Len(page.Data.Data, 1)
+	s.Equal(second.ID, page.Data.Data[0].ID)
+	s.True(page.Data.HasMore)
+
+	_, raw, err = MakeRequest(testApp, "GET", "/api/tags/go/posts?limit=1&cursor="+page.Data.NextCursor, nil, nil)
+	s.Require().NoError(err)
+	page = timelinePage{}
+	ParseJSON(s.T(), raw, &page)
+	s.Require().Len(page.Data.Data, 1)
+	s.Equal(first.ID, page.Data.Data[0].ID)
+	s.False(page.Data.HasMore)
+}
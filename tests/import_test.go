@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	importsvc "starter-gofiber/internal/service/dataimport"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xuri/excelize/v2"
+)
+
+type ImportTestSuite struct {
+	suite.Suite
+	service dataimport.Service
+	admin   *user.User
+}
+
+func TestImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
+
+func (s *ImportTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *ImportTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *ImportTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM imports")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	dataimport.SetDir(s.T().TempDir())
+	s.service = importsvc.NewImportService(postgres.NewImportRepository(testDB), postgres.NewPostRepository(testDB))
+	s.admin = CreateTestUser(testDB, "admin@example.com", "hash", "admin")
+	CreateTestUser(testDB, "Mixed@Example.com", "hash", "user")
+}
+
+// fileHeader builds an uploaded file the way Fiber parses a multipart form
+func (s *ImportTestSuite) fileHeader(name string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", name)
+	s.Require().NoError(err)
+	_, err = part.Write(content)
+	s.Require().NoError(err)
+	s.Require().NoError(w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	s.Require().NoError(err)
+	return form.File["file"][0]
+}
+
+// report reads the error report of an import
+func (s *ImportTestSuite) report(id uint) [][]string {
+	path, _, err := s.service.Report(id)
+	s.Require().NoError(err)
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	s.Require().NoError(err)
+	return records
+}
+
+func (s *ImportTestSuite) TestUsers_RejectsInvalidRows() {
+	file := "Full Name,E-mail,username,Password\n" +
+		"Jane Doe,Jane@Example.com,jane,secret123\n" +
+		"Jo,not-an-email,,\n" +
+		"\n" +
+		"John Roe,john@example.com,,\n" +
+		"Jane Again,jane@example.com,other,\n" +
+		"Taken,admin@example.com,,\n" +
+		"Taken Again,mixed@example.com,,\n"
+
+	resp, err := s.service.Start(&dataimport.ImportRequest{
+		Kind:    dataimport.KindUsers,
+		Mapping: map[string]string{"Full Name": "name"},
+	}, s.fileHeader("users.csv", []byte(file)), s.admin.ID)
+	s.Require().NoError(err)
+	s.Empty(resp.Error)
+	s.Equal(dataimport.StatusCompleted, resp.Status)
+	s.Equal(6, resp.TotalRows)
+	s.Equal(2, resp.ImportedRows)
+	s.Equal(4, resp.RejectedRows)
+	s.Require().NotNil(resp.ReportURL)
+
+	var jane user.User
+	s.Require().NoError(testDB.Where("email = ?", "jane@example.com").First(&jane).Error)
+	s.Equal("jane", *jane.Username)
+	s.NotEqual("secret123", jane.Password)
+	roles, err := config.Enforcer.GetRolesForUser(jane.Email)
+	s.Require().NoError(err)
+	s.Equal([]string{"user"}, roles)
+
+	records := s.report(resp.ID)
+	s.Require().Len(records, 5)
+	s.Equal([]string{"line", "error", "Full Name", "E-mail", "username", "Password"}, records[0])
+	s.Equal("3", records[1][0])
+	s.Contains(records[1][1], "name: min=3")
+	s.Contains(records[1][1], "email: email")
+	s.Equal("6", records[2][0])
+	s.Contains(records[2][1], "repeated in the file")
+	s.Contains(records[3][1], "already exists")
+	s.Contains(records[4][1], "already exists")
+
+	// The upload is only kept until it is processed
+	entries, err := os.ReadDir(dataimport.UploadDir())
+	s.Require().NoError(err)
+	s.Empty(entries)
+}
+
+func (s *ImportTestSuite) TestUsers_DryRunInsertsNothing() {
+	file := "name,email\nJane Doe,jane@example.com\n"
+
+	resp, err := s.service.Start(&dataimport.ImportRequest{Kind: dataimport.KindUsers, DryRun: true},
+		s.fileHeader("users.csv", []byte(file)), s.admin.ID)
+	s.Require().NoError(err)
+	s.Equal(1, resp.ImportedRows)
+	s.Nil(resp.ReportURL)
+
+	var count int64
+	testDB.Model(&user.User{}).Where("email = ?", "jane@example.com").Count(&count)
+	s.Zero(count)
+
+	_, _, err = s.service.Report(resp.ID)
+	s.Error(err)
+}
+
+func (s *ImportTestSuite) TestPosts_ResolvesAuthors() {
+	file := `{"author_email":"ADMIN@example.com","tweet":"Hello","created_at":"2020-01-02"}
+{"user_id":` + jsonID(s.admin.ID) + `,"title":"My first article","body":"# Hi\n\nSome text"}
+{"user_id":` + jsonID(s.admin.ID) + `,"title":"My first article","body":"Again","status":"draft"}
+{"author_email":"nobody@example.com","tweet":"Lost"}
+{"tweet":"No author"
+{"author_email":"mixed@EXAMPLE.com","tweet":"Any case"}
+`
+
+	resp, err := s.service.Start(&dataimport.ImportRequest{Kind: dataimport.KindPosts},
+		s.fileHeader("posts.ndjson", []byte(file)), s.admin.ID)
+	s.Require().NoError(err)
+	s.Equal(dataimport.StatusCompleted, resp.Status)
+	s.Equal(6, resp.TotalRows)
+	s.Equal(4, resp.ImportedRows)
+	s.Equal(2, resp.RejectedRows)
+
+	var posts []post.Post
+	s.Require().NoError(testDB.Where("user_id = ?", s.admin.ID).Order("id").Find(&posts).Error)
+	s.Require().Len(posts, 3)
+	s.Equal(2020, posts[0].CreatedAt.Year())
+	s.Equal("my-first-article", *posts[1].Slug)
+	s.Contains(posts[1].BodyHTML, "<h1")
+	s.Equal("my-first-article-2", *posts[2].Slug)
+	s.Equal(post.StatusDraft, posts[2].Status)
+	s.Nil(posts[2].PublishAt)
+
+	records := s.report(resp.ID)
+	s.Require().Len(records, 3)
+	s.Equal([]string{"line", "error", "data"}, records[0])
+	// Unreadable rows are rejected as they are read, the others once their chunk is inserted
+	s.Equal("5", records[1][0])
+	s.Contains(records[1][1], "invalid JSON")
+	s.Equal("4", records[2][0])
+	s.Contains(records[2][1], "author")
+}
+
+func (s *ImportTestSuite) TestPosts_XLSXNeedsRequiredColumns() {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	s.Require().NoError(f.SetSheetRow(sheet, "A1", &[]interface{}{"Author", "Text"}))
+	s.Require().NoError(f.SetSheetRow(sheet, "A2", &[]interface{}{"admin@example.com", "From a spreadsheet"}))
+	var buf bytes.Buffer
+	s.Require().NoError(f.Write(&buf))
+
+	resp, err := s.service.Start(&dataimport.ImportRequest{Kind: dataimport.KindPosts},
+		s.fileHeader("posts.xlsx", buf.Bytes()), s.admin.ID)
+	s.Require().NoError(err)
+	s.Equal(dataimport.StatusFailed, resp.Status)
+	s.Contains(resp.Error, "author_email or user_id")
+
+	resp, err = s.service.Start(&dataimport.ImportRequest{
+		Kind:    dataimport.KindPosts,
+		Mapping: map[string]string{"Author": "author_email", "Text": "tweet"},
+	}, s.fileHeader("posts.xlsx", buf.Bytes()), s.admin.ID)
+	s.Require().NoError(err)
+	s.Equal(dataimport.StatusCompleted, resp.Status)
+	s.Equal(1, resp.ImportedRows)
+
+	_, err = s.service.Start(&dataimport.ImportRequest{
+		Kind:    dataimport.KindPosts,
+		Mapping: map[string]string{"Author": "password"},
+	}, s.fileHeader("posts.xlsx", buf.Bytes()), s.admin.ID)
+	s.Error(err)
+	_, err = s.service.Start(&dataimport.ImportRequest{Kind: dataimport.KindPosts},
+		s.fileHeader("posts.txt", buf.Bytes()), s.admin.ID)
+	s.Error(err)
+}
+
+func (s *ImportTestSuite) TestRoutes_RequirePermission() {
+	u := CreateTestUser(testDB, "user@example.com", "hash", "user")
+	resp, _, err := MakeRequest(testApp, "GET", "/api/imports", nil, AuthHeader(u))
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	resp, _, err = MakeRequest(testApp, "GET", "/api/imports", nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+// jsonID formats an ID for an NDJSON line
+func jsonID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
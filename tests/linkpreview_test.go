@@ -0,0 +1,249 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/infrastructure/unfurl"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/variables"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestFirstURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/a?b=1", post.FirstURL("Read https://example.com/a?b=1."))
+	assert.Equal(t, "http://example.com", post.FirstURL("(see http://example.com) and https://other.org"))
+	assert.Equal(t, "https://en.wikipedia.org/wiki/Go_(game)", post.FirstURL("https://en.wikipedia.org/wiki/Go_(game)"))
+	assert.Equal(t, "https://example.com/docs", post.FirstURL("[docs](https://example.com/docs)"))
+	assert.Empty(t, post.FirstURL("no links, ftp://example.com or example.com"))
+	assert.Empty(t, post.FirstURL("https://"))
+}
+
+func TestUnfurl_BlocksInternalAddresses(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1"} {
+		assert.True(t, unfurl.IsBlocked(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"93.184.216.34", "2606:4700::1111"} {
+		assert.False(t, unfurl.IsBlocked(net.ParseIP(ip)), ip)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<title>Internal</title>")
+	}))
+	defer server.Close()
+
+	_, err := unfurl.New(unfurl.DefaultConfig()).Page(context.Background(), server.URL)
+	assert.True(t, errors.Is(err, unfurl.ErrBlockedAddress), err)
+	_, err = unfurl.New(unfurl.DefaultConfig()).Page(context.Background(), "file:///etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestUnfurl_Limits(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hop/", func(w http.ResponseWriter, r *http.Request) {
+		var n int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/hop/"), "%d", &n)
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", n+1), http.StatusFound)
+	})
+	mux.HandleFunc("/big.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(bytes.Repeat([]byte{0}, 2048))
+	})
+	mux.HandleFunc("/data.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{}")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := unfurl.DefaultConfig()
+	config.AllowPrivate = true
+	config.MaxImageBytes = 1024
+	fetcher := unfurl.New(config)
+
+	_, err := fetcher.Page(context.Background(), server.URL+"/hop/0")
+	assert.ErrorContains(t, err, "redirects")
+	_, err = fetcher.Image(context.Background(), server.URL+"/big.png")
+	assert.True(t, errors.Is(err, unfurl.ErrTooLarge), err)
+	_, err = fetcher.Page(context.Background(), server.URL+"/data.json")
+	assert.ErrorContains(t, err, "content type")
+}
+
+type LinkPreviewTestSuite struct {
+	suite.Suite
+	service post.Service
+	author  *user.User
+	server  *httptest.Server
+	hits    atomic.Int32
+}
+
+func TestLinkPreviewTestSuite(t *testing.T) {
+	suite.Run(t, new(LinkPreviewTestSuite))
+}
+
+func (s *LinkPreviewTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	worker.SetDB(testDB)
+
+	var img bytes.Buffer
+	s.Require().NoError(png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 1200, 630))))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		s.hits.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head>
+			<title>Fallback title</title>
+			<meta property="og:title" content="Gophers &amp; Fibers">
+			<meta name="twitter:title" content="Twitter title">
+			<meta name="description" content="All about gophers.">
+			<meta property="og:site_name" content="Gopher News">
+			<meta property="og:image" content="/cover.png">
+		</head><body><meta property="og:title" content="Ignored"></body></html>`)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><title>  Just a\n title </title></head></html>")
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/plain", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/cover.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(img.Bytes())
+	})
+	s.server = httptest.NewServer(mux)
+
+	config := unfurl.DefaultConfig()
+	config.AllowPrivate = true
+	worker.SetLinkFetcher(unfurl.New(config))
+}
+
+func (s *LinkPreviewTestSuite) TearDownSuite() {
+	s.server.Close()
+	worker.SetLinkFetcher(unfurl.New(unfurl.DefaultConfig()))
+	worker.SetDB(nil)
+	CleanupTestDB()
+	os.RemoveAll("./public" + variables.LINK_PREVIEW_PATH)
+}
+
+func (s *LinkPreviewTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM link_previews")
+	testDB.Exec("DELETE FROM post_revisions")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+	s.hits.Store(0)
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+}
+
+func (s *LinkPreviewTestSuite) unfurl(postID uint) {
+	payload, err := json.Marshal(worker.UnfurlLinkPayload{PostID: postID})
+	s.Require().NoError(err)
+	s.Require().NoError(worker.HandleUnfurlLink(context.Background(), asynq.NewTask(worker.TaskUnfurlLink, payload)))
+}
+
+func (s *LinkPreviewTestSuite) create(tweet string) *post.PostResponse {
+	resp, err := s.service.Create(&post.PostRequest{Tweet: tweet, UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *LinkPreviewTestSuite) find(id uint) *post.PostResponse {
+	resp, err := s.service.FindByID(id, s.author.ID)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *LinkPreviewTestSuite) TestUnfurl_AttachesCard() {
+	link := s.server.URL + "/article"
+	created := s.create("Worth a read: " + link + ".")
+	s.Nil(created.LinkPreview)
+
+	s.unfurl(created.ID)
+	found := s.find(created.ID)
+	s.Require().NotNil(found.LinkPreview)
+	s.Equal(link, found.LinkPreview.URL)
+	s.Equal("Gophers & Fibers", found.LinkPreview.Title)
+	s.Equal("All about gophers.", found.LinkPreview.Description)
+	s.Equal("Gopher News", found.LinkPreview.SiteName)
+	s.Require().NotNil(found.LinkPreview.ImageURL)
+	s.Require().NotNil(found.LinkPreview.ThumbnailURL)
+
+	var preview linkpreview.LinkPreview
+	s.Require().NoError(testDB.First(&preview).Error)
+	s.Require().Len(preview.Files(), 2)
+	for _, path := range preview.Files() {
+		s.FileExists("./public" + path)
+	}
+
+	// Other posts linking to the URL reuse the cached preview
+	again := s.create("Same link " + link)
+	s.unfurl(again.ID)
+	s.Equal(int32(1), s.hits.Load())
+	s.Require().NotNil(s.find(again.ID).LinkPreview)
+
+	// Editing the link hides the old card until the new one is unfurled
+	updated, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, UserID: s.author.ID, Tweet: "Changed to " + s.server.URL + "/moved"}, s.author.ID)
+	s.Require().NoError(err)
+	s.Nil(updated.LinkPreview)
+	s.unfurl(created.ID)
+	found = s.find(created.ID)
+	s.Require().NotNil(found.LinkPreview)
+	s.Equal(s.server.URL+"/plain", found.LinkPreview.URL)
+	s.Equal("Just a title", found.LinkPreview.Title)
+	s.Nil(found.LinkPreview.ImageURL)
+
+	// Removing the link removes the card
+	_, err = s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, UserID: s.author.ID, Tweet: "No link"}, s.author.ID)
+	s.Require().NoError(err)
+	s.unfurl(created.ID)
+	var stored post.Post
+	s.Require().NoError(testDB.First(&stored, created.ID).Error)
+	s.Nil(stored.LinkPreviewID)
+}
+
+func (s *LinkPreviewTestSuite) TestUnfurl_CachesFailures() {
+	created := s.create("Broken " + s.server.URL + "/missing")
+	s.unfurl(created.ID)
+	s.Nil(s.find(created.ID).LinkPreview)
+
+	var preview linkpreview.LinkPreview
+	s.Require().NoError(testDB.First(&preview).Error)
+	s.Equal(linkpreview.StatusFailed, preview.Status)
+	s.Empty(preview.Files())
+
+	// Internal addresses are refused by the default fetcher
+	worker.SetLinkFetcher(unfurl.New(unfurl.DefaultConfig()))
+	defer func() {
+		config := unfurl.DefaultConfig()
+		config.AllowPrivate = true
+		worker.SetLinkFetcher(unfurl.New(config))
+	}()
+	internal := s.create("Metadata " + s.server.URL + "/article")
+	s.unfurl(internal.ID)
+	s.Nil(s.find(internal.ID).LinkPreview)
+	s.Zero(s.hits.Load())
+}
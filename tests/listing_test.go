@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ListingTestSuite struct {
+	suite.Suite
+	service post.Service
+	alice   *user.User
+	bob     *user.User
+}
+
+func TestListingTestSuite(t *testing.T) {
+	suite.Run(t, new(ListingTestSuite))
+}
+
+func (s *ListingTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *ListingTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *ListingTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM attachments")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.alice = CreateTestUser(testDB, "alice@example.com", "hash", "user")
+	s.bob = CreateTestUser(testDB, "bob@example.com", "hash", "user")
+}
+
+// createPost stores a live post created at the given day of January 2026
+func (s *ListingTestSuite) createPost(u *user.User, tweet string, day, comments int) uint {
+	p := post.Post{Tweet: tweet, UserID: u.ID, CommentCount: comments}
+	p.CreatedAt = time.Date(2026, time.January, day, 12, 0, 0, 0, time.UTC)
+	s.Require().NoError(testDB.Create(&p).Error)
+	return p.ID
+}
+
+func (s *ListingTestSuite) list(path string, query url.Values) (int, pagination.CursorResponse, []post.PostResponse) {
+	resp, raw, err := MakeRequest(testApp, "GET", path+"?"+query.Encode(), nil, nil)
+	s.Require().NoError(err)
+
+	var result struct {
+		Data struct {
+			Data       []post.PostResponse `json:"data"`
+			NextCursor string              `json:"next_cursor"`
+			HasMore    bool                `json:"has_more"`
+			Count      int                 `json:"count"`
+		} `json:"data"`
+	}
+	if resp.StatusCode == http.StatusOK {
+		ParseJSON(s.T(), raw, &result)
+	}
+	page := pagination.CursorResponse{NextCursor: result.Data.NextCursor, HasMore: result.Data.HasMore, Count: result.Data.Count}
+	return resp.StatusCode, page, result.Data.Data
+}
+
+func tweets(posts []post.PostResponse) []string {
+	out := make([]string, 0, len(posts))
+	for _, p := range posts {
+		out = append(out, p.Tweet)
+	}
+	return out
+}
+
+func (s *ListingTestSuite) TestCursor_PagesNewestFirst() {
+	s.createPost(s.alice, "first", 1, 0)
+	s.createPost(s.bob, "second", 2, 0)
+	s.createPost(s.alice, "third", 3, 0)
+
+	status, page, posts := s.list("/api/posts", url.Values{"cursor": {""}, "limit": {"2"}})
+	s.Require().Equal(http.StatusOK, status)
+	s.True(page.HasMore)
+	s.Equal(2, page.Count)
+	s.Equal([]string{"third", "second"}, tweets(posts))
+
+	status, page, posts = s.list("/api/posts", url.Values{"cursor": {page.NextCursor}, "limit": {"2"}})
+	s.Require().Equal(http.StatusOK, status)
+	s.False(page.HasMore)
+	s.Empty(page.NextCursor)
+	s.Equal([]string{"first"}, tweets(posts))
+}
+
+func (s *ListingTestSuite) TestCursor_SortsByAllowlistedField() {
+	// Creation order differs from ID order
+	s.createPost(s.alice, "newest", 20, 1)
+	s.createPost(s.alice, "oldest", 5, 3)
+	s.createPost(s.alice, "middle", 10, 3)
+
+	var seen []string
+	cursor := ""
+	for {
+		status, page, posts := s.list("/api/posts", url.Values{"sort": {"created_at"}, "limit": {"1"}, "cursor": {cursor}})
+		s.Require().Equal(http.StatusOK, status)
+		seen = append(seen, tweets(posts)...)
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	s.Equal([]string{"oldest", "middle", "newest"}, seen)
+
+	// Ties on the sort field are broken by ID in the same direction
+	first, err := s.service.List(post.ListFilter{}, 0, pagination.CursorPagination{Limit: 1, SortBy: "comment_count", SortOrder: "desc"})
+	s.Require().NoError(err)
+	s.Equal([]string{"middle"}, tweets(first.Data.([]post.PostResponse)))
+	second, err := s.service.List(post.ListFilter{}, 0, pagination.CursorPagination{Limit: 2, SortBy: "comment_count", SortOrder: "desc", Cursor: first.NextCursor})
+	s.Require().NoError(err)
+	s.Equal([]string{"oldest", "newest"}, tweets(second.Data.([]post.PostResponse)))
+
+	status, _, _ := s.list("/api/posts", url.Values{"sort": {"tweet"}})
+	s.Equal(http.StatusBadRequest, status)
+	status, _, _ = s.list("/api/posts", url.Values{"sort": {"id,-created_at"}})
+	s.Equal(http.StatusBadRequest, status)
+	_, err = s.service.List(post.ListFilter{}, 0, pagination.CursorPagination{SortBy: "photo"})
+	s.IsType(&apierror.BadRequestError{}, err)
+}
+
+func (s *ListingTestSuite) TestCursor_Filters() {
+	s.createPost(s.alice, "alice early", 1, 0)
+	withPhoto := s.createPost(s.alice, "alice photo", 10, 4)
+	s.createPost(s.bob, "bob attachment", 15, 0)
+	s.createPost(s.bob, "bob late", 25, 0)
+	s.Require().NoError(testDB.Model(&post.Post{}).Where("id = ?", withPhoto).Update("photo", "sunset.jpg").Error)
+	var bobAttached post.Post
+	s.Require().NoError(testDB.Where("tweet = ?", "bob attachment").First(&bobAttached).Error)
+	s.Require().NoError(testDB.Create(&post.Attachment{PostID: &bobAttached.ID, UserID: s.bob.ID, Kind: post.AttachmentImage, Path: "/attachments/a.jpg"}).Error)
+
+	_, _, posts := s.list("/api/posts", url.Values{"user_id": {fmt.Sprint(s.alice.ID)}})
+	s.Equal([]string{"alice photo", "alice early"}, tweets(posts))
+
+	_, _, posts = s.list("/api/posts", url.Values{"created_from": {"2026-01-10"}, "created_to": {"2026-01-15"}})
+	s.Equal([]string{"bob attachment", "alice photo"}, tweets(posts))
+
+	_, _, posts = s.list("/api/posts", url.Values{"has_photo": {"true"}})
+	s.Equal([]string{"bob attachment", "alice photo"}, tweets(posts))
+	_, _, posts = s.list("/api/posts", url.Values{"has_photo": {"false"}})
+	s.Equal([]string{"bob late", "alice early"}, tweets(posts))
+
+	_, _, posts = s.list("/api/posts", url.Values{"filter_comment_count_gte": {"1"}})
+	s.Equal([]string{"alice photo"}, tweets(posts))
+
+	// The user in the path wins over user_id
+	_, _, posts = s.list(fmt.Sprintf("/api/users/%d/posts", s.bob.ID), url.Values{"cursor": {""}, "user_id": {fmt.Sprint(s.alice.ID)}})
+	s.Equal([]string{"bob late", "bob attachment"}, tweets(posts))
+
+	for _, query := range []url.Values{
+		{"filter_tweet_eq": {"x"}},
+		{"filter_user_id_like": {"1"}},
+		{"created_from": {"January"}},
+		{"created_from": {"2026-01-15"}, "created_to": {"2026-01-10"}},
+		{"has_photo": {"maybe"}},
+		{"cursor": {"not-a-cursor"}},
+	} {
+		status, _, _ := s.list("/api/posts", query)
+		s.Equal(http.StatusBadRequest, status, query.Encode())
+	}
+}
+
+func (s *ListingTestSuite) TestPageMode_KeptForCompatibility() {
+	s.createPost(s.alice, "first", 1, 0)
+	s.createPost(s.alice, "second", 2, 0)
+	s.createPost(s.bob, "other", 3, 0)
+
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/users/%d/posts?page=2&limit=1&sort=id", s.alice.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data     []post.PostResponse `json:"data"`
+		Paginate struct {
+			Total int64 `json:"total"`
+		} `json:"paginate"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	s.Equal([]string{"first"}, tweets(result.Data))
+	s.Equal(int64(2), result.Paginate.Total)
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRenderMarkdown_Sanitises(t *testing.T) {
+	rendered, err := post.RenderMarkdown("# Hello\n\nSome **bold** text.\n\n<script>alert(1)</script>\n\n[click](javascript:alert(1)) <img src=x onerror=alert(1)>")
+	assert.NoError(t, err)
+	assert.Contains(t, rendered, "<h1>Hello</h1>")
+	assert.Contains(t, rendered, "<strong>bold</strong>")
+	assert.NotContains(t, rendered, "<script")
+	assert.NotContains(t, rendered, "javascript:")
+	assert.NotContains(t, rendered, "onerror")
+}
+
+func TestExcerptAndReadingTime(t *testing.T) {
+	assert.Equal(t, "short text", post.Excerpt("short text", 20))
+	assert.Equal(t, "one two…", post.Excerpt("one two, three four", 12))
+
+	assert.Equal(t, 0, post.ReadingTime(""))
+	assert.Equal(t, 1, post.ReadingTime("a few words"))
+	assert.Equal(t, 2, post.ReadingTime(strings.Repeat("word ", 201)))
+
+	assert.Equal(t, "hello-world-2026", post.Slugify("  Hello, World! 2026 "))
+	assert.Equal(t, "café-au-lait", post.Slugify("Café au lait"))
+	assert.Equal(t, "post", post.Slugify("!!!"))
+	assert.LessOrEqual(t, len(post.Slugify(strings.Repeat("long title ", 20))), 80)
+}
+
+type LongFormTestSuite struct {
+	suite.Suite
+	service post.Service
+	author  *user.User
+	other   *user.User
+}
+
+func TestLongFormTestSuite(t *testing.T) {
+	suite.Run(t, new(LongFormTestSuite))
+}
+
+func (s *LongFormTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *LongFormTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *LongFormTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM post_revisions")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+	s.Require().NoError(testDB.Model(s.author).Update("username", "author").Error)
+	s.Require().NoError(testDB.Model(s.other).Update("username", "other").Error)
+}
+
+func (s *LongFormTestSuite) article(u *user.User, title, body string) *post.PostResponse {
+	resp, err := s.service.Create(&post.PostRequest{Title: title, Body: body, UserID: u.ID}, u.ID)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *LongFormTestSuite) bySlug(path string) (int, post.PostResponse) {
+	resp, raw, err := MakeRequest(testApp, "GET", path, nil, nil)
+	s.Require().NoError(err)
+
+	var result struct {
+		Data post.PostResponse `json:"data"`
+	}
+	if resp.StatusCode == http.StatusOK {
+		ParseJSON(s.T(), raw, &result)
+	}
+	return resp.StatusCode, result.Data
+}
+
+func (s *LongFormTestSuite) TestCreate_RendersBody() {
+	created := s.article(s.author, "My First Article", "Intro with **bold** text.\n\n<script>alert(1)</script>\n\n## Section\n\nMore words here.")
+
+	s.Require().NotNil(created.Title)
+	s.Equal("My First Article", *created.Title)
+	s.Require().NotNil(created.Slug)
+	s.Equal("my-first-article", *created.Slug)
+	s.Contains(created.Body, "**bold**")
+	s.Contains(created.BodyHTML, "<strong>bold</strong>")
+	s.Contains(created.BodyHTML, "<h2>Section</h2>")
+	s.NotContains(created.BodyHTML, "<script")
+	s.Equal("Intro with bold text. Section More words here.", created.Excerpt)
+	s.Equal(1, created.ReadingTime)
+
+	// Plain posts carry no long-form fields
+	tweet, err := s.service.Create(&post.PostRequest{Tweet: "Just a tweet", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Nil(tweet.Title)
+	s.Nil(tweet.Slug)
+	s.Empty(tweet.BodyHTML)
+	s.Zero(tweet.ReadingTime)
+}
+
+func (s *LongFormTestSuite) TestCreate_Validation() {
+	for _, req := range []post.PostRequest{
+		{UserID: s.author.ID},
+		{Title: "Title only", UserID: s.author.ID},
+		{Body: "Body only", UserID: s.author.ID},
+		{Title: "   ", Body: "Blank title", UserID: s.author.ID},
+		{Title: strings.Repeat("t", post.MaxTitleLength+1), Body: "Body", UserID: s.author.ID},
+	} {
+		_, err := s.service.Create(&req, s.author.ID)
+		s.IsType(&apierror.UnprocessableEntityError{}, err, req.Title)
+	}
+}
+
+func (s *LongFormTestSuite) TestSlug_UniquePerAuthor() {
+	first := s.article(s.author, "Hello World", "First")
+	second := s.article(s.author, "Hello, world!", "Second")
+	others := s.article(s.other, "Hello World", "By someone else")
+
+	s.Equal("hello-world", *first.Slug)
+	s.Equal("hello-world-2", *second.Slug)
+	s.Equal("hello-world", *others.Slug)
+
+	// Deleted posts keep their slug taken
+	s.Require().NoError(s.service.Delete(second.ID, s.author.ID, false))
+	third := s.article(s.author, "Hello World", "Third")
+	s.Equal("hello-world-3", *third.Slug)
+
+	status, found := s.bySlug("/api/posts/by-slug/hello-world")
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(first.ID, found.ID)
+
+	status, found = s.bySlug("/api/posts/by-slug/hello-world?author=other")
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(others.ID, found.ID)
+
+	status, _ = s.bySlug("/api/posts/by-slug/hello-world-2")
+	s.Equal(http.StatusNotFound, status)
+	status, _ = s.bySlug("/api/posts/by-slug/hello-world?author=nobody")
+	s.Equal(http.StatusNotFound, status)
+}
+
+// racingSlugs reports the next slug checks as free, like a concurrent post that has not been inserted yet
+type racingSlugs struct {
+	post.Repository
+	stale int
+}
+
+func (r *racingSlugs) SlugTaken(userID uint, slug string) (bool, error) {
+	if r.stale > 0 {
+		r.stale--
+		return false, nil
+	}
+	return r.Repository.SlugTaken(userID, slug)
+}
+
+func (s *LongFormTestSuite) TestSlug_RetriedWhenTakenConcurrently() {
+	first := s.article(s.author, "Hello World", "First")
+
+	repo := &racingSlugs{Repository: postgres.NewPostRepository(testDB), stale: 1}
+	service := postsvc.NewPostService(repo, postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	second, err := service.Create(&post.PostRequest{Title: "Hello World", Body: "Second", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal("hello-world", *first.Slug)
+	s.Equal("hello-world-2", *second.Slug)
+
+	tweet, err := service.Create(&post.PostRequest{Tweet: "Short", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	title, body := "Hello World", "Third"
+	repo.stale = 1
+	updated, err := service.Update(tweet.ID, &post.PostUpdateRequest{ID: tweet.ID, UserID: s.author.ID, Tweet: "Short", Title: &title, Body: &body}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal("hello-world-3", *updated.Slug)
+}
+
+func (s *LongFormTestSuite) TestSlug_HiddenFromOthersWhilePrivate() {
+	resp, err := s.service.Create(&post.PostRequest{Title: "Secret", Body: "Notes", UserID: s.author.ID, Visibility: post.VisibilityPrivate}, s.author.ID)
+	s.Require().NoError(err)
+
+	_, err = s.service.FindBySlug(*resp.Slug, "", s.other.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+	found, err := s.service.FindBySlug(*resp.Slug, "author", s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(resp.ID, found.ID)
+}
+
+func (s *LongFormTestSuite) TestUpdate_KeepsSlugAndRecordsRevision() {
+	created := s.article(s.author, "Draft Title", "Old *body*")
+
+	title, body := "Better Title", "New **body**"
+	updated, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, UserID: s.author.ID, Title: &title, Body: &body}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal("Better Title", *updated.Title)
+	s.Equal("draft-title", *updated.Slug)
+	s.Contains(updated.BodyHTML, "<strong>body</strong>")
+	s.True(updated.Edited)
+
+	// Omitted fields are kept
+	kept, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, UserID: s.author.ID, Tweet: "Teaser"}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal("New **body**", kept.Body)
+
+	var revisions []post.Revision
+	s.Require().NoError(testDB.Where("post_id = ?", created.ID).Order("id").Find(&revisions).Error)
+	s.Require().Len(revisions, 2)
+	s.Equal("Draft Title", *revisions[0].Title)
+	s.Equal("Old *body*", revisions[0].Body)
+
+	restored, err := s.service.RestoreRevision(created.ID, revisions[0].ID, s.author.ID, false)
+	s.Require().NoError(err)
+	s.Equal("Draft Title", *restored.Title)
+	s.Contains(restored.BodyHTML, "<em>body</em>")
+
+	// A title without a body is rejected, clearing both turns the post into a tweet
+	empty := ""
+	_, err = s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, UserID: s.author.ID, Tweet: "Teaser", Body: &empty}, s.author.ID)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+	plain, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, UserID: s.author.ID, Tweet: "Teaser", Title: &empty, Body: &empty}, s.author.ID)
+	s.Require().NoError(err)
+	s.Nil(plain.Title)
+	s.Nil(plain.Slug)
+	s.Empty(plain.BodyHTML)
+
+	status, _ := s.bySlug(fmt.Sprintf("/api/posts/by-slug/%s", "draft-title"))
+	s.Equal(http.StatusNotFound, status)
+}
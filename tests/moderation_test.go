@@ -0,0 +1,281 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	commentsvc "starter-gofiber/internal/service/comment"
+	moderationsvc "starter-gofiber/internal/service/moderation"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/database"
+	"starter-gofiber/variables"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ModerationTestSuite struct {
+	suite.Suite
+	service   moderation.Service
+	posts     post.Service
+	author    *user.User
+	reporter  *user.User
+	moderator *user.User
+}
+
+func TestModerationTestSuite(t *testing.T) {
+	suite.Run(t, new(ModerationTestSuite))
+}
+
+func (s *ModerationTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	s.Require().NoError(testDB.AutoMigrate(&database.AuditLog{}))
+	moderation.SetAuditLog(true)
+	moderation.SetFilter([]moderation.Rule{
+		moderation.KeywordRule(moderation.FilterBlock, "forbidden phrase"),
+		moderation.KeywordRule(moderation.FilterFlag, "suspicious"),
+	})
+}
+
+func (s *ModerationTestSuite) TearDownSuite() {
+	moderation.SetFilter(nil)
+	moderation.SetAuditLog(false)
+	CleanupTestDB()
+}
+
+func (s *ModerationTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM audit_logs")
+	testDB.Exec("DELETE FROM reports")
+	testDB.Exec("DELETE FROM comments")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	repo := postgres.NewModerationRepository(testDB)
+	postRepo := postgres.NewPostRepository(testDB)
+	commentRepo := postgres.NewCommentRepository(testDB)
+	userRepo := postgres.NewUserRepository(testDB)
+	s.posts = postsvc.NewPostService(postRepo, userRepo, repo)
+	s.service = moderationsvc.NewModerationService(repo, postRepo, s.posts,
+		commentRepo, commentsvc.NewCommentService(commentRepo, postRepo), userRepo)
+
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.reporter = CreateTestUser(testDB, "reporter@example.com", "hash", "user")
+	s.moderator = CreateTestUser(testDB, "moderator@example.com", "hash", variables.ADMIN_ROLE)
+}
+
+func (s *ModerationTestSuite) createPost(tweet string) *post.PostResponse {
+	resp, err := s.posts.Create(&post.PostRequest{Tweet: tweet, UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *ModerationTestSuite) report(u *user.User, req moderation.ReportRequest) (*http.Response, moderation.ReportResponse) {
+	resp, raw, err := MakeRequest(testApp, "POST", "/api/reports", req, AuthHeader(u))
+	s.Require().NoError(err)
+
+	var result struct {
+		Data moderation.ReportResponse `json:"data"`
+	}
+	if resp.StatusCode == http.StatusCreated {
+		ParseJSON(s.T(), raw, &result)
+	}
+	return resp, result.Data
+}
+
+func (s *ModerationTestSuite) resolve(reportID uint, action string) (*moderation.ReportResponse, error) {
+	return s.service.Resolve(reportID, &moderation.ResolveRequest{Action: action, Note: "Reviewed"}, s.moderator.ID)
+}
+
+func (s *ModerationTestSuite) moderationState(postID uint) string {
+	var p post.Post
+	s.Require().NoError(testDB.First(&p, postID).Error)
+	return p.ModerationState
+}
+
+func (s *ModerationTestSuite) auditCount(entityType string, entityID uint) int64 {
+	var count int64
+	testDB.Model(&database.AuditLog{}).
+		Where("entity_type = ? AND entity_id = ? AND action = ?", entityType, entityID, database.AuditActionModerate).
+		Count(&count)
+	return count
+}
+
+func (s *ModerationTestSuite) TestFilter_BlocksPost() {
+	_, err := s.posts.Create(&post.PostRequest{Tweet: "This has a Forbidden Phrase in it", UserID: s.author.ID}, s.author.ID)
+	s.Require().Error(err)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+
+	var count int64
+	testDB.Model(&post.Post{}).Count(&count)
+	s.Zero(count)
+	s.Equal(int64(1), s.auditCount("users", s.author.ID))
+}
+
+func (s *ModerationTestSuite) TestFilter_FlagsPostUntilApproved() {
+	flagged := s.createPost("Something suspicious here")
+	s.Equal(post.ModerationFlagged, flagged.ModerationState)
+
+	posts, _, err := s.posts.FindAll(0, 1, 10)
+	s.Require().NoError(err)
+	s.Empty(posts)
+	_, err = s.posts.FindByID(flagged.ID, s.reporter.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+	_, err = s.posts.FindByID(flagged.ID, s.author.ID)
+	s.NoError(err)
+
+	var report moderation.Report
+	s.Require().NoError(testDB.Where("target_type = ? AND target_id = ?", moderation.TargetPost, flagged.ID).First(&report).Error)
+	s.Nil(report.ReporterID)
+	s.Equal(moderation.ReasonFilter, report.Reason)
+	s.Equal(int64(1), s.auditCount("reports", report.ID))
+
+	resolved, err := s.resolve(report.ID, moderation.ActionDismiss)
+	s.Require().NoError(err)
+	s.Equal(moderation.StatusDismissed, resolved.Status)
+	s.Equal(post.ModerationVisible, s.moderationState(flagged.ID))
+
+	posts, _, err = s.posts.FindAll(0, 1, 10)
+	s.Require().NoError(err)
+	s.Len(posts, 1)
+}
+
+func (s *ModerationTestSuite) TestFilter_ScreensEdits() {
+	p := s.createPost("Hello")
+
+	_, err := s.posts.Update(p.ID, &post.PostUpdateRequest{ID: p.ID, Tweet: "Now with a forbidden phrase", UserID: s.author.ID}, s.author.ID)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+	s.Equal(int64(1), s.auditCount("posts", p.ID))
+
+	updated, err := s.posts.Update(p.ID, &post.PostUpdateRequest{ID: p.ID, Tweet: "Now suspicious", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.ModerationFlagged, updated.ModerationState)
+	s.Equal(post.ModerationFlagged, s.moderationState(p.ID))
+
+	var reports int64
+	testDB.Model(&moderation.Report{}).Where("target_type = ? AND target_id = ?", moderation.TargetPost, p.ID).Count(&reports)
+	s.Equal(int64(1), reports)
+
+	// Still flagged, the report is not queued twice
+	_, err = s.posts.Update(p.ID, &post.PostUpdateRequest{ID: p.ID, Tweet: "Still suspicious", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	testDB.Model(&moderation.Report{}).Where("target_type = ? AND target_id = ?", moderation.TargetPost, p.ID).Count(&reports)
+	s.Equal(int64(1), reports)
+}
+
+func (s *ModerationTestSuite) TestReport_Validation() {
+	p := s.createPost("Hello")
+
+	resp, _ := s.report(s.author, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID, Reason: moderation.ReasonSpam})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp, _ = s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID + 100, Reason: moderation.ReasonSpam})
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID, Reason: moderation.ReasonFilter})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp, created := s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID, Reason: moderation.ReasonSpam, Note: "Buy now links"})
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	s.Equal(moderation.StatusOpen, created.Status)
+	s.Equal(s.author.ID, created.TargetUserID)
+
+	// One pending report per reporter and target
+	resp, _ = s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID, Reason: moderation.ReasonHate})
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *ModerationTestSuite) TestQueue_AssignAndHide() {
+	p := s.createPost("Hello")
+	other := CreateTestUser(testDB, "other@example.com", "hash", "user")
+	_, first := s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID, Reason: moderation.ReasonSpam})
+	_, second := s.report(other, moderation.ReportRequest{TargetType: moderation.TargetPost, TargetID: p.ID, Reason: moderation.ReasonHarassment})
+
+	resp, _, err := MakeRequest(testApp, "GET", "/api/moderation/reports", nil, AuthHeader(s.reporter))
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	queue, total, err := s.service.Queue(moderation.ReportFilter{TargetType: moderation.TargetPost}, 1, 20)
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.Equal(first.ID, queue[0].ID)
+
+	assigned, err := s.service.Assign(first.ID, &moderation.AssignRequest{}, s.moderator.ID)
+	s.Require().NoError(err)
+	s.Equal(moderation.StatusInReview, assigned.Status)
+	s.Require().NotNil(assigned.AssigneeID)
+	s.Equal(s.moderator.ID, *assigned.AssigneeID)
+
+	queue, _, err = s.service.Queue(moderation.ReportFilter{AssigneeID: &s.moderator.ID}, 1, 20)
+	s.Require().NoError(err)
+	s.Len(queue, 1)
+
+	resolved, err := s.resolve(first.ID, moderation.ActionHide)
+	s.Require().NoError(err)
+	s.Equal(moderation.StatusResolved, resolved.Status)
+	s.Equal(moderation.ActionHide, resolved.Resolution)
+	s.Equal(post.ModerationHidden, s.moderationState(p.ID))
+	s.Equal(int64(1), s.auditCount("posts", p.ID))
+
+	// The other report on the post is settled too
+	var secondReport moderation.Report
+	s.Require().NoError(testDB.First(&secondReport, second.ID).Error)
+	s.Equal(moderation.StatusResolved, secondReport.Status)
+
+	// Edits by the author keep the post hidden
+	_, err = s.posts.Update(p.ID, &post.PostUpdateRequest{ID: p.ID, Tweet: "Hello again", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.ModerationHidden, s.moderationState(p.ID))
+
+	_, err = s.resolve(first.ID, moderation.ActionDismiss)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+}
+
+func (s *ModerationTestSuite) TestResolve_DeleteComment() {
+	p := s.createPost("Hello")
+	c := comment.Comment{PostID: p.ID, UserID: s.author.ID, Body: "Rude reply"}
+	s.Require().NoError(testDB.Create(&c).Error)
+
+	_, report := s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetComment, TargetID: c.ID, Reason: moderation.ReasonHarassment})
+	_, err := s.resolve(report.ID, moderation.ActionDelete)
+	s.Require().NoError(err)
+
+	var count int64
+	testDB.Model(&comment.Comment{}).Where("id = ?", c.ID).Count(&count)
+	s.Zero(count)
+}
+
+func (s *ModerationTestSuite) TestResolve_SuspendUser() {
+	_, report := s.report(s.reporter, moderation.ReportRequest{TargetType: moderation.TargetUser, TargetID: s.author.ID, Reason: moderation.ReasonSpam})
+
+	// Users can only be suspended
+	_, err := s.resolve(report.ID, moderation.ActionHide)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+
+	s.Require().NoError(testDB.Omit("User").Create(&user.RefreshToken{UserID: s.author.ID, Token: "author-session", ExpiresAt: time.Now().Add(time.Hour)}).Error)
+	header := AuthHeader(s.author)
+	resp, _, err := MakeRequest(testApp, "GET", "/api/auth/profile", nil, header)
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	resolved, err := s.resolve(report.ID, moderation.ActionSuspend)
+	s.Require().NoError(err)
+	s.Equal(moderation.ActionSuspend, resolved.Resolution)
+
+	var suspended user.User
+	s.Require().NoError(testDB.First(&suspended, s.author.ID).Error)
+	s.NotNil(suspended.SuspendedAt)
+	_, err = postgres.NewUserRepository(testDB).FindRefreshTokenByToken("author-session")
+	s.Error(err)
+	s.Equal(int64(1), s.auditCount("users", s.author.ID))
+
+	// Access tokens issued before the suspension stop working
+	resp, _, err = MakeRequest(testApp, "GET", "/api/auth/profile", nil, header)
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
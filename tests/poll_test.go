@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/poll"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type PollTestSuite struct {
+	suite.Suite
+	posts  post.Service
+	author *user.User
+	voter  *user.User
+	other  *user.User
+}
+
+func TestPollTestSuite(t *testing.T) {
+	suite.Run(t, new(PollTestSuite))
+}
+
+func (s *PollTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	worker.SetDB(testDB)
+}
+
+func (s *PollTestSuite) TearDownSuite() {
+	worker.SetDB(nil)
+	CleanupTestDB()
+}
+
+func (s *PollTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM poll_votes")
+	testDB.Exec("DELETE FROM poll_ballots")
+	testDB.Exec("DELETE FROM poll_options")
+	testDB.Exec("DELETE FROM polls")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.posts = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.voter = CreateTestUser(testDB, "voter@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+}
+
+func (s *PollTestSuite) createPoll(multiple bool, options ...string) *post.PostResponse {
+	resp, err := s.posts.Create(&post.PostRequest{Tweet: "Which one?", UserID: s.author.ID, Poll: &poll.PollRequest{
+		Options:        options,
+		MultipleChoice: multiple,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}}, s.author.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(resp.Poll)
+	return resp
+}
+
+func (s *PollTestSuite) find(postID uint, u *user.User) poll.PollResponse {
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/poll", postID), nil, AuthHeader(u))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data poll.PollResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	return result.Data
+}
+
+func (s *PollTestSuite) vote(postID uint, u *user.User, optionIDs ...uint) (int, poll.PollResponse) {
+	resp, raw, err := MakeRequest(testApp, "POST", fmt.Sprintf("/api/posts/%d/poll/votes", postID),
+		poll.VoteRequest{OptionIDs: optionIDs}, AuthHeader(u))
+	s.Require().NoError(err)
+	var result struct {
+		Data poll.PollResponse `json:"data"`
+	}
+	if resp.StatusCode == http.StatusOK {
+		ParseJSON(s.T(), raw, &result)
+	}
+	return resp.StatusCode, result.Data
+}
+
+func votes(p poll.PollResponse) []int {
+	out := make([]int, 0, len(p.Options))
+	for _, o := range p.Options {
+		if o.Votes == nil {
+			return nil
+		}
+		out = append(out, *o.Votes)
+	}
+	return out
+}
+
+func (s *PollTestSuite) TestCreate_Validation() {
+	now := time.Now()
+	for name, req := range map[string]poll.PollRequest{
+		"one option":     {Options: []string{"Yes"}, ExpiresAt: now.Add(time.Hour)},
+		"five options":   {Options: []string{"A", "B", "C", "D", "E"}, ExpiresAt: now.Add(time.Hour)},
+		"blank option":   {Options: []string{"Yes", "  "}, ExpiresAt: now.Add(time.Hour)},
+		"same option":    {Options: []string{"Yes", "yes"}, ExpiresAt: now.Add(time.Hour)},
+		"expires soon":   {Options: []string{"Yes", "No"}, ExpiresAt: now.Add(time.Minute)},
+		"expires late":   {Options: []string{"Yes", "No"}, ExpiresAt: now.Add(8 * 24 * time.Hour)},
+		"no expiry time": {Options: []string{"Yes", "No"}},
+	} {
+		_, err := s.posts.Create(&post.PostRequest{Tweet: "Vote", UserID: s.author.ID, Poll: &req}, s.author.ID)
+		s.IsType(&apierror.UnprocessableEntityError{}, err, name)
+	}
+
+	// Scheduled posts count the duration from their publish time
+	publishAt := now.Add(48 * time.Hour)
+	resp, err := s.posts.Create(&post.PostRequest{Tweet: "Later", UserID: s.author.ID, PublishAt: &publishAt, Poll: &poll.PollRequest{
+		Options: []string{" Yes ", "No"}, ExpiresAt: publishAt.Add(time.Hour),
+	}}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal("Yes", resp.Poll.Options[0].Text)
+}
+
+func (s *PollTestSuite) TestVote_ResultsHiddenUntilVoted() {
+	created := s.createPoll(false, "Tea", "Coffee", "Water")
+	options := created.Poll.Options
+
+	s.Nil(votes(s.find(created.ID, nil)))
+	s.Nil(votes(s.find(created.ID, s.voter)))
+	s.Equal([]int{0, 0, 0}, votes(s.find(created.ID, s.author)))
+
+	status, _ := s.vote(created.ID, nil, options[0].ID)
+	s.Equal(http.StatusUnauthorized, status)
+	status, _ = s.vote(created.ID, s.voter, options[0].ID, options[1].ID)
+	s.Equal(http.StatusUnprocessableEntity, status)
+	status, _ = s.vote(created.ID, s.voter)
+	s.Equal(http.StatusUnprocessableEntity, status)
+	status, _ = s.vote(created.ID, s.voter, options[0].ID+100)
+	s.Equal(http.StatusUnprocessableEntity, status)
+
+	status, voted := s.vote(created.ID, s.voter, options[1].ID)
+	s.Require().Equal(http.StatusOK, status)
+	s.True(voted.Voted)
+	s.Equal([]uint{options[1].ID}, voted.MyVotes)
+	s.Equal([]int{0, 1, 0}, votes(voted))
+	s.Require().NotNil(voted.VoterCount)
+	s.Equal(1, *voted.VoterCount)
+
+	// One ballot per user
+	status, _ = s.vote(created.ID, s.voter, options[0].ID)
+	s.Equal(http.StatusBadRequest, status)
+
+	s.Nil(votes(s.find(created.ID, s.other)))
+	s.Equal([]int{0, 1, 0}, votes(s.find(created.ID, s.voter)))
+
+	// Listings carry the viewer's ballot
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", created.ID), nil, AuthHeader(s.voter))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data post.PostResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	s.Require().NotNil(result.Data.Poll)
+	s.True(result.Data.Poll.Voted)
+	s.Equal([]int{0, 1, 0}, votes(*result.Data.Poll))
+
+	resp, _, err = MakeRequest(testApp, "GET", "/api/posts/999999/poll", nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *PollTestSuite) TestVote_MultipleChoice() {
+	created := s.createPoll(true, "Red", "Green", "Blue")
+	options := created.Poll.Options
+
+	status, _ := s.vote(created.ID, s.voter, options[0].ID, options[0].ID)
+	s.Equal(http.StatusUnprocessableEntity, status)
+
+	status, voted := s.vote(created.ID, s.voter, options[0].ID, options[2].ID)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal([]int{1, 0, 1}, votes(voted))
+	status, voted = s.vote(created.ID, s.other, options[2].ID)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal([]int{1, 0, 2}, votes(voted))
+	s.Equal(2, *voted.VoterCount)
+}
+
+func (s *PollTestSuite) TestClose_PersistsFinalResults() {
+	created := s.createPoll(false, "Yes", "No")
+	options := created.Poll.Options
+	status, _ := s.vote(created.ID, s.voter, options[0].ID)
+	s.Require().Equal(http.StatusOK, status)
+
+	deleted := s.createPoll(false, "Keep", "Drop")
+	s.Require().NoError(s.posts.Delete(deleted.ID, s.author.ID, false))
+
+	past := time.Now().Add(-time.Minute)
+	s.Require().NoError(testDB.Model(&poll.Poll{}).Where("id IN ?", []uint{created.Poll.ID, deleted.Poll.ID}).Update("expires_at", past).Error)
+
+	// Expired polls stop taking votes before the job ran and show their results to everyone
+	status, _ = s.vote(created.ID, s.other, options[1].ID)
+	s.Equal(http.StatusUnprocessableEntity, status)
+	expired := s.find(created.ID, nil)
+	s.True(expired.Closed)
+	s.Equal([]int{1, 0}, votes(expired))
+
+	s.Require().NoError(worker.HandleClosePolls(context.Background(), asynq.NewTask(worker.TaskClosePolls, nil)))
+
+	var closed poll.Poll
+	s.Require().NoError(testDB.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).First(&closed, created.Poll.ID).Error)
+	s.Require().NotNil(closed.ClosedAt)
+	s.Equal(1, closed.VoterCount)
+	s.Equal(1, closed.Options[0].VoteCount)
+	s.Equal(0, closed.Options[1].VoteCount)
+
+	// Polls of deleted posts are left open
+	var kept poll.Poll
+	s.Require().NoError(testDB.First(&kept, deleted.Poll.ID).Error)
+	s.Nil(kept.ClosedAt)
+
+	final := s.find(created.ID, s.other)
+	s.True(final.Closed)
+	s.Equal([]int{1, 0}, votes(final))
+	s.Equal(1, *final.VoterCount)
+
+	// Closing again does nothing
+	s.Require().NoError(worker.HandleClosePolls(context.Background(), asynq.NewTask(worker.TaskClosePolls, nil)))
+}
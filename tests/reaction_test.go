@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReactionTestSuite struct {
+	suite.Suite
+	author *user.User
+	fan    *user.User
+	post   *post.Post
+}
+
+func TestReactionTestSuite(t *testing.T) {
+	suite.Run(t, new(ReactionTestSuite))
+}
+
+func (s *ReactionTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *ReactionTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *ReactionTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM reactions")
+	testDB.Exec("DELETE FROM reaction_counts")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.fan = CreateTestUser(testDB, "fan@example.com", "hash", "user")
+	s.post = &post.Post{Tweet: "React to me", UserID: s.author.ID}
+	testDB.Create(s.post)
+}
+
+func (s *ReactionTestSuite) react(u *user.User, reactionType string) (*http.Response, reaction.Summary) {
+	resp, raw, err := MakeRequest(testApp, "PUT", fmt.Sprintf("/api/posts/%d/reactions", s.post.ID),
+		reaction.ReactRequest{Type: reactionType}, AuthHeader(u))
+	s.Require().NoError(err)
+
+	var result struct {
+		Data reaction.Summary `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	return resp, result.Data
+}
+
+func (s *ReactionTestSuite) TestReact_IsIdempotent() {
+	_, summary := s.react(s.fan, "like")
+	s.Equal(1, summary.Counts[reaction.TypeLike])
+
+	resp, summary := s.react(s.fan, "like")
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(1, summary.Total)
+	s.Require().NotNil(summary.MyReaction)
+	s.Equal(reaction.TypeLike, *summary.MyReaction)
+
+	// Switching type moves the count instead of adding one
+	_, summary = s.react(s.fan, "love")
+	s.Equal(0, summary.Counts[reaction.TypeLike])
+	s.Equal(1, summary.Counts[reaction.TypeLove])
+	s.Equal(1, summary.Total)
+}
+
+func (s *ReactionTestSuite) TestReact_RejectsUnknownType() {
+	resp, _, err := MakeRequest(testApp, "PUT", fmt.Sprintf("/api/posts/%d/reactions", s.post.ID),
+		reaction.ReactRequest{Type: "meh"}, AuthHeader(s.fan))
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func (s *ReactionTestSuite) TestUnreact_IsIdempotent() {
+	s.react(s.fan, "haha")
+
+	for i := 0; i < 2; i++ {
+		resp, raw, err := MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/posts/%d/reactions", s.post.ID), nil, AuthHeader(s.fan))
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data reaction.Summary `json:"data"`
+		}
+		ParseJSON(s.T(), raw, &result)
+		s.Equal(0, result.Data.Total)
+		s.Nil(result.Data.MyReaction)
+	}
+}
+
+func (s *ReactionTestSuite) TestPostResponse_ReactedFlag() {
+	s.react(s.fan, "wow")
+	s.react(s.author, "like")
+
+	type postResult struct {
+		Data post.PostResponse `json:"data"`
+	}
+
+	_, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", s.post.ID), nil, AuthHeader(s.fan))
+	s.Require().NoError(err)
+	var mine postResult
+	ParseJSON(s.T(), raw, &mine)
+	s.True(mine.Data.Reacted)
+	s.Require().NotNil(mine.Data.Reactions)
+	s.Equal(2, mine.Data.Reactions.Total)
+	s.Equal(reaction.TypeWow, *mine.Data.Reactions.MyReaction)
+
+	_, raw, err = MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", s.post.ID), nil, nil)
+	s.Require().NoError(err)
+	var anonymous postResult
+	ParseJSON(s.T(), raw, &anonymous)
+	s.False(anonymous.Data.Reacted)
+	s.Equal(2, anonymous.Data.Reactions.Total)
+}
+
+func (s *ReactionTestSuite) TestReactors_FilterByType() {
+	s.react(s.fan, "like")
+	s.react(s.author, "sad")
+
+	_, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/reactions/users?type=like", s.post.ID), nil, nil)
+	s.Require().NoError(err)
+	var result struct {
+		Data struct {
+			Data []reaction.ReactorResponse `json:"data"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	s.Require().Len(result.Data.Data, 1)
+	s.Equal(s.fan.ID, result.Data.Data[0].UserID)
+
+	resp, _, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/reactions/users?type=meh", s.post.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *ReactionTestSuite) TestRepository_SaveCountsReplacesSnapshot() {
+	repo := postgres.NewReactionRepository(testDB)
+	s.react(s.fan, "like")
+	s.react(s.author, "like")
+
+	counts, err := repo.CountByPosts([]uint{s.post.ID})
+	s.Require().NoError(err)
+	s.Require().NoError(repo.SaveCounts([]uint{s.post.ID}, counts))
+
+	stored, err := repo.FindCounts([]uint{s.post.ID})
+	s.Require().NoError(err)
+	s.Require().Len(stored, 1)
+	s.Equal(2, stored[0].Count)
+
+	// Posts without reactions lose their stale snapshot
+	s.Require().NoError(repo.SaveCounts([]uint{s.post.ID}, nil))
+	stored, err = repo.FindCounts([]uint{s.post.ID})
+	s.Require().NoError(err)
+	s.Empty(stored)
+}
+
+func (s *ReactionTestSuite) TestNonPublicPost_RejectsReactions() {
+	testDB.Model(s.post).Update("visibility", post.VisibilityFollowers)
+
+	resp, _ := s.react(s.fan, "like")
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, _, err := MakeRequest(testApp, "DELETE", fmt.Sprintf("/api/posts/%d/reactions", s.post.ID), nil, AuthHeader(s.fan))
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	url := fmt.Sprintf("/api/posts/%d/reactions/users", s.post.ID)
+	resp, _, err = MakeRequest(testApp, "GET", url, nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	// The author may still react and list reactors
+	resp, _ = s.react(s.author, "like")
+	s.Equal(http.StatusOK, resp.StatusCode)
+	resp, _, err = MakeRequest(testApp, "GET", url, nil, AuthHeader(s.author))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RepostTestSuite struct {
+	suite.Suite
+	posts  post.Service
+	author *user.User
+	other  *user.User
+}
+
+func TestRepostTestSuite(t *testing.T) {
+	suite.Run(t, new(RepostTestSuite))
+}
+
+func (s *RepostTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *RepostTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *RepostTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM reposts")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.posts = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+}
+
+func (s *RepostTestSuite) counts(postID uint) (reposts, quotes int) {
+	var p post.Post
+	s.Require().NoError(testDB.Unscoped().First(&p, postID).Error)
+	return p.RepostCount, p.QuoteCount
+}
+
+func (s *RepostTestSuite) repost(method string, postID uint, u *user.User) int {
+	var headers map[string]string
+	if u != nil {
+		headers = AuthHeader(u)
+	}
+	resp, _, err := MakeRequest(testApp, method, fmt.Sprintf("/api/posts/%d/repost", postID), nil, headers)
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+func (s *RepostTestSuite) TestQuote_EmbedsAndCountsOriginal() {
+	original := CreateTestPost(s.posts, s.author, "Original thought", nil)
+	quote := CreateTestPost(s.posts, s.other, "Well said", &original.ID)
+
+	s.Require().NotNil(quote.QuotedPostID)
+	s.Equal(original.ID, *quote.QuotedPostID)
+	s.Require().NotNil(quote.QuotedPost)
+	s.Equal("Original thought", quote.QuotedPost.Tweet)
+	_, quotes := s.counts(original.ID)
+	s.Equal(1, quotes)
+
+	// Quotes of quotes embed one level deep
+	nested := CreateTestPost(s.posts, s.author, "Quoting the quote", &quote.ID)
+	s.Require().NotNil(nested.QuotedPost)
+	s.Equal(quote.ID, nested.QuotedPost.ID)
+	s.Require().NotNil(nested.QuotedPost.QuotedPostID)
+	s.Nil(nested.QuotedPost.QuotedPost)
+
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/quotes", original.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data struct {
+			Data []post.PostResponse `json:"data"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	s.Equal([]string{"Well said"}, tweets(result.Data.Data))
+
+	// Edits keep the quoted post
+	updated, err := s.posts.Update(quote.ID, &post.PostUpdateRequest{ID: quote.ID, UserID: s.other.ID, Tweet: "Very well said"}, s.other.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(updated.QuotedPost)
+	s.Equal(original.ID, updated.QuotedPost.ID)
+
+	s.Require().NoError(s.posts.Delete(quote.ID, s.other.ID, false))
+	_, quotes = s.counts(original.ID)
+	s.Zero(quotes)
+}
+
+func (s *RepostTestSuite) TestQuote_ChecksOriginal() {
+	missing := uint(999999)
+	_, err := s.posts.Create(&post.PostRequest{Tweet: "Quoting nothing", UserID: s.other.ID, QuotedPostID: &missing}, s.other.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+
+	private, err := s.posts.Create(&post.PostRequest{Tweet: "Only me", UserID: s.author.ID, Visibility: post.VisibilityPrivate}, s.author.ID)
+	s.Require().NoError(err)
+	_, err = s.posts.Create(&post.PostRequest{Tweet: "Peeking", UserID: s.other.ID, QuotedPostID: &private.ID}, s.other.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+
+	deleted := CreateTestPost(s.posts, s.author, "Soon gone", nil)
+	s.Require().NoError(s.posts.Delete(deleted.ID, s.author.ID, false))
+	_, err = s.posts.Create(&post.PostRequest{Tweet: "Too late", UserID: s.other.ID, QuotedPostID: &deleted.ID}, s.other.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+}
+
+func (s *RepostTestSuite) TestQuote_HidesOriginalNoLongerShared() {
+	original := CreateTestPost(s.posts, s.author, "Public for now", nil)
+	quote := CreateTestPost(s.posts, s.other, "Look at this", &original.ID)
+
+	_, err := s.posts.Update(original.ID, &post.PostUpdateRequest{ID: original.ID, UserID: s.author.ID, Tweet: "Public for now", Visibility: post.VisibilityPrivate}, s.author.ID)
+	s.Require().NoError(err)
+
+	found, err := s.posts.FindByID(quote.ID, s.other.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(found.QuotedPostID)
+	s.Nil(found.QuotedPost)
+	found, err = s.posts.FindByID(quote.ID, s.author.ID)
+	s.Require().NoError(err)
+	s.NotNil(found.QuotedPost)
+
+	// A deleted original is gone for everyone
+	s.Require().NoError(s.posts.Delete(original.ID, s.author.ID, false))
+	found, err = s.posts.FindByID(quote.ID, s.author.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(found.QuotedPostID)
+	s.Nil(found.QuotedPost)
+}
+
+func (s *RepostTestSuite) TestRepost_CountsOncePerUser() {
+	original := CreateTestPost(s.posts, s.author, "Worth sharing", nil)
+
+	s.Equal(http.StatusUnauthorized, s.repost("PUT", original.ID, nil))
+	s.Equal(http.StatusOK, s.repost("PUT", original.ID, s.other))
+	s.Equal(http.StatusOK, s.repost("PUT", original.ID, s.other))
+	s.Equal(http.StatusOK, s.repost("PUT", original.ID, s.author))
+	reposts, _ := s.counts(original.ID)
+	s.Equal(2, reposts)
+
+	s.Equal(http.StatusOK, s.repost("DELETE", original.ID, s.other))
+	s.Equal(http.StatusOK, s.repost("DELETE", original.ID, s.other))
+	reposts, _ = s.counts(original.ID)
+	s.Equal(1, reposts)
+
+	s.Equal(http.StatusNotFound, s.repost("PUT", original.ID+100, s.other))
+}
+
+func (s *RepostTestSuite) TestRepost_ListedOnProfile() {
+	first := CreateTestPost(s.posts, s.author, "First", nil)
+	second := CreateTestPost(s.posts, s.author, "Second", nil)
+	followersOnly, err := s.posts.Create(&post.PostRequest{Tweet: "Followers only", UserID: s.other.ID, Visibility: post.VisibilityFollowers}, s.other.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, s.repost("PUT", first.ID, s.other))
+	s.Require().Equal(http.StatusOK, s.repost("PUT", followersOnly.ID, s.other))
+	s.Require().Equal(http.StatusOK, s.repost("PUT", second.ID, s.other))
+
+	list := func(headers map[string]string) []repost.RepostResponse {
+		resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/users/%d/reposts", s.other.ID), nil, headers)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data struct {
+				Data []repost.RepostResponse `json:"data"`
+			} `json:"data"`
+		}
+		ParseJSON(s.T(), raw, &result)
+		return result.Data.Data
+	}
+
+	// Newest repost first, posts the viewer may not see are left out
+	anonymous := list(nil)
+	s.Require().Len(anonymous, 2)
+	s.Equal("Second", anonymous[0].Post.Tweet)
+	s.Equal(1, anonymous[0].Post.RepostCount)
+	s.Equal("First", anonymous[1].Post.Tweet)
+	s.Len(list(AuthHeader(s.other)), 3)
+
+	// Deleting the original removes its reposts
+	s.Require().NoError(s.posts.Delete(first.ID, s.author.ID, false))
+	s.Len(list(nil), 1)
+	var count int64
+	testDB.Model(&repost.Repost{}).Where("post_id = ?", first.ID).Count(&count)
+	s.Zero(count)
+	s.Equal(http.StatusNotFound, s.repost("PUT", first.ID, s.other))
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+	"starter-gofiber/pkg/pagination"
+	"starter-gofiber/variables"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDiffText(t *testing.T) {
+	from, to := "the quick brown fox", "the slow brown fox jumps"
+	diff := post.DiffText(from, to)
+
+	assert.Equal(t, []post.DiffSegment{
+		{Op: post.DiffEqual, Text: "the "},
+		{Op: post.DiffDelete, Text: "quick"},
+		{Op: post.DiffInsert, Text: "slow"},
+		{Op: post.DiffEqual, Text: " brown fox"},
+		{Op: post.DiffInsert, Text: " jumps"},
+	}, diff)
+
+	// Both sides can be rebuilt from the segments
+	var gotFrom, gotTo strings.Builder
+	for _, seg := range diff {
+		if seg.Op != post.DiffInsert {
+			gotFrom.WriteString(seg.Text)
+		}
+		if seg.Op != post.DiffDelete {
+			gotTo.WriteString(seg.Text)
+		}
+	}
+	assert.Equal(t, from, gotFrom.String())
+	assert.Equal(t, to, gotTo.String())
+
+	assert.Equal(t, []post.DiffSegment{{Op: post.DiffEqual, Text: "same"}}, post.DiffText("same", "same"))
+	assert.Empty(t, post.DiffText("", ""))
+}
+
+type RevisionTestSuite struct {
+	suite.Suite
+	service post.Service
+	author  *user.User
+	other   *user.User
+}
+
+func TestRevisionTestSuite(t *testing.T) {
+	suite.Run(t, new(RevisionTestSuite))
+}
+
+func (s *RevisionTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *RevisionTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *RevisionTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM post_revisions")
+	testDB.Exec("DELETE FROM attachments")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+}
+
+func (s *RevisionTestSuite) edit(id uint, tweet string) *post.PostResponse {
+	resp, err := s.service.Update(id, &post.PostUpdateRequest{ID: id, Tweet: tweet, UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *RevisionTestSuite) revisions(id uint) []post.RevisionResponse {
+	resp, raw, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d/revisions", id), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Data []post.RevisionResponse `json:"data"`
+		} `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	return result.Data.Data
+}
+
+func (s *RevisionTestSuite) TestEdit_RecordsRevisionAndMarksEdited() {
+	created, err := s.service.Create(&post.PostRequest{Tweet: "Hello world", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.False(created.Edited)
+
+	// Saving without changes is not an edit
+	unchanged := s.edit(created.ID, "Hello world")
+	s.False(unchanged.Edited)
+	s.Empty(s.revisions(created.ID))
+
+	s.edit(created.ID, "Hello there world")
+	updated := s.edit(created.ID, "Hi there world")
+	s.True(updated.Edited)
+	s.NotNil(updated.EditedAt)
+
+	revisions := s.revisions(created.ID)
+	s.Require().Len(revisions, 2)
+	s.Equal("Hello there world", revisions[0].Tweet)
+	s.Equal(s.author.ID, revisions[0].EditorID)
+	s.Equal([]post.DiffSegment{
+		{Op: post.DiffDelete, Text: "Hello"},
+		{Op: post.DiffInsert, Text: "Hi"},
+		{Op: post.DiffEqual, Text: " there world"},
+	}, revisions[0].Diff)
+	s.Equal("Hello world", revisions[1].Tweet)
+	s.Equal([]post.DiffSegment{
+		{Op: post.DiffEqual, Text: "Hello "},
+		{Op: post.DiffInsert, Text: "there "},
+		{Op: post.DiffEqual, Text: "world"},
+	}, revisions[1].Diff)
+}
+
+func (s *RevisionTestSuite) TestListRevisions_Paginates() {
+	created, err := s.service.Create(&post.PostRequest{Tweet: "v1", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.edit(created.ID, "v2")
+	s.edit(created.ID, "v3")
+
+	first, err := s.service.ListRevisions(created.ID, 0, pagination.CursorPagination{Limit: 1})
+	s.Require().NoError(err)
+	s.True(first.HasMore)
+	page := first.Data.([]post.RevisionResponse)
+	s.Require().Len(page, 1)
+	s.Equal("v2", page[0].Tweet)
+
+	second, err := s.service.ListRevisions(created.ID, 0, pagination.CursorPagination{Limit: 1, Cursor: first.NextCursor})
+	s.Require().NoError(err)
+	s.False(second.HasMore)
+	page = second.Data.([]post.RevisionResponse)
+	s.Require().Len(page, 1)
+	s.Equal("v1", page[0].Tweet)
+	// The diff leads to the revision on the previous page
+	s.Equal([]post.DiffSegment{{Op: post.DiffDelete, Text: "v1"}, {Op: post.DiffInsert, Text: "v2"}}, page[0].Diff)
+}
+
+func (s *RevisionTestSuite) TestDraftRevisions_HiddenFromOthers() {
+	draft, err := s.service.Create(&post.PostRequest{Tweet: "Draft", UserID: s.author.ID, Status: post.StatusDraft}, s.author.ID)
+	s.Require().NoError(err)
+	updated := s.edit(draft.ID, "Draft v2")
+	// Edits before publishing are not shown as edited
+	s.False(updated.Edited)
+
+	_, err = s.service.ListRevisions(draft.ID, s.other.ID, pagination.CursorPagination{Limit: 10})
+	s.IsType(&apierror.NotFoundError{}, err)
+	resp, err := s.service.ListRevisions(draft.ID, s.author.ID, pagination.CursorPagination{Limit: 10})
+	s.Require().NoError(err)
+	s.Equal(1, resp.Count)
+}
+
+func (s *RevisionTestSuite) TestRestore_OwnerOrAdmin() {
+	created, err := s.service.Create(&post.PostRequest{Tweet: "Original", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.edit(created.ID, "Vandalised")
+	revisions := s.revisions(created.ID)
+	s.Require().Len(revisions, 1)
+	url := fmt.Sprintf("/api/posts/%d/revisions/%d/restore", created.ID, revisions[0].ID)
+
+	resp, _, err := MakeRequest(testApp, "POST", url, nil, AuthHeader(s.other))
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	resp, raw, err := MakeRequest(testApp, "POST", url, nil, AuthHeader(s.author))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var restored struct {
+		Data post.PostResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &restored)
+	s.Equal("Original", restored.Data.Tweet)
+	s.True(restored.Data.Edited)
+
+	// Restoring is recorded too, so it can be undone
+	revisions = s.revisions(created.ID)
+	s.Require().Len(revisions, 2)
+	s.Equal("Vandalised", revisions[0].Tweet)
+
+	admin := CreateTestUser(testDB, "admin@example.com", "hash", variables.ADMIN_ROLE)
+	_, err = config.Enforcer.AddRoleForUser(admin.Email, variables.ADMIN_ROLE)
+	s.Require().NoError(err)
+	defer config.Enforcer.DeleteRoleForUser(admin.Email, variables.ADMIN_ROLE)
+
+	url = fmt.Sprintf("/api/posts/%d/revisions/%d/restore", created.ID, revisions[0].ID)
+	resp, _, err = MakeRequest(testApp, "POST", url, nil, AuthHeader(admin))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var stored post.Post
+	s.Require().NoError(testDB.First(&stored, created.ID).Error)
+	s.Equal("Vandalised", stored.Tweet)
+}
+
+func (s *RevisionTestSuite) TestRestore_RejectsRevisionOfOtherPost() {
+	first, err := s.service.Create(&post.PostRequest{Tweet: "First", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	second, err := s.service.Create(&post.PostRequest{Tweet: "Second", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.edit(first.ID, "First v2")
+	revisions := s.revisions(first.ID)
+	s.Require().Len(revisions, 1)
+
+	_, err = s.service.RestoreRevision(second.ID, revisions[0].ID, s.author.ID, false)
+	s.IsType(&apierror.NotFoundError{}, err)
+}
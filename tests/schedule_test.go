@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/pkg/apierror"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+	service post.Service
+	author  *user.User
+	reader  *user.User
+}
+
+func TestScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleTestSuite))
+}
+
+func (s *ScheduleTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	worker.SetDB(testDB)
+	worker.SetPostRepository(postgres.NewPostRepository(testDB))
+}
+
+func (s *ScheduleTestSuite) TearDownSuite() {
+	worker.SetDB(nil)
+	worker.SetPostRepository(nil)
+	CleanupTestDB()
+}
+
+func (s *ScheduleTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM post_mentions")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.reader = CreateTestUser(testDB, "reader@example.com", "hash", "user")
+}
+
+func (s *ScheduleTestSuite) schedule(tweet string, at time.Time) *post.PostResponse {
+	resp, err := s.service.Create(&post.PostRequest{Tweet: tweet, UserID: s.author.ID, PublishAt: &at}, s.author.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(post.StatusScheduled, resp.Status)
+	return resp
+}
+
+// publishTask builds the task that was enqueued for the post's current schedule
+func (s *ScheduleTestSuite) publishTask(postID uint) *asynq.Task {
+	var p post.Post
+	s.Require().NoError(testDB.First(&p, postID).Error)
+	s.Require().NotNil(p.PublishAt)
+	payload, err := json.Marshal(worker.PublishPostPayload{PostID: postID, PublishAt: p.PublishAt.Unix()})
+	s.Require().NoError(err)
+	return asynq.NewTask(worker.TaskPublishPost, payload)
+}
+
+func (s *ScheduleTestSuite) status(postID uint) string {
+	var p post.Post
+	s.Require().NoError(testDB.First(&p, postID).Error)
+	return p.Status
+}
+
+func (s *ScheduleTestSuite) TestCreate_DefaultsToPublished() {
+	resp, err := s.service.Create(&post.PostRequest{Tweet: "Live now", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.StatusPublished, resp.Status)
+	s.NotNil(resp.PublishAt)
+}
+
+func (s *ScheduleTestSuite) TestCreate_RejectsPastSchedule() {
+	past := time.Now().Add(-time.Minute)
+	_, err := s.service.Create(&post.PostRequest{Tweet: "Too late", UserID: s.author.ID, PublishAt: &past}, s.author.ID)
+	s.Require().Error(err)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+}
+
+func (s *ScheduleTestSuite) TestUnpublished_HiddenFromListings() {
+	draft, err := s.service.Create(&post.PostRequest{Tweet: "Work in progress", UserID: s.author.ID, Status: post.StatusDraft}, s.author.ID)
+	s.Require().NoError(err)
+	s.Nil(draft.PublishAt)
+	s.schedule("Coming soon", time.Now().Add(time.Hour))
+
+	posts, meta, err := s.service.FindAll(0, 1, 10)
+	s.Require().NoError(err)
+	s.Empty(posts)
+	s.Zero(meta.Total)
+
+	_, err = s.service.FindByID(draft.ID, s.reader.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+	_, err = s.service.FindByID(draft.ID, s.author.ID)
+	s.NoError(err)
+
+	resp, _, err := MakeRequest(testApp, "GET", fmt.Sprintf("/api/posts/%d", draft.ID), nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	unpublished, meta, err := s.service.FindUnpublished(s.author.ID, 1, 10)
+	s.Require().NoError(err)
+	s.Equal(int64(2), meta.Total)
+	// Scheduled posts come before drafts
+	s.Equal(post.StatusScheduled, unpublished[0].Status)
+	s.Equal(post.StatusDraft, unpublished[1].Status)
+}
+
+func (s *ScheduleTestSuite) TestPublishTask_IsIdempotent() {
+	scheduled := s.schedule("Launch day", time.Now().Add(time.Hour))
+	task := s.publishTask(scheduled.ID)
+
+	s.Require().NoError(worker.HandlePublishPost(context.Background(), task))
+	s.Equal(post.StatusPublished, s.status(scheduled.ID))
+
+	var published post.Post
+	s.Require().NoError(testDB.First(&published, scheduled.ID).Error)
+
+	// A retried or duplicated task leaves the published post alone
+	s.Require().NoError(worker.HandlePublishPost(context.Background(), task))
+	var again post.Post
+	s.Require().NoError(testDB.First(&again, scheduled.ID).Error)
+	s.True(published.PublishAt.Equal(*again.PublishAt))
+
+	posts, _, err := s.service.FindAll(0, 1, 10)
+	s.Require().NoError(err)
+	s.Len(posts, 1)
+}
+
+func (s *ScheduleTestSuite) TestReschedule_IgnoresStaleTask() {
+	scheduled := s.schedule("Moved", time.Now().Add(time.Hour))
+	stale := s.publishTask(scheduled.ID)
+
+	later := time.Now().Add(2 * time.Hour)
+	updated, err := s.service.Update(scheduled.ID, &post.PostUpdateRequest{ID: scheduled.ID, Tweet: "Moved", UserID: s.author.ID, PublishAt: &later}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.StatusScheduled, updated.Status)
+
+	s.Require().NoError(worker.HandlePublishPost(context.Background(), stale))
+	s.Equal(post.StatusScheduled, s.status(scheduled.ID))
+
+	s.Require().NoError(worker.HandlePublishPost(context.Background(), s.publishTask(scheduled.ID)))
+	s.Equal(post.StatusPublished, s.status(scheduled.ID))
+}
+
+func (s *ScheduleTestSuite) TestCancel_TurnsBackIntoDraft() {
+	scheduled := s.schedule("Never mind", time.Now().Add(time.Hour))
+	task := s.publishTask(scheduled.ID)
+
+	// Editing without status or publish_at keeps the schedule
+	updated, err := s.service.Update(scheduled.ID, &post.PostUpdateRequest{ID: scheduled.ID, Tweet: "Never mind!", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.StatusScheduled, updated.Status)
+	s.Equal(scheduled.PublishAt, updated.PublishAt)
+
+	updated, err = s.service.Update(scheduled.ID, &post.PostUpdateRequest{ID: scheduled.ID, Tweet: "Never mind!", UserID: s.author.ID, Status: post.StatusDraft}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.StatusDraft, updated.Status)
+	s.Nil(updated.PublishAt)
+
+	s.Require().NoError(worker.HandlePublishPost(context.Background(), task))
+	s.Equal(post.StatusDraft, s.status(scheduled.ID))
+}
+
+func (s *ScheduleTestSuite) TestPublished_CannotBeUnpublished() {
+	published, err := s.service.Create(&post.PostRequest{Tweet: "Out there", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+
+	_, err = s.service.Update(published.ID, &post.PostUpdateRequest{ID: published.ID, Tweet: "Out there", UserID: s.author.ID, Status: post.StatusDraft}, s.author.ID)
+	s.Require().Error(err)
+	s.IsType(&apierror.UnprocessableEntityError{}, err)
+}
+
+func (s *ScheduleTestSuite) TestPublishDuePosts() {
+	due := s.schedule("Overdue", time.Now().Add(time.Hour))
+	future := s.schedule("Later", time.Now().Add(time.Hour))
+	s.Require().NoError(testDB.Model(&post.Post{}).Where("id = ?", due.ID).Update("publish_at", time.Now().Add(-time.Minute)).Error)
+
+	s.Require().NoError(worker.HandlePublishDuePosts(context.Background(), asynq.NewTask(worker.TaskPublishDuePosts, nil)))
+	s.Equal(post.StatusPublished, s.status(due.ID))
+	s.Equal(post.StatusScheduled, s.status(future.ID))
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"starter-gofiber/internal/domain/search"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/infrastructure/fulltext"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/worker"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// SearchTestSuite runs against one engine, SQLite FTS5 needs `go test -tags sqlite_fts5`
+type SearchTestSuite struct {
+	suite.Suite
+	backend string
+	alice   *user.User
+	bob     *user.User
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	for _, backend := range []string{fulltext.BackendBleve, fulltext.BackendSQLite} {
+		t.Run(backend, func(t *testing.T) {
+			suite.Run(t, &SearchTestSuite{backend: backend})
+		})
+	}
+}
+
+func (s *SearchTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+
+	// An empty path keeps the Bleve index in memory
+	engine, err := fulltext.New(s.backend, testDB, "")
+	if err != nil {
+		s.T().Skipf("%s engine unavailable: %v", s.backend, err)
+	}
+	fulltext.SetEngine(engine)
+	s.Require().NoError(worker.RegisterSearchCallbacks(testDB))
+}
+
+func (s *SearchTestSuite) TearDownSuite() {
+	fulltext.SetEngine(nil)
+	CleanupTestDB()
+}
+
+func (s *SearchTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+	s.Require().NoError(fulltext.Engine().Reset())
+
+	s.alice = CreateTestUser(testDB, "alice@example.com", "hash", "user")
+	s.Require().NoError(testDB.Model(s.alice).Updates(map[string]interface{}{"name": "Alice Gopher", "username": "alice"}).Error)
+	s.bob = CreateTestUser(testDB, "bob@example.com", "hash", "user")
+}
+
+type searchPage struct {
+	Data struct {
+		Data       []search.Result `json:"data"`
+		NextCursor string          `json:"next_cursor"`
+		HasMore    bool            `json:"has_more"`
+	} `json:"data"`
+}
+
+func (s *SearchTestSuite) search(query url.Values) searchPage {
+	resp, raw, err := MakeRequest(testApp, "GET", "/api/search?"+query.Encode(), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode, string(raw))
+
+	var page searchPage
+	ParseJSON(s.T(), raw, &page)
+	return page
+}
+
+func (s *SearchTestSuite) TestSearch_PostsAndUsersWithHighlight() {
+	p := InsertTestPost(testDB, s.alice, "Belajar <b>fiber</b> framework")
+	InsertTestPost(testDB, s.bob, "Unrelated post")
+
+	page := s.search(url.Values{"q": {"fiber"}})
+	s.Require().Len(page.Data.Data, 1)
+	hit := page.Data.Data[0]
+	s.Equal(search.TypePost, hit.Type)
+	s.Require().NotNil(hit.Post)
+	s.Equal(p.ID, hit.Post.ID)
+	s.Contains(hit.Highlight, "&lt;b&gt;<mark>fiber</mark>&lt;/b&gt;")
+
+	page = s.search(url.Values{"q": {"gopher"}})
+	s.Require().Len(page.Data.Data, 1)
+	s.Require().NotNil(page.Data.Data[0].User)
+	s.Equal(s.alice.ID, page.Data.Data[0].User.ID)
+
+	// Emails are never indexed
+	page = s.search(url.Values{"q": {"example"}})
+	s.Empty(page.Data.Data)
+}
+
+func (s *SearchTestSuite) TestSearch_Filters() {
+	InsertTestPost(testDB, s.alice, "gopher news from alice")
+	bobPost := InsertTestPost(testDB, s.bob, "gopher news from bob")
+
+	page := s.search(url.Values{"q": {"gopher"}, "type": {"post"}, "author_id": {fmt.Sprint(s.bob.ID)}})
+	s.Require().Len(page.Data.Data, 1)
+	s.Equal(bobPost.ID, page.Data.Data[0].Post.ID)
+
+	page = s.search(url.Values{"q": {"gopher"}, "type": {"user"}})
+	s.Require().Len(page.Data.Data, 1)
+	s.Equal(search.TypeUser, page.Data.Data[0].Type)
+
+	page = s.search(url.Values{"q": {"gopher"}, "since": {"2199-01-01"}})
+	s.Empty(page.Data.Data)
+
+	resp, _, err := MakeRequest(testApp, "GET", "/api/search?q=gopher&type=comment", nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *SearchTestSuite) TestSearch_CursorPagination() {
+	for i := 0; i < 3; i++ {
+		InsertTestPost(testDB, s.alice, fmt.Sprintf("paging test %d", i))
+	}
+
+	page := s.search(url.Values{"q": {"paging"}, "type": {"post"}, "sort": {"recent"}, "limit": {"2"}})
+	s.Require().Len(page.Data.Data, 2)
+	s.True(page.Data.HasMore)
+
+	next := s.search(url.Values{"q": {"paging"}, "type": {"post"}, "sort": {"recent"}, "limit": {"2"}, "cursor": {page.Data.NextCursor}})
+	s.Require().Len(next.Data.Data, 1)
+	s.False(next.Data.HasMore)
+	s.Less(next.Data.Data[0].Post.ID, page.Data.Data[1].Post.ID)
+}
+
+func (s *SearchTestSuite) TestIndex_FollowsUpdatesAndDeletes() {
+	repo := postgres.NewPostRepository(testDB)
+	p := InsertTestPost(testDB, s.alice, "original wording")
+
+	p.Tweet = "rewritten wording"
+	s.Require().NoError(repo.Update(p))
+	s.Empty(s.search(url.Values{"q": {"original"}}).Data.Data)
+	s.Len(s.search(url.Values{"q": {"rewritten"}}).Data.Data, 1)
+
+	s.Require().NoError(repo.Delete(p.ID))
+	s.Empty(s.search(url.Values{"q": {"rewritten"}}).Data.Data)
+}
+
+func (s *SearchTestSuite) TestReindex_RebuildsFromDatabase() {
+	InsertTestPost(testDB, s.alice, "reindexed content")
+	s.Require().NoError(fulltext.Engine().Reset())
+	s.Empty(s.search(url.Values{"q": {"reindexed"}}).Data.Data)
+
+	s.Require().NoError(worker.ReindexSearch(testDB))
+	s.Len(s.search(url.Values{"q": {"reindexed"}}).Data.Data, 1)
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/security"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/infrastructure/geoip"
+	"starter-gofiber/internal/repository/postgres"
+	"starter-gofiber/internal/service/auth"
+	consentService "starter-gofiber/internal/service/consent"
+	securityService "starter-gofiber/internal/service/security"
+	"starter-gofiber/pkg/crypto"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SecurityEventTestSuite struct {
+	suite.Suite
+	service security.Service
+	authS   user.Service
+}
+
+func TestSecurityEventTestSuite(t *testing.T) {
+	suite.Run(t, new(SecurityEventTestSuite))
+}
+
+func (s *SecurityEventTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	s.service = securityService.NewSecurityService(postgres.NewSecurityEventRepository(testDB))
+	s.authS = auth.NewAuthService(
+		postgres.NewUserRepository(testDB),
+		consentService.NewConsentService(postgres.NewConsentRepository(testDB)),
+		s.service,
+	)
+
+	policy := security.DefaultPolicy()
+	policy.LockoutThreshold = 3
+	policy.IPFailureAccounts = 3
+	securityService.SetPolicy(policy)
+}
+
+func (s *SecurityEventTestSuite) TearDownSuite() {
+	securityService.SetPolicy(security.DefaultPolicy())
+	geoip.Reset()
+	CleanupTestDB()
+}
+
+func (s *SecurityEventTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM security_events")
+	testDB.Exec("DELETE FROM refresh_tokens")
+	testDB.Exec("DELETE FROM users")
+}
+
+func (s *SecurityEventTestSuite) countEvents(eventType security.EventType) int64 {
+	var count int64
+	testDB.Model(&security.Event{}).Where("type = ?", eventType).Count(&count)
+	return count
+}
+
+func (s *SecurityEventTestSuite) TestLogin_LocksAccountAfterFailures() {
+	hash, err := crypto.HashPassword("Password123!")
+	s.Require().NoError(err)
+	CreateTestUser(testDB, "locked@example.com", hash, "user")
+
+	for i := 0; i < 3; i++ {
+		_, err := s.authS.Login(&user.LoginRequest{Email: "locked@example.com", Password: "wrong"}, "10.0.0.1", "test")
+		s.Error(err)
+	}
+	s.Equal(int64(3), s.countEvents(security.EventLoginFailed))
+	s.Equal(int64(1), s.countEvents(security.EventAccountLocked))
+
+	// Correct password is rejected while locked
+	_, err = s.authS.Login(&user.LoginRequest{Email: "locked@example.com", Password: "Password123!"}, "10.0.0.1", "test")
+	s.Error(err)
+	s.Equal(int64(0), s.countEvents(security.EventLoginSuccess))
+}
+
+func (s *SecurityEventTestSuite) TestLogin_SuccessResetsFailures() {
+	hash, err := crypto.HashPassword("Password123!")
+	s.Require().NoError(err)
+	CreateTestUser(testDB, "forgetful@example.com", hash, "user")
+
+	login := func(password string) error {
+		_, err := s.authS.Login(&user.LoginRequest{Email: "forgetful@example.com", Password: password}, "10.0.0.1", "test")
+		return err
+	}
+	s.Error(login("wrong"))
+	s.Error(login("wrong"))
+	s.NoError(login("Password123!"))
+
+	// Failures before the successful login no longer count towards the threshold
+	s.Error(login("wrong"))
+	s.Error(login("wrong"))
+	s.False(s.service.IsLocked("forgetful@example.com"))
+	s.NoError(login("Password123!"))
+	s.Equal(int64(0), s.countEvents(security.EventAccountLocked))
+}
+
+func (s *SecurityEventTestSuite) TestFailuresAcrossAccountsFromOneIP() {
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		s.service.Record(&security.Event{Email: email, Type: security.EventLoginFailed, IPAddress: "10.0.0.9"})
+	}
+	s.Equal(int64(1), s.countEvents(security.EventAnomalyIPFailures))
+
+	s.service.Record(&security.Event{Email: "d@example.com", Type: security.EventLoginFailed, IPAddress: "10.0.0.9"})
+	s.Equal(int64(1), s.countEvents(security.EventAnomalyIPFailures))
+}
+
+func (s *SecurityEventTestSuite) TestImpossibleTravel() {
+	path := filepath.Join(s.T().TempDir(), "geoip.csv")
+	s.Require().NoError(os.WriteFile(path, []byte(
+		"network,country,latitude,longitude\n"+
+			"203.0.113.0/24,ID,-6.2088,106.8456\n"+
+			"198.51.100.0/24,GB,51.5072,-0.1276\n"), 0o600))
+	s.Require().NoError(geoip.Load(path))
+	defer geoip.Reset()
+
+	u := CreateTestUser(testDB, "travel@example.com", "hashed", "user")
+	s.service.Record(&security.Event{UserID: &u.ID, Email: u.Email, Type: security.EventLoginSuccess, IPAddress: "203.0.113.10"})
+	s.service.Record(&security.Event{UserID: &u.ID, Email: u.Email, Type: security.EventLoginSuccess, IPAddress: "198.51.100.20"})
+
+	s.Equal(int64(1), s.countEvents(security.EventAnomalyImpossibleTravel))
+}
+
+func (s *SecurityEventTestSuite) TestRefreshTokenReuse_RevokesSessions() {
+	hash, err := crypto.HashPassword("Password123!")
+	s.Require().NoError(err)
+	CreateTestUser(testDB, "reuse@example.com", hash, "user")
+
+	login, err := s.authS.Login(&user.LoginRequest{Email: "reuse@example.com", Password: "Password123!"}, "10.0.0.2", "test")
+	s.Require().NoError(err)
+
+	// Sleep so the rotated token gets a different expiry and therefore a different value
+	time.Sleep(time.Second)
+	refreshed, err := s.authS.RefreshToken(&user.RefreshTokenRequest{RefreshToken: login.RefreshToken}, "10.0.0.2", "test")
+	s.Require().NoError(err)
+
+	_, err = s.authS.RefreshToken(&user.RefreshTokenRequest{RefreshToken: login.RefreshToken}, "10.0.0.3", "test")
+	s.Error(err)
+	s.Equal(int64(1), s.countEvents(security.EventTokenReuse))
+
+	// The legitimately rotated token is revoked as well
+	_, err = s.authS.RefreshToken(&user.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken}, "10.0.0.2", "test")
+	s.Error(err)
+}
+
+func (s *SecurityEventTestSuite) TestActivityAndPermissionDenied() {
+	u := CreateTestUser(testDB, "activity@example.com", "hashed", "user")
+	token, err := GenerateTestToken(u)
+	s.Require().NoError(err)
+	headers := map[string]string{"Authorization": "Bearer " + token}
+
+	// Regular users cannot query all events, the denial is recorded
+	resp, _, err := MakeRequest(testApp, "GET", "/api/security/events", nil, headers)
+	s.NoError(err)
+	s.Equal(403, resp.StatusCode)
+	s.Equal(int64(1), s.countEvents(security.EventPermissionDenied))
+
+	resp, body, err := MakeRequest(testApp, "GET", "/api/security/activity", nil, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+
+	var result map[string]interface{}
+	ParseJSON(s.T(), body, &result)
+	events := result["data"].([]interface{})
+	s.Require().Len(events, 1)
+	s.Equal("permission_denied", events[0].(map[string]interface{})["type"])
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/handler/middleware"
+	"starter-gofiber/pkg/crypto"
+
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionTestSuite struct {
+	suite.Suite
+	sessionCookie string
+	csrfToken     string
+}
+
+func TestSessionTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionTestSuite))
+}
+
+func (s *SessionTestSuite) SetupSuite() {
+	// Session routes are only registered when the store is initialized before routing
+	middleware.InitSessionAuth(session.New(session.Config{
+		KeyLookup:      "cookie:" + middleware.SessionCookieName,
+		CookieHTTPOnly: true,
+	}))
+	testApp = SetupTestApp()
+}
+
+func (s *SessionTestSuite) TearDownSuite() {
+	middleware.InitSessionAuth(nil)
+	CleanupTestDB()
+}
+
+func (s *SessionTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM users")
+	testDB.Exec("DELETE FROM refresh_tokens")
+
+	hash, err := crypto.HashPassword("Password123!")
+	s.Require().NoError(err)
+	CreateTestUser(testDB, "session@example.com", hash, "user")
+
+	resp, body, err := MakeRequest(testApp, "POST", "/api/auth/auth/session/login", user.LoginRequest{
+		Email:    "session@example.com",
+		Password: "Password123!",
+	}, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(200, resp.StatusCode)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == middleware.SessionCookieName {
+			s.sessionCookie = cookie.Value
+			s.True(cookie.HttpOnly)
+		}
+	}
+	s.Require().NotEmpty(s.sessionCookie)
+
+	var loginResponse map[string]interface{}
+	ParseJSON(s.T(), body, &loginResponse)
+	data := loginResponse["data"].(map[string]interface{})
+	s.NotContains(data, "token")
+	s.csrfToken = data["csrf_token"].(string)
+}
+
+func (s *SessionTestSuite) cookieHeader() map[string]string {
+	return map[string]string{"Cookie": middleware.SessionCookieName + "=" + s.sessionCookie}
+}
+
+func (s *SessionTestSuite) TestSessionCookie_AuthenticatesSafeRequest() {
+	resp, _, err := MakeRequest(testApp, "GET", "/api/auth/profile", nil, s.cookieHeader())
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+}
+
+func (s *SessionTestSuite) TestSessionCookie_StateChangeRequiresCSRF() {
+	payload := user.UpdateProfileRequest{Name: "Session User"}
+
+	resp, body, err := MakeRequest(testApp, "PUT", "/api/auth/profile", payload, s.cookieHeader())
+	s.NoError(err)
+	AssertErrorResponse(s.T(), resp, 403, body)
+
+	headers := s.cookieHeader()
+	headers["X-CSRF-Token"] = s.csrfToken
+	resp, _, err = MakeRequest(testApp, "PUT", "/api/auth/profile", payload, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+}
+
+func (s *SessionTestSuite) TestSessionLogin_RegeneratesSessionID() {
+	resp, _, err := MakeRequest(testApp, "POST", "/api/auth/auth/session/login", user.LoginRequest{
+		Email:    "session@example.com",
+		Password: "Password123!",
+	}, s.cookieHeader())
+	s.NoError(err)
+	s.Require().Equal(200, resp.StatusCode)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == middleware.SessionCookieName {
+			s.NotEqual(s.sessionCookie, cookie.Value)
+		}
+	}
+
+	// Old session ID must no longer authenticate
+	resp, _, err = MakeRequest(testApp, "GET", "/api/auth/profile", nil, s.cookieHeader())
+	s.NoError(err)
+	s.Equal(401, resp.StatusCode)
+}
+
+func (s *SessionTestSuite) TestSessionLogout_DestroysSession() {
+	headers := s.cookieHeader()
+	headers["X-CSRF-Token"] = s.csrfToken
+	resp, _, err := MakeRequest(testApp, "POST", "/api/auth/auth/session/logout", nil, headers)
+	s.NoError(err)
+	AssertSuccessResponse(s.T(), resp, 200)
+
+	resp, _, err = MakeRequest(testApp, "GET", "/api/auth/profile", nil, s.cookieHeader())
+	s.NoError(err)
+	s.Equal(401, resp.StatusCode)
+}
+
+func (s *SessionTestSuite) TestSessionCookie_LoadsCurrentUser() {
+	middleware.InitEmailVerificationGate([]string{"comment:create"}, 0)
+	defer middleware.InitEmailVerificationGate([]string{"post:create"}, 24*time.Hour)
+
+	headers := s.cookieHeader()
+	headers["X-CSRF-Token"] = s.csrfToken
+	payload := map[string]string{"body": "Hello"}
+
+	// The session was started verified, the account is not anymore
+	testDB.Model(&user.User{}).Where("email = ?", "session@example.com").Update("email_verified", false)
+	resp, body, err := MakeRequest(testApp, "POST", "/api/posts/999999/comments", payload, headers)
+	s.NoError(err)
+	AssertErrorResponse(s.T(), resp, 403, body)
+
+	testDB.Model(&user.User{}).Where("email = ?", "session@example.com").Update("email_verified", true)
+	resp, _, err = MakeRequest(testApp, "POST", "/api/posts/999999/comments", payload, headers)
+	s.NoError(err)
+	s.Equal(404, resp.StatusCode)
+
+	// Suspended accounts are rejected
+	testDB.Model(&user.User{}).Where("email = ?", "session@example.com").Update("suspended_at", time.Now())
+	resp, _, err = MakeRequest(testApp, "GET", "/api/auth/profile", nil, s.cookieHeader())
+	s.NoError(err)
+	s.Equal(403, resp.StatusCode)
+
+	// Deleted accounts lose their sessions
+	testDB.Exec("DELETE FROM users")
+	resp, _, err = MakeRequest(testApp, "GET", "/api/auth/profile", nil, s.cookieHeader())
+	s.NoError(err)
+	s.Equal(401, resp.StatusCode)
+}
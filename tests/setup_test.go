@@ -8,16 +8,31 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/analytics"
+	"starter-gofiber/internal/domain/bookmark"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/consent"
+	"starter-gofiber/internal/domain/dataimport"
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/linkpreview"
+	"starter-gofiber/internal/domain/moderation"
+	"starter-gofiber/internal/domain/poll"
 	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/repost"
+	"starter-gofiber/internal/domain/security"
 	"starter-gofiber/internal/domain/user"
 	"starter-gofiber/pkg/apierror"
 	"starter-gofiber/pkg/crypto"
+	"starter-gofiber/pkg/database"
 	"starter-gofiber/router"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -37,13 +52,43 @@ func SetupTestDB() *gorm.DB {
 	err = db.AutoMigrate(
 		&user.User{},
 		&post.Post{},
+		&post.Tag{},
+		&post.PostTag{},
+		&post.PostMention{},
+		&post.Attachment{},
+		&post.Revision{},
+		&moderation.Report{},
+		&comment.Comment{},
+		&reaction.Reaction{},
+		&reaction.Count{},
+		&bookmark.Bookmark{},
+		&bookmark.Collection{},
+		&repost.Repost{},
+		&poll.Poll{},
+		&poll.Option{},
+		&poll.Ballot{},
+		&poll.Vote{},
+		&linkpreview.LinkPreview{},
+		&analytics.View{},
+		&analytics.DailyStat{},
+		&dataimport.Import{},
+		&follow.Follow{},
 		&user.RefreshToken{},
 		&user.PasswordReset{},
 		&user.EmailVerification{},
+		&user.APIKey{},
+		&user.UserPreferences{},
+		&consent.PolicyVersion{},
+		&consent.UserConsent{},
+		&consent.MarketingConsent{},
+		&security.Event{},
 	)
 	if err != nil {
 		panic("failed to migrate test database: " + err.Error())
 	}
+	if err := database.RegisterSoftDeleteCallbacks(db); err != nil {
+		panic("failed to register soft delete callbacks: " + err.Error())
+	}
 
 	return db
 }
@@ -130,6 +175,51 @@ func CreateTestUser(db *gorm.DB, email, password, role string) *user.User {
 	return usr
 }
 
+// GenerateTestToken signs an RS256 access token, the algorithm JWTMiddleware verifies
+func GenerateTestToken(u *user.User) (string, error) {
+	claims := user.UserClaims{}.FromEntity(*u)
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, user.CustomClaims{
+		ID:            claims.ID,
+		Email:         claims.Email,
+		Role:          claims.Role,
+		EmailVerified: claims.EmailVerified,
+		RegisteredAt:  claims.RegisteredAt,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString(crypto.GetPrivateKey())
+}
+
+// AuthHeader is the Authorization header of an access token for u, nil for anonymous requests
+func AuthHeader(u *user.User) map[string]string {
+	if u == nil {
+		return nil
+	}
+	token, err := GenerateTestToken(u)
+	if err != nil {
+		panic(err)
+	}
+	return map[string]string{"Authorization": "Bearer " + token}
+}
+
+// CreateTestPost creates a post by u through the post service, quoting quoted when it is not nil
+func CreateTestPost(posts post.Service, u *user.User, tweet string, quoted *uint) *post.PostResponse {
+	resp, err := posts.Create(&post.PostRequest{Tweet: tweet, UserID: u.ID, QuotedPostID: quoted}, u.ID)
+	if err != nil {
+		panic(err)
+	}
+	return resp
+}
+
+// InsertTestPost stores a published post by u directly, skipping the post service
+func InsertTestPost(db *gorm.DB, u *user.User, tweet string) *post.Post {
+	p := &post.Post{Tweet: tweet, UserID: u.ID}
+	if err := db.Create(p).Error; err != nil {
+		panic(err)
+	}
+	return p
+}
+
 func AssertSuccessResponse(t *testing.T, resp *http.Response, expectedCode int) {
 	assert.Equal(t, expectedCode, resp.StatusCode, "Expected status code %d, got %d", expectedCode, resp.StatusCode)
 }
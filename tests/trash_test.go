@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"starter-gofiber/internal/config"
+	"starter-gofiber/internal/domain/comment"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/reaction"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/internal/worker"
+	"starter-gofiber/variables"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashTestSuite struct {
+	suite.Suite
+	posts  post.Service
+	author *user.User
+	other  *user.User
+}
+
+func TestTrashTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashTestSuite))
+}
+
+func (s *TrashTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+	worker.SetDB(testDB)
+	worker.SetPostRepository(postgres.NewPostRepository(testDB))
+}
+
+func (s *TrashTestSuite) TearDownSuite() {
+	worker.SetDB(nil)
+	worker.SetPostRepository(nil)
+	CleanupTestDB()
+}
+
+func (s *TrashTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM reactions")
+	testDB.Exec("DELETE FROM comments")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.posts = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.other = CreateTestUser(testDB, "other@example.com", "hash", "user")
+}
+
+func (s *TrashTestSuite) request(method, url string, u *user.User) (int, []byte) {
+	resp, raw, err := MakeRequest(testApp, method, url, nil, AuthHeader(u))
+	s.Require().NoError(err)
+	return resp.StatusCode, raw
+}
+
+func (s *TrashTestSuite) trash(u *user.User, query string) (int, []post.TrashedPostResponse) {
+	code, raw := s.request("GET", "/api/posts/trash"+query, u)
+	var result struct {
+		Data []post.TrashedPostResponse `json:"data"`
+	}
+	if code == http.StatusOK {
+		ParseJSON(s.T(), raw, &result)
+	}
+	return code, result.Data
+}
+
+func (s *TrashTestSuite) TestDelete_ListAndRestore() {
+	original := CreateTestPost(s.posts, s.other, "Original thought", nil)
+	quote := CreateTestPost(s.posts, s.author, "Well said", &original.ID)
+	s.Require().NoError(s.posts.Delete(quote.ID, s.author.ID, false))
+
+	var quoted post.Post
+	s.Require().NoError(testDB.First(&quoted, original.ID).Error)
+	s.Zero(quoted.QuoteCount)
+
+	code, _ := s.request("GET", fmt.Sprintf("/api/posts/%d", quote.ID), s.author)
+	s.Equal(http.StatusNotFound, code)
+
+	code, trashed := s.trash(s.author, "")
+	s.Require().Equal(http.StatusOK, code)
+	s.Require().Len(trashed, 1)
+	s.Equal(quote.ID, trashed[0].ID)
+	s.NotEmpty(trashed[0].DeletedAt)
+	deletedAt, err := time.Parse(variables.FORMAT_TIME, trashed[0].DeletedAt)
+	s.Require().NoError(err)
+	purgeAt, err := time.Parse(variables.FORMAT_TIME, trashed[0].PurgeAt)
+	s.Require().NoError(err)
+	s.Equal(post.TrashRetention(), purgeAt.Sub(deletedAt))
+
+	code, trashed = s.trash(s.other, "")
+	s.Require().Equal(http.StatusOK, code)
+	s.Empty(trashed)
+
+	restoreURL := fmt.Sprintf("/api/posts/%d/restore", quote.ID)
+	code, _ = s.request("POST", restoreURL, s.other)
+	s.Equal(http.StatusForbidden, code)
+	code, _ = s.request("POST", restoreURL, s.author)
+	s.Require().Equal(http.StatusOK, code)
+	code, _ = s.request("POST", restoreURL, s.author)
+	s.Equal(http.StatusNotFound, code)
+
+	code, _ = s.request("GET", fmt.Sprintf("/api/posts/%d", quote.ID), s.other)
+	s.Equal(http.StatusOK, code)
+	s.Require().NoError(testDB.First(&quoted, original.ID).Error)
+	s.Equal(1, quoted.QuoteCount)
+	_, trashed = s.trash(s.author, "")
+	s.Empty(trashed)
+}
+
+func (s *TrashTestSuite) TestPermanentDelete() {
+	p := CreateTestPost(s.posts, s.author, "Short lived", nil)
+	s.Require().NoError(testDB.Create(&comment.Comment{PostID: p.ID, UserID: s.other.ID, Body: "First"}).Error)
+	s.Require().NoError(testDB.Create(&reaction.Reaction{PostID: p.ID, UserID: s.other.ID, Type: reaction.TypeLike}).Error)
+
+	// Only posts in the trash are purged
+	url := fmt.Sprintf("/api/posts/%d/permanent", p.ID)
+	code, _ := s.request("DELETE", url, s.author)
+	s.Equal(http.StatusNotFound, code)
+
+	s.Require().NoError(s.posts.Delete(p.ID, s.author.ID, false))
+	code, _ = s.request("DELETE", url, s.other)
+	s.Equal(http.StatusForbidden, code)
+	code, _ = s.request("DELETE", url, s.author)
+	s.Require().Equal(http.StatusOK, code)
+
+	var posts, comments, reactions int64
+	testDB.Unscoped().Model(&post.Post{}).Where("id = ?", p.ID).Count(&posts)
+	testDB.Unscoped().Model(&comment.Comment{}).Where("post_id = ?", p.ID).Count(&comments)
+	testDB.Model(&reaction.Reaction{}).Where("post_id = ?", p.ID).Count(&reactions)
+	s.Zero(posts)
+	s.Zero(comments)
+	s.Zero(reactions)
+
+	code, _ = s.request("POST", fmt.Sprintf("/api/posts/%d/restore", p.ID), s.author)
+	s.Equal(http.StatusNotFound, code)
+}
+
+func (s *TrashTestSuite) TestAdmin_ManagesEveryTrash() {
+	mine := CreateTestPost(s.posts, s.author, "Mine", nil)
+	theirs := CreateTestPost(s.posts, s.other, "Theirs", nil)
+	s.Require().NoError(s.posts.Delete(mine.ID, s.author.ID, false))
+	s.Require().NoError(s.posts.Delete(theirs.ID, s.other.ID, false))
+
+	code, _ := s.trash(s.author, "?user_id=0")
+	s.Equal(http.StatusForbidden, code)
+
+	admin := CreateTestUser(testDB, "admin@example.com", "hash", variables.ADMIN_ROLE)
+	_, err := config.Enforcer.AddRoleForUser(admin.Email, variables.ADMIN_ROLE)
+	s.Require().NoError(err)
+	defer config.Enforcer.DeleteRoleForUser(admin.Email, variables.ADMIN_ROLE)
+
+	code, trashed := s.trash(admin, "?user_id=0")
+	s.Require().Equal(http.StatusOK, code)
+	s.Len(trashed, 2)
+
+	code, trashed = s.trash(admin, fmt.Sprintf("?user_id=%d", s.other.ID))
+	s.Require().Equal(http.StatusOK, code)
+	s.Require().Len(trashed, 1)
+	s.Equal(theirs.ID, trashed[0].ID)
+
+	code, _ = s.request("POST", fmt.Sprintf("/api/posts/%d/restore", theirs.ID), admin)
+	s.Equal(http.StatusOK, code)
+}
+
+func (s *TrashTestSuite) TestPurge_AfterRetention() {
+	old := CreateTestPost(s.posts, s.author, "Long gone", nil)
+	recent := CreateTestPost(s.posts, s.author, "Just deleted", nil)
+	s.Require().NoError(s.posts.Delete(old.ID, s.author.ID, false))
+	s.Require().NoError(s.posts.Delete(recent.ID, s.author.ID, false))
+	s.Require().NoError(testDB.Unscoped().Model(&post.Post{}).Where("id = ?", old.ID).
+		UpdateColumn("deleted_at", time.Now().Add(-post.TrashRetention()-time.Hour)).Error)
+
+	s.Require().NoError(worker.HandlePurgeTrashedPosts(context.Background(), asynq.NewTask(worker.TaskPurgeTrashedPosts, nil)))
+
+	var ids []uint
+	testDB.Unscoped().Model(&post.Post{}).Where("user_id = ?", s.author.ID).Pluck("id", &ids)
+	s.Equal([]uint{recent.ID}, ids)
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"starter-gofiber/internal/domain/follow"
+	"starter-gofiber/internal/domain/post"
+	"starter-gofiber/internal/domain/user"
+	"starter-gofiber/internal/repository/postgres"
+	postsvc "starter-gofiber/internal/service/post"
+	"starter-gofiber/pkg/apierror"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type VisibilityTestSuite struct {
+	suite.Suite
+	service   post.Service
+	author    *user.User
+	follower  *user.User
+	mentioned *user.User
+	stranger  *user.User
+}
+
+func TestVisibilityTestSuite(t *testing.T) {
+	suite.Run(t, new(VisibilityTestSuite))
+}
+
+func (s *VisibilityTestSuite) SetupSuite() {
+	testApp = SetupTestApp()
+}
+
+func (s *VisibilityTestSuite) TearDownSuite() {
+	CleanupTestDB()
+}
+
+func (s *VisibilityTestSuite) SetupTest() {
+	testDB.Exec("DELETE FROM post_mentions")
+	testDB.Exec("DELETE FROM follows")
+	testDB.Exec("DELETE FROM user_preferences")
+	testDB.Exec("DELETE FROM posts")
+	testDB.Exec("DELETE FROM users")
+
+	s.service = postsvc.NewPostService(postgres.NewPostRepository(testDB), postgres.NewUserRepository(testDB), postgres.NewModerationRepository(testDB))
+	s.author = CreateTestUser(testDB, "author@example.com", "hash", "user")
+	s.follower = CreateTestUser(testDB, "follower@example.com", "hash", "user")
+	s.mentioned = CreateTestUser(testDB, "mentioned@example.com", "hash", "user")
+	s.stranger = CreateTestUser(testDB, "stranger@example.com", "hash", "user")
+	s.Require().NoError(testDB.Model(s.mentioned).Update("username", "mentioned").Error)
+	s.Require().NoError(testDB.Create(&follow.Follow{FollowerID: s.follower.ID, FolloweeID: s.author.ID}).Error)
+}
+
+// createPosts creates one post of each visibility, all mentioning @mentioned
+func (s *VisibilityTestSuite) createPosts() map[string]uint {
+	ids := make(map[string]uint)
+	for _, v := range []string{post.VisibilityPublic, post.VisibilityFollowers, post.VisibilityMentioned, post.VisibilityPrivate} {
+		created, err := s.service.Create(&post.PostRequest{Tweet: v + " post for @mentioned", UserID: s.author.ID, Visibility: v}, s.author.ID)
+		s.Require().NoError(err)
+		s.Equal(v, created.Visibility)
+		ids[v] = created.ID
+	}
+	return ids
+}
+
+// listed returns the visibilities of the posts FindAll shows the viewer
+func (s *VisibilityTestSuite) listed(viewerID uint) []string {
+	posts, meta, err := s.service.FindAll(viewerID, 1, 10)
+	s.Require().NoError(err)
+	s.Equal(int64(len(posts)), meta.Total)
+
+	var visibilities []string
+	for _, p := range posts {
+		visibilities = append(visibilities, p.Visibility)
+	}
+	return visibilities
+}
+
+func (s *VisibilityTestSuite) TestFindAll_FiltersByViewer() {
+	s.createPosts()
+
+	s.ElementsMatch([]string{post.VisibilityPublic}, s.listed(0))
+	s.ElementsMatch([]string{post.VisibilityPublic}, s.listed(s.stranger.ID))
+	s.ElementsMatch([]string{post.VisibilityPublic, post.VisibilityFollowers}, s.listed(s.follower.ID))
+	s.ElementsMatch([]string{post.VisibilityPublic, post.VisibilityFollowers, post.VisibilityMentioned}, s.listed(s.mentioned.ID))
+	s.Len(s.listed(s.author.ID), 4)
+
+	userPosts, _, err := s.service.FindByUserID(s.author.ID, s.follower.ID, 1, 10)
+	s.Require().NoError(err)
+	s.Len(userPosts, 2)
+}
+
+func (s *VisibilityTestSuite) TestFindByID_HidesPostsNotShared() {
+	ids := s.createPosts()
+
+	_, err := s.service.FindByID(ids[post.VisibilityFollowers], 0)
+	s.IsType(&apierror.NotFoundError{}, err)
+	_, err = s.service.FindByID(ids[post.VisibilityFollowers], s.stranger.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+	_, err = s.service.FindByID(ids[post.VisibilityFollowers], s.follower.ID)
+	s.NoError(err)
+
+	_, err = s.service.FindByID(ids[post.VisibilityMentioned], s.follower.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+	_, err = s.service.FindByID(ids[post.VisibilityMentioned], s.mentioned.ID)
+	s.NoError(err)
+
+	_, err = s.service.FindByID(ids[post.VisibilityPrivate], s.mentioned.ID)
+	s.IsType(&apierror.NotFoundError{}, err)
+	_, err = s.service.FindByID(ids[post.VisibilityPrivate], s.author.ID)
+	s.NoError(err)
+
+	// Anonymous requests only get public posts
+	resp, raw, err := MakeRequest(testApp, "GET", "/api/posts", nil, nil)
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data []post.PostResponse `json:"data"`
+	}
+	ParseJSON(s.T(), raw, &result)
+	s.Require().Len(result.Data, 1)
+	s.Equal(ids[post.VisibilityPublic], result.Data[0].ID)
+}
+
+func (s *VisibilityTestSuite) TestCreate_DefaultsToProfileVisibility() {
+	created, err := s.service.Create(&post.PostRequest{Tweet: "No preference", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.VisibilityPublic, created.Visibility)
+
+	prefs := &user.UserPreferences{UserID: s.author.ID}
+	s.Require().NoError(prefs.SetData(&user.PreferencesData{ProfileVisibility: "friends"}))
+	s.Require().NoError(testDB.Omit("User").Create(prefs).Error)
+
+	created, err = s.service.Create(&post.PostRequest{Tweet: "Friends only", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.VisibilityFollowers, created.Visibility)
+
+	// An explicit visibility wins, edits keep it unless given
+	created, err = s.service.Create(&post.PostRequest{Tweet: "Everyone", UserID: s.author.ID, Visibility: post.VisibilityPublic}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.VisibilityPublic, created.Visibility)
+	updated, err := s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, Tweet: "Everyone!", UserID: s.author.ID}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.VisibilityPublic, updated.Visibility)
+	updated, err = s.service.Update(created.ID, &post.PostUpdateRequest{ID: created.ID, Tweet: "Everyone!", UserID: s.author.ID, Visibility: post.VisibilityPrivate}, s.author.ID)
+	s.Require().NoError(err)
+	s.Equal(post.VisibilityPrivate, updated.Visibility)
+}
@@ -8,11 +8,16 @@ import (
 var (
 	STATIC_PATH = "/storage"
 	POST_PATH   = "/post/"
-	AVATAR_PATH = "/avatars/"
-	FORMAT_TIME = time.RFC3339
-	ADMIN_ROLE  = "admin"
-	USER_ROLE   = "user"
-	USER_ID     = "user_id"
+	// ATTACHMENT_PATH holds processed post attachments
+	ATTACHMENT_PATH = "/attachments/"
+	AVATAR_PATH     = "/avatars/"
+	FORMAT_TIME     = time.RFC3339
+	ADMIN_ROLE      = "admin"
+	USER_ROLE       = "user"
+	USER_ID         = "user_id"
+
+	// LINK_PREVIEW_PATH holds the images of unfurled links
+	LINK_PREVIEW_PATH = "/link-previews/"
 )
 
 func GenerateStatic(paths []string) string {